@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"addon-radar/internal/backtest"
+	"addon-radar/internal/database"
+	"addon-radar/internal/trending"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Eventual hits need time after the replayed range to show their gains
+const defaultHitHorizon = 7 * 24 * time.Hour
+
+func main() {
+	var paramFiles []string
+	flag.Func("params", `JSON trending parameter set to replay, or "default" for the built-in set (repeatable)`, func(v string) error {
+		paramFiles = append(paramFiles, v)
+		return nil
+	})
+	from := flag.String("from", "", "start of the replayed range, YYYY-MM-DD or RFC 3339 (default: 7 days before --to)")
+	to := flag.String("to", "", "end of the replayed range, YYYY-MM-DD or RFC 3339 (default: --hit-horizon ago)")
+	step := flag.Duration("step", time.Hour, "time between replayed calculations")
+	hitCount := flag.Int("hits", 50, "number of top download gainers counted as eventual hits")
+	hitHorizon := flag.Duration("hit-horizon", defaultHitHorizon, "how long after --to download gains count towards eventual hits")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL required")
+	}
+
+	sets, err := loadParamSets(paramFiles)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := backtest.Config{
+		Step:       *step,
+		HitCount:   int32(*hitCount), //nolint:gosec // flag value is small
+		HitHorizon: *hitHorizon,
+	}
+	cfg.To = time.Now().Add(-*hitHorizon).Truncate(time.Hour)
+	if *to != "" {
+		if cfg.To, err = parseTime(*to); err != nil {
+			log.Fatal(err)
+		}
+	}
+	cfg.From = cfg.To.Add(-7 * 24 * time.Hour)
+	if *from != "" {
+		if cfg.From, err = parseTime(*from); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pool.Close()
+
+	slog.Info("starting backtest", "from", cfg.From, "to", cfg.To, "step", cfg.Step, "sets", len(sets))
+	report, err := backtest.Run(ctx, database.New(pool), cfg, sets)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := printReport(os.Stdout, report); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadParamSets reads the parameter sets to compare; none means the built-in set.
+func loadParamSets(files []string) ([]trending.Params, error) {
+	if len(files) == 0 {
+		return []trending.Params{trending.DefaultParams()}, nil
+	}
+
+	sets := make([]trending.Params, 0, len(files))
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		p := trending.DefaultParams()
+		if f != "default" {
+			var err error
+			if p, err = trending.LoadParamsFile(f); err != nil {
+				return nil, fmt.Errorf("%s: %w", f, err)
+			}
+		}
+		if seen[p.Version] {
+			return nil, fmt.Errorf("parameter set version %q given twice", p.Version)
+		}
+		seen[p.Version] = true
+		sets = append(sets, p)
+	}
+	return sets, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: want YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
+
+func printReport(out io.Writer, r *backtest.Report) error {
+	fmt.Fprintf(out, "Backtest %s to %s: %d steps, %d eventual hits\n\n",
+		r.From.Format(time.RFC3339), r.To.Format(time.RFC3339), r.Steps, r.HitCount)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tLIST\tCHURN\tAVG HOURS ON LIST\tDISTINCT\tHITS LISTED\tHITS EARLY")
+	for _, s := range r.Sets {
+		fmt.Fprintf(w, "%s\thot\t%.1f%%\t%.1f\t%d\t%d\t%d\n",
+			s.Version, s.Hot.Churn*100, s.Hot.AvgHoursOnList, s.Hot.DistinctAddons, s.Hits.Listed, s.Hits.CaughtEarly)
+		fmt.Fprintf(w, "%s\trising\t%.1f%%\t%.1f\t%d\t\t\n",
+			s.Version, s.Rising.Churn*100, s.Rising.AvgHoursOnList, s.Rising.DistinctAddons)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Overlap) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SET A\tSET B\tHOT OVERLAP\tRISING OVERLAP")
+	for _, o := range r.Overlap {
+		fmt.Fprintf(w, "%s\t%s\t%.1f%%\t%.1f%%\n", o.A, o.B, o.Hot*100, o.Rising*100)
+	}
+	return w.Flush()
+}
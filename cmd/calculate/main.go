@@ -2,17 +2,36 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"log/slog"
 	"os"
+	"time"
 
 	"addon-radar/internal/database"
+	"addon-radar/internal/joblock"
+	"addon-radar/internal/sync"
 	"addon-radar/internal/trending"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func main() {
+	force := flag.Bool("force", false, "run even if another process holds the trending lock")
+	paramsFile := flag.String("params", os.Getenv("TRENDING_PARAMS_FILE"), "JSON trending parameter set to use instead of the active one")
+	activate := flag.Bool("activate", false, "record the --params set as the active one and exit without calculating")
+	shadow := flag.Bool("shadow", false, "record the --params set as the shadow set, scored alongside the live one, and exit")
+	clearShadow := flag.Bool("clear-shadow", false, "stop scoring the shadow set and exit")
+	rollback := flag.Bool("rollback", false, "discard the live trending scores, restore the previous generation and exit")
+	flag.Parse()
+
+	if *activate && *paramsFile == "" {
+		log.Fatal("--activate requires --params")
+	}
+	if *shadow && *paramsFile == "" {
+		log.Fatal("--shadow requires --params")
+	}
+
 	ctx := context.Background()
 
 	dbURL := os.Getenv("DATABASE_URL")
@@ -28,8 +47,80 @@ func main() {
 
 	slog.Info("connected to database")
 
-	calc := trending.NewCalculator(database.New(pool))
-	if err := calc.CalculateAll(ctx); err != nil {
+	queries := database.New(pool)
+
+	if *activate {
+		params, err := trending.LoadParamsFile(*paramsFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := trending.ActivateParams(ctx, pool, params); err != nil {
+			log.Fatal(err)
+		}
+		slog.Info("activated trending params", "version", params.Version)
+		return
+	}
+
+	if *shadow {
+		params, err := trending.LoadParamsFile(*paramsFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := trending.SetShadowParams(ctx, pool, params); err != nil {
+			log.Fatal(err)
+		}
+		slog.Info("set shadow trending params", "version", params.Version)
+		return
+	}
+
+	if *clearShadow {
+		if err := trending.ClearShadowParams(ctx, queries); err != nil {
+			log.Fatal(err)
+		}
+		slog.Info("cleared shadow trending params")
+		return
+	}
+
+	if *rollback {
+		locker := joblock.NewLocker(pool, "calculate")
+		err := locker.Run(ctx, joblock.JobTrending, *force, func(ctx context.Context) error {
+			restored, err := trending.Rollback(ctx, pool)
+			if err != nil {
+				return err
+			}
+			slog.Info("restored previous trending generation", "run_id", restored.RunID, "published_at", restored.PublishedAt.Time)
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Don't publish scores computed from a suspicious sync
+	reason, err := sync.LatestSyncQuarantine(ctx, queries)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if reason != "" {
+		slog.Warn("skipping trending calculation: latest sync is quarantined", "reason", reason)
+		return
+	}
+
+	locker := joblock.NewLocker(pool, "calculate")
+	err = locker.Run(ctx, joblock.JobTrending, *force, func(ctx context.Context) error {
+		calculator := trending.NewCalculator(trending.NewPostgresStore(pool))
+		calculator.SetParamsFile(*paramsFile)
+		if err := calculator.CalculateAll(ctx); err != nil {
+			return err
+		}
+		if _, err := trending.DetectComebacks(ctx, queries, time.Now()); err != nil {
+			return err
+		}
+		_, err := trending.UpdateForecasts(ctx, pool, time.Now())
+		return err
+	})
+	if err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"addon-radar/internal/config"
+	"addon-radar/internal/database"
+	"addon-radar/internal/joblock"
+	"addon-radar/internal/scheduler"
+	"addon-radar/internal/sync"
+)
+
+const (
+	// hotRefreshLimit is how many addons from each trending list are re-fetched by the hot refresh job
+	hotRefreshLimit = 100
+
+	// incrementalSyncOverlap re-fetches a little before the last run to cover
+	// clock skew and CurseForge search index lag
+	incrementalSyncOverlap = 5 * time.Minute
+
+	// incrementalSyncLookback is used when the incremental sync has never succeeded
+	incrementalSyncLookback = 24 * time.Hour
+)
+
+// lockFor maps each daemon job to the lock guarding the tables it writes
+var lockFor = map[string]string{
+	"full_sync":        joblock.JobSync,
+	"incremental_sync": joblock.JobSync,
+	"hot_refresh":      joblock.JobSync,
+	"trending":         joblock.JobTrending,
+	"cleanup":          joblock.JobSync,
+}
+
+// withJobLock wraps a scheduled job so it only runs while holding the job lock.
+// A job skipped because of a held lock is reported as a failed run.
+func withJobLock(locker *joblock.Locker, job string, run func(context.Context, time.Time) error) func(context.Context, time.Time) error {
+	return func(ctx context.Context, lastSuccess time.Time) error {
+		return locker.Run(ctx, job, false, func(ctx context.Context) error {
+			return run(ctx, lastSuccess)
+		})
+	}
+}
+
+// runDaemon runs sync, trending and cleanup jobs on the built-in schedule until
+// SIGTERM or SIGINT. A job in progress finishes before the daemon exits.
+func runDaemon(ctx context.Context, pool *pgxpool.Pool, syncService *sync.Service, locker *joblock.Locker, cfg *config.Config) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	queries := database.New(pool)
+	schedule := cfg.Schedule
+
+	jobs := []scheduler.Job{
+		{
+			Name:     "full_sync",
+			Interval: schedule.FullSyncInterval,
+			Jitter:   schedule.FullSyncJitter,
+			Run: func(ctx context.Context, _ time.Time) error {
+				result, err := syncService.RunFullSync(ctx)
+				if err != nil {
+					return err
+				}
+				if result.Quarantined {
+					return nil
+				}
+				return markMissingInactive(ctx, queries, result.SyncedIDs)
+			},
+		},
+		{
+			Name:     "incremental_sync",
+			Interval: schedule.IncrementalSyncInterval,
+			Jitter:   schedule.IncrementalSyncJitter,
+			Run: func(ctx context.Context, lastSuccess time.Time) error {
+				since := lastSuccess.Add(-incrementalSyncOverlap)
+				if lastSuccess.IsZero() {
+					since = time.Now().Add(-incrementalSyncLookback)
+				}
+				_, err := syncService.RunIncrementalSync(ctx, since)
+				return err
+			},
+		},
+		{
+			Name:     "hot_refresh",
+			Interval: schedule.HotRefreshInterval,
+			Jitter:   schedule.HotRefreshJitter,
+			Run: func(ctx context.Context, _ time.Time) error {
+				_, err := syncService.RefreshTrendingAddons(ctx, hotRefreshLimit)
+				return err
+			},
+		},
+		{
+			Name:     "trending",
+			Interval: schedule.TrendingInterval,
+			Jitter:   schedule.TrendingJitter,
+			Run: func(ctx context.Context, _ time.Time) error {
+				return calculateTrending(ctx, pool, cfg.TrendingParamsFile)
+			},
+		},
+		{
+			Name:     "cleanup",
+			Interval: schedule.CleanupInterval,
+			Jitter:   schedule.CleanupJitter,
+			Run: func(ctx context.Context, _ time.Time) error {
+				return cleanupSnapshots(ctx, queries)
+			},
+		},
+	}
+
+	for i, job := range jobs {
+		if job.Interval <= 0 {
+			return fmt.Errorf("job %s: interval must be positive, got %s", job.Name, job.Interval)
+		}
+		jobs[i].Run = withJobLock(locker, lockFor[job.Name], job.Run)
+	}
+
+	slog.Info("starting sync daemon")
+	return scheduler.New(scheduler.NewPostgresStore(queries), jobs...).Run(ctx)
+}
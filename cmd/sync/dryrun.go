@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"addon-radar/internal/sync"
+)
+
+// runDryRun compares a fresh CurseForge fetch against the database and prints
+// or exports the differences. Nothing is written to the database.
+func runDryRun(ctx context.Context, syncService *sync.Service, reportPath string) error {
+	slog.Info("starting dry-run sync, no changes will be written")
+
+	report, err := syncService.RunDryRun(ctx, sync.DefaultDiffTopDeltas)
+	if err != nil {
+		return err
+	}
+
+	// Apply the same safety thresholds so the report reflects what a real run would do
+	reason, err := syncService.QuarantineReason(ctx, report.FetchedCount, report.FetchedCount, 0)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		slog.Warn("a real sync would be quarantined: no inactive marking or trending publication",
+			"reason", reason,
+		)
+	}
+
+	slog.Info("dry-run complete",
+		"fetched", report.FetchedCount,
+		"new", len(report.NewAddons),
+		"would_deactivate", len(report.WouldDeactivate),
+		"reactivated", len(report.Reactivated),
+		"renamed", len(report.Renamed),
+		"category_changes", len(report.CategoryChanges),
+	)
+
+	if reportPath == "" {
+		return report.WriteText(os.Stdout)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+	if err := os.WriteFile(reportPath, data, 0o600); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	slog.Info("dry-run report written", "path", reportPath)
+	return nil
+}
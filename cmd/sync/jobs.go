@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"addon-radar/internal/database"
+	"addon-radar/internal/sync"
+	"addon-radar/internal/trending"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// calculateTrending recalculates trending scores from the latest snapshots,
+// records any new comebacks and refreshes download forecasts. It is skipped
+// while the latest full sync is quarantined. An empty paramsFile uses the
+// active parameter set from the database.
+func calculateTrending(ctx context.Context, pool *pgxpool.Pool, paramsFile string) error {
+	reason, err := sync.LatestSyncQuarantine(ctx, database.New(pool))
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		slog.Warn("skipping trending calculation: latest sync is quarantined", "reason", reason)
+		return nil
+	}
+
+	slog.Info("starting trending calculation")
+	calculator := trending.NewCalculator(trending.NewPostgresStore(pool))
+	calculator.SetParamsFile(paramsFile)
+	if err := calculator.CalculateAll(ctx); err != nil {
+		return fmt.Errorf("trending calculation: %w", err)
+	}
+	if _, err := trending.DetectComebacks(ctx, database.New(pool), time.Now()); err != nil {
+		return fmt.Errorf("comeback detection: %w", err)
+	}
+	if _, err := trending.UpdateForecasts(ctx, pool, time.Now()); err != nil {
+		return fmt.Errorf("download forecasts: %w", err)
+	}
+	return nil
+}
+
+// cleanupSnapshots deletes old snapshots (95-day retention) in batches
+// to avoid long-running transactions that lock the table
+func cleanupSnapshots(ctx context.Context, queries *database.Queries) error {
+	var totalDeleted int64
+	for {
+		deleted, err := queries.DeleteOldSnapshotsBatch(ctx, snapshotDeleteBatchSize)
+		if err != nil {
+			return fmt.Errorf("snapshot cleanup batch (deleted %d so far): %w", totalDeleted, err)
+		}
+		totalDeleted += deleted
+		if deleted == 0 {
+			break
+		}
+		if deleted == int64(snapshotDeleteBatchSize) {
+			// More batches to process, yield briefly to reduce contention
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	if totalDeleted > 0 {
+		slog.Info("snapshots cleaned", "count", totalDeleted)
+	}
+	return nil
+}
+
+// markMissingInactive marks addons absent from a healthy full sync as inactive.
+// Callers must not pass IDs from a quarantined run.
+func markMissingInactive(ctx context.Context, queries *database.Queries, syncedIDs []int32) error {
+	// Never mark everything inactive, whatever the thresholds are set to
+	if len(syncedIDs) == 0 {
+		slog.Warn("skipping inactive marking: no addons synced")
+		return nil
+	}
+
+	inactive, err := queries.MarkMissingAddonsInactive(ctx, syncedIDs)
+	if err != nil {
+		return fmt.Errorf("mark inactive: %w", err)
+	}
+	if inactive > 0 {
+		slog.Info("addons marked inactive", "count", inactive)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"addon-radar/internal/joblock"
+)
+
+// printLocks writes the recorded holder of each job lock to stdout
+func printLocks(ctx context.Context, locker *joblock.Locker) error {
+	statuses, err := locker.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		fmt.Println("no job locks held")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tHOLDER\tACQUIRED\tHEARTBEAT\tCONNECTED\tSTATE") //nolint:errcheck // Best-effort CLI output
+	for _, st := range statuses {
+		state := "ok"
+		if st.Stale {
+			state = "STALE"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s ago\t%t\t%s\n", //nolint:errcheck // Best-effort CLI output
+			st.Job, st.Holder,
+			st.AcquiredAt.Format(time.RFC3339),
+			time.Since(st.HeartbeatAt).Round(time.Second),
+			st.HolderConnected, state,
+		)
+	}
+	return w.Flush()
+}
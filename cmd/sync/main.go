@@ -2,36 +2,45 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log/slog"
 	"os"
-	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"addon-radar/internal/config"
 	"addon-radar/internal/database"
+	"addon-radar/internal/joblock"
 	"addon-radar/internal/sync"
-	"addon-radar/internal/trending"
 )
 
 const (
 	// snapshotDeleteBatchSize is the number of old snapshots to delete per batch
 	// to avoid long-running transactions that lock the table.
 	snapshotDeleteBatchSize = 10000
-
-	// minSyncedAddonsThreshold is the minimum number of addons that must be synced
-	// before marking missing addons as inactive. Prevents catastrophic data loss
-	// if CurseForge API returns empty response.
-	minSyncedAddonsThreshold = 1000
 )
 
 func main() {
+	dryRun := flag.Bool("dry-run", false, "fetch from CurseForge and report what would change without writing")
+	reportPath := flag.String("report", "", "with --dry-run, write the report as JSON to this path instead of printing it")
+	daemon := flag.Bool("daemon", false, "run continuously on the built-in schedule instead of a single sync")
+	force := flag.Bool("force", false, "run even if another process holds the job lock (not with --daemon)")
+	showLocks := flag.Bool("locks", false, "print job lock holders, flagging stale ones, and exit")
+	flag.Parse()
+
 	// Setup structured logging
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
 	slog.SetDefault(logger)
 
+	if *daemon && *force {
+		// Every scheduled run would skip the lock for the life of the process
+		slog.Error("--force can't be used with --daemon")
+		os.Exit(1)
+	}
+
 	slog.Info("addon-radar sync starting...")
 
 	// Load configuration
@@ -67,60 +76,73 @@ func main() {
 
 	slog.Info("database connected successfully")
 
-	// Run sync
 	syncService := sync.NewService(pool, cfg.CurseForgeAPIKey)
-	syncedIDs, err := syncService.RunFullSync(ctx)
-	if err != nil {
-		slog.Error("sync failed", "error", err)
-		os.Exit(1)
+	syncService.SetSafetyThresholds(sync.SafetyThresholds{
+		MinAddons:         cfg.Safety.MinAddons,
+		MinRatioOfLastRun: cfg.Safety.MinRatioOfLastRun,
+		MaxErrorRate:      cfg.Safety.MaxErrorRate,
+	})
+	locker := joblock.NewLocker(pool, "sync")
+
+	if *showLocks {
+		if err := printLocks(ctx, locker); err != nil {
+			slog.Error("failed to list job locks", "error", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	slog.Info("sync complete")
+	if *daemon {
+		if err := runDaemon(ctx, pool, syncService, locker, cfg); err != nil {
+			slog.Error("daemon failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	// Run trending calculation
-	slog.Info("starting trending calculation")
-	queries := database.New(pool)
-	calculator := trending.NewCalculator(queries)
-	if err := calculator.CalculateAll(ctx); err != nil {
-		slog.Error("trending calculation failed", "error", err)
-		// Don't exit - sync succeeded, trending is secondary
+	if *dryRun {
+		if err := runDryRun(ctx, syncService, *reportPath); err != nil {
+			slog.Error("dry run failed", "error", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	// Cleanup: delete old snapshots (95-day retention) in batches
-	// to avoid long-running transactions that lock the table
-	var totalDeleted int64
-	for {
-		deleted, err := queries.DeleteOldSnapshotsBatch(ctx, snapshotDeleteBatchSize)
+	queries := database.New(pool)
+
+	// Sync, cleanup and inactive marking all hold the sync lock so two runs never interleave
+	err = locker.Run(ctx, joblock.JobSync, *force, func(ctx context.Context) error {
+		result, err := syncService.RunFullSync(ctx)
 		if err != nil {
-			slog.Warn("snapshot cleanup batch failed", "error", err, "deleted_so_far", totalDeleted)
-			break
+			return fmt.Errorf("sync: %w", err)
 		}
-		totalDeleted += deleted
-		if deleted == 0 {
-			break
+
+		slog.Info("sync complete")
+
+		// Snapshots from a quarantined run are kept, but nothing is published from them
+		if !result.Quarantined {
+			// Trending is secondary: log failures but keep going
+			err = locker.Run(ctx, joblock.JobTrending, *force, func(ctx context.Context) error {
+				return calculateTrending(ctx, pool, cfg.TrendingParamsFile)
+			})
+			if err != nil {
+				slog.Error("trending calculation failed", "error", err)
+			}
 		}
-		if deleted == int64(snapshotDeleteBatchSize) {
-			// More batches to process, yield briefly to reduce contention
-			time.Sleep(100 * time.Millisecond)
+
+		if err := cleanupSnapshots(ctx, queries); err != nil {
+			slog.Warn("snapshot cleanup failed", "error", err)
 		}
-	}
-	if totalDeleted > 0 {
-		slog.Info("snapshots cleaned", "count", totalDeleted)
-	}
 
-	// Cleanup: mark missing addons as inactive
-	// Guard against empty or suspiciously small sync results to prevent catastrophic data loss
-	if len(syncedIDs) < minSyncedAddonsThreshold {
-		slog.Warn("skipping inactive marking: synced addon count below threshold",
-			"synced", len(syncedIDs),
-			"threshold", minSyncedAddonsThreshold,
-		)
-	} else {
-		inactive, err := queries.MarkMissingAddonsInactive(ctx, syncedIDs)
-		if err != nil {
-			slog.Warn("mark inactive failed", "error", err)
-		} else if inactive > 0 {
-			slog.Info("addons marked inactive", "count", inactive)
+		if !result.Quarantined {
+			if err := markMissingInactive(ctx, queries, result.SyncedIDs); err != nil {
+				slog.Warn("mark inactive failed", "error", err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("sync failed", "error", err)
+		os.Exit(1)
 	}
 }
@@ -48,6 +48,7 @@ func main() {
 	}
 
 	server := api.NewServer(database.New(pool))
+	server.SetAdminToken(cfg.AdminToken)
 	if err := server.Run(fmt.Sprintf(":%s", port)); err != nil {
 		slog.Error("server failed", "error", err)
 		os.Exit(1)
@@ -1,14 +1,21 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"addon-radar/internal/database"
+	"addon-radar/internal/sync"
+	"addon-radar/internal/trending"
 )
 
 type AddonResponse struct {
@@ -23,6 +30,9 @@ type AddonResponse struct {
 	PopularityRank int32    `json:"popularity_rank,omitempty"`
 	GameVersions   []string `json:"game_versions"`
 	LastUpdatedAt  string   `json:"last_updated_at,omitempty"`
+	IsComeback     bool     `json:"is_comeback"`    // Revived after a long dormancy, recently
+	HeatIndex      *int16   `json:"heat_index"`     // 0-100 percentile of hot or rising score; nil when not scored
+	HeatSparkline  []int16  `json:"heat_sparkline"` // Daily heat index over the last 7 days, oldest first
 }
 
 type TrendingAddonResponse struct {
@@ -32,6 +42,8 @@ type TrendingAddonResponse struct {
 	RankChange24h    *int    `json:"rank_change_24h"` // nil = new to list
 	RankChange7d     *int    `json:"rank_change_7d"`  // nil = new to list
 	DownloadVelocity float64 `json:"download_velocity"`
+	ThumbsVelocity   float64 `json:"thumbs_velocity,omitempty"` // Set on the loved list
+	CreatedAt        string  `json:"created_at,omitempty"`      // Set on the new list
 }
 
 func addonToResponse(a database.Addon) AddonResponse {
@@ -59,6 +71,13 @@ func addonToResponse(a database.Addon) AddonResponse {
 	if a.LastUpdatedAt.Valid {
 		resp.LastUpdatedAt = a.LastUpdatedAt.Time.Format("2006-01-02T15:04:05Z")
 	}
+	if a.ComebackAt.Valid {
+		resp.IsComeback = time.Since(a.ComebackAt.Time) < trending.ComebackListedWindow
+	}
+	if a.HeatIndex.Valid {
+		resp.HeatIndex = &a.HeatIndex.Int16
+		resp.HeatSparkline = a.HeatSparkline
+	}
 
 	return resp
 }
@@ -72,6 +91,18 @@ func escapeLikePattern(s string) string {
 	return s
 }
 
+// parseDiversity reads the diversity query parameter; "off" asks for the uncapped list order.
+func parseDiversity(c *gin.Context) (rawOrder bool, ok bool) {
+	switch c.DefaultQuery("diversity", "on") {
+	case "on":
+		return false, true
+	case "off":
+		return true, true
+	}
+	respondBadRequest(c, "diversity must be on or off")
+	return false, false
+}
+
 // parsePaginationParams extracts and validates page, perPage, and calculates offset.
 func parsePaginationParams(c *gin.Context) (page, perPage, offset int) {
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -126,6 +157,11 @@ func (s *Server) handleListAddons(c *gin.Context) {
 	page, perPage, offset := parsePaginationParams(c)
 	search := c.Query("search")
 	categoryStr := c.Query("category")
+	sortBy := c.DefaultQuery("sort", "downloads")
+	if sortBy != "downloads" && sortBy != "heat" {
+		respondBadRequest(c, "sort must be downloads or heat")
+		return
+	}
 	ctx := c.Request.Context()
 
 	var addons []database.Addon
@@ -140,9 +176,10 @@ func (s *Server) handleListAddons(c *gin.Context) {
 		searchText := pgtype.Text{String: escapedSearch, Valid: true}
 
 		addons, err = s.db.SearchAddons(ctx, database.SearchAddonsParams{
-			Limit:   int32(perPage), //nolint:gosec // perPage validated to be <= 100
-			Offset:  int32(offset),  //nolint:gosec // offset validated via perPage <= 100
-			Column3: searchText,
+			Search: searchText,
+			Sort:   sortBy,
+			Limit:  int32(perPage), //nolint:gosec // perPage validated to be <= 100
+			Offset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
 		})
 		if err != nil {
 			slog.Error("failed to search addons", "error", err)
@@ -159,9 +196,10 @@ func (s *Server) handleListAddons(c *gin.Context) {
 		}
 
 		addons, err = s.db.ListAddonsByCategory(ctx, database.ListAddonsByCategoryParams{
-			Limit:   int32(perPage),    //nolint:gosec // perPage validated to be <= 100
-			Offset:  int32(offset),     //nolint:gosec // offset validated via perPage <= 100
-			Column3: int32(categoryID), //nolint:gosec // validated via ParseInt
+			CategoryID: int32(categoryID), //nolint:gosec // validated via ParseInt
+			Sort:       sortBy,
+			Limit:      int32(perPage), //nolint:gosec // perPage validated to be <= 100
+			Offset:     int32(offset),  //nolint:gosec // offset validated via perPage <= 100
 		})
 		if err != nil {
 			slog.Error("failed to list addons by category", "error", err)
@@ -171,6 +209,7 @@ func (s *Server) handleListAddons(c *gin.Context) {
 		total, err = s.db.CountAddonsByCategory(ctx, int32(categoryID)) //nolint:gosec // validated via ParseInt
 	} else {
 		addons, err = s.db.ListAddons(ctx, database.ListAddonsParams{
+			Sort:   sortBy,
 			Limit:  int32(perPage), //nolint:gosec // perPage validated to be <= 100
 			Offset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
 		})
@@ -209,6 +248,88 @@ func (s *Server) handleGetAddon(c *gin.Context) {
 	respondWithData(c, addonToResponse(addon))
 }
 
+type RemovedAddonResponse struct {
+	AddonResponse
+	RemovedAt string `json:"removed_at"`
+}
+
+// handleRemovedAddons lists addons that vanished from CurseForge with their last known stats.
+func (s *Server) handleRemovedAddons(c *gin.Context) {
+	page, perPage, offset := parsePaginationParams(c)
+	ctx := c.Request.Context()
+
+	total, err := s.db.CountRemovedAddons(ctx)
+	if err != nil {
+		slog.Error("failed to count removed addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListRemovedAddons(ctx, database.ListRemovedAddonsParams{
+		Limit:  int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		Offset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to list removed addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := make([]RemovedAddonResponse, len(addons))
+	for i, a := range addons {
+		response[i] = RemovedAddonResponse{
+			AddonResponse: addonToResponse(a.Addon),
+		}
+		if a.RemovedAt.Valid {
+			response[i].RemovedAt = a.RemovedAt.Time.Format("2006-01-02T15:04:05Z")
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+type ReturnedAddonResponse struct {
+	AddonResponse
+	ReturnedAt string `json:"returned_at"`
+	RemovedAt  string `json:"removed_at,omitempty"` // Empty if removed before events were recorded
+}
+
+// handleReturnedAddons lists addons that came back after being missing from CurseForge.
+func (s *Server) handleReturnedAddons(c *gin.Context) {
+	page, perPage, offset := parsePaginationParams(c)
+	ctx := c.Request.Context()
+
+	total, err := s.db.CountReactivatedAddons(ctx)
+	if err != nil {
+		slog.Error("failed to count returned addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListReactivatedAddons(ctx, database.ListReactivatedAddonsParams{
+		Limit:  int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		Offset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to list returned addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := make([]ReturnedAddonResponse, len(addons))
+	for i, a := range addons {
+		response[i] = ReturnedAddonResponse{
+			AddonResponse: addonToResponse(a.Addon),
+			ReturnedAt:    a.ReturnedAt.Time.Format("2006-01-02T15:04:05Z"),
+		}
+		if a.RemovedAt.Valid {
+			response[i].RemovedAt = a.RemovedAt.Time.Format("2006-01-02T15:04:05Z")
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
 type SnapshotResponse struct {
 	RecordedAt     string `json:"recorded_at"`
 	DownloadCount  int64  `json:"download_count"`
@@ -261,12 +382,371 @@ func (s *Server) handleGetAddonHistory(c *gin.Context) {
 	respondWithData(c, response)
 }
 
+// StoredScoreResponse is an addon's row in trending_scores, as served by the trending lists.
+type StoredScoreResponse struct {
+	HotScore              float64 `json:"hot_score"`
+	RisingScore           float64 `json:"rising_score"`
+	LovedScore            float64 `json:"loved_score"`
+	FreshScore            float64 `json:"fresh_score"`
+	DownloadVelocity      float64 `json:"download_velocity"`
+	DownloadGrowthPct     float64 `json:"download_growth_pct"`
+	SizeMultiplier        float64 `json:"size_multiplier"`
+	MaintenanceMultiplier float64 `json:"maintenance_multiplier"`
+	FirstHotAt            string  `json:"first_hot_at,omitempty"`
+	FirstRisingAt         string  `json:"first_rising_at,omitempty"`
+	FirstLovedAt          string  `json:"first_loved_at,omitempty"`
+	ParamsVersion         string  `json:"params_version,omitempty"`
+	CalculatedAt          string  `json:"calculated_at,omitempty"`
+}
+
+// ListStatusResponse says whether an addon is on a trending list, and if not, why not.
+// An unlisted addon with no exclusions qualifies now and appears after the next calculation.
+type ListStatusResponse struct {
+	Listed     bool     `json:"listed"`
+	Rank       int64    `json:"rank,omitempty"`
+	RawRank    int64    `json:"raw_rank,omitempty"` // Rank before the diversity caps, 0 when they keep it off the list
+	ExcludedBy []string `json:"excluded_by"`
+}
+
+// ScoreResponse explains an addon's trending scores. Breakdown is recomputed
+// from the latest snapshots with the parameters of the latest calculation, so
+// it can run slightly ahead of Stored.
+type ScoreResponse struct {
+	AddonID       int32                `json:"addon_id"`
+	Slug          string               `json:"slug"`
+	ParamsVersion string               `json:"params_version"`
+	Stored        *StoredScoreResponse `json:"stored"`
+	Breakdown     trending.Breakdown   `json:"breakdown"`
+	Hot           ListStatusResponse   `json:"hot"`
+	Rising        ListStatusResponse   `json:"rising"`
+	Loved         ListStatusResponse   `json:"loved"`
+	Fresh         ListStatusResponse   `json:"fresh"`
+}
+
+func storedScoreToResponse(t database.TrendingScore) *StoredScoreResponse {
+	resp := &StoredScoreResponse{
+		HotScore:              numericToFloat64(t.HotScore),
+		RisingScore:           numericToFloat64(t.RisingScore),
+		LovedScore:            numericToFloat64(t.LovedScore),
+		FreshScore:            numericToFloat64(t.FreshScore),
+		DownloadVelocity:      numericToFloat64(t.DownloadVelocity),
+		DownloadGrowthPct:     numericToFloat64(t.DownloadGrowthPct),
+		SizeMultiplier:        numericToFloat64(t.SizeMultiplier),
+		MaintenanceMultiplier: numericToFloat64(t.MaintenanceMultiplier),
+		ParamsVersion:         t.ParamsVersion.String,
+	}
+	if t.FirstHotAt.Valid {
+		resp.FirstHotAt = t.FirstHotAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	if t.FirstRisingAt.Valid {
+		resp.FirstRisingAt = t.FirstRisingAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	if t.FirstLovedAt.Valid {
+		resp.FirstLovedAt = t.FirstLovedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	if t.CalculatedAt.Valid {
+		resp.CalculatedAt = t.CalculatedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	return resp
+}
+
+// handleGetAddonScore explains how an addon's trending scores are derived and
+// which thresholds keep it off the trending lists.
+func (s *Server) handleGetAddonScore(c *gin.Context) {
+	slug := c.Param("slug")
+	ctx := c.Request.Context()
+
+	addon, err := s.db.GetAddonBySlug(ctx, slug)
+	if err != nil {
+		respondNotFound(c, "Addon not found")
+		return
+	}
+
+	params, err := trending.CurrentParams(ctx, s.db)
+	if err != nil {
+		slog.Error("failed to get trending params", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	stat, err := s.db.GetAddonSnapshotStats(ctx, addon.ID)
+	if err != nil {
+		slog.Error("failed to get addon snapshot stats", "error", err)
+		respondInternalError(c)
+		return
+	}
+	percentile95, err := s.db.GetDownloadPercentile(ctx)
+	if err != nil {
+		slog.Error("failed to get download percentile", "error", err)
+		respondInternalError(c)
+		return
+	}
+	// Same fallback as the calculator
+	if percentile95 <= 0 {
+		percentile95 = 500000
+	}
+	updates, err := s.db.CountAddonRecentFileUpdates(ctx, addon.ID)
+	if err != nil {
+		slog.Error("failed to count addon file updates", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	var stored *database.TrendingScore
+	score, err := s.db.GetTrendingScore(ctx, addon.ID)
+	switch {
+	case err == nil:
+		stored = &score
+	case !errors.Is(err, pgx.ErrNoRows):
+		slog.Error("failed to get trending score", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	existing := database.GetAllTrendingScoresRow{AddonID: addon.ID}
+	if stored != nil {
+		existing.FirstHotAt = stored.FirstHotAt
+		existing.FirstRisingAt = stored.FirstRisingAt
+		existing.FirstLovedAt = stored.FirstLovedAt
+	}
+	breakdown := trending.Explain(params, database.GetAllSnapshotStatsRow(stat), percentile95, updates, existing, time.Now())
+
+	response := ScoreResponse{
+		AddonID:       addon.ID,
+		Slug:          addon.Slug,
+		ParamsVersion: params.Version,
+		Breakdown:     breakdown,
+		Hot:           ListStatusResponse{ExcludedBy: []string{}},
+		Rising:        ListStatusResponse{ExcludedBy: []string{}},
+		Loved:         ListStatusResponse{ExcludedBy: []string{}},
+		Fresh:         ListStatusResponse{ExcludedBy: []string{}},
+	}
+	if stored != nil {
+		response.Stored = storedScoreToResponse(*stored)
+	}
+
+	// Listing follows the stored scores, the same way the trending endpoints do
+	active := addon.Status.String == "active"
+	downloads := addon.DownloadCount.Int64
+	var storedHot, storedRising, storedLoved, storedFresh float64
+	if stored != nil {
+		storedHot = numericToFloat64(stored.HotScore)
+		storedRising = numericToFloat64(stored.RisingScore)
+		storedLoved = numericToFloat64(stored.LovedScore)
+		storedFresh = numericToFloat64(stored.FreshScore)
+	}
+
+	if active && storedHot > 0 && downloads >= params.MinHotDownloads {
+		above, err := listRank(stored.HotRank, func() (int64, error) {
+			return s.db.CountHotAddonsAbove(ctx, database.CountHotAddonsAboveParams{
+				MinDownloads: params.MinHotDownloads,
+				Score:        stored.HotScore,
+			})
+		})
+		if err != nil {
+			slog.Error("failed to rank hot addon", "error", err)
+			respondInternalError(c)
+			return
+		}
+		response.Hot = ListStatusResponse{Listed: true, Rank: above + 1, RawRank: int64(stored.HotRawRank.Int16), ExcludedBy: []string{}}
+	} else {
+		response.Hot.ExcludedBy = listExclusions(active, stored != nil, trending.HotExclusions(params, breakdown))
+	}
+
+	onHotList := response.Hot.Listed && response.Hot.Rank <= int64(params.ListSize)
+	if active && storedRising > 0 && !onHotList &&
+		downloads >= params.MinRisingDownloads && downloads <= params.MaxRisingDownloads {
+		above, err := listRank(stored.RisingRank, func() (int64, error) {
+			return s.db.CountRisingAddonsAbove(ctx, database.CountRisingAddonsAboveParams{
+				MinDownloads: params.MinRisingDownloads,
+				MaxDownloads: params.MaxRisingDownloads,
+				Score:        stored.RisingScore,
+				HotListSize:  params.ListSize,
+			})
+		})
+		if err != nil {
+			slog.Error("failed to rank rising addon", "error", err)
+			respondInternalError(c)
+			return
+		}
+		response.Rising = ListStatusResponse{Listed: true, Rank: above + 1, RawRank: int64(stored.RisingRawRank.Int16), ExcludedBy: []string{}}
+	} else {
+		response.Rising.ExcludedBy = listExclusions(active, stored != nil, trending.RisingExclusions(params, breakdown, onHotList))
+	}
+
+	if active && storedLoved > 0 && downloads >= params.MinLovedDownloads {
+		above, err := listRank(stored.LovedRank, func() (int64, error) {
+			return s.db.CountLovedAddonsAbove(ctx, database.CountLovedAddonsAboveParams{
+				MinDownloads: params.MinLovedDownloads,
+				Score:        stored.LovedScore,
+			})
+		})
+		if err != nil {
+			slog.Error("failed to rank loved addon", "error", err)
+			respondInternalError(c)
+			return
+		}
+		response.Loved = ListStatusResponse{Listed: true, Rank: above + 1, RawRank: int64(stored.LovedRawRank.Int16), ExcludedBy: []string{}}
+	} else {
+		response.Loved.ExcludedBy = listExclusions(active, stored != nil, trending.LovedExclusions(params, breakdown))
+	}
+
+	if active && storedFresh > 0 && downloads >= params.MinFreshDownloads {
+		above, err := s.db.CountFreshAddonsAbove(ctx, database.CountFreshAddonsAboveParams{
+			MinDownloads: params.MinFreshDownloads,
+			Score:        stored.FreshScore,
+		})
+		if err != nil {
+			slog.Error("failed to rank fresh addon", "error", err)
+			respondInternalError(c)
+			return
+		}
+		response.Fresh = ListStatusResponse{Listed: true, Rank: above + 1, ExcludedBy: []string{}}
+	} else {
+		response.Fresh.ExcludedBy = listExclusions(active, stored != nil, trending.FreshExclusions(params, breakdown))
+	}
+
+	respondWithData(c, response)
+}
+
+// listRank returns how many entries are ranked above an addon on a list: its
+// stored rank while it is a member, which hysteresis can keep below higher
+// scores, otherwise the count of entries ranked above its score.
+func listRank(stored pgtype.Int2, countAbove func() (int64, error)) (int64, error) {
+	if stored.Valid {
+		return int64(stored.Int16) - 1, nil
+	}
+	return countAbove()
+}
+
+// listExclusions adds the reasons that apply to every list to a list's own.
+func listExclusions(active, calculated bool, reasons []string) []string {
+	var common []string
+	if !active {
+		common = append(common, trending.ExclusionNotActive)
+	}
+	if !calculated {
+		common = append(common, trending.ExclusionNotCalculated)
+	}
+	return append(common, reasons...)
+}
+
 type CategoryResponse struct {
-	ID       int32  `json:"id"`
-	Name     string `json:"name"`
-	Slug     string `json:"slug"`
-	ParentID int32  `json:"parent_id,omitempty"`
-	IconURL  string `json:"icon_url,omitempty"`
+	ID           int32  `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	ParentID     int32  `json:"parent_id,omitempty"`
+	ClassID      int32  `json:"class_id,omitempty"`
+	IsClass      bool   `json:"is_class"`
+	DisplayIndex int32  `json:"display_index"`
+	IconURL      string `json:"icon_url,omitempty"`
+}
+
+type CategoryTreeNode struct {
+	CategoryResponse
+	Children []CategoryTreeNode `json:"children"`
+}
+
+type CategoryDetailResponse struct {
+	CategoryResponse
+	Subcategories  []CategoryResponse `json:"subcategories"`
+	AddonCount     int64              `json:"addon_count"`
+	TotalDownloads int64              `json:"total_downloads"`
+	TopAddons      []AddonResponse    `json:"top_addons"`
+}
+
+// categoryTopAddonsLimit is the number of top addons included in a category detail response
+const categoryTopAddonsLimit = 10
+
+func categoryToResponse(cat database.Category) CategoryResponse {
+	resp := CategoryResponse{
+		ID:           cat.ID,
+		Name:         cat.Name,
+		Slug:         cat.Slug,
+		IsClass:      cat.IsClass,
+		DisplayIndex: cat.DisplayIndex,
+	}
+	if cat.ParentID.Valid {
+		resp.ParentID = cat.ParentID.Int32
+	}
+	if cat.ClassID.Valid {
+		resp.ClassID = cat.ClassID.Int32
+	}
+	if cat.IconUrl.Valid {
+		resp.IconURL = cat.IconUrl.String
+	}
+	return resp
+}
+
+// sortCategories orders categories the way CurseForge displays them.
+func sortCategories(categories []database.Category) {
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].DisplayIndex != categories[j].DisplayIndex {
+			return categories[i].DisplayIndex < categories[j].DisplayIndex
+		}
+		return categories[i].Name < categories[j].Name
+	})
+}
+
+// childrenByParent groups categories by parent ID. Categories whose parent is
+// missing from the list are grouped under 0 so they show up as roots.
+func childrenByParent(categories []database.Category) map[int32][]database.Category {
+	known := make(map[int32]bool, len(categories))
+	for _, cat := range categories {
+		known[cat.ID] = true
+	}
+
+	children := make(map[int32][]database.Category)
+	for _, cat := range categories {
+		var parent int32
+		if cat.ParentID.Valid && cat.ParentID.Int32 != cat.ID && known[cat.ParentID.Int32] {
+			parent = cat.ParentID.Int32
+		}
+		children[parent] = append(children[parent], cat)
+	}
+	for _, list := range children {
+		sortCategories(list)
+	}
+	return children
+}
+
+// buildCategoryTree nests categories under their parents.
+func buildCategoryTree(categories []database.Category) []CategoryTreeNode {
+	children := childrenByParent(categories)
+	visited := make(map[int32]bool, len(categories))
+
+	var build func(parent int32) []CategoryTreeNode
+	build = func(parent int32) []CategoryTreeNode {
+		nodes := []CategoryTreeNode{}
+		for _, cat := range children[parent] {
+			if visited[cat.ID] {
+				continue
+			}
+			visited[cat.ID] = true
+			nodes = append(nodes, CategoryTreeNode{
+				CategoryResponse: categoryToResponse(cat),
+				Children:         build(cat.ID),
+			})
+		}
+		return nodes
+	}
+	return build(0)
+}
+
+// descendantCategoryIDs returns the ID of root and every category below it.
+func descendantCategoryIDs(categories []database.Category, root int32) []int32 {
+	children := childrenByParent(categories)
+	ids := []int32{root}
+	seen := map[int32]bool{root: true}
+	for i := 0; i < len(ids); i++ {
+		for _, cat := range children[ids[i]] {
+			if !seen[cat.ID] {
+				seen[cat.ID] = true
+				ids = append(ids, cat.ID)
+			}
+		}
+	}
+	return ids
 }
 
 func (s *Server) handleListCategories(c *gin.Context) {
@@ -281,27 +761,99 @@ func (s *Server) handleListCategories(c *gin.Context) {
 
 	response := make([]CategoryResponse, len(categories))
 	for i, cat := range categories {
-		response[i] = CategoryResponse{
-			ID:   cat.ID,
-			Name: cat.Name,
-			Slug: cat.Slug,
-		}
-		if cat.ParentID.Valid {
-			response[i].ParentID = cat.ParentID.Int32
-		}
-		if cat.IconUrl.Valid {
-			response[i].IconURL = cat.IconUrl.String
-		}
+		response[i] = categoryToResponse(cat)
+	}
+
+	respondWithData(c, response)
+}
+
+func (s *Server) handleCategoryTree(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	categories, err := s.db.ListCategories(ctx)
+	if err != nil {
+		slog.Error("failed to list categories", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	respondWithData(c, buildCategoryTree(categories))
+}
+
+// handleGetCategory returns a category with its direct subcategories. Addon stats
+// and top addons include addons from every subcategory below it.
+func (s *Server) handleGetCategory(c *gin.Context) {
+	slug := c.Param("slug")
+	ctx := c.Request.Context()
+
+	category, err := s.db.GetCategoryBySlug(ctx, slug)
+	if err != nil {
+		respondNotFound(c, "Category not found")
+		return
+	}
+
+	categories, err := s.db.ListCategories(ctx)
+	if err != nil {
+		slog.Error("failed to list categories", "error", err)
+		respondInternalError(c)
+		return
+	}
+	ids := descendantCategoryIDs(categories, category.ID)
+
+	stats, err := s.db.GetCategoryAddonStats(ctx, ids)
+	if err != nil {
+		slog.Error("failed to get category stats", "error", err, "category", slug)
+		respondInternalError(c)
+		return
+	}
+
+	topAddons, err := s.db.ListTopAddonsInCategories(ctx, database.ListTopAddonsInCategoriesParams{
+		CategoryIds: ids,
+		LimitCount:  categoryTopAddonsLimit,
+	})
+	if err != nil {
+		slog.Error("failed to list top addons in category", "error", err, "category", slug)
+		respondInternalError(c)
+		return
+	}
+
+	response := CategoryDetailResponse{
+		CategoryResponse: categoryToResponse(category),
+		Subcategories:    []CategoryResponse{},
+		AddonCount:       stats.AddonCount,
+		TotalDownloads:   stats.TotalDownloads,
+		TopAddons:        make([]AddonResponse, len(topAddons)),
+	}
+	for _, sub := range childrenByParent(categories)[category.ID] {
+		response.Subcategories = append(response.Subcategories, categoryToResponse(sub))
+	}
+	for i, a := range topAddons {
+		response.TopAddons[i] = addonToResponse(a)
 	}
 
 	respondWithData(c, response)
 }
 
 func (s *Server) handleTrendingHot(c *gin.Context) {
+	if c.Query("at") != "" {
+		s.handleArchivedTrending(c, "hot")
+		return
+	}
+	rawOrder, ok := parseDiversity(c)
+	if !ok {
+		return
+	}
 	page, perPage, offset := parsePaginationParams(c)
 	ctx := c.Request.Context()
 
-	total, err := s.db.CountHotAddons(ctx)
+	params, err := trending.CurrentParams(ctx, s.db)
+	if err != nil {
+		slog.Error("failed to get trending params", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	total, err := s.db.CountHotAddons(ctx, params.MinHotDownloads)
 	if err != nil {
 		slog.Error("failed to count hot addons", "error", err)
 		respondInternalError(c)
@@ -309,8 +861,10 @@ func (s *Server) handleTrendingHot(c *gin.Context) {
 	}
 
 	addons, err := s.db.ListHotAddonsPaginated(ctx, database.ListHotAddonsPaginatedParams{
-		Limit:  int32(perPage), //nolint:gosec // perPage validated to be <= 100
-		Offset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+		MinDownloads: params.MinHotDownloads,
+		RawOrder:     rawOrder,
+		PageSize:     int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset:   int32(offset),  //nolint:gosec // offset validated via perPage <= 100
 	})
 	if err != nil {
 		slog.Error("failed to get hot addons", "error", err)
@@ -329,17 +883,12 @@ func (s *Server) handleTrendingHot(c *gin.Context) {
 	response := make([]TrendingAddonResponse, len(addons))
 	for i, a := range addons {
 		response[i] = TrendingAddonResponse{
-			AddonResponse: addonToResponse(database.Addon{
-				ID: a.ID, Name: a.Name, Slug: a.Slug, Summary: a.Summary,
-				AuthorName: a.AuthorName, LogoUrl: a.LogoUrl, DownloadCount: a.DownloadCount,
-				ThumbsUpCount: a.ThumbsUpCount, PopularityRank: a.PopularityRank,
-				GameVersions: a.GameVersions, LastUpdatedAt: a.LastUpdatedAt,
-			}),
+			AddonResponse:    addonToResponse(a.Addon),
 			Rank:             offset + i + 1,
 			Score:            numericToFloat64(a.HotScore),
 			DownloadVelocity: numericToFloat64(a.DownloadVelocity),
 		}
-		if rc, ok := rankChangeMap[a.ID]; ok {
+		if rc, ok := rankChangeMap[a.Addon.ID]; ok {
 			applyRankChanges(&response[i], rc)
 		}
 	}
@@ -348,22 +897,45 @@ func (s *Server) handleTrendingHot(c *gin.Context) {
 }
 
 func (s *Server) handleTrendingRising(c *gin.Context) {
+	if c.Query("at") != "" {
+		s.handleArchivedTrending(c, "rising")
+		return
+	}
+	rawOrder, ok := parseDiversity(c)
+	if !ok {
+		return
+	}
 	page, perPage, offset := parsePaginationParams(c)
 	ctx := c.Request.Context()
 
-	total, err := s.db.CountRisingAddons(ctx)
+	params, err := trending.CurrentParams(ctx, s.db)
 	if err != nil {
-		slog.Error("failed to count rising addons", "error", err)
+		slog.Error("failed to get trending params", "error", err)
 		respondInternalError(c)
 		return
 	}
 
-	addons, err := s.db.ListRisingAddonsPaginated(ctx, database.ListRisingAddonsPaginatedParams{
-		Limit:  int32(perPage), //nolint:gosec // perPage validated to be <= 100
-		Offset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	total, err := s.db.CountRisingAddons(ctx, database.CountRisingAddonsParams{
+		MinDownloads: params.MinRisingDownloads,
+		MaxDownloads: params.MaxRisingDownloads,
+		HotListSize:  params.ListSize,
 	})
 	if err != nil {
-		slog.Error("failed to get rising addons", "error", err)
+		slog.Error("failed to count rising addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListRisingAddonsPaginated(ctx, database.ListRisingAddonsPaginatedParams{
+		MinDownloads: params.MinRisingDownloads,
+		MaxDownloads: params.MaxRisingDownloads,
+		HotListSize:  params.ListSize,
+		RawOrder:     rawOrder,
+		PageSize:     int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset:   int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to get rising addons", "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -379,20 +951,847 @@ func (s *Server) handleTrendingRising(c *gin.Context) {
 	response := make([]TrendingAddonResponse, len(addons))
 	for i, a := range addons {
 		response[i] = TrendingAddonResponse{
-			AddonResponse: addonToResponse(database.Addon{
-				ID: a.ID, Name: a.Name, Slug: a.Slug, Summary: a.Summary,
-				AuthorName: a.AuthorName, LogoUrl: a.LogoUrl, DownloadCount: a.DownloadCount,
-				ThumbsUpCount: a.ThumbsUpCount, PopularityRank: a.PopularityRank,
-				GameVersions: a.GameVersions, LastUpdatedAt: a.LastUpdatedAt,
-			}),
+			AddonResponse:    addonToResponse(a.Addon),
 			Rank:             offset + i + 1,
 			Score:            numericToFloat64(a.RisingScore),
 			DownloadVelocity: numericToFloat64(a.DownloadVelocity),
 		}
-		if rc, ok := rankChangeMap[a.ID]; ok {
+		if rc, ok := rankChangeMap[a.Addon.ID]; ok {
+			applyRankChanges(&response[i], rc)
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+// handleTrendingLoved returns addons whose users are endorsing them fastest
+// relative to their downloads.
+func (s *Server) handleTrendingLoved(c *gin.Context) {
+	rawOrder, ok := parseDiversity(c)
+	if !ok {
+		return
+	}
+	page, perPage, offset := parsePaginationParams(c)
+	ctx := c.Request.Context()
+
+	params, err := trending.CurrentParams(ctx, s.db)
+	if err != nil {
+		slog.Error("failed to get trending params", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	total, err := s.db.CountLovedAddons(ctx, params.MinLovedDownloads)
+	if err != nil {
+		slog.Error("failed to count loved addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListLovedAddonsPaginated(ctx, database.ListLovedAddonsPaginatedParams{
+		MinDownloads: params.MinLovedDownloads,
+		RawOrder:     rawOrder,
+		PageSize:     int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset:   int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to get loved addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	rankChanges, err := s.db.GetRankChanges(ctx)
+	if err != nil {
+		slog.Error("failed to get rank changes", "error", err)
+		respondInternalError(c)
+		return
+	}
+	rankChangeMap := buildRankChangeMap(rankChanges, "loved")
+
+	response := make([]TrendingAddonResponse, len(addons))
+	for i, a := range addons {
+		response[i] = TrendingAddonResponse{
+			AddonResponse:    addonToResponse(a.Addon),
+			Rank:             offset + i + 1,
+			Score:            numericToFloat64(a.LovedScore),
+			DownloadVelocity: numericToFloat64(a.DownloadVelocity),
+			ThumbsVelocity:   numericToFloat64(a.ThumbsVelocity),
+		}
+		if rc, ok := rankChangeMap[a.Addon.ID]; ok {
+			applyRankChanges(&response[i], rc)
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+// handleTrendingNew returns recently created addons with the strongest early
+// traction.
+func (s *Server) handleTrendingNew(c *gin.Context) {
+	window := c.DefaultQuery("window", "month")
+	if window != "week" && window != "month" {
+		respondBadRequest(c, "window must be week or month")
+		return
+	}
+	page, perPage, offset := parsePaginationParams(c)
+	ctx := c.Request.Context()
+
+	params, err := trending.CurrentParams(ctx, s.db)
+	if err != nil {
+		slog.Error("failed to get trending params", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	// The month window is the whole stored list, which covers the parameters' fresh age
+	days := params.FreshMaxAgeDays
+	if window == "week" {
+		if days < 7 {
+			respondBadRequest(c, "window is longer than the new list covers")
+			return
+		}
+		days = 7
+	}
+	createdAfter := pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, -int(days)), Valid: true}
+
+	total, err := s.db.CountFreshAddons(ctx, database.CountFreshAddonsParams{
+		MinDownloads: params.MinFreshDownloads,
+		CreatedAfter: createdAfter,
+	})
+	if err != nil {
+		slog.Error("failed to count fresh addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListFreshAddonsPaginated(ctx, database.ListFreshAddonsPaginatedParams{
+		MinDownloads: params.MinFreshDownloads,
+		CreatedAfter: createdAfter,
+		PageSize:     int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset:   int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to get fresh addons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	// Rank history is recorded for the whole list, so only it has rank changes
+	var rankChangeMap map[int32]database.GetRankChangesRow
+	if days == params.FreshMaxAgeDays {
+		rankChanges, err := s.db.GetRankChanges(ctx)
+		if err != nil {
+			slog.Error("failed to get rank changes", "error", err)
+			respondInternalError(c)
+			return
+		}
+		rankChangeMap = buildRankChangeMap(rankChanges, "fresh")
+	}
+
+	response := make([]TrendingAddonResponse, len(addons))
+	for i, a := range addons {
+		response[i] = TrendingAddonResponse{
+			AddonResponse:    addonToResponse(a.Addon),
+			Rank:             offset + i + 1,
+			Score:            numericToFloat64(a.FreshScore),
+			DownloadVelocity: numericToFloat64(a.DownloadVelocity),
+		}
+		if a.Addon.CreatedAt.Valid {
+			response[i].CreatedAt = a.Addon.CreatedAt.Time.Format("2006-01-02T15:04:05Z")
+		}
+		if rc, ok := rankChangeMap[a.Addon.ID]; ok {
 			applyRankChanges(&response[i], rc)
 		}
 	}
 
 	respondWithPagination(c, response, page, perPage, int(total))
 }
+
+type ComebackResponse struct {
+	AddonResponse
+	ReleasedAt         string  `json:"released_at"`
+	PreviousReleasedAt string  `json:"previous_released_at"`
+	DormantDays        int     `json:"dormant_days"`
+	DormantVelocity    float64 `json:"dormant_velocity"` // Downloads per hour before the release
+	RevivedVelocity    float64 `json:"revived_velocity"` // Downloads per hour since the release
+	DetectedAt         string  `json:"detected_at"`
+}
+
+// handleTrendingComebacks lists addons revived by a release after a long
+// dormancy, most recently detected first.
+func (s *Server) handleTrendingComebacks(c *gin.Context) {
+	page, perPage, offset := parsePaginationParams(c)
+	ctx := c.Request.Context()
+	since := pgtype.Timestamptz{Time: time.Now().Add(-trending.ComebackListedWindow), Valid: true}
+
+	total, err := s.db.CountComebacks(ctx, since)
+	if err != nil {
+		slog.Error("failed to count comebacks", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListComebacks(ctx, database.ListComebacksParams{
+		Since:      since,
+		PageSize:   int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to list comebacks", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := make([]ComebackResponse, len(addons))
+	for i, a := range addons {
+		response[i] = ComebackResponse{
+			AddonResponse:      addonToResponse(a.Addon),
+			ReleasedAt:         a.ReleaseDate.Time.Format("2006-01-02T15:04:05Z"),
+			PreviousReleasedAt: a.PreviousReleaseDate.Time.Format("2006-01-02T15:04:05Z"),
+			DormantDays:        int(a.ReleaseDate.Time.Sub(a.PreviousReleaseDate.Time).Hours() / 24),
+			DormantVelocity:    numericToFloat64(a.DormantVelocity),
+			RevivedVelocity:    numericToFloat64(a.RevivedVelocity),
+			DetectedAt:         a.DetectedAt.Time.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+// ProjectionResponse is projected total downloads with a 95% prediction interval.
+type ProjectionResponse struct {
+	Expected int64 `json:"expected"`
+	Low      int64 `json:"low"`
+	High     int64 `json:"high"`
+}
+
+// ForecastResponse is an addon's downloads projected from its hourly snapshots.
+type ForecastResponse struct {
+	AddonID      int32              `json:"addon_id"`
+	Slug         string             `json:"slug"`
+	ForecastFrom string             `json:"forecast_from"` // Hour of the last snapshot the forecast used
+	Downloads    int64              `json:"downloads"`     // Downloads at that snapshot
+	HistoryHours int32              `json:"history_hours"`
+	Next24h      ProjectionResponse `json:"next_24h"`
+	Next7d       ProjectionResponse `json:"next_7d"`
+	CalculatedAt string             `json:"calculated_at"`
+}
+
+// handleGetAddonForecast returns an addon's projected downloads for the next
+// 24 hours and 7 days.
+func (s *Server) handleGetAddonForecast(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	addon, err := s.db.GetAddonBySlug(ctx, c.Param("slug"))
+	if err != nil {
+		respondNotFound(c, "Addon not found")
+		return
+	}
+
+	f, err := s.db.GetAddonForecast(ctx, addon.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondNotFound(c, "No forecast for this addon yet")
+		return
+	}
+	if err != nil {
+		slog.Error("failed to get addon forecast", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	respondWithData(c, ForecastResponse{
+		AddonID:      addon.ID,
+		Slug:         addon.Slug,
+		ForecastFrom: f.ForecastFrom.Time.Format("2006-01-02T15:04:05Z"),
+		Downloads:    f.DownloadCount,
+		HistoryHours: f.HistoryHours,
+		Next24h:      ProjectionResponse{Expected: f.Projected24h, Low: f.Low24h, High: f.High24h},
+		Next7d:       ProjectionResponse{Expected: f.Projected7d, Low: f.Low7d, High: f.High7d},
+		CalculatedAt: f.CalculatedAt.Time.Format("2006-01-02T15:04:05Z"),
+	})
+}
+
+// CrossingResponse is an addon projected to cross a download count within 7 days.
+type CrossingResponse struct {
+	AddonResponse
+	Projected24h int64              `json:"projected_24h"`
+	Next7d       ProjectionResponse `json:"next_7d"`
+}
+
+// handleForecastCrossing lists addons projected to cross a download count
+// (downloads, 1M by default) within the next 7 days.
+func (s *Server) handleForecastCrossing(c *gin.Context) {
+	downloads, err := strconv.ParseInt(c.DefaultQuery("downloads", "1000000"), 10, 64)
+	if err != nil || downloads <= 0 {
+		respondBadRequest(c, "downloads must be a positive number")
+		return
+	}
+	page, perPage, offset := parsePaginationParams(c)
+	ctx := c.Request.Context()
+
+	total, err := s.db.CountAddonsProjectedToCross(ctx, downloads)
+	if err != nil {
+		slog.Error("failed to count addons projected to cross", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListAddonsProjectedToCross(ctx, database.ListAddonsProjectedToCrossParams{
+		Downloads:  downloads,
+		PageSize:   int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to list addons projected to cross", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := make([]CrossingResponse, len(addons))
+	for i, a := range addons {
+		response[i] = CrossingResponse{
+			AddonResponse: addonToResponse(a.Addon),
+			Projected24h:  a.Projected24h,
+			Next7d:        ProjectionResponse{Expected: a.Projected7d, Low: a.Low7d, High: a.High7d},
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+// MilestoneResponse is a download or thumbs-up count an addon crossed.
+type MilestoneResponse struct {
+	Metric    string `json:"metric"` // downloads or thumbs_up
+	Threshold int64  `json:"threshold"`
+	CrossedAt string `json:"crossed_at"` // Interpolated between snapshots
+}
+
+// AddonMilestoneResponse is a milestone in the feed, with the addon that crossed it.
+type AddonMilestoneResponse struct {
+	AddonResponse
+	MilestoneResponse
+}
+
+// handleMilestones lists milestones crossed by active addons, most recent
+// first, optionally for one metric.
+func (s *Server) handleMilestones(c *gin.Context) {
+	metric := c.Query("metric")
+	if metric != "" && metric != sync.MetricDownloads && metric != sync.MetricThumbsUp {
+		respondBadRequest(c, "metric must be downloads or thumbs_up")
+		return
+	}
+	page, perPage, offset := parsePaginationParams(c)
+	ctx := c.Request.Context()
+
+	total, err := s.db.CountMilestones(ctx, metric)
+	if err != nil {
+		slog.Error("failed to count milestones", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	milestones, err := s.db.ListMilestones(ctx, database.ListMilestonesParams{
+		Metric:     metric,
+		PageSize:   int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to list milestones", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := make([]AddonMilestoneResponse, len(milestones))
+	for i, m := range milestones {
+		response[i] = AddonMilestoneResponse{
+			AddonResponse: addonToResponse(m.Addon),
+			MilestoneResponse: MilestoneResponse{
+				Metric:    m.Metric,
+				Threshold: m.Threshold,
+				CrossedAt: m.CrossedAt.Time.Format("2006-01-02T15:04:05Z"),
+			},
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+// handleGetAddonMilestones returns every milestone an addon crossed, most
+// recent first.
+func (s *Server) handleGetAddonMilestones(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	addon, err := s.db.GetAddonBySlug(ctx, c.Param("slug"))
+	if err != nil {
+		respondNotFound(c, "Addon not found")
+		return
+	}
+
+	milestones, err := s.db.ListAddonMilestones(ctx, addon.ID)
+	if err != nil {
+		slog.Error("failed to list addon milestones", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := make([]MilestoneResponse, len(milestones))
+	for i, m := range milestones {
+		response[i] = MilestoneResponse{
+			Metric:    m.Metric,
+			Threshold: m.Threshold,
+			CrossedAt: m.CrossedAt.Time.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	respondWithData(c, response)
+}
+
+// StintResponse is an unbroken run of an addon on a trending list.
+type StintResponse struct {
+	List       string  `json:"list"`
+	EnteredAt  string  `json:"entered_at"`
+	ExitedAt   string  `json:"exited_at,omitempty"` // Unset while still listed
+	Hours      float64 `json:"hours"`               // While still listed, up to the last calculation
+	BestRank   int16   `json:"best_rank"`
+	HoursAtTop float64 `json:"hours_at_top"`
+}
+
+// stintHours is how long a stint lasted, or has lasted so far.
+func stintHours(entered, exited, lastSeen pgtype.Timestamptz) float64 {
+	end := lastSeen.Time
+	if exited.Valid {
+		end = exited.Time
+	}
+	return end.Sub(entered.Time).Hours()
+}
+
+// handleGetAddonStints returns every stint of an addon on the overall
+// trending lists, most recent first.
+func (s *Server) handleGetAddonStints(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	addon, err := s.db.GetAddonBySlug(ctx, c.Param("slug"))
+	if err != nil {
+		respondNotFound(c, "Addon not found")
+		return
+	}
+
+	stints, err := s.db.ListAddonStints(ctx, addon.ID)
+	if err != nil {
+		slog.Error("failed to list addon stints", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := make([]StintResponse, len(stints))
+	for i, st := range stints {
+		response[i] = StintResponse{
+			List:       st.Category,
+			EnteredAt:  st.EnteredAt.Time.Format("2006-01-02T15:04:05Z"),
+			Hours:      stintHours(st.EnteredAt, st.ExitedAt, st.LastSeenAt),
+			BestRank:   st.BestRank,
+			HoursAtTop: numericToFloat64(st.HoursAtTop),
+		}
+		if st.ExitedAt.Valid {
+			response[i].ExitedAt = st.ExitedAt.Time.Format("2006-01-02T15:04:05Z")
+		}
+	}
+
+	respondWithData(c, response)
+}
+
+// HallOfFameResponse is an addon with its longest stint on a list.
+type HallOfFameResponse struct {
+	AddonResponse
+	Stint StintResponse `json:"stint"`
+}
+
+// handleHallOfFame lists addons by their longest stint on a trending list
+// (list, hot by default), longest first.
+func (s *Server) handleHallOfFame(c *gin.Context) {
+	list := c.DefaultQuery("list", "hot")
+	switch list {
+	case "hot", "rising", "loved", "fresh":
+	default:
+		respondBadRequest(c, "list must be hot, rising, loved or fresh")
+		return
+	}
+	page, perPage, offset := parsePaginationParams(c)
+	ctx := c.Request.Context()
+
+	total, err := s.db.CountLongestStints(ctx, list)
+	if err != nil {
+		slog.Error("failed to count longest stints", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListLongestStints(ctx, database.ListLongestStintsParams{
+		Category:   list,
+		PageSize:   int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset: int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to list longest stints", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := make([]HallOfFameResponse, len(addons))
+	for i, a := range addons {
+		response[i] = HallOfFameResponse{
+			AddonResponse: addonToResponse(a.Addon),
+			Stint: StintResponse{
+				List:       list,
+				EnteredAt:  a.EnteredAt.Time.Format("2006-01-02T15:04:05Z"),
+				Hours:      a.Hours,
+				BestRank:   a.BestRank,
+				HoursAtTop: numericToFloat64(a.HoursAtTop),
+			},
+		}
+		if a.ExitedAt.Valid {
+			response[i].Stint.ExitedAt = a.ExitedAt.Time.Format("2006-01-02T15:04:05Z")
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+// WeeklyLeaderboardResponse is the archived hot and rising lists of an ISO week.
+type WeeklyLeaderboardResponse struct {
+	Week     string                  `json:"week"`      // ISO week, e.g. 2026-W35
+	StartsOn string                  `json:"starts_on"` // Monday of the week
+	Hot      []TrendingAddonResponse `json:"hot"`
+	Rising   []TrendingAddonResponse `json:"rising"`
+}
+
+// archivedList returns a list from the leaderboard archive. Archived entries
+// keep rank and score only, so velocity and rank changes are left unset.
+func (s *Server) archivedList(ctx context.Context, period string, start time.Time, list string) ([]TrendingAddonResponse, error) {
+	entries, err := s.db.ListArchivedLeaderboard(ctx, database.ListArchivedLeaderboardParams{
+		Period:      period,
+		PeriodStart: pgtype.Date{Time: start, Valid: true},
+		List:        list,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]TrendingAddonResponse, len(entries))
+	for i, a := range entries {
+		response[i] = TrendingAddonResponse{
+			AddonResponse: addonToResponse(a.Addon),
+			Rank:          int(a.Rank),
+			Score:         numericToFloat64(a.Score),
+		}
+	}
+	return response, nil
+}
+
+// handleArchivedTrending serves the list archived for the UTC day given by
+// the at query parameter.
+func (s *Server) handleArchivedTrending(c *gin.Context, list string) {
+	at, err := time.Parse(time.DateOnly, c.Query("at"))
+	if err != nil {
+		respondBadRequest(c, "at must be a date such as 2026-09-01")
+		return
+	}
+	page, perPage, offset := parsePaginationParams(c)
+
+	response, err := s.archivedList(c.Request.Context(), trending.PeriodDaily, at, list)
+	if err != nil {
+		slog.Error("failed to get archived leaderboard", "list", list, "at", at, "error", err)
+		respondInternalError(c)
+		return
+	}
+	if len(response) == 0 {
+		respondNotFound(c, "No "+list+" list archived for "+at.Format(time.DateOnly))
+		return
+	}
+
+	// Archived lists are short, so they are paginated in memory
+	start := min(offset, len(response))
+	end := min(offset+perPage, len(response))
+	respondWithPagination(c, response[start:end], page, perPage, len(response))
+}
+
+// handleWeeklyLeaderboard serves the hot and rising lists archived for an
+// ISO week.
+func (s *Server) handleWeeklyLeaderboard(c *gin.Context) {
+	ctx := c.Request.Context()
+	monday, err := trending.ParseISOWeek(c.Param("isoweek"))
+	if err != nil {
+		respondBadRequest(c, "week must be an ISO week such as 2026-W35")
+		return
+	}
+
+	hot, err := s.archivedList(ctx, trending.PeriodWeekly, monday, "hot")
+	if err != nil {
+		slog.Error("failed to get archived leaderboard", "list", "hot", "week", monday, "error", err)
+		respondInternalError(c)
+		return
+	}
+	rising, err := s.archivedList(ctx, trending.PeriodWeekly, monday, "rising")
+	if err != nil {
+		slog.Error("failed to get archived leaderboard", "list", "rising", "week", monday, "error", err)
+		respondInternalError(c)
+		return
+	}
+	week := trending.FormatISOWeek(monday)
+	if len(hot) == 0 && len(rising) == 0 {
+		respondNotFound(c, "No leaderboard archived for week "+week)
+		return
+	}
+
+	respondWithData(c, WeeklyLeaderboardResponse{
+		Week:     week,
+		StartsOn: monday.Format(time.DateOnly),
+		Hot:      hot,
+		Rising:   rising,
+	})
+}
+
+// buildCategoryRankChangeMap is buildRankChangeMap for one category's lists.
+func buildCategoryRankChangeMap(rankChanges []database.GetCategoryRankChangesRow, list string) map[int32]database.GetRankChangesRow {
+	m := make(map[int32]database.GetRankChangesRow)
+	for _, rc := range rankChanges {
+		if rc.List == list {
+			m[rc.AddonID] = database.GetRankChangesRow{
+				AddonID:     rc.AddonID,
+				Category:    rc.List,
+				CurrentRank: rc.CurrentRank,
+				Score:       rc.Score,
+				Rank24hAgo:  rc.Rank24hAgo,
+				Rank7dAgo:   rc.Rank7dAgo,
+			}
+		}
+	}
+	return m
+}
+
+// handleCategoryTrendingHot returns the hot list ranked within a single category.
+func (s *Server) handleCategoryTrendingHot(c *gin.Context) {
+	page, perPage, offset := parsePaginationParams(c)
+	slug := c.Param("slug")
+	ctx := c.Request.Context()
+
+	category, err := s.db.GetCategoryBySlug(ctx, slug)
+	if err != nil {
+		respondNotFound(c, "Category not found")
+		return
+	}
+
+	params, err := trending.CurrentParams(ctx, s.db)
+	if err != nil {
+		slog.Error("failed to get trending params", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	total, err := s.db.CountCategoryHotAddons(ctx, database.CountCategoryHotAddonsParams{
+		CategoryID:   category.ID,
+		MinDownloads: params.MinHotDownloads,
+	})
+	if err != nil {
+		slog.Error("failed to count category hot addons", "error", err, "category", slug)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListCategoryHotAddonsPaginated(ctx, database.ListCategoryHotAddonsPaginatedParams{
+		CategoryID:   category.ID,
+		MinDownloads: params.MinHotDownloads,
+		PageSize:     int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset:   int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to get category hot addons", "error", err, "category", slug)
+		respondInternalError(c)
+		return
+	}
+
+	rankChanges, err := s.db.GetCategoryRankChanges(ctx, category.ID)
+	if err != nil {
+		slog.Error("failed to get category rank changes", "error", err, "category", slug)
+		respondInternalError(c)
+		return
+	}
+	rankChangeMap := buildCategoryRankChangeMap(rankChanges, "hot")
+
+	response := make([]TrendingAddonResponse, len(addons))
+	for i, a := range addons {
+		response[i] = TrendingAddonResponse{
+			AddonResponse:    addonToResponse(a.Addon),
+			Rank:             offset + i + 1,
+			Score:            numericToFloat64(a.HotScore),
+			DownloadVelocity: numericToFloat64(a.DownloadVelocity),
+		}
+		if rc, ok := rankChangeMap[a.Addon.ID]; ok {
+			applyRankChanges(&response[i], rc)
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+// handleCategoryTrendingRising returns the rising list ranked within a single
+// category, excluding the top of that category's hot list.
+func (s *Server) handleCategoryTrendingRising(c *gin.Context) {
+	page, perPage, offset := parsePaginationParams(c)
+	slug := c.Param("slug")
+	ctx := c.Request.Context()
+
+	category, err := s.db.GetCategoryBySlug(ctx, slug)
+	if err != nil {
+		respondNotFound(c, "Category not found")
+		return
+	}
+
+	params, err := trending.CurrentParams(ctx, s.db)
+	if err != nil {
+		slog.Error("failed to get trending params", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	total, err := s.db.CountCategoryRisingAddons(ctx, database.CountCategoryRisingAddonsParams{
+		CategoryID:   category.ID,
+		MinDownloads: params.MinRisingDownloads,
+		MaxDownloads: params.MaxRisingDownloads,
+		HotListSize:  params.ListSize,
+	})
+	if err != nil {
+		slog.Error("failed to count category rising addons", "error", err, "category", slug)
+		respondInternalError(c)
+		return
+	}
+
+	addons, err := s.db.ListCategoryRisingAddonsPaginated(ctx, database.ListCategoryRisingAddonsPaginatedParams{
+		CategoryID:   category.ID,
+		MinDownloads: params.MinRisingDownloads,
+		MaxDownloads: params.MaxRisingDownloads,
+		HotListSize:  params.ListSize,
+		PageSize:     int32(perPage), //nolint:gosec // perPage validated to be <= 100
+		PageOffset:   int32(offset),  //nolint:gosec // offset validated via perPage <= 100
+	})
+	if err != nil {
+		slog.Error("failed to get category rising addons", "error", err, "category", slug)
+		respondInternalError(c)
+		return
+	}
+
+	rankChanges, err := s.db.GetCategoryRankChanges(ctx, category.ID)
+	if err != nil {
+		slog.Error("failed to get category rank changes", "error", err, "category", slug)
+		respondInternalError(c)
+		return
+	}
+	rankChangeMap := buildCategoryRankChangeMap(rankChanges, "rising")
+
+	response := make([]TrendingAddonResponse, len(addons))
+	for i, a := range addons {
+		response[i] = TrendingAddonResponse{
+			AddonResponse:    addonToResponse(a.Addon),
+			Rank:             offset + i + 1,
+			Score:            numericToFloat64(a.RisingScore),
+			DownloadVelocity: numericToFloat64(a.DownloadVelocity),
+		}
+		if rc, ok := rankChangeMap[a.Addon.ID]; ok {
+			applyRankChanges(&response[i], rc)
+		}
+	}
+
+	respondWithPagination(c, response, page, perPage, int(total))
+}
+
+// ShadowDiffResponse is an addon on the live or shadow list with its rank on each.
+type ShadowDiffResponse struct {
+	AddonResponse
+	LiveRank   *int16 `json:"live_rank"`   // Null when not on the live list
+	ShadowRank *int16 `json:"shadow_rank"` // Null when not on the shadow list
+}
+
+// ShadowComparisonResponse is how the live and shadow lists compared in one calculation.
+type ShadowComparisonResponse struct {
+	CalculatedAt    string   `json:"calculated_at"`
+	LiveVersion     string   `json:"live_version"`
+	ShadowVersion   string   `json:"shadow_version"`
+	Overlap         float64  `json:"overlap"`
+	RankCorrelation float64  `json:"rank_correlation"`
+	LiveChurn       *float64 `json:"live_churn"`   // Null without a previous list
+	ShadowChurn     *float64 `json:"shadow_churn"` // Null without a previous list
+}
+
+// AdminShadowResponse compares a live list with the shadow parameter set's.
+type AdminShadowResponse struct {
+	List        string                     `json:"list"`
+	Diff        []ShadowDiffResponse       `json:"diff"`
+	Comparisons []ShadowComparisonResponse `json:"comparisons"` // Oldest first
+}
+
+func (s *Server) handleAdminShadow(c *gin.Context) {
+	list := c.DefaultQuery("list", "hot")
+	switch list {
+	case "hot", "rising", "loved":
+	default:
+		respondBadRequest(c, "list must be hot, rising or loved")
+		return
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "14"))
+	if err != nil || days < 1 || days > 90 {
+		days = 14
+	}
+	ctx := c.Request.Context()
+
+	diff, err := s.db.ListShadowDiff(ctx, list)
+	if err != nil {
+		slog.Error("failed to list shadow diff", "error", err)
+		respondInternalError(c)
+		return
+	}
+	comparisons, err := s.db.ListShadowComparisons(ctx, database.ListShadowComparisonsParams{
+		List:  list,
+		Since: pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, -days), Valid: true},
+	})
+	if err != nil {
+		slog.Error("failed to list shadow comparisons", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	response := AdminShadowResponse{
+		List:        list,
+		Diff:        make([]ShadowDiffResponse, len(diff)),
+		Comparisons: make([]ShadowComparisonResponse, len(comparisons)),
+	}
+	for i, a := range diff {
+		response.Diff[i] = ShadowDiffResponse{
+			AddonResponse: addonToResponse(a.Addon),
+		}
+		if a.LiveRank.Valid {
+			response.Diff[i].LiveRank = &a.LiveRank.Int16
+		}
+		if a.ShadowRank.Valid {
+			response.Diff[i].ShadowRank = &a.ShadowRank.Int16
+		}
+	}
+	for i, cmp := range comparisons {
+		response.Comparisons[i] = ShadowComparisonResponse{
+			CalculatedAt:    cmp.CalculatedAt.Time.Format("2006-01-02T15:04:05Z"),
+			LiveVersion:     cmp.LiveVersion,
+			ShadowVersion:   cmp.ShadowVersion,
+			Overlap:         numericToFloat64(cmp.Overlap),
+			RankCorrelation: numericToFloat64(cmp.RankCorrelation),
+		}
+		if cmp.LiveChurn.Valid {
+			churn := numericToFloat64(cmp.LiveChurn)
+			response.Comparisons[i].LiveChurn = &churn
+		}
+		if cmp.ShadowChurn.Valid {
+			churn := numericToFloat64(cmp.ShadowChurn)
+			response.Comparisons[i].ShadowChurn = &churn
+		}
+	}
+
+	respondWithData(c, response)
+}
@@ -136,6 +136,42 @@ func TestListAddons(t *testing.T) {
 		assert.Equal(t, 200, w.Code)
 		// Search should return results containing "Addon A"
 	})
+
+	t.Run("sort by heat", func(t *testing.T) {
+		// Every active addon has a heat index once trending has run
+		_, err := tdb.Pool.Exec(ctx, `
+			UPDATE addons SET heat_index = 0, heat_sparkline = '{0}';
+			UPDATE addons SET heat_index = 40, heat_sparkline = '{10,25,40}' WHERE id = 3;
+			UPDATE addons SET heat_index = 90, heat_sparkline = '{90}' WHERE id = 7;
+			UPDATE addons SET heat_index = 15, heat_sparkline = '{30,15}' WHERE id = 5
+		`)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/addons?sort=heat&per_page=3", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code)
+
+		var resp struct {
+			Data []AddonResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 3)
+		assert.Equal(t, int32(7), resp.Data[0].ID)
+		assert.Equal(t, int32(3), resp.Data[1].ID)
+		assert.Equal(t, []int16{10, 25, 40}, resp.Data[1].HeatSparkline)
+		assert.Equal(t, int32(5), resp.Data[2].ID)
+		if assert.NotNil(t, resp.Data[2].HeatIndex) {
+			assert.Equal(t, int16(15), *resp.Data[2].HeatIndex)
+		}
+
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/api/v1/addons?sort=hottest", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code)
+	})
 }
 
 func TestListAddonsByCategory(t *testing.T) {
@@ -272,6 +308,109 @@ func TestListCategories(t *testing.T) {
 	assert.Len(t, data, 1)
 }
 
+func TestCategoryTreeAndDetail(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	// Class 1 > 10 > 100, with 11 as a second child of the class
+	for _, cat := range []database.UpsertCategoryParams{
+		{ID: 1, Name: "Addons", Slug: "addons", IsClass: true},
+		{ID: 10, Name: "Combat", Slug: "combat", ParentID: pgtype.Int4{Int32: 1, Valid: true}, ClassID: pgtype.Int4{Int32: 1, Valid: true}, DisplayIndex: 2},
+		{ID: 11, Name: "Bags", Slug: "bags", ParentID: pgtype.Int4{Int32: 1, Valid: true}, ClassID: pgtype.Int4{Int32: 1, Valid: true}, DisplayIndex: 1},
+		{ID: 100, Name: "Damage Meters", Slug: "damage-meters", ParentID: pgtype.Int4{Int32: 10, Valid: true}, ClassID: pgtype.Int4{Int32: 1, Valid: true}},
+	} {
+		require.NoError(t, tdb.Queries.UpsertCategory(ctx, cat))
+	}
+
+	for i, categories := range [][]int32{{10}, {100}, {11}} {
+		_, err := tdb.Pool.Exec(ctx, `
+			INSERT INTO addons (id, slug, name, status, categories, download_count)
+			VALUES ($1, $2, $3, 'active', $4, $5)
+		`, i+1, fmt.Sprintf("addon-%d", i+1), fmt.Sprintf("Addon %d", i+1), categories, (i+1)*100)
+		require.NoError(t, err)
+	}
+
+	server := NewServer(tdb.Queries)
+
+	t.Run("tree nests categories in display order", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/categories/tree", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code)
+
+		var resp struct {
+			Data []CategoryTreeNode `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		require.Len(t, resp.Data, 1)
+		assert.True(t, resp.Data[0].IsClass)
+		require.Len(t, resp.Data[0].Children, 2)
+		assert.Equal(t, "bags", resp.Data[0].Children[0].Slug)
+		assert.Equal(t, "combat", resp.Data[0].Children[1].Slug)
+		require.Len(t, resp.Data[0].Children[1].Children, 1)
+		assert.Equal(t, "damage-meters", resp.Data[0].Children[1].Children[0].Slug)
+	})
+
+	t.Run("detail includes subcategory addons", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/categories/combat", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code)
+
+		var resp struct {
+			Data CategoryDetailResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		assert.Equal(t, int32(10), resp.Data.ID)
+		require.Len(t, resp.Data.Subcategories, 1)
+		assert.Equal(t, "damage-meters", resp.Data.Subcategories[0].Slug)
+		assert.Equal(t, int64(2), resp.Data.AddonCount)
+		assert.Equal(t, int64(300), resp.Data.TotalDownloads)
+		require.Len(t, resp.Data.TopAddons, 2)
+		assert.Equal(t, "addon-2", resp.Data.TopAddons[0].Slug)
+	})
+
+	t.Run("unknown category returns 404", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/categories/nope", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, 404, w.Code)
+	})
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	parent := func(id int32) pgtype.Int4 { return pgtype.Int4{Int32: id, Valid: true} }
+	categories := []database.Category{
+		{ID: 3, Name: "Child B", Slug: "child-b", ParentID: parent(1)},
+		{ID: 2, Name: "Child A", Slug: "child-a", ParentID: parent(1)},
+		{ID: 1, Name: "Root", Slug: "root"},
+		{ID: 4, Name: "Orphan", Slug: "orphan", ParentID: parent(99)},
+		{ID: 5, Name: "Grandchild", Slug: "grandchild", ParentID: parent(2)},
+	}
+
+	tree := buildCategoryTree(categories)
+
+	// Orphans whose parent is missing are shown as roots
+	require.Len(t, tree, 2)
+	assert.Equal(t, "orphan", tree[0].Slug)
+	assert.Equal(t, "root", tree[1].Slug)
+	require.Len(t, tree[1].Children, 2)
+	assert.Equal(t, "child-a", tree[1].Children[0].Slug)
+	assert.Equal(t, "grandchild", tree[1].Children[0].Children[0].Slug)
+	assert.Empty(t, tree[1].Children[1].Children)
+
+	assert.ElementsMatch(t, []int32{1, 2, 3, 5}, descendantCategoryIDs(categories, 1))
+	assert.Equal(t, []int32{4}, descendantCategoryIDs(categories, 4))
+}
+
 func TestTrendingHot(t *testing.T) {
 	tdb := testutil.SetupTestDB(t)
 	ctx := context.Background()
@@ -354,6 +493,549 @@ func TestTrendingRising(t *testing.T) {
 	}
 }
 
+func TestTrendingLoved(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	// Loved needs download_count >= 1000; the small addon is below the gate
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count) VALUES
+			(1, 'loved-addon', 'Loved Addon', 'active', 2000),
+			(2, 'big-addon', 'Big Addon', 'active', 90000),
+			(3, 'small-addon', 'Small Addon', 'active', 200)
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO trending_scores (addon_id, hot_score, rising_score, loved_score, thumbs_velocity) VALUES
+			(1, 0, 0, 8.5, 0.4),
+			(2, 50, 0, 1.2, 0.6),
+			(3, 0, 0, 20, 0.2)
+	`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/trending/loved", nil)
+	require.NoError(t, err)
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var resp struct {
+		Data []TrendingAddonResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Data, 2) {
+		assert.Equal(t, "loved-addon", resp.Data[0].Slug)
+		assert.InDelta(t, 8.5, resp.Data[0].Score, 0.01)
+		assert.InDelta(t, 0.4, resp.Data[0].ThumbsVelocity, 0.01)
+		assert.Equal(t, "big-addon", resp.Data[1].Slug)
+		assert.Equal(t, 2, resp.Data[1].Rank)
+	}
+}
+
+func TestTrendingNew(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count, created_at) VALUES
+			(1, 'new-addon', 'New Addon', 'active', 800, NOW() - INTERVAL '2 days'),
+			(2, 'newer-addon', 'Newer Addon', 'active', 300, NOW() - INTERVAL '1 day'),
+			(3, 'old-addon', 'Old Addon', 'active', 90000, NOW() - INTERVAL '2 years'),
+			(4, 'last-month', 'Last Month', 'active', 5000, NOW() - INTERVAL '20 days')
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO trending_scores (addon_id, hot_score, fresh_score) VALUES
+			(1, 0, 0.9),
+			(2, 0, 0.4),
+			(3, 40, 0),
+			(4, 0, 0.6)
+	`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+	get := func(path string) (int, []TrendingAddonResponse) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		var resp struct {
+			Data []TrendingAddonResponse `json:"data"`
+		}
+		if w.Code == 200 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.Data
+	}
+
+	t.Run("defaults to this month", func(t *testing.T) {
+		code, data := get("/api/v1/trending/new")
+		assert.Equal(t, 200, code)
+		if assert.Len(t, data, 3) {
+			assert.Equal(t, "new-addon", data[0].Slug)
+			assert.InDelta(t, 0.9, data[0].Score, 0.01)
+			assert.NotEmpty(t, data[0].CreatedAt)
+			assert.Equal(t, "last-month", data[1].Slug)
+			assert.Equal(t, "newer-addon", data[2].Slug)
+			assert.Equal(t, 3, data[2].Rank)
+		}
+
+		_, month := get("/api/v1/trending/new?window=month")
+		assert.Equal(t, data, month)
+	})
+
+	t.Run("this week", func(t *testing.T) {
+		code, data := get("/api/v1/trending/new?window=week")
+		assert.Equal(t, 200, code)
+		if assert.Len(t, data, 2) {
+			assert.Equal(t, "new-addon", data[0].Slug)
+			assert.Equal(t, "newer-addon", data[1].Slug)
+			assert.Equal(t, 2, data[1].Rank)
+		}
+	})
+
+	t.Run("rejects other windows", func(t *testing.T) {
+		code, _ := get("/api/v1/trending/new?window=year")
+		assert.Equal(t, 400, code)
+	})
+
+	t.Run("month is the whole list", func(t *testing.T) {
+		_, err := tdb.Pool.Exec(ctx, `
+			INSERT INTO trending_param_sets (version, params) VALUES ('short', '{"version": "short", "fresh_max_age_days": 5}');
+			INSERT INTO trending_calculation_runs (id, params_version, params_source, started_at, processed_count)
+				VALUES (1, 'short', 'file', NOW(), 4);
+			INSERT INTO trending_generations (slot, run_id, published_at) VALUES ('live', 1, NOW())
+		`)
+		require.NoError(t, err)
+
+		code, data := get("/api/v1/trending/new")
+		assert.Equal(t, 200, code)
+		assert.Len(t, data, 2, "only addons within the list's 5 days")
+
+		code, _ = get("/api/v1/trending/new?window=week")
+		assert.Equal(t, 400, code, "a week is longer than the list covers")
+	})
+}
+
+func TestTrendingComebacks(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count, comeback_at) VALUES
+			(1, 'revived-addon', 'Revived Addon', 'active', 5000, NOW() - INTERVAL '2 days'),
+			(2, 'old-comeback', 'Old Comeback', 'active', 5000, NOW() - INTERVAL '60 days')
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO comeback_events (addon_id, release_date, previous_release_date, dormant_velocity, revived_velocity, detected_at) VALUES
+			(1, NOW() - INTERVAL '4 days', NOW() - INTERVAL '300 days', 0.1, 6.5, NOW() - INTERVAL '2 days'),
+			(2, NOW() - INTERVAL '62 days', NOW() - INTERVAL '400 days', 0, 3, NOW() - INTERVAL '60 days')
+	`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/trending/comebacks", nil)
+	require.NoError(t, err)
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var resp struct {
+		Data []ComebackResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Data, 1, "comebacks detected over a month ago aren't listed") {
+		assert.Equal(t, "revived-addon", resp.Data[0].Slug)
+		assert.True(t, resp.Data[0].IsComeback)
+		assert.Equal(t, 296, resp.Data[0].DormantDays)
+		assert.InDelta(t, 6.5, resp.Data[0].RevivedVelocity, 0.01)
+	}
+
+	// The badge wears off with the listing
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/addons/old-comeback", nil)
+	require.NoError(t, err)
+	server.ServeHTTP(w, req)
+	var addon struct {
+		Data AddonResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &addon))
+	assert.False(t, addon.Data.IsComeback)
+}
+
+func TestArchivedLeaderboards(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count) VALUES
+			(1, 'expansion-hit', 'Expansion Hit', 'active', 90000),
+			(2, 'runner-up', 'Runner Up', 'inactive', 40000)
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO trending_param_sets (version, params) VALUES ('v3-default', '{}');
+		INSERT INTO trending_calculation_runs (id, params_version, params_source, started_at, processed_count)
+			VALUES (1, 'v3-default', 'default', '2026-09-06 23:00:00+00', 2);
+		INSERT INTO leaderboard_archive (period, period_start, list, rank, addon_id, score, recorded_at, run_id) VALUES
+			('daily', '2026-09-01', 'hot', 1, 1, 120.5, '2026-09-01 23:00:00+00', 1),
+			('daily', '2026-09-01', 'hot', 2, 2, 80, '2026-09-01 23:00:00+00', 1),
+			('weekly', '2026-08-31', 'hot', 1, 1, 110, '2026-09-06 23:00:00+00', 1),
+			('weekly', '2026-08-31', 'rising', 1, 2, 9.5, '2026-09-06 23:00:00+00', 1)
+	`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/trending/hot?at=2026-09-01")
+	assert.Equal(t, 200, w.Code)
+	var daily struct {
+		Data []TrendingAddonResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &daily))
+	if assert.Len(t, daily.Data, 2, "addons no longer active stay in the archive") {
+		assert.Equal(t, "expansion-hit", daily.Data[0].Slug)
+		assert.Equal(t, 1, daily.Data[0].Rank)
+		assert.InDelta(t, 120.5, daily.Data[0].Score, 0.01)
+	}
+
+	assert.Equal(t, 404, get("/api/v1/trending/rising?at=2026-09-01").Code)
+	assert.Equal(t, 400, get("/api/v1/trending/hot?at=yesterday").Code)
+
+	w = get("/api/v1/leaderboards/weekly/2026-W36")
+	assert.Equal(t, 200, w.Code)
+	var weekly struct {
+		Data WeeklyLeaderboardResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &weekly))
+	assert.Equal(t, "2026-W36", weekly.Data.Week)
+	assert.Equal(t, "2026-08-31", weekly.Data.StartsOn)
+	assert.Len(t, weekly.Data.Hot, 1)
+	if assert.Len(t, weekly.Data.Rising, 1) {
+		assert.Equal(t, "runner-up", weekly.Data.Rising[0].Slug)
+	}
+
+	assert.Equal(t, 404, get("/api/v1/leaderboards/weekly/2026-W35").Code)
+	assert.Equal(t, 400, get("/api/v1/leaderboards/weekly/2026-09-01").Code)
+}
+
+func TestForecasts(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count) VALUES
+			(1, 'almost-there', 'Almost There', 'active', 990000),
+			(2, 'slow-climber', 'Slow Climber', 'active', 900000),
+			(3, 'no-history', 'No History', 'active', 100)
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO addon_forecasts (addon_id, forecast_from, download_count, history_hours,
+			projected_24h, low_24h, high_24h, projected_7d, low_7d, high_7d) VALUES
+			(1, NOW(), 990000, 168, 992000, 991000, 993000, 1004000, 998000, 1010000),
+			(2, NOW(), 900000, 168, 901000, 900500, 901500, 907000, 904000, 910000)
+	`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/addons/almost-there/forecast")
+	assert.Equal(t, 200, w.Code)
+	var forecast struct {
+		Data ForecastResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &forecast))
+	assert.Equal(t, int64(990000), forecast.Data.Downloads)
+	assert.Equal(t, ProjectionResponse{Expected: 1004000, Low: 998000, High: 1010000}, forecast.Data.Next7d)
+
+	assert.Equal(t, 404, get("/api/v1/addons/no-history/forecast").Code)
+
+	w = get("/api/v1/forecasts/crossing")
+	assert.Equal(t, 200, w.Code)
+	var crossing struct {
+		Data []CrossingResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &crossing))
+	if assert.Len(t, crossing.Data, 1) {
+		assert.Equal(t, "almost-there", crossing.Data[0].Slug)
+	}
+
+	w = get("/api/v1/forecasts/crossing?downloads=905000")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &crossing))
+	assert.Len(t, crossing.Data, 1, "addons already past the count are left out")
+	assert.Equal(t, 400, get("/api/v1/forecasts/crossing?downloads=lots").Code)
+}
+
+func TestMilestones(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count, thumbs_up_count) VALUES
+			(1, 'popular', 'Popular', 'active', 1200000, 1500),
+			(2, 'gone', 'Gone', 'inactive', 20000, 10)
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO milestones (addon_id, metric, threshold, crossed_at) VALUES
+			(1, 'downloads', 1000000, NOW() - INTERVAL '2 days'),
+			(1, 'thumbs_up', 1000, NOW() - INTERVAL '1 day'),
+			(2, 'downloads', 10000, NOW())
+	`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/milestones")
+	assert.Equal(t, 200, w.Code)
+	var feed struct {
+		Data []AddonMilestoneResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &feed))
+	if assert.Len(t, feed.Data, 2, "inactive addons are left out") {
+		assert.Equal(t, "thumbs_up", feed.Data[0].Metric)
+		assert.Equal(t, "popular", feed.Data[0].Slug)
+		assert.Equal(t, int64(1000000), feed.Data[1].Threshold)
+	}
+
+	w = get("/api/v1/milestones?metric=downloads")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &feed))
+	assert.Len(t, feed.Data, 1)
+	assert.Equal(t, 400, get("/api/v1/milestones?metric=stars").Code)
+
+	w = get("/api/v1/addons/popular/milestones")
+	assert.Equal(t, 200, w.Code)
+	var addon struct {
+		Data []MilestoneResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &addon))
+	assert.Len(t, addon.Data, 2)
+	assert.Equal(t, 404, get("/api/v1/addons/missing/milestones").Code)
+}
+
+func TestStints(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count) VALUES
+			(1, 'long-runner', 'Long Runner', 'active', 50000),
+			(2, 'flash', 'Flash', 'active', 50000)
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO trending_stints (addon_id, category, entered_at, exited_at, last_seen_at, last_rank, best_rank, hours_at_top) VALUES
+			(1, 'hot', NOW() - INTERVAL '10 days', NOW() - INTERVAL '4 days', NOW() - INTERVAL '4 days 1 hour', 3, 1, 12),
+			(1, 'hot', NOW() - INTERVAL '2 days', NULL, NOW(), 2, 2, 0),
+			(2, 'hot', NOW() - INTERVAL '1 day', NULL, NOW(), 1, 1, 24),
+			(2, 'rising', NOW() - INTERVAL '20 days', NOW() - INTERVAL '1 day', NOW() - INTERVAL '1 day', 5, 5, 0)
+	`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/addons/long-runner/stints")
+	assert.Equal(t, 200, w.Code)
+	var stints struct {
+		Data []StintResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stints))
+	if assert.Len(t, stints.Data, 2) {
+		assert.Empty(t, stints.Data[0].ExitedAt, "most recent stint is still running")
+		assert.InDelta(t, 48, stints.Data[0].Hours, 0.1)
+		assert.InDelta(t, 144, stints.Data[1].Hours, 0.1)
+		assert.InDelta(t, 12, stints.Data[1].HoursAtTop, 0.01)
+	}
+
+	// Each addon appears once, with its longest stint on the list
+	w = get("/api/v1/trending/hall-of-fame")
+	assert.Equal(t, 200, w.Code)
+	var fame struct {
+		Data []HallOfFameResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fame))
+	if assert.Len(t, fame.Data, 2) {
+		assert.Equal(t, "long-runner", fame.Data[0].Slug)
+		assert.InDelta(t, 144, fame.Data[0].Stint.Hours, 0.1)
+		assert.Equal(t, "flash", fame.Data[1].Slug)
+	}
+
+	w = get("/api/v1/trending/hall-of-fame?list=rising")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fame))
+	assert.Len(t, fame.Data, 1)
+	assert.Equal(t, 400, get("/api/v1/trending/hall-of-fame?list=best").Code)
+}
+
+func TestAdminShadow(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count) VALUES
+			(1, 'both', 'Both', 'active', 50000),
+			(2, 'live-only', 'Live Only', 'active', 50000),
+			(3, 'shadow-only', 'Shadow Only', 'active', 50000);
+		INSERT INTO trending_param_sets (version, params, is_shadow) VALUES ('candidate', '{}', TRUE);
+		INSERT INTO trending_scores (addon_id, hot_score, rising_score, hot_rank) VALUES (1, 10, 0, 1), (2, 5, 0, 2);
+		INSERT INTO trending_shadow_scores (addon_id, params_version, hot_rank, calculated_at) VALUES
+			(1, 'candidate', 2, NOW()), (3, 'candidate', 1, NOW());
+		INSERT INTO trending_shadow_comparisons (calculated_at, live_version, shadow_version, list, overlap, rank_correlation, live_churn, shadow_churn) VALUES
+			(NOW() - INTERVAL '30 days', 'v3-default', 'candidate', 'hot', 1, 1, NULL, NULL),
+			(NOW() - INTERVAL '2 hours', 'v3-default', 'candidate', 'hot', 0.5, 0.2, NULL, NULL),
+			(NOW() - INTERVAL '1 hour', 'v3-default', 'candidate', 'hot', 0.3333, -0.5, 0, 0.5)
+	`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+	get := func(path, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	// Hidden until a token is configured, then the token is required
+	assert.Equal(t, 404, get("/api/v1/admin/shadow", "").Code)
+	server.SetAdminToken("secret")
+	assert.Equal(t, 401, get("/api/v1/admin/shadow", "").Code)
+	assert.Equal(t, 401, get("/api/v1/admin/shadow", "wrong").Code)
+	assert.Equal(t, 400, get("/api/v1/admin/shadow?list=fresh", "secret").Code)
+
+	w := get("/api/v1/admin/shadow", "secret")
+	assert.Equal(t, 200, w.Code)
+	var response struct {
+		Data AdminShadowResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	diff := response.Data.Diff
+	if assert.Len(t, diff, 3) {
+		assert.Equal(t, "both", diff[0].Slug)
+		assert.Equal(t, int16(2), *diff[0].ShadowRank)
+		assert.Equal(t, "live-only", diff[1].Slug)
+		assert.Nil(t, diff[1].ShadowRank)
+		assert.Equal(t, "shadow-only", diff[2].Slug)
+		assert.Nil(t, diff[2].LiveRank)
+	}
+
+	comparisons := response.Data.Comparisons
+	if assert.Len(t, comparisons, 2, "comparisons older than 14 days are left out") {
+		assert.Nil(t, comparisons[0].LiveChurn)
+		assert.InDelta(t, -0.5, comparisons[1].RankCorrelation, 0.0001)
+		assert.InDelta(t, 0.5, *comparisons[1].ShadowChurn, 0.0001)
+	}
+}
+
+func TestCategoryTrending(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	for _, cat := range []database.UpsertCategoryParams{
+		{ID: 10, Name: "Professions", Slug: "professions"},
+		{ID: 11, Name: "Pet Battles", Slug: "pet-battles"},
+	} {
+		require.NoError(t, tdb.Queries.UpsertCategory(ctx, cat))
+	}
+	for _, id := range []int32{1, 2, 3} {
+		_, err := tdb.Pool.Exec(ctx, `
+			INSERT INTO addons (id, slug, name, status, categories, download_count)
+			VALUES ($1, $2, $3, 'active', '{10}', 1000)
+		`, id, fmt.Sprintf("addon-%d", id), fmt.Sprintf("Addon %d", id))
+		require.NoError(t, err)
+	}
+	// Addon 1 leads hot; addon 2 is on the hot list too, so only 3 is rising
+	for _, s := range []struct {
+		id          int32
+		hot, rising float64
+	}{{1, 50, 5}, {2, 40, 8}, {3, 0, 3}} {
+		_, err := tdb.Pool.Exec(ctx, `
+			INSERT INTO category_trending_scores (category_id, addon_id, hot_score, rising_score, calculated_at)
+			VALUES (10, $1, $2, $3, NOW())
+		`, s.id, s.hot, s.rising)
+		require.NoError(t, err)
+	}
+
+	server := NewServer(tdb.Queries)
+	get := func(path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+	slugs := func(resp map[string]interface{}) []string {
+		var out []string
+		for _, d := range resp["data"].([]interface{}) {
+			out = append(out, d.(map[string]interface{})["slug"].(string))
+		}
+		return out
+	}
+
+	t.Run("hot ranks within the category", func(t *testing.T) {
+		code, resp := get("/api/v1/categories/professions/trending/hot")
+		assert.Equal(t, 200, code)
+		assert.Equal(t, []string{"addon-1", "addon-2"}, slugs(resp))
+	})
+
+	t.Run("rising excludes the category's hot list", func(t *testing.T) {
+		code, resp := get("/api/v1/categories/professions/trending/rising")
+		assert.Equal(t, 200, code)
+		assert.Equal(t, []string{"addon-3"}, slugs(resp))
+	})
+
+	t.Run("other categories have their own lists", func(t *testing.T) {
+		code, resp := get("/api/v1/categories/pet-battles/trending/hot")
+		assert.Equal(t, 200, code)
+		assert.Empty(t, resp["data"])
+	})
+
+	t.Run("unknown category returns 404", func(t *testing.T) {
+		code, _ := get("/api/v1/categories/nope/trending/hot")
+		assert.Equal(t, 404, code)
+	})
+}
+
 func TestGetAddonHistory(t *testing.T) {
 	tdb := testutil.SetupTestDB(t)
 	ctx := context.Background()
@@ -799,3 +1481,167 @@ func TestTrendingHotPagination(t *testing.T) {
 		assert.True(t, hasVelocity, "response should include download_velocity")
 	})
 }
+
+func TestRemovedAndReturnedAddons(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		_, err := tdb.Pool.Exec(ctx, `
+			INSERT INTO addons (id, slug, name, status, download_count)
+			VALUES ($1, $2, $3, 'active', 1000)
+		`, i, fmt.Sprintf("addon-%d", i), fmt.Sprintf("Addon %d", i))
+		require.NoError(t, err)
+	}
+
+	// Addons 2 and 3 vanish, then addon 3 comes back
+	_, err := tdb.Queries.MarkMissingAddonsInactive(ctx, []int32{1})
+	require.NoError(t, err)
+	_, err = tdb.Queries.RecordAddonReactivation(ctx, 3)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `UPDATE addons SET status = 'active' WHERE id = 3`)
+	require.NoError(t, err)
+
+	server := NewServer(tdb.Queries)
+
+	t.Run("removed lists inactive addons with last known stats", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/addons/removed", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code)
+
+		var resp struct {
+			Data []RemovedAddonResponse `json:"data"`
+			Meta Meta                   `json:"meta"`
+		}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, 1, resp.Meta.Total)
+		assert.Equal(t, "addon-2", resp.Data[0].Slug)
+		assert.Equal(t, int64(1000), resp.Data[0].DownloadCount)
+		assert.NotEmpty(t, resp.Data[0].RemovedAt)
+	})
+
+	t.Run("returned lists reactivated addons", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/addons/returned", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code)
+
+		var resp struct {
+			Data []ReturnedAddonResponse `json:"data"`
+		}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, "addon-3", resp.Data[0].Slug)
+		assert.NotEmpty(t, resp.Data[0].ReturnedAt)
+		assert.NotEmpty(t, resp.Data[0].RemovedAt)
+	})
+
+	t.Run("static routes do not shadow addon slugs", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/addons/addon-1", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code)
+	})
+}
+
+func TestGetAddonScore(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count) VALUES
+			(1, 'hot-addon', 'Hot Addon', 'active', 5000),
+			(2, 'tiny-addon', 'Tiny Addon', 'active', 20),
+			(3, 'new-addon', 'New Addon', 'active', 1000)
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO trending_scores (addon_id, hot_score, rising_score, download_velocity, size_multiplier) VALUES
+			(1, 12.5, 0, 40, 0.6),
+			(2, 0, 0, 0, 0.1)
+	`)
+	require.NoError(t, err)
+	for i := 0; i < 6; i++ {
+		_, err = tdb.Pool.Exec(ctx, `
+			INSERT INTO snapshots (addon_id, recorded_at, download_count)
+			VALUES (1, NOW() - make_interval(hours => $1), $2)
+		`, i, 5000-i*100)
+		require.NoError(t, err)
+	}
+
+	server := NewServer(tdb.Queries)
+
+	getScore := func(t *testing.T, slug string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/addons/"+slug+"/score", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		require.Equal(t, 200, w.Code)
+
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		data, ok := resp["data"].(map[string]interface{})
+		require.True(t, ok)
+		return data
+	}
+
+	t.Run("listed addon", func(t *testing.T) {
+		data := getScore(t, "hot-addon")
+		hot := data["hot"].(map[string]interface{})
+		assert.Equal(t, true, hot["listed"])
+		assert.InDelta(t, 1, hot["rank"], 0)
+		assert.Empty(t, hot["excluded_by"])
+
+		stored := data["stored"].(map[string]interface{})
+		assert.InDelta(t, 12.5, stored["hot_score"], 0.01)
+		assert.InDelta(t, 0.6, stored["size_multiplier"], 0.01)
+
+		breakdown := data["breakdown"].(map[string]interface{})
+		assert.InDelta(t, 500, breakdown["download_change_24h"], 0)
+		assert.Positive(t, breakdown["hot_signal"])
+		assert.Positive(t, breakdown["hot_decay"])
+	})
+
+	t.Run("excluded by thresholds", func(t *testing.T) {
+		data := getScore(t, "tiny-addon")
+		hot := data["hot"].(map[string]interface{})
+		assert.Equal(t, false, hot["listed"])
+		assert.Equal(t, []interface{}{"below_min_downloads", "no_positive_signal"}, hot["excluded_by"])
+
+		rising := data["rising"].(map[string]interface{})
+		assert.Contains(t, rising["excluded_by"], "below_min_downloads")
+
+		loved := data["loved"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"below_min_downloads", "no_positive_signal"}, loved["excluded_by"])
+
+		fresh := data["fresh"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"not_new", "no_positive_signal"}, fresh["excluded_by"])
+	})
+
+	t.Run("not yet calculated", func(t *testing.T) {
+		data := getScore(t, "new-addon")
+		assert.Nil(t, data["stored"])
+		hot := data["hot"].(map[string]interface{})
+		assert.Contains(t, hot["excluded_by"], "not_calculated")
+	})
+
+	t.Run("unknown addon", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/addons/missing/score", nil)
+		require.NoError(t, err)
+		server.ServeHTTP(w, req)
+		assert.Equal(t, 404, w.Code)
+	})
+}
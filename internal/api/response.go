@@ -49,6 +49,14 @@ func respondWithError(c *gin.Context, status int, code, message string) {
 	})
 }
 
+func respondBadRequest(c *gin.Context, message string) {
+	respondWithError(c, 400, "bad_request", message)
+}
+
+func respondUnauthorized(c *gin.Context, message string) {
+	respondWithError(c, 401, "unauthorized", message)
+}
+
 func respondNotFound(c *gin.Context, message string) {
 	respondWithError(c, 404, "not_found", message)
 }
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"crypto/subtle"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -10,8 +12,9 @@ import (
 )
 
 type Server struct {
-	db     *database.Queries
-	router *gin.Engine
+	db         *database.Queries
+	router     *gin.Engine
+	adminToken string
 }
 
 func NewServer(db *database.Queries) *Server {
@@ -22,6 +25,12 @@ func NewServer(db *database.Queries) *Server {
 	return s
 }
 
+// SetAdminToken sets the bearer token for the admin endpoints, which are
+// hidden while it's empty.
+func (s *Server) SetAdminToken(token string) {
+	s.adminToken = token
+}
+
 // ServeHTTP implements the http.Handler interface
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
@@ -38,11 +47,33 @@ func (s *Server) setupRouter() {
 	{
 		api.GET("/health", s.handleHealth)
 		api.GET("/addons", s.handleListAddons)
+		api.GET("/addons/removed", s.handleRemovedAddons)
+		api.GET("/addons/returned", s.handleReturnedAddons)
 		api.GET("/addons/:slug", s.handleGetAddon)
 		api.GET("/addons/:slug/history", s.handleGetAddonHistory)
+		api.GET("/addons/:slug/score", s.handleGetAddonScore)
+		api.GET("/addons/:slug/forecast", s.handleGetAddonForecast)
+		api.GET("/addons/:slug/milestones", s.handleGetAddonMilestones)
+		api.GET("/addons/:slug/stints", s.handleGetAddonStints)
 		api.GET("/categories", s.handleListCategories)
+		api.GET("/categories/tree", s.handleCategoryTree)
+		api.GET("/categories/:slug", s.handleGetCategory)
+		api.GET("/categories/:slug/trending/hot", s.handleCategoryTrendingHot)
+		api.GET("/categories/:slug/trending/rising", s.handleCategoryTrendingRising)
 		api.GET("/trending/hot", s.handleTrendingHot)
 		api.GET("/trending/rising", s.handleTrendingRising)
+		api.GET("/trending/loved", s.handleTrendingLoved)
+		api.GET("/trending/new", s.handleTrendingNew)
+		api.GET("/trending/comebacks", s.handleTrendingComebacks)
+		api.GET("/trending/hall-of-fame", s.handleHallOfFame)
+		api.GET("/leaderboards/weekly/:isoweek", s.handleWeeklyLeaderboard)
+		api.GET("/forecasts/crossing", s.handleForecastCrossing)
+		api.GET("/milestones", s.handleMilestones)
+	}
+
+	admin := r.Group("/api/v1/admin", s.adminMiddleware())
+	{
+		admin.GET("/shadow", s.handleAdminShadow)
 	}
 
 	s.router = r
@@ -78,6 +109,25 @@ func (s *Server) corsMiddleware() gin.HandlerFunc {
 	}
 }
 
+// adminMiddleware requires the admin bearer token. Without a configured token
+// the admin endpoints don't exist.
+func (s *Server) adminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.adminToken == "" {
+			respondNotFound(c, "Not found")
+			c.Abort()
+			return
+		}
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			respondUnauthorized(c, "Invalid or missing admin token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 func (s *Server) handleHealth(c *gin.Context) {
 	c.JSON(200, gin.H{
 		"status": "ok",
@@ -0,0 +1,210 @@
+// Package backtest replays trending parameter sets over historical snapshots
+// so algorithm changes can be compared before they ship.
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+	"addon-radar/internal/trending"
+)
+
+// Config sets the range a backtest replays.
+type Config struct {
+	From time.Time
+	To   time.Time
+	Step time.Duration
+
+	// Eventual hits are the HitCount addons with the largest download gains
+	// between From and To+HitHorizon.
+	HitCount   int32
+	HitHorizon time.Duration
+}
+
+// SetReport is the outcome of replaying one parameter set.
+type SetReport struct {
+	Version string      `json:"version"`
+	Hot     ListMetrics `json:"hot"`
+	Rising  ListMetrics `json:"rising"`
+	Hits    HitMetrics  `json:"hits"`
+}
+
+// Overlap is the average Jaccard overlap of two sets' lists across all steps.
+type Overlap struct {
+	A      string  `json:"a"`
+	B      string  `json:"b"`
+	Hot    float64 `json:"hot"`
+	Rising float64 `json:"rising"`
+}
+
+// Report is the outcome of a backtest.
+type Report struct {
+	From     time.Time   `json:"from"`
+	To       time.Time   `json:"to"`
+	Steps    int         `json:"steps"`
+	HitCount int         `json:"hit_count"`
+	Sets     []SetReport `json:"sets"`
+	Overlap  []Overlap   `json:"overlap"`
+}
+
+// Run replays every parameter set at each step from cfg.From to cfg.To, using
+// only the snapshots recorded by each step. It reads from the database but
+// never writes to it.
+func Run(ctx context.Context, db *database.Queries, cfg Config, sets []trending.Params) (*Report, error) {
+	if len(sets) == 0 {
+		return nil, errors.New("no parameter sets to backtest")
+	}
+	if cfg.Step <= 0 {
+		return nil, fmt.Errorf("step must be positive, got %s", cfg.Step)
+	}
+	if !cfg.From.Before(cfg.To) {
+		return nil, fmt.Errorf("from (%s) must be before to (%s)", cfg.From, cfg.To)
+	}
+
+	gains, err := db.ListTopDownloadGains(ctx, database.ListTopDownloadGainsParams{
+		FromTime:   pgtype.Timestamptz{Time: cfg.From, Valid: true},
+		ToTime:     pgtype.Timestamptz{Time: cfg.To.Add(cfg.HitHorizon), Valid: true},
+		LimitCount: cfg.HitCount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list eventual hits: %w", err)
+	}
+	hits := make([]Hit, len(gains))
+	for i, g := range gains {
+		hits[i] = Hit{AddonID: g.AddonID, StartDownloads: g.StartDownloads, Gain: g.Gain}
+	}
+
+	type setState struct {
+		replay *trending.Replay
+		hot    *listTracker
+		rising *listTracker
+		hits   *hitTracker
+	}
+	states := make([]setState, len(sets))
+	for i, p := range sets {
+		states[i] = setState{
+			replay: trending.NewReplay(p),
+			hot:    newListTracker(cfg.Step),
+			rising: newListTracker(cfg.Step),
+			hits:   newHitTracker(hits),
+		}
+	}
+	overlapHot := make([][]float64, len(sets))
+	overlapRising := make([][]float64, len(sets))
+	for i := range sets {
+		overlapHot[i] = make([]float64, len(sets))
+		overlapRising[i] = make([]float64, len(sets))
+	}
+
+	steps := 0
+	for at := cfg.From; !at.After(cfg.To); at = at.Add(cfg.Step) {
+		stats, percentile95, updates, err := loadPoint(ctx, db, at)
+		if err != nil {
+			return nil, fmt.Errorf("load snapshots as of %s: %w", at.Format(time.RFC3339), err)
+		}
+		downloads := make(map[int32]int64, len(stats))
+		for _, s := range stats {
+			downloads[s.AddonID] = s.DownloadCount.Int64
+		}
+
+		lists := make([]trending.Lists, len(states))
+		for i, st := range states {
+			lists[i] = st.replay.Step(at, stats, percentile95, updates)
+			st.hot.observe(lists[i].Hot)
+			st.rising.observe(lists[i].Rising)
+			st.hits.observe(lists[i].Hot, downloads)
+			st.hits.observe(lists[i].Rising, downloads)
+		}
+		for i := range lists {
+			for j := i + 1; j < len(lists); j++ {
+				overlapHot[i][j] += jaccard(lists[i].Hot, lists[j].Hot)
+				overlapRising[i][j] += jaccard(lists[i].Rising, lists[j].Rising)
+			}
+		}
+
+		steps++
+		if steps%24 == 0 {
+			slog.Info("backtest progress", "at", at.Format(time.RFC3339), "steps", steps)
+		}
+	}
+
+	report := &Report{From: cfg.From, To: cfg.To, Steps: steps, HitCount: len(hits)}
+	for i, st := range states {
+		report.Sets = append(report.Sets, SetReport{
+			Version: sets[i].Version,
+			Hot:     st.hot.metrics(),
+			Rising:  st.rising.metrics(),
+			Hits:    st.hits.metrics(),
+		})
+		for j := i + 1; j < len(sets); j++ {
+			report.Overlap = append(report.Overlap, Overlap{
+				A:      sets[i].Version,
+				B:      sets[j].Version,
+				Hot:    overlapHot[i][j] / float64(steps),
+				Rising: overlapRising[i][j] / float64(steps),
+			})
+		}
+	}
+	return report, nil
+}
+
+// loadPoint fetches the calculator inputs as they stood at a point in time.
+func loadPoint(ctx context.Context, db *database.Queries, at time.Time) ([]database.GetAllSnapshotStatsRow, float64, map[int32]int32, error) {
+	asOf := pgtype.Timestamptz{Time: at, Valid: true}
+
+	rows, err := db.GetAllSnapshotStatsAsOf(ctx, asOf)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	stats := make([]database.GetAllSnapshotStatsRow, len(rows))
+	for i, r := range rows {
+		stats[i] = database.GetAllSnapshotStatsRow{
+			AddonID:           r.AddonID,
+			DownloadCount:     pgtype.Int8{Int64: r.DownloadCount, Valid: true},
+			ThumbsUpCount:     r.ThumbsUpCount,
+			LatestFileDate:    r.LatestFileDate,
+			CreatedAt:         r.CreatedAt,
+			AuthorID:          r.AuthorID,
+			PrimaryCategoryID: r.PrimaryCategoryID,
+			DownloadChange24h: r.DownloadChange24h,
+			ThumbsChange24h:   r.ThumbsChange24h,
+			SnapshotCount24h:  r.SnapshotCount24h,
+			DownloadChange7d:  r.DownloadChange7d,
+			ThumbsChange7d:    r.ThumbsChange7d,
+			MinDownloads7d:    r.MinDownloads7d,
+			DownloadSlope24h:  r.DownloadSlope24h,
+			ThumbsSlope24h:    r.ThumbsSlope24h,
+			DownloadFit24h:    r.DownloadFit24h,
+			ThumbsFit24h:      r.ThumbsFit24h,
+			SpanHours24h:      r.SpanHours24h,
+			DownloadSlope7d:   r.DownloadSlope7d,
+			ThumbsSlope7d:     r.ThumbsSlope7d,
+		}
+	}
+
+	percentile95, err := db.GetDownloadPercentileAsOf(ctx, asOf)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	// Same fallback as the live calculator
+	if percentile95 <= 0 {
+		percentile95 = 500000
+	}
+
+	updateCounts, err := db.CountAllRecentFileUpdatesAsOf(ctx, asOf)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	updates := make(map[int32]int32, len(updateCounts))
+	for _, u := range updateCounts {
+		updates[u.AddonID] = u.UpdateCount
+	}
+
+	return stats, percentile95, updates, nil
+}
@@ -0,0 +1,80 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/testutil"
+	"addon-radar/internal/trending"
+)
+
+// seedHourlySnapshots creates an addon gaining perHour downloads every hour over the given range
+func seedHourlySnapshots(t *testing.T, tdb *testutil.TestDB, id int32, start time.Time, hours int, downloads, perHour int64) {
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count, created_at)
+		VALUES ($1, $2, $3, 'active', $4, $5)
+	`, id, "addon-"+string(rune('a'+id)), "Addon", downloads+perHour*int64(hours), start.Add(-30*24*time.Hour))
+	require.NoError(t, err)
+
+	for h := 0; h <= hours; h++ {
+		_, err := tdb.Pool.Exec(ctx, `
+			INSERT INTO snapshots (addon_id, recorded_at, download_count)
+			VALUES ($1, $2, $3)
+		`, id, start.Add(time.Duration(h)*time.Hour), downloads+perHour*int64(h))
+		require.NoError(t, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(-72 * time.Hour).Truncate(time.Hour)
+	seedHourlySnapshots(t, tdb, 1, start, 72, 100000, 500)
+	seedHourlySnapshots(t, tdb, 2, start, 72, 5000, 20)
+	seedHourlySnapshots(t, tdb, 3, start, 72, 800, 1)
+
+	strict := trending.DefaultParams()
+	strict.Version = "strict"
+	strict.MinHotDownloads = 1000000
+
+	report, err := Run(ctx, tdb.Queries, Config{
+		From:       start.Add(24 * time.Hour),
+		To:         start.Add(48 * time.Hour),
+		Step:       time.Hour,
+		HitCount:   2,
+		HitHorizon: 24 * time.Hour,
+	}, []trending.Params{trending.DefaultParams(), strict})
+	require.NoError(t, err)
+
+	assert.Equal(t, 25, report.Steps)
+	assert.Equal(t, 2, report.HitCount)
+	require.Len(t, report.Sets, 2)
+	require.Len(t, report.Overlap, 1)
+
+	assert.Positive(t, report.Sets[0].Hot.DistinctAddons)
+	assert.Equal(t, 0, report.Sets[1].Hot.DistinctAddons, "strict gate keeps every addon off the hot list")
+	assert.Equal(t, HitMetrics{Listed: 2, CaughtEarly: 2}, report.Sets[0].Hits, "both gainers are hot from the first step")
+	assert.Equal(t, HitMetrics{}, report.Sets[1].Hits)
+	assert.Less(t, report.Overlap[0].Hot, 1.0)
+}
+
+func TestRunValidation(t *testing.T) {
+	now := time.Now()
+	sets := []trending.Params{trending.DefaultParams()}
+
+	_, err := Run(context.Background(), nil, Config{From: now, To: now.Add(time.Hour)}, sets)
+	assert.ErrorContains(t, err, "step must be positive")
+
+	_, err = Run(context.Background(), nil, Config{From: now, To: now, Step: time.Hour}, sets)
+	assert.ErrorContains(t, err, "must be before")
+
+	_, err = Run(context.Background(), nil, Config{From: now, To: now.Add(time.Hour), Step: time.Hour}, nil)
+	assert.ErrorContains(t, err, "no parameter sets")
+}
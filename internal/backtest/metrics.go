@@ -0,0 +1,160 @@
+package backtest
+
+import "time"
+
+// ListMetrics summarises how one list behaved over a backtest.
+type ListMetrics struct {
+	Churn          float64 `json:"churn"`             // Average share of the list that is new at each step
+	AvgHoursOnList float64 `json:"avg_hours_on_list"` // Average length of an uninterrupted stay
+	DistinctAddons int     `json:"distinct_addons"`
+}
+
+// listTracker follows one list across steps.
+type listTracker struct {
+	step time.Duration
+
+	prev     map[int32]bool
+	running  map[int32]int // Consecutive steps each listed addon has been on the list
+	stints   []int
+	seen     map[int32]bool
+	churnSum float64
+	samples  int
+}
+
+func newListTracker(step time.Duration) *listTracker {
+	return &listTracker{
+		step:    step,
+		running: make(map[int32]int),
+		seen:    make(map[int32]bool),
+	}
+}
+
+func (t *listTracker) observe(ids []int32) {
+	current := make(map[int32]bool, len(ids))
+	for _, id := range ids {
+		current[id] = true
+		t.seen[id] = true
+	}
+
+	// The first step has nothing to churn against
+	if t.prev != nil && len(current) > 0 {
+		entered := 0
+		for id := range current {
+			if !t.prev[id] {
+				entered++
+			}
+		}
+		t.churnSum += float64(entered) / float64(len(current))
+		t.samples++
+	}
+
+	for id, steps := range t.running {
+		if !current[id] {
+			t.stints = append(t.stints, steps)
+			delete(t.running, id)
+		}
+	}
+	for id := range current {
+		t.running[id]++
+	}
+	t.prev = current
+}
+
+func (t *listTracker) metrics() ListMetrics {
+	// Stays still running at the end of the range count as they stand
+	stints := t.stints
+	for _, steps := range t.running {
+		stints = append(stints, steps)
+	}
+
+	var m ListMetrics
+	m.DistinctAddons = len(t.seen)
+	if t.samples > 0 {
+		m.Churn = t.churnSum / float64(t.samples)
+	}
+	if len(stints) > 0 {
+		total := 0
+		for _, s := range stints {
+			total += s
+		}
+		m.AvgHoursOnList = float64(total) / float64(len(stints)) * t.step.Hours()
+	}
+	return m
+}
+
+// Hit is an addon with one of the largest download gains over the hit window.
+type Hit struct {
+	AddonID        int32 `json:"addon_id"`
+	StartDownloads int64 `json:"start_downloads"`
+	Gain           int64 `json:"gain"`
+}
+
+// HitMetrics counts how many eventual hits a parameter set surfaced, and how
+// many of those it surfaced before they had made half of their gain.
+type HitMetrics struct {
+	Listed      int `json:"listed"`
+	CaughtEarly int `json:"caught_early"`
+}
+
+type hitTracker struct {
+	hits   map[int32]Hit
+	listed map[int32]bool
+	early  map[int32]bool
+}
+
+func newHitTracker(hits []Hit) *hitTracker {
+	t := &hitTracker{
+		hits:   make(map[int32]Hit, len(hits)),
+		listed: make(map[int32]bool),
+		early:  make(map[int32]bool),
+	}
+	for _, h := range hits {
+		t.hits[h.AddonID] = h
+	}
+	return t
+}
+
+// observe records hits on either list; downloads holds each addon's download
+// count at the step.
+func (t *hitTracker) observe(ids []int32, downloads map[int32]int64) {
+	for _, id := range ids {
+		hit, ok := t.hits[id]
+		if !ok {
+			continue
+		}
+		t.listed[id] = true
+		if downloads[id]-hit.StartDownloads <= hit.Gain/2 {
+			t.early[id] = true
+		}
+	}
+}
+
+func (t *hitTracker) metrics() HitMetrics {
+	return HitMetrics{Listed: len(t.listed), CaughtEarly: len(t.early)}
+}
+
+// jaccard returns the overlap of two lists; two empty lists overlap fully.
+func jaccard(a, b []int32) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	inA := make(map[int32]bool, len(a))
+	for _, id := range a {
+		inA[id] = true
+	}
+	shared := 0
+	union := len(inA)
+	seenB := make(map[int32]bool, len(b))
+	for _, id := range b {
+		if seenB[id] {
+			continue
+		}
+		seenB[id] = true
+		if inA[id] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
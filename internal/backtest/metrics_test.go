@@ -0,0 +1,63 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTracker(t *testing.T) {
+	tracker := newListTracker(time.Hour)
+	tracker.observe([]int32{1, 2})
+	tracker.observe([]int32{1, 3}) // 3 enters, 2 leaves after 1h
+	tracker.observe([]int32{1, 3})
+	tracker.observe([]int32{4, 3}) // 4 enters, 1 leaves after 3h
+
+	m := tracker.metrics()
+	assert.Equal(t, 4, m.DistinctAddons)
+	// Churn: 1/2, 0/2, 1/2 across the three transitions
+	assert.InDelta(t, 1.0/3, m.Churn, 0.0001)
+	// Stays: 2 (1h), 1 (3h), plus running 3 (3h) and 4 (1h)
+	assert.InDelta(t, 2.0, m.AvgHoursOnList, 0.0001)
+}
+
+func TestListTrackerEmpty(t *testing.T) {
+	tracker := newListTracker(time.Hour)
+	tracker.observe(nil)
+	tracker.observe(nil)
+
+	assert.Equal(t, ListMetrics{}, tracker.metrics())
+}
+
+func TestHitTracker(t *testing.T) {
+	tracker := newHitTracker([]Hit{
+		{AddonID: 1, StartDownloads: 1000, Gain: 10000},
+		{AddonID: 2, StartDownloads: 500, Gain: 2000},
+		{AddonID: 3, StartDownloads: 100, Gain: 5000},
+	})
+
+	// Addon 1 is listed early, addon 2 only after most of its gain, addon 3 never
+	tracker.observe([]int32{1, 9}, map[int32]int64{1: 3000, 9: 50})
+	tracker.observe([]int32{2}, map[int32]int64{2: 2400})
+
+	assert.Equal(t, HitMetrics{Listed: 2, CaughtEarly: 1}, tracker.metrics())
+}
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int32
+		want float64
+	}{
+		{"both empty", nil, nil, 1},
+		{"one empty", []int32{1}, nil, 0},
+		{"identical", []int32{1, 2}, []int32{2, 1}, 1},
+		{"partial", []int32{1, 2, 3}, []int32{2, 3, 4}, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, jaccard(tt.a, tt.b), 0.0001)
+		})
+	}
+}
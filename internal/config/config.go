@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -8,6 +10,36 @@ type Config struct {
 	DatabaseURL      string `envconfig:"DATABASE_URL" required:"true"`
 	CurseForgeAPIKey string `envconfig:"CURSEFORGE_API_KEY"` // Optional for web, required for sync
 	Environment      string `envconfig:"ENV" default:"development"`
+	AdminToken       string `envconfig:"ADMIN_TOKEN"` // Bearer token for /api/v1/admin; unset disables it
+
+	// Optional JSON trending parameter set; overrides the active set in the database
+	TrendingParamsFile string `envconfig:"TRENDING_PARAMS_FILE"`
+
+	Schedule ScheduleConfig   `envconfig:"SCHEDULE"` // Used by sync --daemon
+	Safety   SyncSafetyConfig `envconfig:"SYNC"`
+}
+
+// SyncSafetyConfig sets when a full sync is quarantined instead of acted on,
+// e.g. SYNC_MIN_RATIO_OF_LAST_RUN=0.9 quarantines runs with under 90% of the last run's addons.
+type SyncSafetyConfig struct {
+	MinAddons         int     `envconfig:"MIN_ADDONS" default:"1000"`
+	MinRatioOfLastRun float64 `envconfig:"MIN_RATIO_OF_LAST_RUN" default:"0.9"`
+	MaxErrorRate      float64 `envconfig:"MAX_ERROR_RATE" default:"0.01"`
+}
+
+// ScheduleConfig sets how often each daemon job runs, e.g. SCHEDULE_FULL_SYNC_INTERVAL=1h.
+// Each run is delayed by a random amount up to the job's jitter.
+type ScheduleConfig struct {
+	FullSyncInterval        time.Duration `envconfig:"FULL_SYNC_INTERVAL" default:"1h"`
+	FullSyncJitter          time.Duration `envconfig:"FULL_SYNC_JITTER" default:"5m"`
+	IncrementalSyncInterval time.Duration `envconfig:"INCREMENTAL_SYNC_INTERVAL" default:"15m"`
+	IncrementalSyncJitter   time.Duration `envconfig:"INCREMENTAL_SYNC_JITTER" default:"2m"`
+	HotRefreshInterval      time.Duration `envconfig:"HOT_REFRESH_INTERVAL" default:"5m"`
+	HotRefreshJitter        time.Duration `envconfig:"HOT_REFRESH_JITTER" default:"1m"`
+	TrendingInterval        time.Duration `envconfig:"TRENDING_INTERVAL" default:"1h"`
+	TrendingJitter          time.Duration `envconfig:"TRENDING_JITTER" default:"5m"`
+	CleanupInterval         time.Duration `envconfig:"CLEANUP_INTERVAL" default:"24h"`
+	CleanupJitter           time.Duration `envconfig:"CLEANUP_JITTER" default:"30m"`
 }
 
 func Load() (*Config, error) {
@@ -1,6 +1,7 @@
 package curseforge
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -62,6 +63,12 @@ func isClientError(err error) bool {
 
 // doRequest performs an HTTP request with authentication and retry logic
 func (c *Client) doRequest(ctx context.Context, method, path string, query url.Values) ([]byte, error) {
+	return c.doRequestWithBody(ctx, method, path, query, nil)
+}
+
+// doRequestWithBody performs an HTTP request with an optional JSON body.
+// The body is a byte slice so it can be resent on retries.
+func (c *Client) doRequestWithBody(ctx context.Context, method, path string, query url.Values, payload []byte) ([]byte, error) {
 	const maxRetries = 3
 
 	// Warn if context deadline may be too short for retries
@@ -98,7 +105,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, query url.V
 			}
 		}
 
-		body, err := c.doRequestOnce(ctx, method, path, query)
+		body, err := c.doRequestOnce(ctx, method, path, query, payload)
 		if err == nil {
 			return body, nil
 		}
@@ -115,19 +122,27 @@ func (c *Client) doRequest(ctx context.Context, method, path string, query url.V
 }
 
 // doRequestOnce performs a single HTTP request with authentication
-func (c *Client) doRequestOnce(ctx context.Context, method, path string, query url.Values) ([]byte, error) {
+func (c *Client) doRequestOnce(ctx context.Context, method, path string, query url.Values, payload []byte) ([]byte, error) {
 	reqURL := c.baseURL + path
 	if len(query) > 0 {
 		reqURL += "?" + query.Encode()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("x-api-key", c.apiKey)
 	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -303,6 +318,73 @@ func (c *Client) GetAllWoWAddons(ctx context.Context) ([]Mod, error) {
 	return c.GetAllAddonsForVersion(ctx, GameVersionTypeRetail)
 }
 
+// GetWoWAddonsUpdatedSince fetches WoW Retail addons modified after since.
+// Results are paged by last-updated order and paging stops at the first older addon.
+func (c *Client) GetWoWAddonsUpdatedSince(ctx context.Context, since time.Time) ([]Mod, error) {
+	var mods []Mod
+	pageSize := 50
+	index := 0
+
+	for {
+		resp, err := c.SearchMods(ctx, SearchModsParams{
+			GameID:            GameIDWoW,
+			GameVersionTypeID: GameVersionTypeRetail,
+			SortField:         SortFieldLastUpdated,
+			Index:             index,
+			PageSize:          pageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch page at index %d: %w", index, err)
+		}
+
+		for _, mod := range resp.Data {
+			if !mod.DateModified.After(since) {
+				return mods, nil
+			}
+			mods = append(mods, mod)
+		}
+
+		if len(resp.Data) < pageSize || index+pageSize >= resp.Pagination.TotalCount ||
+			index+pageSize >= MaxSearchResults {
+			return mods, nil
+		}
+
+		index += pageSize
+
+		// Small delay to be nice to the API
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// maxModsPerRequest is the largest batch accepted by POST /v1/mods
+const maxModsPerRequest = 1000
+
+// GetMods fetches specific mods by ID
+func (c *Client) GetMods(ctx context.Context, modIDs []int) ([]Mod, error) {
+	var mods []Mod
+	for start := 0; start < len(modIDs); start += maxModsPerRequest {
+		end := min(start+maxModsPerRequest, len(modIDs))
+
+		payload, err := json.Marshal(GetModsRequest{ModIDs: modIDs[start:end]})
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+
+		body, err := c.doRequestWithBody(ctx, http.MethodPost, "/v1/mods", nil, payload)
+		if err != nil {
+			return nil, fmt.Errorf("get mods: %w", err)
+		}
+
+		var result GetModsResponse
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("unmarshal mods: %w", err)
+		}
+		mods = append(mods, result.Data...)
+	}
+
+	return mods, nil
+}
+
 // GetCategories fetches all categories for a game
 func (c *Client) GetCategories(ctx context.Context, gameID int) ([]Category, error) {
 	query := url.Values{}
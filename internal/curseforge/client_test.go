@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -153,6 +154,67 @@ func TestGetCategories(t *testing.T) {
 	})
 }
 
+func TestGetWoWAddonsUpdatedSince(t *testing.T) {
+	since := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/mods/search", r.URL.Path)
+		assert.Equal(t, "3", r.URL.Query().Get("sortField"))
+		assert.Equal(t, "desc", r.URL.Query().Get("sortOrder"))
+
+		response := SearchModsResponse{
+			Data: []Mod{
+				{ID: 1, DateModified: since.Add(2 * time.Hour)},
+				{ID: 2, DateModified: since.Add(time.Minute)},
+				{ID: 3, DateModified: since},
+				{ID: 4, DateModified: since.Add(-time.Hour)},
+			},
+			Pagination: Pagination{TotalCount: 100},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response) //nolint:errcheck // Test mock encode
+	}))
+	defer server.Close()
+
+	client := newTestClient("fake-key")
+	client.baseURL = server.URL
+
+	mods, err := client.GetWoWAddonsUpdatedSince(context.Background(), since)
+
+	require.NoError(t, err)
+	require.Len(t, mods, 2)
+	assert.Equal(t, 1, mods[0].ID)
+	assert.Equal(t, 2, mods[1].ID)
+}
+
+func TestGetMods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/mods", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var req GetModsRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		response := GetModsResponse{}
+		for _, id := range req.ModIDs {
+			response.Data = append(response.Data, Mod{ID: id})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response) //nolint:errcheck // Test mock encode
+	}))
+	defer server.Close()
+
+	client := newTestClient("fake-key")
+	client.baseURL = server.URL
+
+	mods, err := client.GetMods(context.Background(), []int{10, 20})
+
+	require.NoError(t, err)
+	require.Len(t, mods, 2)
+	assert.Equal(t, 20, mods[1].ID)
+}
+
 func TestNewClient(t *testing.T) {
 	client := NewClient("test-api-key")
 
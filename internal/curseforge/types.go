@@ -64,13 +64,17 @@ type Mod struct {
 
 // Category represents an addon category
 type Category struct {
-	ID       int    `json:"id"`
-	GameID   int    `json:"gameId"`
-	Name     string `json:"name"`
-	Slug     string `json:"slug"`
-	URL      string `json:"url"`
-	IconURL  string `json:"iconUrl"`
-	ParentID int    `json:"parentCategoryId"`
+	ID           int       `json:"id"`
+	GameID       int       `json:"gameId"`
+	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
+	URL          string    `json:"url"`
+	IconURL      string    `json:"iconUrl"`
+	DateModified time.Time `json:"dateModified"`
+	IsClass      bool      `json:"isClass"`
+	ClassID      int       `json:"classId"`
+	ParentID     int       `json:"parentCategoryId"`
+	DisplayIndex int       `json:"displayIndex"`
 }
 
 // Author represents an addon author
@@ -115,3 +119,13 @@ type FileIndex struct {
 type GetCategoriesResponse struct {
 	Data []Category `json:"data"`
 }
+
+// GetModsRequest is the body for POST /v1/mods
+type GetModsRequest struct {
+	ModIDs []int `json:"modIds"`
+}
+
+// GetModsResponse is the response from POST /v1/mods
+type GetModsResponse struct {
+	Data []Mod `json:"data"`
+}
@@ -30,14 +30,176 @@ type Addon struct {
 	PopularityRank    pgtype.Int4        `json:"popularity_rank"`
 	Rating            pgtype.Numeric     `json:"rating"`
 	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
+	ComebackAt        pgtype.Timestamptz `json:"comeback_at"`
+	HeatIndex         pgtype.Int2        `json:"heat_index"`
+	HeatSparkline     []int16            `json:"heat_sparkline"`
+}
+
+type AddonForecast struct {
+	AddonID       int32              `json:"addon_id"`
+	ForecastFrom  pgtype.Timestamptz `json:"forecast_from"`
+	DownloadCount int64              `json:"download_count"`
+	HistoryHours  int32              `json:"history_hours"`
+	Projected24h  int64              `json:"projected_24h"`
+	Low24h        int64              `json:"low_24h"`
+	High24h       int64              `json:"high_24h"`
+	Projected7d   int64              `json:"projected_7d"`
+	Low7d         int64              `json:"low_7d"`
+	High7d        int64              `json:"high_7d"`
+	CalculatedAt  pgtype.Timestamptz `json:"calculated_at"`
+}
+
+type AddonStatusEvent struct {
+	ID            int64              `json:"id"`
+	AddonID       int32              `json:"addon_id"`
+	FromStatus    pgtype.Text        `json:"from_status"`
+	ToStatus      string             `json:"to_status"`
+	Reason        string             `json:"reason"`
+	DownloadCount pgtype.Int8        `json:"download_count"`
+	ThumbsUpCount pgtype.Int4        `json:"thumbs_up_count"`
+	OccurredAt    pgtype.Timestamptz `json:"occurred_at"`
 }
 
 type Category struct {
-	ID       int32       `json:"id"`
-	Name     string      `json:"name"`
-	Slug     string      `json:"slug"`
-	ParentID pgtype.Int4 `json:"parent_id"`
-	IconUrl  pgtype.Text `json:"icon_url"`
+	ID           int32              `json:"id"`
+	Name         string             `json:"name"`
+	Slug         string             `json:"slug"`
+	ParentID     pgtype.Int4        `json:"parent_id"`
+	IconUrl      pgtype.Text        `json:"icon_url"`
+	ClassID      pgtype.Int4        `json:"class_id"`
+	IsClass      bool               `json:"is_class"`
+	DisplayIndex int32              `json:"display_index"`
+	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type CategoryRankHistory struct {
+	CategoryID int32              `json:"category_id"`
+	AddonID    int32              `json:"addon_id"`
+	List       string             `json:"list"`
+	Rank       int16              `json:"rank"`
+	Score      pgtype.Numeric     `json:"score"`
+	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
+}
+
+type CategoryTrendingScore struct {
+	CategoryID       int32              `json:"category_id"`
+	AddonID          int32              `json:"addon_id"`
+	HotScore         pgtype.Numeric     `json:"hot_score"`
+	RisingScore      pgtype.Numeric     `json:"rising_score"`
+	DownloadVelocity pgtype.Numeric     `json:"download_velocity"`
+	SizeMultiplier   pgtype.Numeric     `json:"size_multiplier"`
+	FirstHotAt       pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt    pgtype.Timestamptz `json:"first_rising_at"`
+	CalculatedAt     pgtype.Timestamptz `json:"calculated_at"`
+	HotRank          pgtype.Int2        `json:"hot_rank"`
+	RisingRank       pgtype.Int2        `json:"rising_rank"`
+	HotMisses        int16              `json:"hot_misses"`
+	RisingMisses     int16              `json:"rising_misses"`
+}
+
+type CategoryTrendingScoresPrevious struct {
+	CategoryID       int32              `json:"category_id"`
+	AddonID          int32              `json:"addon_id"`
+	HotScore         pgtype.Numeric     `json:"hot_score"`
+	RisingScore      pgtype.Numeric     `json:"rising_score"`
+	DownloadVelocity pgtype.Numeric     `json:"download_velocity"`
+	SizeMultiplier   pgtype.Numeric     `json:"size_multiplier"`
+	FirstHotAt       pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt    pgtype.Timestamptz `json:"first_rising_at"`
+	CalculatedAt     pgtype.Timestamptz `json:"calculated_at"`
+	HotRank          pgtype.Int2        `json:"hot_rank"`
+	RisingRank       pgtype.Int2        `json:"rising_rank"`
+	HotMisses        int16              `json:"hot_misses"`
+	RisingMisses     int16              `json:"rising_misses"`
+}
+
+type CategoryTrendingScoresStaging struct {
+	CategoryID       int32              `json:"category_id"`
+	AddonID          int32              `json:"addon_id"`
+	HotScore         pgtype.Numeric     `json:"hot_score"`
+	RisingScore      pgtype.Numeric     `json:"rising_score"`
+	DownloadVelocity pgtype.Numeric     `json:"download_velocity"`
+	SizeMultiplier   pgtype.Numeric     `json:"size_multiplier"`
+	FirstHotAt       pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt    pgtype.Timestamptz `json:"first_rising_at"`
+	CalculatedAt     pgtype.Timestamptz `json:"calculated_at"`
+	HotRank          pgtype.Int2        `json:"hot_rank"`
+	RisingRank       pgtype.Int2        `json:"rising_rank"`
+	HotMisses        int16              `json:"hot_misses"`
+	RisingMisses     int16              `json:"rising_misses"`
+}
+
+type ComebackEvent struct {
+	ID                  int64              `json:"id"`
+	AddonID             int32              `json:"addon_id"`
+	ReleaseDate         pgtype.Timestamptz `json:"release_date"`
+	PreviousReleaseDate pgtype.Timestamptz `json:"previous_release_date"`
+	DormantVelocity     pgtype.Numeric     `json:"dormant_velocity"`
+	RevivedVelocity     pgtype.Numeric     `json:"revived_velocity"`
+	DetectedAt          pgtype.Timestamptz `json:"detected_at"`
+}
+
+type HeatIndexDaily struct {
+	AddonID   int32       `json:"addon_id"`
+	Day       pgtype.Date `json:"day"`
+	HeatIndex int16       `json:"heat_index"`
+	RunID     int64       `json:"run_id"`
+}
+
+type HeatIndexPrevious struct {
+	AddonID         int32       `json:"addon_id"`
+	Day             pgtype.Date `json:"day"`
+	HeatIndex       int16       `json:"heat_index"`
+	RunID           int64       `json:"run_id"`
+	ReplacedByRunID int64       `json:"replaced_by_run_id"`
+}
+
+type JobLock struct {
+	JobName     string             `json:"job_name"`
+	Holder      string             `json:"holder"`
+	BackendPid  int32              `json:"backend_pid"`
+	AcquiredAt  pgtype.Timestamptz `json:"acquired_at"`
+	HeartbeatAt pgtype.Timestamptz `json:"heartbeat_at"`
+}
+
+type LeaderboardArchive struct {
+	Period      string             `json:"period"`
+	PeriodStart pgtype.Date        `json:"period_start"`
+	List        string             `json:"list"`
+	Rank        int16              `json:"rank"`
+	AddonID     int32              `json:"addon_id"`
+	Score       pgtype.Numeric     `json:"score"`
+	RecordedAt  pgtype.Timestamptz `json:"recorded_at"`
+	RunID       int64              `json:"run_id"`
+}
+
+type LeaderboardArchivePrevious struct {
+	Period          string             `json:"period"`
+	PeriodStart     pgtype.Date        `json:"period_start"`
+	List            string             `json:"list"`
+	Rank            int16              `json:"rank"`
+	AddonID         int32              `json:"addon_id"`
+	Score           pgtype.Numeric     `json:"score"`
+	RecordedAt      pgtype.Timestamptz `json:"recorded_at"`
+	RunID           int64              `json:"run_id"`
+	ReplacedByRunID int64              `json:"replaced_by_run_id"`
+}
+
+type Milestone struct {
+	ID         int64              `json:"id"`
+	AddonID    int32              `json:"addon_id"`
+	Metric     string             `json:"metric"`
+	Threshold  int64              `json:"threshold"`
+	CrossedAt  pgtype.Timestamptz `json:"crossed_at"`
+	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
+}
+
+type ScheduledJobRun struct {
+	JobName        string             `json:"job_name"`
+	LastStartedAt  pgtype.Timestamptz `json:"last_started_at"`
+	LastFinishedAt pgtype.Timestamptz `json:"last_finished_at"`
+	LastSuccessAt  pgtype.Timestamptz `json:"last_success_at"`
+	LastError      pgtype.Text        `json:"last_error"`
 }
 
 type Snapshot struct {
@@ -51,6 +213,41 @@ type Snapshot struct {
 	LatestFileDate pgtype.Timestamptz `json:"latest_file_date"`
 }
 
+type SyncRun struct {
+	ID               int64              `json:"id"`
+	StartedAt        pgtype.Timestamptz `json:"started_at"`
+	FinishedAt       pgtype.Timestamptz `json:"finished_at"`
+	FetchedCount     int32              `json:"fetched_count"`
+	SyncedCount      int32              `json:"synced_count"`
+	ErrorCount       int32              `json:"error_count"`
+	BaselineCount    pgtype.Int4        `json:"baseline_count"`
+	Quarantined      bool               `json:"quarantined"`
+	QuarantineReason pgtype.Text        `json:"quarantine_reason"`
+}
+
+type TrendingCalculationRun struct {
+	ID             int64              `json:"id"`
+	ParamsVersion  string             `json:"params_version"`
+	ParamsSource   string             `json:"params_source"`
+	StartedAt      pgtype.Timestamptz `json:"started_at"`
+	FinishedAt     pgtype.Timestamptz `json:"finished_at"`
+	ProcessedCount int32              `json:"processed_count"`
+}
+
+type TrendingGeneration struct {
+	Slot        string             `json:"slot"`
+	RunID       int64              `json:"run_id"`
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+}
+
+type TrendingParamSet struct {
+	Version   string             `json:"version"`
+	Params    []byte             `json:"params"`
+	IsActive  bool               `json:"is_active"`
+	IsShadow  bool               `json:"is_shadow"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type TrendingRankHistory struct {
 	AddonID    int32              `json:"addon_id"`
 	Category   string             `json:"category"`
@@ -72,4 +269,132 @@ type TrendingScore struct {
 	FirstHotAt            pgtype.Timestamptz `json:"first_hot_at"`
 	FirstRisingAt         pgtype.Timestamptz `json:"first_rising_at"`
 	CalculatedAt          pgtype.Timestamptz `json:"calculated_at"`
+	ParamsVersion         pgtype.Text        `json:"params_version"`
+	InputsHash            pgtype.Int8        `json:"inputs_hash"`
+	LovedScore            pgtype.Numeric     `json:"loved_score"`
+	FirstLovedAt          pgtype.Timestamptz `json:"first_loved_at"`
+	FreshScore            pgtype.Numeric     `json:"fresh_score"`
+	HotRank               pgtype.Int2        `json:"hot_rank"`
+	RisingRank            pgtype.Int2        `json:"rising_rank"`
+	LovedRank             pgtype.Int2        `json:"loved_rank"`
+	HotMisses             int16              `json:"hot_misses"`
+	RisingMisses          int16              `json:"rising_misses"`
+	LovedMisses           int16              `json:"loved_misses"`
+	HotRawRank            pgtype.Int2        `json:"hot_raw_rank"`
+	RisingRawRank         pgtype.Int2        `json:"rising_raw_rank"`
+	LovedRawRank          pgtype.Int2        `json:"loved_raw_rank"`
+}
+
+type TrendingScoresPrevious struct {
+	AddonID               int32              `json:"addon_id"`
+	HotScore              pgtype.Numeric     `json:"hot_score"`
+	RisingScore           pgtype.Numeric     `json:"rising_score"`
+	DownloadVelocity      pgtype.Numeric     `json:"download_velocity"`
+	ThumbsVelocity        pgtype.Numeric     `json:"thumbs_velocity"`
+	DownloadGrowthPct     pgtype.Numeric     `json:"download_growth_pct"`
+	ThumbsGrowthPct       pgtype.Numeric     `json:"thumbs_growth_pct"`
+	SizeMultiplier        pgtype.Numeric     `json:"size_multiplier"`
+	MaintenanceMultiplier pgtype.Numeric     `json:"maintenance_multiplier"`
+	FirstHotAt            pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt         pgtype.Timestamptz `json:"first_rising_at"`
+	CalculatedAt          pgtype.Timestamptz `json:"calculated_at"`
+	ParamsVersion         pgtype.Text        `json:"params_version"`
+	InputsHash            pgtype.Int8        `json:"inputs_hash"`
+	LovedScore            pgtype.Numeric     `json:"loved_score"`
+	FirstLovedAt          pgtype.Timestamptz `json:"first_loved_at"`
+	FreshScore            pgtype.Numeric     `json:"fresh_score"`
+	HotRank               pgtype.Int2        `json:"hot_rank"`
+	RisingRank            pgtype.Int2        `json:"rising_rank"`
+	LovedRank             pgtype.Int2        `json:"loved_rank"`
+	HotMisses             int16              `json:"hot_misses"`
+	RisingMisses          int16              `json:"rising_misses"`
+	LovedMisses           int16              `json:"loved_misses"`
+	HotRawRank            pgtype.Int2        `json:"hot_raw_rank"`
+	RisingRawRank         pgtype.Int2        `json:"rising_raw_rank"`
+	LovedRawRank          pgtype.Int2        `json:"loved_raw_rank"`
+}
+
+type TrendingScoresStaging struct {
+	AddonID               int32              `json:"addon_id"`
+	HotScore              pgtype.Numeric     `json:"hot_score"`
+	RisingScore           pgtype.Numeric     `json:"rising_score"`
+	DownloadVelocity      pgtype.Numeric     `json:"download_velocity"`
+	ThumbsVelocity        pgtype.Numeric     `json:"thumbs_velocity"`
+	DownloadGrowthPct     pgtype.Numeric     `json:"download_growth_pct"`
+	ThumbsGrowthPct       pgtype.Numeric     `json:"thumbs_growth_pct"`
+	SizeMultiplier        pgtype.Numeric     `json:"size_multiplier"`
+	MaintenanceMultiplier pgtype.Numeric     `json:"maintenance_multiplier"`
+	FirstHotAt            pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt         pgtype.Timestamptz `json:"first_rising_at"`
+	CalculatedAt          pgtype.Timestamptz `json:"calculated_at"`
+	ParamsVersion         pgtype.Text        `json:"params_version"`
+	InputsHash            pgtype.Int8        `json:"inputs_hash"`
+	LovedScore            pgtype.Numeric     `json:"loved_score"`
+	FirstLovedAt          pgtype.Timestamptz `json:"first_loved_at"`
+	FreshScore            pgtype.Numeric     `json:"fresh_score"`
+	HotRank               pgtype.Int2        `json:"hot_rank"`
+	RisingRank            pgtype.Int2        `json:"rising_rank"`
+	LovedRank             pgtype.Int2        `json:"loved_rank"`
+	HotMisses             int16              `json:"hot_misses"`
+	RisingMisses          int16              `json:"rising_misses"`
+	LovedMisses           int16              `json:"loved_misses"`
+	HotRawRank            pgtype.Int2        `json:"hot_raw_rank"`
+	RisingRawRank         pgtype.Int2        `json:"rising_raw_rank"`
+	LovedRawRank          pgtype.Int2        `json:"loved_raw_rank"`
+}
+
+type TrendingShadowComparison struct {
+	ID              int64              `json:"id"`
+	CalculatedAt    pgtype.Timestamptz `json:"calculated_at"`
+	LiveVersion     string             `json:"live_version"`
+	ShadowVersion   string             `json:"shadow_version"`
+	List            string             `json:"list"`
+	Overlap         pgtype.Numeric     `json:"overlap"`
+	RankCorrelation pgtype.Numeric     `json:"rank_correlation"`
+	LiveChurn       pgtype.Numeric     `json:"live_churn"`
+	ShadowChurn     pgtype.Numeric     `json:"shadow_churn"`
+}
+
+type TrendingShadowScore struct {
+	AddonID       int32              `json:"addon_id"`
+	ParamsVersion string             `json:"params_version"`
+	HotScore      pgtype.Numeric     `json:"hot_score"`
+	RisingScore   pgtype.Numeric     `json:"rising_score"`
+	LovedScore    pgtype.Numeric     `json:"loved_score"`
+	FreshScore    pgtype.Numeric     `json:"fresh_score"`
+	FirstHotAt    pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt pgtype.Timestamptz `json:"first_rising_at"`
+	FirstLovedAt  pgtype.Timestamptz `json:"first_loved_at"`
+	HotRank       pgtype.Int2        `json:"hot_rank"`
+	RisingRank    pgtype.Int2        `json:"rising_rank"`
+	LovedRank     pgtype.Int2        `json:"loved_rank"`
+	HotMisses     int16              `json:"hot_misses"`
+	RisingMisses  int16              `json:"rising_misses"`
+	LovedMisses   int16              `json:"loved_misses"`
+	HotRawRank    pgtype.Int2        `json:"hot_raw_rank"`
+	RisingRawRank pgtype.Int2        `json:"rising_raw_rank"`
+	LovedRawRank  pgtype.Int2        `json:"loved_raw_rank"`
+	CalculatedAt  pgtype.Timestamptz `json:"calculated_at"`
+}
+
+type TrendingStint struct {
+	ID         int64              `json:"id"`
+	AddonID    int32              `json:"addon_id"`
+	Category   string             `json:"category"`
+	EnteredAt  pgtype.Timestamptz `json:"entered_at"`
+	ExitedAt   pgtype.Timestamptz `json:"exited_at"`
+	LastSeenAt pgtype.Timestamptz `json:"last_seen_at"`
+	LastRank   int16              `json:"last_rank"`
+	BestRank   int16              `json:"best_rank"`
+	HoursAtTop pgtype.Numeric     `json:"hours_at_top"`
+}
+
+type TrendingStintsPrevious struct {
+	ID              int64              `json:"id"`
+	ReplacedByRunID int64              `json:"replaced_by_run_id"`
+	ExitedAt        pgtype.Timestamptz `json:"exited_at"`
+	LastSeenAt      pgtype.Timestamptz `json:"last_seen_at"`
+	LastRank        int16              `json:"last_rank"`
+	BestRank        int16              `json:"best_rank"`
+	HoursAtTop      pgtype.Numeric     `json:"hours_at_top"`
 }
@@ -11,37 +11,146 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const clearRisingAgeForDroppedAddons = `-- name: ClearRisingAgeForDroppedAddons :exec
-UPDATE trending_scores
-SET first_rising_at = NULL
-WHERE addon_id NOT IN (
-    SELECT addon_id FROM trending_scores
-    WHERE rising_score > 0
-    ORDER BY rising_score DESC
-    LIMIT 20
+const activateTrendingParamSet = `-- name: ActivateTrendingParamSet :execrows
+UPDATE trending_param_sets SET is_active = TRUE WHERE version = $1
+`
+
+func (q *Queries) ActivateTrendingParamSet(ctx context.Context, version string) (int64, error) {
+	result, err := q.db.Exec(ctx, activateTrendingParamSet, version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const carryForwardTrendingScores = `-- name: CarryForwardTrendingScores :exec
+INSERT INTO trending_scores_staging (
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+)
+SELECT
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_scores
+WHERE addon_id = ANY($1::integer[])
+`
+
+// Stage the live rows of addons that were skipped because their inputs didn't change
+func (q *Queries) CarryForwardTrendingScores(ctx context.Context, addonIds []int32) error {
+	_, err := q.db.Exec(ctx, carryForwardTrendingScores, addonIds)
+	return err
+}
+
+const claimJobLock = `-- name: ClaimJobLock :exec
+INSERT INTO job_locks (job_name, holder, backend_pid, acquired_at, heartbeat_at)
+VALUES ($1, $2, pg_backend_pid(), NOW(), NOW())
+ON CONFLICT (job_name) DO UPDATE SET
+    holder = EXCLUDED.holder,
+    backend_pid = EXCLUDED.backend_pid,
+    acquired_at = EXCLUDED.acquired_at,
+    heartbeat_at = EXCLUDED.heartbeat_at
+`
+
+type ClaimJobLockParams struct {
+	JobName string `json:"job_name"`
+	Holder  string `json:"holder"`
+}
+
+func (q *Queries) ClaimJobLock(ctx context.Context, arg ClaimJobLockParams) error {
+	_, err := q.db.Exec(ctx, claimJobLock, arg.JobName, arg.Holder)
+	return err
+}
+
+const clearShadowTrendingParamSets = `-- name: ClearShadowTrendingParamSets :exec
+UPDATE trending_param_sets SET is_shadow = FALSE WHERE is_shadow
+`
+
+func (q *Queries) ClearShadowTrendingParamSets(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, clearShadowTrendingParamSets)
+	return err
+}
+
+const clearStaleAddonHeat = `-- name: ClearStaleAddonHeat :execrows
+UPDATE addons
+SET heat_index = NULL, heat_sparkline = NULL
+WHERE heat_index IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM heat_index_daily d WHERE d.addon_id = addons.id AND d.day = $1)
+`
+
+// Clear the heat index of addons not scored on the given day
+func (q *Queries) ClearStaleAddonHeat(ctx context.Context, day pgtype.Date) (int64, error) {
+	result, err := q.db.Exec(ctx, clearStaleAddonHeat, day)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const closeStint = `-- name: CloseStint :exec
+UPDATE trending_stints
+SET exited_at = $1,
+    hours_at_top = hours_at_top + $2::numeric
+WHERE id = $3
+`
+
+type CloseStintParams struct {
+	ExitedAt pgtype.Timestamptz `json:"exited_at"`
+	TopHours pgtype.Numeric     `json:"top_hours"`
+	ID       int64              `json:"id"`
+}
+
+func (q *Queries) CloseStint(ctx context.Context, arg CloseStintParams) error {
+	_, err := q.db.Exec(ctx, closeStint, arg.ExitedAt, arg.TopHours, arg.ID)
+	return err
+}
+
+const copyCategoryTrendingScoresToPrevious = `-- name: CopyCategoryTrendingScoresToPrevious :exec
+INSERT INTO category_trending_scores_previous (
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
 )
+SELECT
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+FROM category_trending_scores
 `
 
-// Reset first_rising_at for addons that dropped out of rising list
-func (q *Queries) ClearRisingAgeForDroppedAddons(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, clearRisingAgeForDroppedAddons)
+func (q *Queries) CopyCategoryTrendingScoresToPrevious(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, copyCategoryTrendingScoresToPrevious)
 	return err
 }
 
-const clearTrendingAgeForDroppedAddons = `-- name: ClearTrendingAgeForDroppedAddons :exec
-UPDATE trending_scores
-SET first_hot_at = NULL
-WHERE addon_id NOT IN (
-    SELECT addon_id FROM trending_scores
-    WHERE hot_score > 0
-    ORDER BY hot_score DESC
-    LIMIT 20
+const copyTrendingScoresToPrevious = `-- name: CopyTrendingScoresToPrevious :exec
+INSERT INTO trending_scores_previous (
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
 )
+SELECT
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_scores
 `
 
-// Reset first_hot_at for addons that dropped out of hot list
-func (q *Queries) ClearTrendingAgeForDroppedAddons(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, clearTrendingAgeForDroppedAddons)
+func (q *Queries) CopyTrendingScoresToPrevious(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, copyTrendingScoresToPrevious)
 	return err
 }
 
@@ -56,6 +165,22 @@ func (q *Queries) CountActiveAddons(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countAddonRecentFileUpdates = `-- name: CountAddonRecentFileUpdates :one
+SELECT COUNT(DISTINCT DATE(latest_file_date))::int AS update_count
+FROM snapshots
+WHERE addon_id = $1
+  AND recorded_at >= NOW() - INTERVAL '90 days'
+  AND latest_file_date IS NOT NULL
+`
+
+// CountAllRecentFileUpdates for a single addon
+func (q *Queries) CountAddonRecentFileUpdates(ctx context.Context, addonID int32) (int32, error) {
+	row := q.db.QueryRow(ctx, countAddonRecentFileUpdates, addonID)
+	var update_count int32
+	err := row.Scan(&update_count)
+	return update_count, err
+}
+
 const countAddons = `-- name: CountAddons :one
 SELECT COUNT(*) FROM addons WHERE status = 'active'
 `
@@ -80,6 +205,22 @@ func (q *Queries) CountAddonsByCategory(ctx context.Context, dollar_1 int32) (in
 	return count, err
 }
 
+const countAddonsProjectedToCross = `-- name: CountAddonsProjectedToCross :one
+SELECT COUNT(*)
+FROM addon_forecasts f
+JOIN addons a ON a.id = f.addon_id
+WHERE a.status = 'active'
+  AND a.download_count < $1::bigint
+  AND f.projected_7d >= $1::bigint
+`
+
+func (q *Queries) CountAddonsProjectedToCross(ctx context.Context, downloads int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countAddonsProjectedToCross, downloads)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countAllRecentFileUpdates = `-- name: CountAllRecentFileUpdates :many
 SELECT
     addon_id,
@@ -116,17 +257,262 @@ func (q *Queries) CountAllRecentFileUpdates(ctx context.Context) ([]CountAllRece
 	return items, nil
 }
 
+const countAllRecentFileUpdatesAsOf = `-- name: CountAllRecentFileUpdatesAsOf :many
+SELECT
+    addon_id,
+    COUNT(DISTINCT DATE(latest_file_date))::int AS update_count
+FROM snapshots
+WHERE recorded_at <= $1::timestamptz
+  AND recorded_at >= $1::timestamptz - INTERVAL '90 days'
+  AND latest_file_date IS NOT NULL
+GROUP BY addon_id
+`
+
+type CountAllRecentFileUpdatesAsOfRow struct {
+	AddonID     int32 `json:"addon_id"`
+	UpdateCount int32 `json:"update_count"`
+}
+
+// CountAllRecentFileUpdates as it would have returned at as_of
+func (q *Queries) CountAllRecentFileUpdatesAsOf(ctx context.Context, asOf pgtype.Timestamptz) ([]CountAllRecentFileUpdatesAsOfRow, error) {
+	rows, err := q.db.Query(ctx, countAllRecentFileUpdatesAsOf, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountAllRecentFileUpdatesAsOfRow{}
+	for rows.Next() {
+		var i CountAllRecentFileUpdatesAsOfRow
+		if err := rows.Scan(&i.AddonID, &i.UpdateCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countCategoryHotAddons = `-- name: CountCategoryHotAddons :one
+SELECT COUNT(*)
+FROM addons a
+JOIN category_trending_scores t ON a.id = t.addon_id
+WHERE t.category_id = $1
+  AND a.status = 'active'
+  AND a.download_count >= $2::bigint
+  AND t.hot_score > 0
+`
+
+type CountCategoryHotAddonsParams struct {
+	CategoryID   int32 `json:"category_id"`
+	MinDownloads int64 `json:"min_downloads"`
+}
+
+func (q *Queries) CountCategoryHotAddons(ctx context.Context, arg CountCategoryHotAddonsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countCategoryHotAddons, arg.CategoryID, arg.MinDownloads)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countCategoryRisingAddons = `-- name: CountCategoryRisingAddons :one
+SELECT COUNT(*)
+FROM addons a
+JOIN category_trending_scores t ON a.id = t.addon_id
+WHERE t.category_id = $1
+  AND a.status = 'active'
+  AND a.download_count >= $2::bigint
+  AND a.download_count <= $3::bigint
+  AND t.rising_score > 0
+  AND a.id NOT IN (
+      SELECT addon_id FROM category_trending_scores
+      WHERE category_id = $1
+        AND hot_score > 0
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT $4
+  )
+`
+
+type CountCategoryRisingAddonsParams struct {
+	CategoryID   int32 `json:"category_id"`
+	MinDownloads int64 `json:"min_downloads"`
+	MaxDownloads int64 `json:"max_downloads"`
+	HotListSize  int32 `json:"hot_list_size"`
+}
+
+func (q *Queries) CountCategoryRisingAddons(ctx context.Context, arg CountCategoryRisingAddonsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countCategoryRisingAddons,
+		arg.CategoryID,
+		arg.MinDownloads,
+		arg.MaxDownloads,
+		arg.HotListSize,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countComebacks = `-- name: CountComebacks :one
+SELECT COUNT(*)
+FROM comeback_events e
+JOIN addons a ON a.id = e.addon_id
+WHERE a.status = 'active'
+  AND e.detected_at >= $1::timestamptz
+`
+
+func (q *Queries) CountComebacks(ctx context.Context, since pgtype.Timestamptz) (int64, error) {
+	row := q.db.QueryRow(ctx, countComebacks, since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFreshAddons = `-- name: CountFreshAddons :one
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND t.fresh_score > 0
+  AND a.created_at >= $2::timestamptz
+`
+
+type CountFreshAddonsParams struct {
+	MinDownloads int64              `json:"min_downloads"`
+	CreatedAfter pgtype.Timestamptz `json:"created_after"`
+}
+
+func (q *Queries) CountFreshAddons(ctx context.Context, arg CountFreshAddonsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countFreshAddons, arg.MinDownloads, arg.CreatedAfter)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFreshAddonsAbove = `-- name: CountFreshAddonsAbove :one
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND t.fresh_score > $2::numeric
+`
+
+type CountFreshAddonsAboveParams struct {
+	MinDownloads int64          `json:"min_downloads"`
+	Score        pgtype.Numeric `json:"score"`
+}
+
+// Fresh list entries ranked above the given score
+func (q *Queries) CountFreshAddonsAbove(ctx context.Context, arg CountFreshAddonsAboveParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countFreshAddonsAbove, arg.MinDownloads, arg.Score)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countHotAddons = `-- name: CountHotAddons :one
 SELECT COUNT(*)
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 500
+  AND a.download_count >= $1::bigint
   AND t.hot_score > 0
 `
 
-func (q *Queries) CountHotAddons(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, countHotAddons)
+func (q *Queries) CountHotAddons(ctx context.Context, minDownloads int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countHotAddons, minDownloads)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countHotAddonsAbove = `-- name: CountHotAddonsAbove :one
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND (t.hot_rank IS NOT NULL OR t.hot_score > $2::numeric)
+`
+
+type CountHotAddonsAboveParams struct {
+	MinDownloads int64          `json:"min_downloads"`
+	Score        pgtype.Numeric `json:"score"`
+}
+
+// Hot list entries ranked above the given score
+func (q *Queries) CountHotAddonsAbove(ctx context.Context, arg CountHotAddonsAboveParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countHotAddonsAbove, arg.MinDownloads, arg.Score)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countLongestStints = `-- name: CountLongestStints :one
+SELECT COUNT(DISTINCT s.addon_id)
+FROM trending_stints s
+JOIN addons a ON a.id = s.addon_id
+WHERE a.status = 'active'
+  AND s.category = $1
+`
+
+func (q *Queries) CountLongestStints(ctx context.Context, category string) (int64, error) {
+	row := q.db.QueryRow(ctx, countLongestStints, category)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countLovedAddons = `-- name: CountLovedAddons :one
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND t.loved_score > 0
+`
+
+func (q *Queries) CountLovedAddons(ctx context.Context, minDownloads int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countLovedAddons, minDownloads)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countLovedAddonsAbove = `-- name: CountLovedAddonsAbove :one
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND (t.loved_rank IS NOT NULL OR t.loved_score > $2::numeric)
+`
+
+type CountLovedAddonsAboveParams struct {
+	MinDownloads int64          `json:"min_downloads"`
+	Score        pgtype.Numeric `json:"score"`
+}
+
+// Loved list entries ranked above the given score
+func (q *Queries) CountLovedAddonsAbove(ctx context.Context, arg CountLovedAddonsAboveParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countLovedAddonsAbove, arg.MinDownloads, arg.Score)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countMilestones = `-- name: CountMilestones :one
+SELECT COUNT(*)
+FROM milestones m
+JOIN addons a ON a.id = m.addon_id
+WHERE a.status = 'active'
+  AND ($1::text = '' OR m.metric = $1::text)
+`
+
+func (q *Queries) CountMilestones(ctx context.Context, metric string) (int64, error) {
+	row := q.db.QueryRow(ctx, countMilestones, metric)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -145,6 +531,21 @@ func (q *Queries) CountOldSnapshots(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countReactivatedAddons = `-- name: CountReactivatedAddons :one
+SELECT COUNT(*)
+FROM addon_status_events e
+JOIN addons a ON a.id = e.addon_id
+WHERE e.to_status = 'active'
+  AND a.status = 'active'
+`
+
+func (q *Queries) CountReactivatedAddons(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countReactivatedAddons)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countRecentFileUpdates = `-- name: CountRecentFileUpdates :one
 SELECT COUNT(DISTINCT DATE(latest_file_date))
 FROM snapshots
@@ -166,24 +567,77 @@ func (q *Queries) CountRecentFileUpdates(ctx context.Context, arg CountRecentFil
 	return count, err
 }
 
+const countRemovedAddons = `-- name: CountRemovedAddons :one
+SELECT COUNT(*) FROM addons WHERE status = 'inactive'
+`
+
+func (q *Queries) CountRemovedAddons(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countRemovedAddons)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countRisingAddons = `-- name: CountRisingAddons :one
 SELECT COUNT(*)
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 50
-  AND a.download_count <= 10000
+  AND a.download_count >= $1::bigint
+  AND a.download_count <= $2::bigint
   AND t.rising_score > 0
   AND a.id NOT IN (
       SELECT addon_id FROM trending_scores
       WHERE hot_score > 0
-      ORDER BY hot_score DESC
-      LIMIT 20
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT $3
+  )
+`
+
+type CountRisingAddonsParams struct {
+	MinDownloads int64 `json:"min_downloads"`
+	MaxDownloads int64 `json:"max_downloads"`
+	HotListSize  int32 `json:"hot_list_size"`
+}
+
+func (q *Queries) CountRisingAddons(ctx context.Context, arg CountRisingAddonsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRisingAddons, arg.MinDownloads, arg.MaxDownloads, arg.HotListSize)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countRisingAddonsAbove = `-- name: CountRisingAddonsAbove :one
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND a.download_count <= $2::bigint
+  AND (t.rising_rank IS NOT NULL OR t.rising_score > $3::numeric)
+  AND a.id NOT IN (
+      SELECT addon_id FROM trending_scores
+      WHERE hot_score > 0
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT $4
   )
 `
 
-func (q *Queries) CountRisingAddons(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, countRisingAddons)
+type CountRisingAddonsAboveParams struct {
+	MinDownloads int64          `json:"min_downloads"`
+	MaxDownloads int64          `json:"max_downloads"`
+	Score        pgtype.Numeric `json:"score"`
+	HotListSize  int32          `json:"hot_list_size"`
+}
+
+// Rising list entries ranked above the given score
+func (q *Queries) CountRisingAddonsAbove(ctx context.Context, arg CountRisingAddonsAboveParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRisingAddonsAbove,
+		arg.MinDownloads,
+		arg.MaxDownloads,
+		arg.Score,
+		arg.HotListSize,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -239,22 +693,166 @@ func (q *Queries) CreateSnapshot(ctx context.Context, arg CreateSnapshotParams)
 	return err
 }
 
-const deleteOldRankHistory = `-- name: DeleteOldRankHistory :execrows
-DELETE FROM trending_rank_history
-WHERE recorded_at < NOW() - INTERVAL '8 days'
+const deactivateTrendingParamSets = `-- name: DeactivateTrendingParamSets :exec
+UPDATE trending_param_sets SET is_active = FALSE WHERE is_active
 `
 
-// Delete rank history older than 8 days (1-day buffer for 7-day lookback queries)
-func (q *Queries) DeleteOldRankHistory(ctx context.Context) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteOldRankHistory)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+func (q *Queries) DeactivateTrendingParamSets(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deactivateTrendingParamSets)
+	return err
 }
 
-const deleteOldSnapshotsBatch = `-- name: DeleteOldSnapshotsBatch :execrows
-DELETE FROM snapshots
+const deleteAddonForecasts = `-- name: DeleteAddonForecasts :exec
+DELETE FROM addon_forecasts
+`
+
+func (q *Queries) DeleteAddonForecasts(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteAddonForecasts)
+	return err
+}
+
+const deleteArchivedLeaderboard = `-- name: DeleteArchivedLeaderboard :exec
+DELETE FROM leaderboard_archive
+WHERE period = $1 AND period_start = $2 AND list = $3
+`
+
+type DeleteArchivedLeaderboardParams struct {
+	Period      string      `json:"period"`
+	PeriodStart pgtype.Date `json:"period_start"`
+	List        string      `json:"list"`
+}
+
+// Clear an archived list before archiving its replacement
+func (q *Queries) DeleteArchivedLeaderboard(ctx context.Context, arg DeleteArchivedLeaderboardParams) error {
+	_, err := q.db.Exec(ctx, deleteArchivedLeaderboard, arg.Period, arg.PeriodStart, arg.List)
+	return err
+}
+
+const deleteArchivedLeaderboardsOfRun = `-- name: DeleteArchivedLeaderboardsOfRun :execrows
+DELETE FROM leaderboard_archive WHERE run_id = $1
+`
+
+func (q *Queries) DeleteArchivedLeaderboardsOfRun(ctx context.Context, runID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteArchivedLeaderboardsOfRun, runID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteCategoryRankHistorySince = `-- name: DeleteCategoryRankHistorySince :execrows
+DELETE FROM category_rank_history
+WHERE recorded_at >= $1
+`
+
+func (q *Queries) DeleteCategoryRankHistorySince(ctx context.Context, since pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCategoryRankHistorySince, since)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteCategoryTrendingScores = `-- name: DeleteCategoryTrendingScores :exec
+DELETE FROM category_trending_scores
+`
+
+func (q *Queries) DeleteCategoryTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteCategoryTrendingScores)
+	return err
+}
+
+const deleteHeatIndexDay = `-- name: DeleteHeatIndexDay :exec
+DELETE FROM heat_index_daily WHERE day = $1
+`
+
+func (q *Queries) DeleteHeatIndexDay(ctx context.Context, day pgtype.Date) error {
+	_, err := q.db.Exec(ctx, deleteHeatIndexDay, day)
+	return err
+}
+
+const deleteHeatIndexOfRun = `-- name: DeleteHeatIndexOfRun :execrows
+DELETE FROM heat_index_daily WHERE run_id = $1
+`
+
+func (q *Queries) DeleteHeatIndexOfRun(ctx context.Context, runID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteHeatIndexOfRun, runID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteJobLock = `-- name: DeleteJobLock :exec
+DELETE FROM job_locks WHERE job_name = $1 AND holder = $2
+`
+
+type DeleteJobLockParams struct {
+	JobName string `json:"job_name"`
+	Holder  string `json:"holder"`
+}
+
+func (q *Queries) DeleteJobLock(ctx context.Context, arg DeleteJobLockParams) error {
+	_, err := q.db.Exec(ctx, deleteJobLock, arg.JobName, arg.Holder)
+	return err
+}
+
+const deleteOldCategoryRankHistory = `-- name: DeleteOldCategoryRankHistory :execrows
+DELETE FROM category_rank_history
+WHERE recorded_at < NOW() - INTERVAL '8 days'
+`
+
+// Same 8-day retention as trending_rank_history
+func (q *Queries) DeleteOldCategoryRankHistory(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOldCategoryRankHistory)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOldHeatIndex = `-- name: DeleteOldHeatIndex :execrows
+DELETE FROM heat_index_daily WHERE day < (NOW() AT TIME ZONE 'UTC')::date - 7
+`
+
+// Heat index days are UTC dates
+func (q *Queries) DeleteOldHeatIndex(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOldHeatIndex)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOldRankHistory = `-- name: DeleteOldRankHistory :execrows
+DELETE FROM trending_rank_history
+WHERE recorded_at < NOW() - INTERVAL '8 days'
+`
+
+// Delete rank history older than 8 days (1-day buffer for 7-day lookback queries)
+func (q *Queries) DeleteOldRankHistory(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOldRankHistory)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOldShadowComparisons = `-- name: DeleteOldShadowComparisons :execrows
+DELETE FROM trending_shadow_comparisons
+WHERE calculated_at < NOW() - INTERVAL '90 days'
+`
+
+func (q *Queries) DeleteOldShadowComparisons(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOldShadowComparisons)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOldSnapshotsBatch = `-- name: DeleteOldSnapshotsBatch :execrows
+DELETE FROM snapshots
 WHERE id IN (
     SELECT id FROM snapshots
     WHERE recorded_at < NOW() - INTERVAL '95 days'
@@ -273,8 +871,169 @@ func (q *Queries) DeleteOldSnapshotsBatch(ctx context.Context, limit int32) (int
 	return result.RowsAffected(), nil
 }
 
+const deletePreviousCategoryTrendingScores = `-- name: DeletePreviousCategoryTrendingScores :exec
+DELETE FROM category_trending_scores_previous
+`
+
+func (q *Queries) DeletePreviousCategoryTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deletePreviousCategoryTrendingScores)
+	return err
+}
+
+const deletePreviousHeatIndex = `-- name: DeletePreviousHeatIndex :exec
+DELETE FROM heat_index_previous
+`
+
+func (q *Queries) DeletePreviousHeatIndex(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deletePreviousHeatIndex)
+	return err
+}
+
+const deletePreviousLeaderboards = `-- name: DeletePreviousLeaderboards :exec
+DELETE FROM leaderboard_archive_previous
+`
+
+func (q *Queries) DeletePreviousLeaderboards(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deletePreviousLeaderboards)
+	return err
+}
+
+const deletePreviousStints = `-- name: DeletePreviousStints :exec
+DELETE FROM trending_stints_previous
+`
+
+func (q *Queries) DeletePreviousStints(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deletePreviousStints)
+	return err
+}
+
+const deletePreviousTrendingScores = `-- name: DeletePreviousTrendingScores :exec
+DELETE FROM trending_scores_previous
+`
+
+func (q *Queries) DeletePreviousTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deletePreviousTrendingScores)
+	return err
+}
+
+const deleteRankHistorySince = `-- name: DeleteRankHistorySince :execrows
+DELETE FROM trending_rank_history
+WHERE recorded_at >= $1
+`
+
+// Drop ranks recorded from a generation that was rolled back
+func (q *Queries) DeleteRankHistorySince(ctx context.Context, since pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteRankHistorySince, since)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteShadowScores = `-- name: DeleteShadowScores :exec
+DELETE FROM trending_shadow_scores
+`
+
+func (q *Queries) DeleteShadowScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteShadowScores)
+	return err
+}
+
+const deleteStagedCategoryTrendingScores = `-- name: DeleteStagedCategoryTrendingScores :exec
+DELETE FROM category_trending_scores_staging
+`
+
+func (q *Queries) DeleteStagedCategoryTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteStagedCategoryTrendingScores)
+	return err
+}
+
+const deleteStagedTrendingScores = `-- name: DeleteStagedTrendingScores :exec
+DELETE FROM trending_scores_staging
+`
+
+func (q *Queries) DeleteStagedTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteStagedTrendingScores)
+	return err
+}
+
+const deleteStintsEnteredSince = `-- name: DeleteStintsEnteredSince :execrows
+DELETE FROM trending_stints
+WHERE entered_at >= $1
+`
+
+// Drop stints opened by a generation that was rolled back
+func (q *Queries) DeleteStintsEnteredSince(ctx context.Context, since pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteStintsEnteredSince, since)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTrendingGeneration = `-- name: DeleteTrendingGeneration :exec
+DELETE FROM trending_generations WHERE slot = $1
+`
+
+func (q *Queries) DeleteTrendingGeneration(ctx context.Context, slot string) error {
+	_, err := q.db.Exec(ctx, deleteTrendingGeneration, slot)
+	return err
+}
+
+const deleteTrendingScores = `-- name: DeleteTrendingScores :exec
+DELETE FROM trending_scores
+`
+
+func (q *Queries) DeleteTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteTrendingScores)
+	return err
+}
+
+const extendStint = `-- name: ExtendStint :exec
+UPDATE trending_stints
+SET last_rank = $1,
+    best_rank = LEAST(best_rank, $1),
+    last_seen_at = $2,
+    hours_at_top = hours_at_top + $3::numeric
+WHERE id = $4
+`
+
+type ExtendStintParams struct {
+	Rank     int16              `json:"rank"`
+	SeenAt   pgtype.Timestamptz `json:"seen_at"`
+	TopHours pgtype.Numeric     `json:"top_hours"`
+	ID       int64              `json:"id"`
+}
+
+func (q *Queries) ExtendStint(ctx context.Context, arg ExtendStintParams) error {
+	_, err := q.db.Exec(ctx, extendStint,
+		arg.Rank,
+		arg.SeenAt,
+		arg.TopHours,
+		arg.ID,
+	)
+	return err
+}
+
+const getActiveTrendingParamSet = `-- name: GetActiveTrendingParamSet :one
+SELECT version, params, is_active, is_shadow, created_at FROM trending_param_sets WHERE is_active
+`
+
+func (q *Queries) GetActiveTrendingParamSet(ctx context.Context) (TrendingParamSet, error) {
+	row := q.db.QueryRow(ctx, getActiveTrendingParamSet)
+	var i TrendingParamSet
+	err := row.Scan(
+		&i.Version,
+		&i.Params,
+		&i.IsActive,
+		&i.IsShadow,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getAddonByID = `-- name: GetAddonByID :one
-SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date FROM addons WHERE id = $1
+SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date, comeback_at, heat_index, heat_sparkline FROM addons WHERE id = $1
 `
 
 func (q *Queries) GetAddonByID(ctx context.Context, id int32) (Addon, error) {
@@ -302,12 +1061,15 @@ func (q *Queries) GetAddonByID(ctx context.Context, id int32) (Addon, error) {
 		&i.PopularityRank,
 		&i.Rating,
 		&i.LatestFileDate,
+		&i.ComebackAt,
+		&i.HeatIndex,
+		&i.HeatSparkline,
 	)
 	return i, err
 }
 
 const getAddonBySlug = `-- name: GetAddonBySlug :one
-SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date FROM addons WHERE slug = $1 AND status = 'active'
+SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date, comeback_at, heat_index, heat_sparkline FROM addons WHERE slug = $1 AND status = 'active'
 `
 
 func (q *Queries) GetAddonBySlug(ctx context.Context, slug string) (Addon, error) {
@@ -335,6 +1097,32 @@ func (q *Queries) GetAddonBySlug(ctx context.Context, slug string) (Addon, error
 		&i.PopularityRank,
 		&i.Rating,
 		&i.LatestFileDate,
+		&i.ComebackAt,
+		&i.HeatIndex,
+		&i.HeatSparkline,
+	)
+	return i, err
+}
+
+const getAddonForecast = `-- name: GetAddonForecast :one
+SELECT addon_id, forecast_from, download_count, history_hours, projected_24h, low_24h, high_24h, projected_7d, low_7d, high_7d, calculated_at FROM addon_forecasts WHERE addon_id = $1
+`
+
+func (q *Queries) GetAddonForecast(ctx context.Context, addonID int32) (AddonForecast, error) {
+	row := q.db.QueryRow(ctx, getAddonForecast, addonID)
+	var i AddonForecast
+	err := row.Scan(
+		&i.AddonID,
+		&i.ForecastFrom,
+		&i.DownloadCount,
+		&i.HistoryHours,
+		&i.Projected24h,
+		&i.Low24h,
+		&i.High24h,
+		&i.Projected7d,
+		&i.Low7d,
+		&i.High7d,
+		&i.CalculatedAt,
 	)
 	return i, err
 }
@@ -351,6 +1139,109 @@ func (q *Queries) GetAddonLatestFileDate(ctx context.Context, id int32) (pgtype.
 	return latest_file_date, err
 }
 
+const getAddonSnapshotStats = `-- name: GetAddonSnapshotStats :one
+WITH stats_24h AS (
+    SELECT
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        COUNT(*)::int AS snapshot_count,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope,
+        regr_r2(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_fit,
+        regr_r2(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_fit,
+        EXTRACT(EPOCH FROM MAX(recorded_at) - MIN(recorded_at))::float8 / 3600 AS span_hours
+    FROM snapshots
+    WHERE addon_id = $1
+      AND recorded_at >= NOW() - INTERVAL '24 hours'
+),
+stats_7d AS (
+    SELECT
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        MIN(download_count)::bigint AS min_downloads,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope
+    FROM snapshots
+    WHERE addon_id = $1
+      AND recorded_at >= NOW() - INTERVAL '7 days'
+)
+SELECT
+    a.id AS addon_id,
+    a.download_count,
+    a.thumbs_up_count,
+    a.latest_file_date,
+    a.created_at,
+    a.author_id,
+    a.primary_category_id,
+    s24.download_change AS download_change_24h,
+    s24.thumbs_change AS thumbs_change_24h,
+    s24.snapshot_count AS snapshot_count_24h,
+    s7.download_change AS download_change_7d,
+    s7.thumbs_change AS thumbs_change_7d,
+    COALESCE(s7.min_downloads, a.download_count)::bigint AS min_downloads_7d,
+    COALESCE(s24.download_slope, 0)::float8 AS download_slope_24h,
+    COALESCE(s24.thumbs_slope, 0)::float8 AS thumbs_slope_24h,
+    COALESCE(s24.download_fit, 0)::float8 AS download_fit_24h,
+    COALESCE(s24.thumbs_fit, 0)::float8 AS thumbs_fit_24h,
+    COALESCE(s24.span_hours, 0)::float8 AS span_hours_24h,
+    COALESCE(s7.download_slope, 0)::float8 AS download_slope_7d,
+    COALESCE(s7.thumbs_slope, 0)::float8 AS thumbs_slope_7d
+FROM addons a, stats_24h s24, stats_7d s7
+WHERE a.id = $1
+`
+
+type GetAddonSnapshotStatsRow struct {
+	AddonID           int32              `json:"addon_id"`
+	DownloadCount     pgtype.Int8        `json:"download_count"`
+	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
+	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	AuthorID          pgtype.Int4        `json:"author_id"`
+	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
+	DownloadChange24h int64              `json:"download_change_24h"`
+	ThumbsChange24h   int32              `json:"thumbs_change_24h"`
+	SnapshotCount24h  int32              `json:"snapshot_count_24h"`
+	DownloadChange7d  int64              `json:"download_change_7d"`
+	ThumbsChange7d    int32              `json:"thumbs_change_7d"`
+	MinDownloads7d    int64              `json:"min_downloads_7d"`
+	DownloadSlope24h  float64            `json:"download_slope_24h"`
+	ThumbsSlope24h    float64            `json:"thumbs_slope_24h"`
+	DownloadFit24h    float64            `json:"download_fit_24h"`
+	ThumbsFit24h      float64            `json:"thumbs_fit_24h"`
+	SpanHours24h      float64            `json:"span_hours_24h"`
+	DownloadSlope7d   float64            `json:"download_slope_7d"`
+	ThumbsSlope7d     float64            `json:"thumbs_slope_7d"`
+}
+
+// GetAllSnapshotStats for a single addon, whatever its status
+func (q *Queries) GetAddonSnapshotStats(ctx context.Context, addonID int32) (GetAddonSnapshotStatsRow, error) {
+	row := q.db.QueryRow(ctx, getAddonSnapshotStats, addonID)
+	var i GetAddonSnapshotStatsRow
+	err := row.Scan(
+		&i.AddonID,
+		&i.DownloadCount,
+		&i.ThumbsUpCount,
+		&i.LatestFileDate,
+		&i.CreatedAt,
+		&i.AuthorID,
+		&i.PrimaryCategoryID,
+		&i.DownloadChange24h,
+		&i.ThumbsChange24h,
+		&i.SnapshotCount24h,
+		&i.DownloadChange7d,
+		&i.ThumbsChange7d,
+		&i.MinDownloads7d,
+		&i.DownloadSlope24h,
+		&i.ThumbsSlope24h,
+		&i.DownloadFit24h,
+		&i.ThumbsFit24h,
+		&i.SpanHours24h,
+		&i.DownloadSlope7d,
+		&i.ThumbsSlope7d,
+	)
+	return i, err
+}
+
 const getAddonSnapshots = `-- name: GetAddonSnapshots :many
 SELECT recorded_at, download_count, thumbs_up_count, popularity_rank
 FROM snapshots
@@ -420,27 +1311,141 @@ func (q *Queries) GetAllAddonIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
-const getAllSnapshotStats = `-- name: GetAllSnapshotStats :many
-WITH stats_24h AS (
-    SELECT
-        addon_id,
-        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
-        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
-        COUNT(*)::int AS snapshot_count,
-        MIN(download_count)::bigint AS min_downloads
-    FROM snapshots
-    WHERE recorded_at >= NOW() - INTERVAL '24 hours'
-    GROUP BY addon_id
-),
-stats_7d AS (
-    SELECT
-        addon_id,
-        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
-        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
-        MIN(download_count)::bigint AS min_downloads
-    FROM snapshots
-    WHERE recorded_at >= NOW() - INTERVAL '7 days'
-    GROUP BY addon_id
+const getAllCategoryTrendingScores = `-- name: GetAllCategoryTrendingScores :many
+SELECT category_id, addon_id, first_hot_at, first_rising_at, hot_rank, rising_rank, hot_misses, rising_misses
+FROM category_trending_scores
+`
+
+type GetAllCategoryTrendingScoresRow struct {
+	CategoryID    int32              `json:"category_id"`
+	AddonID       int32              `json:"addon_id"`
+	FirstHotAt    pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt pgtype.Timestamptz `json:"first_rising_at"`
+	HotRank       pgtype.Int2        `json:"hot_rank"`
+	RisingRank    pgtype.Int2        `json:"rising_rank"`
+	HotMisses     int16              `json:"hot_misses"`
+	RisingMisses  int16              `json:"rising_misses"`
+}
+
+// Bulk fetch list ages and places for every category
+func (q *Queries) GetAllCategoryTrendingScores(ctx context.Context) ([]GetAllCategoryTrendingScoresRow, error) {
+	rows, err := q.db.Query(ctx, getAllCategoryTrendingScores)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAllCategoryTrendingScoresRow{}
+	for rows.Next() {
+		var i GetAllCategoryTrendingScoresRow
+		if err := rows.Scan(
+			&i.CategoryID,
+			&i.AddonID,
+			&i.FirstHotAt,
+			&i.FirstRisingAt,
+			&i.HotRank,
+			&i.RisingRank,
+			&i.HotMisses,
+			&i.RisingMisses,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllShadowScores = `-- name: GetAllShadowScores :many
+SELECT
+    addon_id, params_version, first_hot_at, first_rising_at, first_loved_at,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_shadow_scores
+`
+
+type GetAllShadowScoresRow struct {
+	AddonID       int32              `json:"addon_id"`
+	ParamsVersion string             `json:"params_version"`
+	FirstHotAt    pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt pgtype.Timestamptz `json:"first_rising_at"`
+	FirstLovedAt  pgtype.Timestamptz `json:"first_loved_at"`
+	HotRank       pgtype.Int2        `json:"hot_rank"`
+	RisingRank    pgtype.Int2        `json:"rising_rank"`
+	LovedRank     pgtype.Int2        `json:"loved_rank"`
+	HotMisses     int16              `json:"hot_misses"`
+	RisingMisses  int16              `json:"rising_misses"`
+	LovedMisses   int16              `json:"loved_misses"`
+	HotRawRank    pgtype.Int2        `json:"hot_raw_rank"`
+	RisingRawRank pgtype.Int2        `json:"rising_raw_rank"`
+	LovedRawRank  pgtype.Int2        `json:"loved_raw_rank"`
+}
+
+// List state of the shadow parameter set's last calculation
+func (q *Queries) GetAllShadowScores(ctx context.Context) ([]GetAllShadowScoresRow, error) {
+	rows, err := q.db.Query(ctx, getAllShadowScores)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAllShadowScoresRow{}
+	for rows.Next() {
+		var i GetAllShadowScoresRow
+		if err := rows.Scan(
+			&i.AddonID,
+			&i.ParamsVersion,
+			&i.FirstHotAt,
+			&i.FirstRisingAt,
+			&i.FirstLovedAt,
+			&i.HotRank,
+			&i.RisingRank,
+			&i.LovedRank,
+			&i.HotMisses,
+			&i.RisingMisses,
+			&i.LovedMisses,
+			&i.HotRawRank,
+			&i.RisingRawRank,
+			&i.LovedRawRank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllSnapshotStats = `-- name: GetAllSnapshotStats :many
+WITH stats_24h AS (
+    SELECT
+        addon_id,
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        COUNT(*)::int AS snapshot_count,
+        MIN(download_count)::bigint AS min_downloads,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope,
+        regr_r2(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_fit,
+        regr_r2(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_fit,
+        EXTRACT(EPOCH FROM MAX(recorded_at) - MIN(recorded_at))::float8 / 3600 AS span_hours
+    FROM snapshots
+    WHERE recorded_at >= NOW() - INTERVAL '24 hours'
+    GROUP BY addon_id
+),
+stats_7d AS (
+    SELECT
+        addon_id,
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        MIN(download_count)::bigint AS min_downloads,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope
+    FROM snapshots
+    WHERE recorded_at >= NOW() - INTERVAL '7 days'
+    GROUP BY addon_id
 )
 SELECT
     a.id AS addon_id,
@@ -448,12 +1453,21 @@ SELECT
     a.thumbs_up_count,
     a.latest_file_date,
     a.created_at,
+    a.author_id,
+    a.primary_category_id,
     COALESCE(s24.download_change, 0) AS download_change_24h,
     COALESCE(s24.thumbs_change, 0) AS thumbs_change_24h,
     COALESCE(s24.snapshot_count, 0) AS snapshot_count_24h,
     COALESCE(s7.download_change, 0) AS download_change_7d,
     COALESCE(s7.thumbs_change, 0) AS thumbs_change_7d,
-    COALESCE(s7.min_downloads, a.download_count) AS min_downloads_7d
+    COALESCE(s7.min_downloads, a.download_count) AS min_downloads_7d,
+    COALESCE(s24.download_slope, 0)::float8 AS download_slope_24h,
+    COALESCE(s24.thumbs_slope, 0)::float8 AS thumbs_slope_24h,
+    COALESCE(s24.download_fit, 0)::float8 AS download_fit_24h,
+    COALESCE(s24.thumbs_fit, 0)::float8 AS thumbs_fit_24h,
+    COALESCE(s24.span_hours, 0)::float8 AS span_hours_24h,
+    COALESCE(s7.download_slope, 0)::float8 AS download_slope_7d,
+    COALESCE(s7.thumbs_slope, 0)::float8 AS thumbs_slope_7d
 FROM addons a
 LEFT JOIN stats_24h s24 ON a.id = s24.addon_id
 LEFT JOIN stats_7d s7 ON a.id = s7.addon_id
@@ -466,15 +1480,26 @@ type GetAllSnapshotStatsRow struct {
 	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
 	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	AuthorID          pgtype.Int4        `json:"author_id"`
+	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
 	DownloadChange24h int64              `json:"download_change_24h"`
 	ThumbsChange24h   int32              `json:"thumbs_change_24h"`
 	SnapshotCount24h  int32              `json:"snapshot_count_24h"`
 	DownloadChange7d  int64              `json:"download_change_7d"`
 	ThumbsChange7d    int32              `json:"thumbs_change_7d"`
 	MinDownloads7d    int64              `json:"min_downloads_7d"`
+	DownloadSlope24h  float64            `json:"download_slope_24h"`
+	ThumbsSlope24h    float64            `json:"thumbs_slope_24h"`
+	DownloadFit24h    float64            `json:"download_fit_24h"`
+	ThumbsFit24h      float64            `json:"thumbs_fit_24h"`
+	SpanHours24h      float64            `json:"span_hours_24h"`
+	DownloadSlope7d   float64            `json:"download_slope_7d"`
+	ThumbsSlope7d     float64            `json:"thumbs_slope_7d"`
 }
 
-// Bulk fetch snapshot stats for all addons in both time windows
+// Bulk fetch snapshot stats for all addons in both time windows. Slopes are
+// least-squares fits of the counts against snapshot time, in units per hour;
+// fits are their R² and span_hours_24h how much of the day the snapshots cover.
 func (q *Queries) GetAllSnapshotStats(ctx context.Context) ([]GetAllSnapshotStatsRow, error) {
 	rows, err := q.db.Query(ctx, getAllSnapshotStats)
 	if err != nil {
@@ -490,12 +1515,152 @@ func (q *Queries) GetAllSnapshotStats(ctx context.Context) ([]GetAllSnapshotStat
 			&i.ThumbsUpCount,
 			&i.LatestFileDate,
 			&i.CreatedAt,
+			&i.AuthorID,
+			&i.PrimaryCategoryID,
+			&i.DownloadChange24h,
+			&i.ThumbsChange24h,
+			&i.SnapshotCount24h,
+			&i.DownloadChange7d,
+			&i.ThumbsChange7d,
+			&i.MinDownloads7d,
+			&i.DownloadSlope24h,
+			&i.ThumbsSlope24h,
+			&i.DownloadFit24h,
+			&i.ThumbsFit24h,
+			&i.SpanHours24h,
+			&i.DownloadSlope7d,
+			&i.ThumbsSlope7d,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllSnapshotStatsAsOf = `-- name: GetAllSnapshotStatsAsOf :many
+WITH visible AS (
+    SELECT addon_id, recorded_at, download_count, thumbs_up_count, latest_file_date
+    FROM snapshots
+    WHERE recorded_at <= $1::timestamptz
+      AND recorded_at >= $1::timestamptz - INTERVAL '7 days'
+),
+latest AS (
+    SELECT DISTINCT ON (addon_id) addon_id, download_count, thumbs_up_count, latest_file_date
+    FROM visible
+    ORDER BY addon_id, recorded_at DESC
+),
+stats_24h AS (
+    SELECT
+        addon_id,
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        COUNT(*)::int AS snapshot_count,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope,
+        regr_r2(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_fit,
+        regr_r2(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_fit,
+        EXTRACT(EPOCH FROM MAX(recorded_at) - MIN(recorded_at))::float8 / 3600 AS span_hours
+    FROM visible
+    WHERE recorded_at >= $1::timestamptz - INTERVAL '24 hours'
+    GROUP BY addon_id
+),
+stats_7d AS (
+    SELECT
+        addon_id,
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        MIN(download_count)::bigint AS min_downloads,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope
+    FROM visible
+    GROUP BY addon_id
+)
+SELECT
+    l.addon_id,
+    l.download_count,
+    l.thumbs_up_count,
+    l.latest_file_date,
+    a.created_at,
+    a.author_id,
+    a.primary_category_id,
+    COALESCE(s24.download_change, 0) AS download_change_24h,
+    COALESCE(s24.thumbs_change, 0) AS thumbs_change_24h,
+    COALESCE(s24.snapshot_count, 0) AS snapshot_count_24h,
+    s7.download_change AS download_change_7d,
+    s7.thumbs_change AS thumbs_change_7d,
+    s7.min_downloads AS min_downloads_7d,
+    COALESCE(s24.download_slope, 0)::float8 AS download_slope_24h,
+    COALESCE(s24.thumbs_slope, 0)::float8 AS thumbs_slope_24h,
+    COALESCE(s24.download_fit, 0)::float8 AS download_fit_24h,
+    COALESCE(s24.thumbs_fit, 0)::float8 AS thumbs_fit_24h,
+    COALESCE(s24.span_hours, 0)::float8 AS span_hours_24h,
+    COALESCE(s7.download_slope, 0)::float8 AS download_slope_7d,
+    COALESCE(s7.thumbs_slope, 0)::float8 AS thumbs_slope_7d
+FROM latest l
+JOIN addons a ON a.id = l.addon_id
+JOIN stats_7d s7 ON s7.addon_id = l.addon_id
+LEFT JOIN stats_24h s24 ON s24.addon_id = l.addon_id
+`
+
+type GetAllSnapshotStatsAsOfRow struct {
+	AddonID           int32              `json:"addon_id"`
+	DownloadCount     int64              `json:"download_count"`
+	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
+	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	AuthorID          pgtype.Int4        `json:"author_id"`
+	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
+	DownloadChange24h int64              `json:"download_change_24h"`
+	ThumbsChange24h   int32              `json:"thumbs_change_24h"`
+	SnapshotCount24h  int32              `json:"snapshot_count_24h"`
+	DownloadChange7d  int64              `json:"download_change_7d"`
+	ThumbsChange7d    int32              `json:"thumbs_change_7d"`
+	MinDownloads7d    int64              `json:"min_downloads_7d"`
+	DownloadSlope24h  float64            `json:"download_slope_24h"`
+	ThumbsSlope24h    float64            `json:"thumbs_slope_24h"`
+	DownloadFit24h    float64            `json:"download_fit_24h"`
+	ThumbsFit24h      float64            `json:"thumbs_fit_24h"`
+	SpanHours24h      float64            `json:"span_hours_24h"`
+	DownloadSlope7d   float64            `json:"download_slope_7d"`
+	ThumbsSlope7d     float64            `json:"thumbs_slope_7d"`
+}
+
+// GetAllSnapshotStats as it would have returned at as_of, using only snapshots
+// recorded by then; counts come from each addon's latest visible snapshot
+func (q *Queries) GetAllSnapshotStatsAsOf(ctx context.Context, asOf pgtype.Timestamptz) ([]GetAllSnapshotStatsAsOfRow, error) {
+	rows, err := q.db.Query(ctx, getAllSnapshotStatsAsOf, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAllSnapshotStatsAsOfRow{}
+	for rows.Next() {
+		var i GetAllSnapshotStatsAsOfRow
+		if err := rows.Scan(
+			&i.AddonID,
+			&i.DownloadCount,
+			&i.ThumbsUpCount,
+			&i.LatestFileDate,
+			&i.CreatedAt,
+			&i.AuthorID,
+			&i.PrimaryCategoryID,
 			&i.DownloadChange24h,
 			&i.ThumbsChange24h,
 			&i.SnapshotCount24h,
 			&i.DownloadChange7d,
 			&i.ThumbsChange7d,
 			&i.MinDownloads7d,
+			&i.DownloadSlope24h,
+			&i.ThumbsSlope24h,
+			&i.DownloadFit24h,
+			&i.ThumbsFit24h,
+			&i.SpanHours24h,
+			&i.DownloadSlope7d,
+			&i.ThumbsSlope7d,
 		); err != nil {
 			return nil, err
 		}
@@ -508,7 +1673,23 @@ func (q *Queries) GetAllSnapshotStats(ctx context.Context) ([]GetAllSnapshotStat
 }
 
 const getAllTrendingScores = `-- name: GetAllTrendingScores :many
-SELECT addon_id, first_hot_at, first_rising_at
+SELECT
+    addon_id,
+    first_hot_at,
+    first_rising_at,
+    first_loved_at,
+    inputs_hash,
+    hot_rank,
+    rising_rank,
+    loved_rank,
+    hot_misses,
+    rising_misses,
+    loved_misses,
+    hot_raw_rank,
+    rising_raw_rank,
+    loved_raw_rank,
+    (COALESCE(hot_score, 0) = 0 AND COALESCE(rising_score, 0) = 0 AND COALESCE(loved_score, 0) = 0
+     AND COALESCE(fresh_score, 0) = 0)::boolean AS unscored
 FROM trending_scores
 `
 
@@ -516,6 +1697,18 @@ type GetAllTrendingScoresRow struct {
 	AddonID       int32              `json:"addon_id"`
 	FirstHotAt    pgtype.Timestamptz `json:"first_hot_at"`
 	FirstRisingAt pgtype.Timestamptz `json:"first_rising_at"`
+	FirstLovedAt  pgtype.Timestamptz `json:"first_loved_at"`
+	InputsHash    pgtype.Int8        `json:"inputs_hash"`
+	HotRank       pgtype.Int2        `json:"hot_rank"`
+	RisingRank    pgtype.Int2        `json:"rising_rank"`
+	LovedRank     pgtype.Int2        `json:"loved_rank"`
+	HotMisses     int16              `json:"hot_misses"`
+	RisingMisses  int16              `json:"rising_misses"`
+	LovedMisses   int16              `json:"loved_misses"`
+	HotRawRank    pgtype.Int2        `json:"hot_raw_rank"`
+	RisingRawRank pgtype.Int2        `json:"rising_raw_rank"`
+	LovedRawRank  pgtype.Int2        `json:"loved_raw_rank"`
+	Unscored      bool               `json:"unscored"`
 }
 
 // Bulk fetch all existing trending scores
@@ -528,7 +1721,23 @@ func (q *Queries) GetAllTrendingScores(ctx context.Context) ([]GetAllTrendingSco
 	items := []GetAllTrendingScoresRow{}
 	for rows.Next() {
 		var i GetAllTrendingScoresRow
-		if err := rows.Scan(&i.AddonID, &i.FirstHotAt, &i.FirstRisingAt); err != nil {
+		if err := rows.Scan(
+			&i.AddonID,
+			&i.FirstHotAt,
+			&i.FirstRisingAt,
+			&i.FirstLovedAt,
+			&i.InputsHash,
+			&i.HotRank,
+			&i.RisingRank,
+			&i.LovedRank,
+			&i.HotMisses,
+			&i.RisingMisses,
+			&i.LovedMisses,
+			&i.HotRawRank,
+			&i.RisingRawRank,
+			&i.LovedRawRank,
+			&i.Unscored,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -539,8 +1748,30 @@ func (q *Queries) GetAllTrendingScores(ctx context.Context) ([]GetAllTrendingSco
 	return items, nil
 }
 
+const getCategoryAddonStats = `-- name: GetCategoryAddonStats :one
+SELECT
+    COUNT(*) AS addon_count,
+    COALESCE(SUM(download_count), 0)::bigint AS total_downloads
+FROM addons
+WHERE status = 'active'
+  AND categories && $1::integer[]
+`
+
+type GetCategoryAddonStatsRow struct {
+	AddonCount     int64 `json:"addon_count"`
+	TotalDownloads int64 `json:"total_downloads"`
+}
+
+// Addon count and total downloads for active addons in any of the given categories
+func (q *Queries) GetCategoryAddonStats(ctx context.Context, categoryIds []int32) (GetCategoryAddonStatsRow, error) {
+	row := q.db.QueryRow(ctx, getCategoryAddonStats, categoryIds)
+	var i GetCategoryAddonStatsRow
+	err := row.Scan(&i.AddonCount, &i.TotalDownloads)
+	return i, err
+}
+
 const getCategoryBySlug = `-- name: GetCategoryBySlug :one
-SELECT id, name, slug, parent_id, icon_url FROM categories WHERE slug = $1
+SELECT id, name, slug, parent_id, icon_url, class_id, is_class, display_index, deleted_at FROM categories WHERE slug = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetCategoryBySlug(ctx context.Context, slug string) (Category, error) {
@@ -552,41 +1783,46 @@ func (q *Queries) GetCategoryBySlug(ctx context.Context, slug string) (Category,
 		&i.Slug,
 		&i.ParentID,
 		&i.IconUrl,
+		&i.ClassID,
+		&i.IsClass,
+		&i.DisplayIndex,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const getDownloadPercentile = `-- name: GetDownloadPercentile :one
-SELECT COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY download_count), 500000)::FLOAT8 AS percentile_95
-FROM addons
-WHERE status = 'active' AND download_count > 0
+const getCategoryDownloadPercentiles = `-- name: GetCategoryDownloadPercentiles :many
+SELECT
+    c.id AS category_id,
+    PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY a.download_count)::FLOAT8 AS percentile_95
+FROM categories c
+JOIN addons a ON c.id = ANY(a.categories)
+WHERE c.deleted_at IS NULL
+  AND a.status = 'active'
+  AND a.download_count > 0
+GROUP BY c.id
 `
 
-// Gets the Nth percentile of total downloads for size multiplier calculation
-func (q *Queries) GetDownloadPercentile(ctx context.Context) (float64, error) {
-	row := q.db.QueryRow(ctx, getDownloadPercentile)
-	var percentile_95 float64
-	err := row.Scan(&percentile_95)
-	return percentile_95, err
+type GetCategoryDownloadPercentilesRow struct {
+	CategoryID   int32   `json:"category_id"`
+	Percentile95 float64 `json:"percentile_95"`
 }
 
-const getHotAddonIDs = `-- name: GetHotAddonIDs :many
-SELECT id FROM addons WHERE is_hot = TRUE AND status = 'active'
-`
-
-func (q *Queries) GetHotAddonIDs(ctx context.Context) ([]int32, error) {
-	rows, err := q.db.Query(ctx, getHotAddonIDs)
+// 95th percentile of downloads among each category's active addons, for the
+// per-category size multiplier
+func (q *Queries) GetCategoryDownloadPercentiles(ctx context.Context) ([]GetCategoryDownloadPercentilesRow, error) {
+	rows, err := q.db.Query(ctx, getCategoryDownloadPercentiles)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []int32{}
+	items := []GetCategoryDownloadPercentilesRow{}
 	for rows.Next() {
-		var id int32
-		if err := rows.Scan(&id); err != nil {
+		var i GetCategoryDownloadPercentilesRow
+		if err := rows.Scan(&i.CategoryID, &i.Percentile95); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -594,83 +1830,61 @@ func (q *Queries) GetHotAddonIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
-const getRankAt = `-- name: GetRankAt :one
-SELECT rank FROM trending_rank_history
-WHERE addon_id = $1
-  AND category = $2
-  AND recorded_at <= $3
-ORDER BY recorded_at DESC
-LIMIT 1
-`
-
-type GetRankAtParams struct {
-	AddonID    int32              `json:"addon_id"`
-	Category   string             `json:"category"`
-	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
-}
-
-// Get the rank of an addon at a specific time (closest record before that time)
-func (q *Queries) GetRankAt(ctx context.Context, arg GetRankAtParams) (int16, error) {
-	row := q.db.QueryRow(ctx, getRankAt, arg.AddonID, arg.Category, arg.RecordedAt)
-	var rank int16
-	err := row.Scan(&rank)
-	return rank, err
-}
-
-const getRankChanges = `-- name: GetRankChanges :many
+const getCategoryRankChanges = `-- name: GetCategoryRankChanges :many
 WITH current_ranks AS (
-    -- Use DISTINCT ON to get most recent rank per addon/category
-    -- (each INSERT has a slightly different microsecond timestamp)
-    SELECT DISTINCT ON (addon_id, category) addon_id, category, rank, score
-    FROM trending_rank_history
-    ORDER BY addon_id, category, recorded_at DESC
+    SELECT DISTINCT ON (addon_id, list) addon_id, list, rank, score
+    FROM category_rank_history
+    WHERE category_id = $1
+    ORDER BY addon_id, list, recorded_at DESC
 ),
 ranks_24h AS (
-    SELECT DISTINCT ON (addon_id, category) addon_id, category, rank
-    FROM trending_rank_history
-    WHERE recorded_at <= NOW() - INTERVAL '24 hours'
-    ORDER BY addon_id, category, recorded_at DESC
+    SELECT DISTINCT ON (addon_id, list) addon_id, list, rank
+    FROM category_rank_history
+    WHERE category_id = $1
+      AND recorded_at <= NOW() - INTERVAL '24 hours'
+    ORDER BY addon_id, list, recorded_at DESC
 ),
 ranks_7d AS (
-    SELECT DISTINCT ON (addon_id, category) addon_id, category, rank
-    FROM trending_rank_history
-    WHERE recorded_at <= NOW() - INTERVAL '7 days'
-    ORDER BY addon_id, category, recorded_at DESC
+    SELECT DISTINCT ON (addon_id, list) addon_id, list, rank
+    FROM category_rank_history
+    WHERE category_id = $1
+      AND recorded_at <= NOW() - INTERVAL '7 days'
+    ORDER BY addon_id, list, recorded_at DESC
 )
 SELECT
     c.addon_id,
-    c.category,
+    c.list,
     c.rank AS current_rank,
     c.score,
     r24.rank AS rank_24h_ago,
     r7.rank AS rank_7d_ago
 FROM current_ranks c
-LEFT JOIN ranks_24h r24 ON c.addon_id = r24.addon_id AND c.category = r24.category
-LEFT JOIN ranks_7d r7 ON c.addon_id = r7.addon_id AND c.category = r7.category
+LEFT JOIN ranks_24h r24 ON c.addon_id = r24.addon_id AND c.list = r24.list
+LEFT JOIN ranks_7d r7 ON c.addon_id = r7.addon_id AND c.list = r7.list
 `
 
-type GetRankChangesRow struct {
+type GetCategoryRankChangesRow struct {
 	AddonID     int32          `json:"addon_id"`
-	Category    string         `json:"category"`
+	List        string         `json:"list"`
 	CurrentRank int16          `json:"current_rank"`
 	Score       pgtype.Numeric `json:"score"`
 	Rank24hAgo  pgtype.Int2    `json:"rank_24h_ago"`
 	Rank7dAgo   pgtype.Int2    `json:"rank_7d_ago"`
 }
 
-// Get rank changes for top addons (24h and 7d ago)
-func (q *Queries) GetRankChanges(ctx context.Context) ([]GetRankChangesRow, error) {
-	rows, err := q.db.Query(ctx, getRankChanges)
+// GetRankChanges for one category's lists
+func (q *Queries) GetCategoryRankChanges(ctx context.Context, categoryID int32) ([]GetCategoryRankChangesRow, error) {
+	rows, err := q.db.Query(ctx, getCategoryRankChanges, categoryID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetRankChangesRow{}
+	items := []GetCategoryRankChangesRow{}
 	for rows.Next() {
-		var i GetRankChangesRow
+		var i GetCategoryRankChangesRow
 		if err := rows.Scan(
 			&i.AddonID,
-			&i.Category,
+			&i.List,
 			&i.CurrentRank,
 			&i.Score,
 			&i.Rank24hAgo,
@@ -686,30 +1900,330 @@ func (q *Queries) GetRankChanges(ctx context.Context) ([]GetRankChangesRow, erro
 	return items, nil
 }
 
-const getSnapshotStats = `-- name: GetSnapshotStats :one
-SELECT
-    COALESCE(MAX(download_count) - MIN(download_count), 0) AS download_change,
-    COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0) AS thumbs_change,
-    COUNT(*) AS snapshot_count,
-    MIN(download_count) AS min_downloads,
-    MAX(download_count) AS max_downloads
-FROM snapshots
-WHERE addon_id = $1
-  AND recorded_at >= NOW() - ($2 || ' hours')::INTERVAL
+const getCurrentTrendingParamSet = `-- name: GetCurrentTrendingParamSet :one
+SELECT p.version, p.params, p.is_active, p.is_shadow, p.created_at
+FROM trending_generations g
+JOIN trending_calculation_runs r ON r.id = g.run_id
+JOIN trending_param_sets p ON p.version = r.params_version
+WHERE g.slot = 'live'
 `
 
-type GetSnapshotStatsParams struct {
-	AddonID int32       `json:"addon_id"`
-	Column2 pgtype.Text `json:"column_2"`
+// Parameter set that produced the live trending scores
+func (q *Queries) GetCurrentTrendingParamSet(ctx context.Context) (TrendingParamSet, error) {
+	row := q.db.QueryRow(ctx, getCurrentTrendingParamSet)
+	var i TrendingParamSet
+	err := row.Scan(
+		&i.Version,
+		&i.Params,
+		&i.IsActive,
+		&i.IsShadow,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-type GetSnapshotStatsRow struct {
-	DownloadChange interface{} `json:"download_change"`
-	ThumbsChange   interface{} `json:"thumbs_change"`
-	SnapshotCount  int64       `json:"snapshot_count"`
-	MinDownloads   interface{} `json:"min_downloads"`
-	MaxDownloads   interface{} `json:"max_downloads"`
-}
+const getDownloadPercentile = `-- name: GetDownloadPercentile :one
+SELECT COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY download_count), 500000)::FLOAT8 AS percentile_95
+FROM addons
+WHERE status = 'active' AND download_count > 0
+`
+
+// Gets the Nth percentile of total downloads for size multiplier calculation
+func (q *Queries) GetDownloadPercentile(ctx context.Context) (float64, error) {
+	row := q.db.QueryRow(ctx, getDownloadPercentile)
+	var percentile_95 float64
+	err := row.Scan(&percentile_95)
+	return percentile_95, err
+}
+
+const getDownloadPercentileAsOf = `-- name: GetDownloadPercentileAsOf :one
+SELECT COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY download_count), 500000)::FLOAT8 AS percentile_95
+FROM (
+    SELECT DISTINCT ON (addon_id) download_count
+    FROM snapshots
+    WHERE recorded_at <= $1::timestamptz
+      AND recorded_at >= $1::timestamptz - INTERVAL '7 days'
+    ORDER BY addon_id, recorded_at DESC
+) latest
+WHERE download_count > 0
+`
+
+// GetDownloadPercentile over each addon's latest snapshot in the week before as_of
+func (q *Queries) GetDownloadPercentileAsOf(ctx context.Context, asOf pgtype.Timestamptz) (float64, error) {
+	row := q.db.QueryRow(ctx, getDownloadPercentileAsOf, asOf)
+	var percentile_95 float64
+	err := row.Scan(&percentile_95)
+	return percentile_95, err
+}
+
+const getHotAddonIDs = `-- name: GetHotAddonIDs :many
+SELECT id FROM addons WHERE is_hot = TRUE AND status = 'active'
+`
+
+func (q *Queries) GetHotAddonIDs(ctx context.Context) ([]int32, error) {
+	rows, err := q.db.Query(ctx, getHotAddonIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getJobLock = `-- name: GetJobLock :one
+SELECT
+    l.job_name, l.holder, l.backend_pid, l.acquired_at, l.heartbeat_at,
+    EXISTS (SELECT 1 FROM pg_stat_activity a WHERE a.pid = l.backend_pid) AS holder_connected
+FROM job_locks l
+WHERE l.job_name = $1
+`
+
+type GetJobLockRow struct {
+	JobName         string             `json:"job_name"`
+	Holder          string             `json:"holder"`
+	BackendPid      int32              `json:"backend_pid"`
+	AcquiredAt      pgtype.Timestamptz `json:"acquired_at"`
+	HeartbeatAt     pgtype.Timestamptz `json:"heartbeat_at"`
+	HolderConnected bool               `json:"holder_connected"`
+}
+
+func (q *Queries) GetJobLock(ctx context.Context, jobName string) (GetJobLockRow, error) {
+	row := q.db.QueryRow(ctx, getJobLock, jobName)
+	var i GetJobLockRow
+	err := row.Scan(
+		&i.JobName,
+		&i.Holder,
+		&i.BackendPid,
+		&i.AcquiredAt,
+		&i.HeartbeatAt,
+		&i.HolderConnected,
+	)
+	return i, err
+}
+
+const getLastHealthySyncRun = `-- name: GetLastHealthySyncRun :one
+SELECT id, started_at, finished_at, fetched_count, synced_count, error_count, baseline_count, quarantined, quarantine_reason FROM sync_runs
+WHERE NOT quarantined
+ORDER BY started_at DESC
+LIMIT 1
+`
+
+// Most recent run that was not quarantined, used as the baseline for relative thresholds
+func (q *Queries) GetLastHealthySyncRun(ctx context.Context) (SyncRun, error) {
+	row := q.db.QueryRow(ctx, getLastHealthySyncRun)
+	var i SyncRun
+	err := row.Scan(
+		&i.ID,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.FetchedCount,
+		&i.SyncedCount,
+		&i.ErrorCount,
+		&i.BaselineCount,
+		&i.Quarantined,
+		&i.QuarantineReason,
+	)
+	return i, err
+}
+
+const getLatestHeatIndexDay = `-- name: GetLatestHeatIndexDay :one
+SELECT MAX(day)::date AS day FROM heat_index_daily
+`
+
+func (q *Queries) GetLatestHeatIndexDay(ctx context.Context) (pgtype.Date, error) {
+	row := q.db.QueryRow(ctx, getLatestHeatIndexDay)
+	var day pgtype.Date
+	err := row.Scan(&day)
+	return day, err
+}
+
+const getLatestSnapshot = `-- name: GetLatestSnapshot :one
+SELECT recorded_at, download_count, thumbs_up_count
+FROM snapshots
+WHERE addon_id = $1
+ORDER BY recorded_at DESC
+LIMIT 1
+`
+
+type GetLatestSnapshotRow struct {
+	RecordedAt    pgtype.Timestamptz `json:"recorded_at"`
+	DownloadCount int64              `json:"download_count"`
+	ThumbsUpCount pgtype.Int4        `json:"thumbs_up_count"`
+}
+
+func (q *Queries) GetLatestSnapshot(ctx context.Context, addonID int32) (GetLatestSnapshotRow, error) {
+	row := q.db.QueryRow(ctx, getLatestSnapshot, addonID)
+	var i GetLatestSnapshotRow
+	err := row.Scan(&i.RecordedAt, &i.DownloadCount, &i.ThumbsUpCount)
+	return i, err
+}
+
+const getLatestSyncRun = `-- name: GetLatestSyncRun :one
+SELECT id, started_at, finished_at, fetched_count, synced_count, error_count, baseline_count, quarantined, quarantine_reason FROM sync_runs
+ORDER BY started_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestSyncRun(ctx context.Context) (SyncRun, error) {
+	row := q.db.QueryRow(ctx, getLatestSyncRun)
+	var i SyncRun
+	err := row.Scan(
+		&i.ID,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.FetchedCount,
+		&i.SyncedCount,
+		&i.ErrorCount,
+		&i.BaselineCount,
+		&i.Quarantined,
+		&i.QuarantineReason,
+	)
+	return i, err
+}
+
+const getRankAt = `-- name: GetRankAt :one
+SELECT rank FROM trending_rank_history
+WHERE addon_id = $1
+  AND category = $2
+  AND recorded_at <= $3
+ORDER BY recorded_at DESC
+LIMIT 1
+`
+
+type GetRankAtParams struct {
+	AddonID    int32              `json:"addon_id"`
+	Category   string             `json:"category"`
+	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
+}
+
+// Get the rank of an addon at a specific time (closest record before that time)
+func (q *Queries) GetRankAt(ctx context.Context, arg GetRankAtParams) (int16, error) {
+	row := q.db.QueryRow(ctx, getRankAt, arg.AddonID, arg.Category, arg.RecordedAt)
+	var rank int16
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getRankChanges = `-- name: GetRankChanges :many
+WITH current_ranks AS (
+    -- Use DISTINCT ON to get most recent rank per addon/category
+    -- (each INSERT has a slightly different microsecond timestamp)
+    SELECT DISTINCT ON (addon_id, category) addon_id, category, rank, score
+    FROM trending_rank_history
+    ORDER BY addon_id, category, recorded_at DESC
+),
+ranks_24h AS (
+    SELECT DISTINCT ON (addon_id, category) addon_id, category, rank
+    FROM trending_rank_history
+    WHERE recorded_at <= NOW() - INTERVAL '24 hours'
+    ORDER BY addon_id, category, recorded_at DESC
+),
+ranks_7d AS (
+    SELECT DISTINCT ON (addon_id, category) addon_id, category, rank
+    FROM trending_rank_history
+    WHERE recorded_at <= NOW() - INTERVAL '7 days'
+    ORDER BY addon_id, category, recorded_at DESC
+)
+SELECT
+    c.addon_id,
+    c.category,
+    c.rank AS current_rank,
+    c.score,
+    r24.rank AS rank_24h_ago,
+    r7.rank AS rank_7d_ago
+FROM current_ranks c
+LEFT JOIN ranks_24h r24 ON c.addon_id = r24.addon_id AND c.category = r24.category
+LEFT JOIN ranks_7d r7 ON c.addon_id = r7.addon_id AND c.category = r7.category
+`
+
+type GetRankChangesRow struct {
+	AddonID     int32          `json:"addon_id"`
+	Category    string         `json:"category"`
+	CurrentRank int16          `json:"current_rank"`
+	Score       pgtype.Numeric `json:"score"`
+	Rank24hAgo  pgtype.Int2    `json:"rank_24h_ago"`
+	Rank7dAgo   pgtype.Int2    `json:"rank_7d_ago"`
+}
+
+// Get rank changes for top addons (24h and 7d ago)
+func (q *Queries) GetRankChanges(ctx context.Context) ([]GetRankChangesRow, error) {
+	rows, err := q.db.Query(ctx, getRankChanges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRankChangesRow{}
+	for rows.Next() {
+		var i GetRankChangesRow
+		if err := rows.Scan(
+			&i.AddonID,
+			&i.Category,
+			&i.CurrentRank,
+			&i.Score,
+			&i.Rank24hAgo,
+			&i.Rank7dAgo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getShadowTrendingParamSet = `-- name: GetShadowTrendingParamSet :one
+SELECT version, params, is_active, is_shadow, created_at FROM trending_param_sets WHERE is_shadow
+`
+
+func (q *Queries) GetShadowTrendingParamSet(ctx context.Context) (TrendingParamSet, error) {
+	row := q.db.QueryRow(ctx, getShadowTrendingParamSet)
+	var i TrendingParamSet
+	err := row.Scan(
+		&i.Version,
+		&i.Params,
+		&i.IsActive,
+		&i.IsShadow,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSnapshotStats = `-- name: GetSnapshotStats :one
+SELECT
+    COALESCE(MAX(download_count) - MIN(download_count), 0) AS download_change,
+    COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0) AS thumbs_change,
+    COUNT(*) AS snapshot_count,
+    MIN(download_count) AS min_downloads,
+    MAX(download_count) AS max_downloads
+FROM snapshots
+WHERE addon_id = $1
+  AND recorded_at >= NOW() - ($2 || ' hours')::INTERVAL
+`
+
+type GetSnapshotStatsParams struct {
+	AddonID int32       `json:"addon_id"`
+	Column2 pgtype.Text `json:"column_2"`
+}
+
+type GetSnapshotStatsRow struct {
+	DownloadChange interface{} `json:"download_change"`
+	ThumbsChange   interface{} `json:"thumbs_change"`
+	SnapshotCount  int64       `json:"snapshot_count"`
+	MinDownloads   interface{} `json:"min_downloads"`
+	MaxDownloads   interface{} `json:"max_downloads"`
+}
 
 // Gets download/thumbs changes for velocity calculation
 func (q *Queries) GetSnapshotStats(ctx context.Context, arg GetSnapshotStatsParams) (GetSnapshotStatsRow, error) {
@@ -725,8 +2239,36 @@ func (q *Queries) GetSnapshotStats(ctx context.Context, arg GetSnapshotStatsPara
 	return i, err
 }
 
+const getTrendingGeneration = `-- name: GetTrendingGeneration :one
+SELECT slot, run_id, published_at FROM trending_generations WHERE slot = $1
+`
+
+func (q *Queries) GetTrendingGeneration(ctx context.Context, slot string) (TrendingGeneration, error) {
+	row := q.db.QueryRow(ctx, getTrendingGeneration, slot)
+	var i TrendingGeneration
+	err := row.Scan(&i.Slot, &i.RunID, &i.PublishedAt)
+	return i, err
+}
+
+const getTrendingParamSet = `-- name: GetTrendingParamSet :one
+SELECT version, params, is_active, is_shadow, created_at FROM trending_param_sets WHERE version = $1
+`
+
+func (q *Queries) GetTrendingParamSet(ctx context.Context, version string) (TrendingParamSet, error) {
+	row := q.db.QueryRow(ctx, getTrendingParamSet, version)
+	var i TrendingParamSet
+	err := row.Scan(
+		&i.Version,
+		&i.Params,
+		&i.IsActive,
+		&i.IsShadow,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getTrendingScore = `-- name: GetTrendingScore :one
-SELECT addon_id, hot_score, rising_score, download_velocity, thumbs_velocity, download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier, first_hot_at, first_rising_at, calculated_at FROM trending_scores WHERE addon_id = $1
+SELECT addon_id, hot_score, rising_score, download_velocity, thumbs_velocity, download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier, first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash, loved_score, first_loved_at, fresh_score, hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses, hot_raw_rank, rising_raw_rank, loved_raw_rank FROM trending_scores WHERE addon_id = $1
 `
 
 func (q *Queries) GetTrendingScore(ctx context.Context, addonID int32) (TrendingScore, error) {
@@ -745,79 +2287,1430 @@ func (q *Queries) GetTrendingScore(ctx context.Context, addonID int32) (Trending
 		&i.FirstHotAt,
 		&i.FirstRisingAt,
 		&i.CalculatedAt,
+		&i.ParamsVersion,
+		&i.InputsHash,
+		&i.LovedScore,
+		&i.FirstLovedAt,
+		&i.FreshScore,
+		&i.HotRank,
+		&i.RisingRank,
+		&i.LovedRank,
+		&i.HotMisses,
+		&i.RisingMisses,
+		&i.LovedMisses,
+		&i.HotRawRank,
+		&i.RisingRawRank,
+		&i.LovedRawRank,
 	)
 	return i, err
 }
 
-const insertRankHistory = `-- name: InsertRankHistory :exec
-INSERT INTO trending_rank_history (addon_id, category, rank, score, recorded_at)
-VALUES ($1, $2, $3, $4, NOW())
+const heartbeatJobLock = `-- name: HeartbeatJobLock :execrows
+UPDATE job_locks SET heartbeat_at = NOW()
+WHERE job_name = $1 AND holder = $2
 `
 
-type InsertRankHistoryParams struct {
-	AddonID  int32          `json:"addon_id"`
-	Category string         `json:"category"`
-	Rank     int16          `json:"rank"`
-	Score    pgtype.Numeric `json:"score"`
+type HeartbeatJobLockParams struct {
+	JobName string `json:"job_name"`
+	Holder  string `json:"holder"`
 }
 
-// Record current rank for an addon in a category (deprecated: use InsertRankHistoryWithTime)
-func (q *Queries) InsertRankHistory(ctx context.Context, arg InsertRankHistoryParams) error {
-	_, err := q.db.Exec(ctx, insertRankHistory,
-		arg.AddonID,
-		arg.Category,
+func (q *Queries) HeartbeatJobLock(ctx context.Context, arg HeartbeatJobLockParams) (int64, error) {
+	result, err := q.db.Exec(ctx, heartbeatJobLock, arg.JobName, arg.Holder)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+type InsertAddonForecastsParams struct {
+	AddonID       int32              `json:"addon_id"`
+	ForecastFrom  pgtype.Timestamptz `json:"forecast_from"`
+	DownloadCount int64              `json:"download_count"`
+	HistoryHours  int32              `json:"history_hours"`
+	Projected24h  int64              `json:"projected_24h"`
+	Low24h        int64              `json:"low_24h"`
+	High24h       int64              `json:"high_24h"`
+	Projected7d   int64              `json:"projected_7d"`
+	Low7d         int64              `json:"low_7d"`
+	High7d        int64              `json:"high_7d"`
+	CalculatedAt  pgtype.Timestamptz `json:"calculated_at"`
+}
+
+const insertArchivedLeaderboardEntry = `-- name: InsertArchivedLeaderboardEntry :exec
+INSERT INTO leaderboard_archive (period, period_start, list, rank, addon_id, score, recorded_at, run_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type InsertArchivedLeaderboardEntryParams struct {
+	Period      string             `json:"period"`
+	PeriodStart pgtype.Date        `json:"period_start"`
+	List        string             `json:"list"`
+	Rank        int16              `json:"rank"`
+	AddonID     int32              `json:"addon_id"`
+	Score       pgtype.Numeric     `json:"score"`
+	RecordedAt  pgtype.Timestamptz `json:"recorded_at"`
+	RunID       int64              `json:"run_id"`
+}
+
+func (q *Queries) InsertArchivedLeaderboardEntry(ctx context.Context, arg InsertArchivedLeaderboardEntryParams) error {
+	_, err := q.db.Exec(ctx, insertArchivedLeaderboardEntry,
+		arg.Period,
+		arg.PeriodStart,
+		arg.List,
 		arg.Rank,
+		arg.AddonID,
 		arg.Score,
+		arg.RecordedAt,
+		arg.RunID,
 	)
 	return err
 }
 
-const insertRankHistoryWithTime = `-- name: InsertRankHistoryWithTime :exec
-INSERT INTO trending_rank_history (addon_id, category, rank, score, recorded_at)
-VALUES ($1, $2, $3, $4, $5)
+const insertCategoryRankHistory = `-- name: InsertCategoryRankHistory :exec
+INSERT INTO category_rank_history (category_id, addon_id, list, rank, score, recorded_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type InsertCategoryRankHistoryParams struct {
+	CategoryID int32              `json:"category_id"`
+	AddonID    int32              `json:"addon_id"`
+	List       string             `json:"list"`
+	Rank       int16              `json:"rank"`
+	Score      pgtype.Numeric     `json:"score"`
+	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
+}
+
+func (q *Queries) InsertCategoryRankHistory(ctx context.Context, arg InsertCategoryRankHistoryParams) error {
+	_, err := q.db.Exec(ctx, insertCategoryRankHistory,
+		arg.CategoryID,
+		arg.AddonID,
+		arg.List,
+		arg.Rank,
+		arg.Score,
+		arg.RecordedAt,
+	)
+	return err
+}
+
+type InsertHeatIndexDailyParams struct {
+	AddonID   int32       `json:"addon_id"`
+	Day       pgtype.Date `json:"day"`
+	HeatIndex int16       `json:"heat_index"`
+	RunID     int64       `json:"run_id"`
+}
+
+const insertRankHistory = `-- name: InsertRankHistory :exec
+INSERT INTO trending_rank_history (addon_id, category, rank, score, recorded_at)
+VALUES ($1, $2, $3, $4, NOW())
+`
+
+type InsertRankHistoryParams struct {
+	AddonID  int32          `json:"addon_id"`
+	Category string         `json:"category"`
+	Rank     int16          `json:"rank"`
+	Score    pgtype.Numeric `json:"score"`
+}
+
+// Record current rank for an addon in a category (deprecated: use InsertRankHistoryWithTime)
+func (q *Queries) InsertRankHistory(ctx context.Context, arg InsertRankHistoryParams) error {
+	_, err := q.db.Exec(ctx, insertRankHistory,
+		arg.AddonID,
+		arg.Category,
+		arg.Rank,
+		arg.Score,
+	)
+	return err
+}
+
+const insertRankHistoryWithTime = `-- name: InsertRankHistoryWithTime :exec
+INSERT INTO trending_rank_history (addon_id, category, rank, score, recorded_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type InsertRankHistoryWithTimeParams struct {
+	AddonID    int32              `json:"addon_id"`
+	Category   string             `json:"category"`
+	Rank       int16              `json:"rank"`
+	Score      pgtype.Numeric     `json:"score"`
+	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
+}
+
+// Record current rank with explicit timestamp (use for batch consistency)
+func (q *Queries) InsertRankHistoryWithTime(ctx context.Context, arg InsertRankHistoryWithTimeParams) error {
+	_, err := q.db.Exec(ctx, insertRankHistoryWithTime,
+		arg.AddonID,
+		arg.Category,
+		arg.Rank,
+		arg.Score,
+		arg.RecordedAt,
+	)
+	return err
+}
+
+const insertShadowComparison = `-- name: InsertShadowComparison :exec
+INSERT INTO trending_shadow_comparisons (
+    calculated_at, live_version, shadow_version, list, overlap, rank_correlation, live_churn, shadow_churn
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type InsertShadowComparisonParams struct {
+	CalculatedAt    pgtype.Timestamptz `json:"calculated_at"`
+	LiveVersion     string             `json:"live_version"`
+	ShadowVersion   string             `json:"shadow_version"`
+	List            string             `json:"list"`
+	Overlap         pgtype.Numeric     `json:"overlap"`
+	RankCorrelation pgtype.Numeric     `json:"rank_correlation"`
+	LiveChurn       pgtype.Numeric     `json:"live_churn"`
+	ShadowChurn     pgtype.Numeric     `json:"shadow_churn"`
+}
+
+func (q *Queries) InsertShadowComparison(ctx context.Context, arg InsertShadowComparisonParams) error {
+	_, err := q.db.Exec(ctx, insertShadowComparison,
+		arg.CalculatedAt,
+		arg.LiveVersion,
+		arg.ShadowVersion,
+		arg.List,
+		arg.Overlap,
+		arg.RankCorrelation,
+		arg.LiveChurn,
+		arg.ShadowChurn,
+	)
+	return err
+}
+
+type InsertShadowScoresParams struct {
+	AddonID       int32              `json:"addon_id"`
+	ParamsVersion string             `json:"params_version"`
+	HotScore      pgtype.Numeric     `json:"hot_score"`
+	RisingScore   pgtype.Numeric     `json:"rising_score"`
+	LovedScore    pgtype.Numeric     `json:"loved_score"`
+	FreshScore    pgtype.Numeric     `json:"fresh_score"`
+	FirstHotAt    pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt pgtype.Timestamptz `json:"first_rising_at"`
+	FirstLovedAt  pgtype.Timestamptz `json:"first_loved_at"`
+	HotRank       pgtype.Int2        `json:"hot_rank"`
+	RisingRank    pgtype.Int2        `json:"rising_rank"`
+	LovedRank     pgtype.Int2        `json:"loved_rank"`
+	HotMisses     int16              `json:"hot_misses"`
+	RisingMisses  int16              `json:"rising_misses"`
+	LovedMisses   int16              `json:"loved_misses"`
+	HotRawRank    pgtype.Int2        `json:"hot_raw_rank"`
+	RisingRawRank pgtype.Int2        `json:"rising_raw_rank"`
+	LovedRawRank  pgtype.Int2        `json:"loved_raw_rank"`
+	CalculatedAt  pgtype.Timestamptz `json:"calculated_at"`
+}
+
+type InsertStagedCategoryTrendingScoresParams struct {
+	CategoryID       int32              `json:"category_id"`
+	AddonID          int32              `json:"addon_id"`
+	HotScore         pgtype.Numeric     `json:"hot_score"`
+	RisingScore      pgtype.Numeric     `json:"rising_score"`
+	DownloadVelocity pgtype.Numeric     `json:"download_velocity"`
+	SizeMultiplier   pgtype.Numeric     `json:"size_multiplier"`
+	FirstHotAt       pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt    pgtype.Timestamptz `json:"first_rising_at"`
+	CalculatedAt     pgtype.Timestamptz `json:"calculated_at"`
+	HotRank          pgtype.Int2        `json:"hot_rank"`
+	RisingRank       pgtype.Int2        `json:"rising_rank"`
+	HotMisses        int16              `json:"hot_misses"`
+	RisingMisses     int16              `json:"rising_misses"`
+}
+
+type InsertStagedTrendingScoresParams struct {
+	AddonID               int32              `json:"addon_id"`
+	HotScore              pgtype.Numeric     `json:"hot_score"`
+	RisingScore           pgtype.Numeric     `json:"rising_score"`
+	DownloadVelocity      pgtype.Numeric     `json:"download_velocity"`
+	ThumbsVelocity        pgtype.Numeric     `json:"thumbs_velocity"`
+	DownloadGrowthPct     pgtype.Numeric     `json:"download_growth_pct"`
+	ThumbsGrowthPct       pgtype.Numeric     `json:"thumbs_growth_pct"`
+	SizeMultiplier        pgtype.Numeric     `json:"size_multiplier"`
+	MaintenanceMultiplier pgtype.Numeric     `json:"maintenance_multiplier"`
+	FirstHotAt            pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt         pgtype.Timestamptz `json:"first_rising_at"`
+	CalculatedAt          pgtype.Timestamptz `json:"calculated_at"`
+	ParamsVersion         pgtype.Text        `json:"params_version"`
+	InputsHash            pgtype.Int8        `json:"inputs_hash"`
+	LovedScore            pgtype.Numeric     `json:"loved_score"`
+	FirstLovedAt          pgtype.Timestamptz `json:"first_loved_at"`
+	FreshScore            pgtype.Numeric     `json:"fresh_score"`
+	HotRank               pgtype.Int2        `json:"hot_rank"`
+	RisingRank            pgtype.Int2        `json:"rising_rank"`
+	LovedRank             pgtype.Int2        `json:"loved_rank"`
+	HotMisses             int16              `json:"hot_misses"`
+	RisingMisses          int16              `json:"rising_misses"`
+	LovedMisses           int16              `json:"loved_misses"`
+	HotRawRank            pgtype.Int2        `json:"hot_raw_rank"`
+	RisingRawRank         pgtype.Int2        `json:"rising_raw_rank"`
+	LovedRawRank          pgtype.Int2        `json:"loved_raw_rank"`
+}
+
+const insertSyncRun = `-- name: InsertSyncRun :exec
+INSERT INTO sync_runs (started_at, fetched_count, synced_count, error_count, baseline_count, quarantined, quarantine_reason)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type InsertSyncRunParams struct {
+	StartedAt        pgtype.Timestamptz `json:"started_at"`
+	FetchedCount     int32              `json:"fetched_count"`
+	SyncedCount      int32              `json:"synced_count"`
+	ErrorCount       int32              `json:"error_count"`
+	BaselineCount    pgtype.Int4        `json:"baseline_count"`
+	Quarantined      bool               `json:"quarantined"`
+	QuarantineReason pgtype.Text        `json:"quarantine_reason"`
+}
+
+func (q *Queries) InsertSyncRun(ctx context.Context, arg InsertSyncRunParams) error {
+	_, err := q.db.Exec(ctx, insertSyncRun,
+		arg.StartedAt,
+		arg.FetchedCount,
+		arg.SyncedCount,
+		arg.ErrorCount,
+		arg.BaselineCount,
+		arg.Quarantined,
+		arg.QuarantineReason,
+	)
+	return err
+}
+
+const insertTrendingCalculationRun = `-- name: InsertTrendingCalculationRun :one
+INSERT INTO trending_calculation_runs (params_version, params_source, started_at, processed_count)
+VALUES ($1, $2, $3, $4)
+RETURNING id
+`
+
+type InsertTrendingCalculationRunParams struct {
+	ParamsVersion  string             `json:"params_version"`
+	ParamsSource   string             `json:"params_source"`
+	StartedAt      pgtype.Timestamptz `json:"started_at"`
+	ProcessedCount int32              `json:"processed_count"`
+}
+
+func (q *Queries) InsertTrendingCalculationRun(ctx context.Context, arg InsertTrendingCalculationRunParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertTrendingCalculationRun,
+		arg.ParamsVersion,
+		arg.ParamsSource,
+		arg.StartedAt,
+		arg.ProcessedCount,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const keepArchivedLeaderboard = `-- name: KeepArchivedLeaderboard :exec
+INSERT INTO leaderboard_archive_previous (period, period_start, list, rank, addon_id, score, recorded_at, run_id, replaced_by_run_id)
+SELECT period, period_start, list, rank, addon_id, score, recorded_at, run_id, $1
+FROM leaderboard_archive
+WHERE period = $2 AND period_start = $3 AND list = $4
+`
+
+type KeepArchivedLeaderboardParams struct {
+	ReplacedByRunID int64       `json:"replaced_by_run_id"`
+	Period          string      `json:"period"`
+	PeriodStart     pgtype.Date `json:"period_start"`
+	List            string      `json:"list"`
+}
+
+// Keep an archived list before the given run replaces it
+func (q *Queries) KeepArchivedLeaderboard(ctx context.Context, arg KeepArchivedLeaderboardParams) error {
+	_, err := q.db.Exec(ctx, keepArchivedLeaderboard,
+		arg.ReplacedByRunID,
+		arg.Period,
+		arg.PeriodStart,
+		arg.List,
+	)
+	return err
+}
+
+const keepHeatIndexDay = `-- name: KeepHeatIndexDay :exec
+INSERT INTO heat_index_previous (addon_id, day, heat_index, run_id, replaced_by_run_id)
+SELECT addon_id, day, heat_index, run_id, $1
+FROM heat_index_daily
+WHERE day = $2
+`
+
+type KeepHeatIndexDayParams struct {
+	ReplacedByRunID int64       `json:"replaced_by_run_id"`
+	Day             pgtype.Date `json:"day"`
+}
+
+// Keep the day's heat index before the given run replaces it
+func (q *Queries) KeepHeatIndexDay(ctx context.Context, arg KeepHeatIndexDayParams) error {
+	_, err := q.db.Exec(ctx, keepHeatIndexDay, arg.ReplacedByRunID, arg.Day)
+	return err
+}
+
+const keepOpenStints = `-- name: KeepOpenStints :exec
+INSERT INTO trending_stints_previous (id, replaced_by_run_id, exited_at, last_seen_at, last_rank, best_rank, hours_at_top)
+SELECT id, $1, exited_at, last_seen_at, last_rank, best_rank, hours_at_top
+FROM trending_stints
+WHERE exited_at IS NULL
+`
+
+// Keep the open stints as they are before the given run advances them
+func (q *Queries) KeepOpenStints(ctx context.Context, runID int64) error {
+	_, err := q.db.Exec(ctx, keepOpenStints, runID)
+	return err
+}
+
+const listActiveAddonCategories = `-- name: ListActiveAddonCategories :many
+SELECT a.id AS addon_id, c.id AS category_id
+FROM addons a
+JOIN categories c ON c.id = ANY(a.categories)
+WHERE a.status = 'active'
+  AND c.deleted_at IS NULL
+`
+
+type ListActiveAddonCategoriesRow struct {
+	AddonID    int32 `json:"addon_id"`
+	CategoryID int32 `json:"category_id"`
+}
+
+// Category memberships of every active addon
+func (q *Queries) ListActiveAddonCategories(ctx context.Context) ([]ListActiveAddonCategoriesRow, error) {
+	rows, err := q.db.Query(ctx, listActiveAddonCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActiveAddonCategoriesRow{}
+	for rows.Next() {
+		var i ListActiveAddonCategoriesRow
+		if err := rows.Scan(&i.AddonID, &i.CategoryID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAddonMilestones = `-- name: ListAddonMilestones :many
+SELECT metric, threshold, crossed_at
+FROM milestones
+WHERE addon_id = $1
+ORDER BY crossed_at DESC, id DESC
+`
+
+type ListAddonMilestonesRow struct {
+	Metric    string             `json:"metric"`
+	Threshold int64              `json:"threshold"`
+	CrossedAt pgtype.Timestamptz `json:"crossed_at"`
+}
+
+func (q *Queries) ListAddonMilestones(ctx context.Context, addonID int32) ([]ListAddonMilestonesRow, error) {
+	rows, err := q.db.Query(ctx, listAddonMilestones, addonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAddonMilestonesRow{}
+	for rows.Next() {
+		var i ListAddonMilestonesRow
+		if err := rows.Scan(&i.Metric, &i.Threshold, &i.CrossedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAddonStints = `-- name: ListAddonStints :many
+SELECT category, entered_at, exited_at, last_seen_at, best_rank, hours_at_top
+FROM trending_stints
+WHERE addon_id = $1
+ORDER BY entered_at DESC, id DESC
+`
+
+type ListAddonStintsRow struct {
+	Category   string             `json:"category"`
+	EnteredAt  pgtype.Timestamptz `json:"entered_at"`
+	ExitedAt   pgtype.Timestamptz `json:"exited_at"`
+	LastSeenAt pgtype.Timestamptz `json:"last_seen_at"`
+	BestRank   int16              `json:"best_rank"`
+	HoursAtTop pgtype.Numeric     `json:"hours_at_top"`
+}
+
+func (q *Queries) ListAddonStints(ctx context.Context, addonID int32) ([]ListAddonStintsRow, error) {
+	rows, err := q.db.Query(ctx, listAddonStints, addonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAddonStintsRow{}
+	for rows.Next() {
+		var i ListAddonStintsRow
+		if err := rows.Scan(
+			&i.Category,
+			&i.EnteredAt,
+			&i.ExitedAt,
+			&i.LastSeenAt,
+			&i.BestRank,
+			&i.HoursAtTop,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAddons = `-- name: ListAddons :many
+SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date, comeback_at, heat_index, heat_sparkline FROM addons
+WHERE status = 'active'
+ORDER BY
+    CASE WHEN $1::text = 'heat' THEN heat_index END DESC NULLS LAST,
+    download_count DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListAddonsParams struct {
+	Sort   string `json:"sort"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+// Sort is 'heat' for heat index first, otherwise by downloads
+func (q *Queries) ListAddons(ctx context.Context, arg ListAddonsParams) ([]Addon, error) {
+	rows, err := q.db.Query(ctx, listAddons, arg.Sort, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Addon{}
+	for rows.Next() {
+		var i Addon
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Summary,
+			&i.AuthorName,
+			&i.AuthorID,
+			&i.LogoUrl,
+			&i.PrimaryCategoryID,
+			&i.Categories,
+			&i.GameVersions,
+			&i.CreatedAt,
+			&i.LastUpdatedAt,
+			&i.LastSyncedAt,
+			&i.IsHot,
+			&i.HotUntil,
+			&i.Status,
+			&i.DownloadCount,
+			&i.ThumbsUpCount,
+			&i.PopularityRank,
+			&i.Rating,
+			&i.LatestFileDate,
+			&i.ComebackAt,
+			&i.HeatIndex,
+			&i.HeatSparkline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAddonsByCategory = `-- name: ListAddonsByCategory :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline FROM addons a
+WHERE a.status = 'active'
+  AND $1::int = ANY(a.categories)
+ORDER BY
+    CASE WHEN $2::text = 'heat' THEN a.heat_index END DESC NULLS LAST,
+    a.download_count DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListAddonsByCategoryParams struct {
+	CategoryID int32  `json:"category_id"`
+	Sort       string `json:"sort"`
+	Limit      int32  `json:"limit"`
+	Offset     int32  `json:"offset"`
+}
+
+func (q *Queries) ListAddonsByCategory(ctx context.Context, arg ListAddonsByCategoryParams) ([]Addon, error) {
+	rows, err := q.db.Query(ctx, listAddonsByCategory,
+		arg.CategoryID,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Addon{}
+	for rows.Next() {
+		var i Addon
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Summary,
+			&i.AuthorName,
+			&i.AuthorID,
+			&i.LogoUrl,
+			&i.PrimaryCategoryID,
+			&i.Categories,
+			&i.GameVersions,
+			&i.CreatedAt,
+			&i.LastUpdatedAt,
+			&i.LastSyncedAt,
+			&i.IsHot,
+			&i.HotUntil,
+			&i.Status,
+			&i.DownloadCount,
+			&i.ThumbsUpCount,
+			&i.PopularityRank,
+			&i.Rating,
+			&i.LatestFileDate,
+			&i.ComebackAt,
+			&i.HeatIndex,
+			&i.HeatSparkline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAddonsForDiff = `-- name: ListAddonsForDiff :many
+SELECT id, name, slug, status, categories, download_count
+FROM addons
+`
+
+type ListAddonsForDiffRow struct {
+	ID            int32       `json:"id"`
+	Name          string      `json:"name"`
+	Slug          string      `json:"slug"`
+	Status        pgtype.Text `json:"status"`
+	Categories    []int32     `json:"categories"`
+	DownloadCount pgtype.Int8 `json:"download_count"`
+}
+
+// Current addon state for comparing against a dry-run sync
+func (q *Queries) ListAddonsForDiff(ctx context.Context) ([]ListAddonsForDiffRow, error) {
+	rows, err := q.db.Query(ctx, listAddonsForDiff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAddonsForDiffRow{}
+	for rows.Next() {
+		var i ListAddonsForDiffRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Status,
+			&i.Categories,
+			&i.DownloadCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAddonsForTrendingCalc = `-- name: ListAddonsForTrendingCalc :many
+SELECT id, download_count, thumbs_up_count, latest_file_date, created_at
+FROM addons
+WHERE status = 'active'
+`
+
+type ListAddonsForTrendingCalcRow struct {
+	ID             int32              `json:"id"`
+	DownloadCount  pgtype.Int8        `json:"download_count"`
+	ThumbsUpCount  pgtype.Int4        `json:"thumbs_up_count"`
+	LatestFileDate pgtype.Timestamptz `json:"latest_file_date"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+// Get addons with basic info needed for trending calculation
+func (q *Queries) ListAddonsForTrendingCalc(ctx context.Context) ([]ListAddonsForTrendingCalcRow, error) {
+	rows, err := q.db.Query(ctx, listAddonsForTrendingCalc)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAddonsForTrendingCalcRow{}
+	for rows.Next() {
+		var i ListAddonsForTrendingCalcRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.DownloadCount,
+			&i.ThumbsUpCount,
+			&i.LatestFileDate,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAddonsProjectedToCross = `-- name: ListAddonsProjectedToCross :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, f.projected_24h, f.projected_7d, f.low_7d, f.high_7d, f.calculated_at
+FROM addon_forecasts f
+JOIN addons a ON a.id = f.addon_id
+WHERE a.status = 'active'
+  AND a.download_count < $1::bigint
+  AND f.projected_7d >= $1::bigint
+ORDER BY a.download_count DESC, a.id
+LIMIT $2 OFFSET $3
+`
+
+type ListAddonsProjectedToCrossParams struct {
+	Downloads  int64 `json:"downloads"`
+	PageSize   int32 `json:"page_size"`
+	PageOffset int32 `json:"page_offset"`
+}
+
+type ListAddonsProjectedToCrossRow struct {
+	Addon        Addon              `json:"addon"`
+	Projected24h int64              `json:"projected_24h"`
+	Projected7d  int64              `json:"projected_7d"`
+	Low7d        int64              `json:"low_7d"`
+	High7d       int64              `json:"high_7d"`
+	CalculatedAt pgtype.Timestamptz `json:"calculated_at"`
+}
+
+// Active addons still below the given download count that are projected to
+// reach it within 7 days, nearest to the count first
+func (q *Queries) ListAddonsProjectedToCross(ctx context.Context, arg ListAddonsProjectedToCrossParams) ([]ListAddonsProjectedToCrossRow, error) {
+	rows, err := q.db.Query(ctx, listAddonsProjectedToCross, arg.Downloads, arg.PageSize, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAddonsProjectedToCrossRow{}
+	for rows.Next() {
+		var i ListAddonsProjectedToCrossRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.Projected24h,
+			&i.Projected7d,
+			&i.Low7d,
+			&i.High7d,
+			&i.CalculatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArchivedLeaderboard = `-- name: ListArchivedLeaderboard :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, l.rank, l.score, l.recorded_at
+FROM leaderboard_archive l
+JOIN addons a ON a.id = l.addon_id
+WHERE l.period = $1 AND l.period_start = $2 AND l.list = $3
+ORDER BY l.rank
+`
+
+type ListArchivedLeaderboardParams struct {
+	Period      string      `json:"period"`
+	PeriodStart pgtype.Date `json:"period_start"`
+	List        string      `json:"list"`
+}
+
+type ListArchivedLeaderboardRow struct {
+	Addon      Addon              `json:"addon"`
+	Rank       int16              `json:"rank"`
+	Score      pgtype.Numeric     `json:"score"`
+	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
+}
+
+// An archived list with its addons, by rank
+func (q *Queries) ListArchivedLeaderboard(ctx context.Context, arg ListArchivedLeaderboardParams) ([]ListArchivedLeaderboardRow, error) {
+	rows, err := q.db.Query(ctx, listArchivedLeaderboard, arg.Period, arg.PeriodStart, arg.List)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListArchivedLeaderboardRow{}
+	for rows.Next() {
+		var i ListArchivedLeaderboardRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.Rank,
+			&i.Score,
+			&i.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCategories = `-- name: ListCategories :many
+SELECT id, name, slug, parent_id, icon_url, class_id, is_class, display_index, deleted_at FROM categories WHERE deleted_at IS NULL ORDER BY name
+`
+
+func (q *Queries) ListCategories(ctx context.Context) ([]Category, error) {
+	rows, err := q.db.Query(ctx, listCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Category{}
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.ParentID,
+			&i.IconUrl,
+			&i.ClassID,
+			&i.IsClass,
+			&i.DisplayIndex,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCategoryHotAddonsPaginated = `-- name: ListCategoryHotAddonsPaginated :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.hot_score, t.download_velocity
+FROM addons a
+JOIN category_trending_scores t ON a.id = t.addon_id
+WHERE t.category_id = $1
+  AND a.status = 'active'
+  AND a.download_count >= $2::bigint
+  AND t.hot_score > 0
+ORDER BY t.hot_rank NULLS LAST, t.hot_score DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListCategoryHotAddonsPaginatedParams struct {
+	CategoryID   int32 `json:"category_id"`
+	MinDownloads int64 `json:"min_downloads"`
+	PageSize     int32 `json:"page_size"`
+	PageOffset   int32 `json:"page_offset"`
+}
+
+type ListCategoryHotAddonsPaginatedRow struct {
+	Addon            Addon          `json:"addon"`
+	HotScore         pgtype.Numeric `json:"hot_score"`
+	DownloadVelocity pgtype.Numeric `json:"download_velocity"`
+}
+
+func (q *Queries) ListCategoryHotAddonsPaginated(ctx context.Context, arg ListCategoryHotAddonsPaginatedParams) ([]ListCategoryHotAddonsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listCategoryHotAddonsPaginated,
+		arg.CategoryID,
+		arg.MinDownloads,
+		arg.PageSize,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCategoryHotAddonsPaginatedRow{}
+	for rows.Next() {
+		var i ListCategoryHotAddonsPaginatedRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.HotScore,
+			&i.DownloadVelocity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCategoryRisingAddonsPaginated = `-- name: ListCategoryRisingAddonsPaginated :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.rising_score, t.download_velocity
+FROM addons a
+JOIN category_trending_scores t ON a.id = t.addon_id
+WHERE t.category_id = $1
+  AND a.status = 'active'
+  AND a.download_count >= $2::bigint
+  AND a.download_count <= $3::bigint
+  AND t.rising_score > 0
+  AND a.id NOT IN (
+      SELECT addon_id FROM category_trending_scores
+      WHERE category_id = $1
+        AND hot_score > 0
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT $4
+  )
+ORDER BY t.rising_rank NULLS LAST, t.rising_score DESC
+LIMIT $5 OFFSET $6
+`
+
+type ListCategoryRisingAddonsPaginatedParams struct {
+	CategoryID   int32 `json:"category_id"`
+	MinDownloads int64 `json:"min_downloads"`
+	MaxDownloads int64 `json:"max_downloads"`
+	HotListSize  int32 `json:"hot_list_size"`
+	PageSize     int32 `json:"page_size"`
+	PageOffset   int32 `json:"page_offset"`
+}
+
+type ListCategoryRisingAddonsPaginatedRow struct {
+	Addon            Addon          `json:"addon"`
+	RisingScore      pgtype.Numeric `json:"rising_score"`
+	DownloadVelocity pgtype.Numeric `json:"download_velocity"`
+}
+
+// Rising within a category excludes the top of that category's hot list
+func (q *Queries) ListCategoryRisingAddonsPaginated(ctx context.Context, arg ListCategoryRisingAddonsPaginatedParams) ([]ListCategoryRisingAddonsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listCategoryRisingAddonsPaginated,
+		arg.CategoryID,
+		arg.MinDownloads,
+		arg.MaxDownloads,
+		arg.HotListSize,
+		arg.PageSize,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCategoryRisingAddonsPaginatedRow{}
+	for rows.Next() {
+		var i ListCategoryRisingAddonsPaginatedRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.RisingScore,
+			&i.DownloadVelocity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listComebackCandidates = `-- name: ListComebackCandidates :many
+WITH released AS (
+    SELECT id AS addon_id, latest_file_date
+    FROM addons
+    WHERE status = 'active'
+      AND latest_file_date >= $1::timestamptz
+),
+before_release AS (
+    SELECT
+        s.addon_id,
+        MAX(s.latest_file_date)::timestamptz AS previous_release_date,
+        (MAX(s.download_count) - MIN(s.download_count))::bigint AS download_change,
+        (EXTRACT(EPOCH FROM MAX(s.recorded_at) - MIN(s.recorded_at)) / 3600)::float8 AS hours
+    FROM snapshots s
+    JOIN released r ON r.addon_id = s.addon_id
+    WHERE s.latest_file_date < r.latest_file_date
+    GROUP BY s.addon_id
+),
+since_release AS (
+    SELECT
+        s.addon_id,
+        (MAX(s.download_count) - MIN(s.download_count))::bigint AS download_change,
+        (EXTRACT(EPOCH FROM MAX(s.recorded_at) - MIN(s.recorded_at)) / 3600)::float8 AS hours
+    FROM snapshots s
+    JOIN released r ON r.addon_id = s.addon_id
+    WHERE s.latest_file_date >= r.latest_file_date
+    GROUP BY s.addon_id
+)
+SELECT
+    r.addon_id,
+    r.latest_file_date AS release_date,
+    b.previous_release_date,
+    b.download_change AS dormant_download_change,
+    b.hours AS dormant_hours,
+    s.download_change AS revived_download_change,
+    s.hours AS revived_hours
+FROM released r
+JOIN before_release b ON b.addon_id = r.addon_id
+JOIN since_release s ON s.addon_id = r.addon_id
+WHERE NOT EXISTS (
+    SELECT 1 FROM comeback_events e
+    WHERE e.addon_id = r.addon_id AND e.release_date = r.latest_file_date
+)
+`
+
+type ListComebackCandidatesRow struct {
+	AddonID               int32              `json:"addon_id"`
+	ReleaseDate           pgtype.Timestamptz `json:"release_date"`
+	PreviousReleaseDate   pgtype.Timestamptz `json:"previous_release_date"`
+	DormantDownloadChange int64              `json:"dormant_download_change"`
+	DormantHours          float64            `json:"dormant_hours"`
+	RevivedDownloadChange int64              `json:"revived_download_change"`
+	RevivedHours          float64            `json:"revived_hours"`
+}
+
+// Addons with a file released since released_after, with their downloads
+// before and since that release as seen in snapshots. Releases already
+// recorded as comebacks are skipped.
+func (q *Queries) ListComebackCandidates(ctx context.Context, releasedAfter pgtype.Timestamptz) ([]ListComebackCandidatesRow, error) {
+	rows, err := q.db.Query(ctx, listComebackCandidates, releasedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListComebackCandidatesRow{}
+	for rows.Next() {
+		var i ListComebackCandidatesRow
+		if err := rows.Scan(
+			&i.AddonID,
+			&i.ReleaseDate,
+			&i.PreviousReleaseDate,
+			&i.DormantDownloadChange,
+			&i.DormantHours,
+			&i.RevivedDownloadChange,
+			&i.RevivedHours,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listComebacks = `-- name: ListComebacks :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, e.release_date, e.previous_release_date, e.dormant_velocity, e.revived_velocity, e.detected_at
+FROM comeback_events e
+JOIN addons a ON a.id = e.addon_id
+WHERE a.status = 'active'
+  AND e.detected_at >= $1::timestamptz
+ORDER BY e.detected_at DESC, e.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListComebacksParams struct {
+	Since      pgtype.Timestamptz `json:"since"`
+	PageSize   int32              `json:"page_size"`
+	PageOffset int32              `json:"page_offset"`
+}
+
+type ListComebacksRow struct {
+	Addon               Addon              `json:"addon"`
+	ReleaseDate         pgtype.Timestamptz `json:"release_date"`
+	PreviousReleaseDate pgtype.Timestamptz `json:"previous_release_date"`
+	DormantVelocity     pgtype.Numeric     `json:"dormant_velocity"`
+	RevivedVelocity     pgtype.Numeric     `json:"revived_velocity"`
+	DetectedAt          pgtype.Timestamptz `json:"detected_at"`
+}
+
+// Comebacks detected since the given time, most recent first
+func (q *Queries) ListComebacks(ctx context.Context, arg ListComebacksParams) ([]ListComebacksRow, error) {
+	rows, err := q.db.Query(ctx, listComebacks, arg.Since, arg.PageSize, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListComebacksRow{}
+	for rows.Next() {
+		var i ListComebacksRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.ReleaseDate,
+			&i.PreviousReleaseDate,
+			&i.DormantVelocity,
+			&i.RevivedVelocity,
+			&i.DetectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFreshAddons = `-- name: ListFreshAddons :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.fresh_score, t.download_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND t.fresh_score > 0
+ORDER BY t.fresh_score DESC
+LIMIT $2
+`
+
+type ListFreshAddonsParams struct {
+	MinDownloads int64 `json:"min_downloads"`
+	LimitCount   int32 `json:"limit_count"`
+}
+
+type ListFreshAddonsRow struct {
+	ID                int32              `json:"id"`
+	Name              string             `json:"name"`
+	Slug              string             `json:"slug"`
+	Summary           pgtype.Text        `json:"summary"`
+	AuthorName        pgtype.Text        `json:"author_name"`
+	AuthorID          pgtype.Int4        `json:"author_id"`
+	LogoUrl           pgtype.Text        `json:"logo_url"`
+	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
+	Categories        []int32            `json:"categories"`
+	GameVersions      []string           `json:"game_versions"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	LastUpdatedAt     pgtype.Timestamptz `json:"last_updated_at"`
+	LastSyncedAt      pgtype.Timestamptz `json:"last_synced_at"`
+	IsHot             pgtype.Bool        `json:"is_hot"`
+	HotUntil          pgtype.Timestamptz `json:"hot_until"`
+	Status            pgtype.Text        `json:"status"`
+	DownloadCount     pgtype.Int8        `json:"download_count"`
+	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
+	PopularityRank    pgtype.Int4        `json:"popularity_rank"`
+	Rating            pgtype.Numeric     `json:"rating"`
+	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
+	ComebackAt        pgtype.Timestamptz `json:"comeback_at"`
+	HeatIndex         pgtype.Int2        `json:"heat_index"`
+	HeatSparkline     []int16            `json:"heat_sparkline"`
+	FreshScore        pgtype.Numeric     `json:"fresh_score"`
+	DownloadVelocity  pgtype.Numeric     `json:"download_velocity"`
+}
+
+func (q *Queries) ListFreshAddons(ctx context.Context, arg ListFreshAddonsParams) ([]ListFreshAddonsRow, error) {
+	rows, err := q.db.Query(ctx, listFreshAddons, arg.MinDownloads, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFreshAddonsRow{}
+	for rows.Next() {
+		var i ListFreshAddonsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Summary,
+			&i.AuthorName,
+			&i.AuthorID,
+			&i.LogoUrl,
+			&i.PrimaryCategoryID,
+			&i.Categories,
+			&i.GameVersions,
+			&i.CreatedAt,
+			&i.LastUpdatedAt,
+			&i.LastSyncedAt,
+			&i.IsHot,
+			&i.HotUntil,
+			&i.Status,
+			&i.DownloadCount,
+			&i.ThumbsUpCount,
+			&i.PopularityRank,
+			&i.Rating,
+			&i.LatestFileDate,
+			&i.ComebackAt,
+			&i.HeatIndex,
+			&i.HeatSparkline,
+			&i.FreshScore,
+			&i.DownloadVelocity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFreshAddonsPaginated = `-- name: ListFreshAddonsPaginated :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.fresh_score, t.download_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND t.fresh_score > 0
+  AND a.created_at >= $2::timestamptz
+ORDER BY t.fresh_score DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListFreshAddonsPaginatedParams struct {
+	MinDownloads int64              `json:"min_downloads"`
+	CreatedAfter pgtype.Timestamptz `json:"created_after"`
+	PageSize     int32              `json:"page_size"`
+	PageOffset   int32              `json:"page_offset"`
+}
+
+type ListFreshAddonsPaginatedRow struct {
+	Addon            Addon          `json:"addon"`
+	FreshScore       pgtype.Numeric `json:"fresh_score"`
+	DownloadVelocity pgtype.Numeric `json:"download_velocity"`
+}
+
+// Fresh list entries created since the given time
+func (q *Queries) ListFreshAddonsPaginated(ctx context.Context, arg ListFreshAddonsPaginatedParams) ([]ListFreshAddonsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listFreshAddonsPaginated,
+		arg.MinDownloads,
+		arg.CreatedAfter,
+		arg.PageSize,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFreshAddonsPaginatedRow{}
+	for rows.Next() {
+		var i ListFreshAddonsPaginatedRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.FreshScore,
+			&i.DownloadVelocity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHotAddons = `-- name: ListHotAddons :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.hot_score, t.download_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND t.hot_score > 0
+ORDER BY t.hot_rank NULLS LAST, t.hot_score DESC
+LIMIT $2
 `
 
-type InsertRankHistoryWithTimeParams struct {
-	AddonID    int32              `json:"addon_id"`
-	Category   string             `json:"category"`
-	Rank       int16              `json:"rank"`
-	Score      pgtype.Numeric     `json:"score"`
-	RecordedAt pgtype.Timestamptz `json:"recorded_at"`
-}
-
-// Record current rank with explicit timestamp (use for batch consistency)
-func (q *Queries) InsertRankHistoryWithTime(ctx context.Context, arg InsertRankHistoryWithTimeParams) error {
-	_, err := q.db.Exec(ctx, insertRankHistoryWithTime,
-		arg.AddonID,
-		arg.Category,
-		arg.Rank,
-		arg.Score,
-		arg.RecordedAt,
-	)
-	return err
+type ListHotAddonsParams struct {
+	MinDownloads int64 `json:"min_downloads"`
+	LimitCount   int32 `json:"limit_count"`
 }
 
-const listAddons = `-- name: ListAddons :many
-SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date FROM addons
-WHERE status = 'active'
-ORDER BY download_count DESC
-LIMIT $1 OFFSET $2
-`
-
-type ListAddonsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type ListHotAddonsRow struct {
+	ID                int32              `json:"id"`
+	Name              string             `json:"name"`
+	Slug              string             `json:"slug"`
+	Summary           pgtype.Text        `json:"summary"`
+	AuthorName        pgtype.Text        `json:"author_name"`
+	AuthorID          pgtype.Int4        `json:"author_id"`
+	LogoUrl           pgtype.Text        `json:"logo_url"`
+	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
+	Categories        []int32            `json:"categories"`
+	GameVersions      []string           `json:"game_versions"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	LastUpdatedAt     pgtype.Timestamptz `json:"last_updated_at"`
+	LastSyncedAt      pgtype.Timestamptz `json:"last_synced_at"`
+	IsHot             pgtype.Bool        `json:"is_hot"`
+	HotUntil          pgtype.Timestamptz `json:"hot_until"`
+	Status            pgtype.Text        `json:"status"`
+	DownloadCount     pgtype.Int8        `json:"download_count"`
+	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
+	PopularityRank    pgtype.Int4        `json:"popularity_rank"`
+	Rating            pgtype.Numeric     `json:"rating"`
+	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
+	ComebackAt        pgtype.Timestamptz `json:"comeback_at"`
+	HeatIndex         pgtype.Int2        `json:"heat_index"`
+	HeatSparkline     []int16            `json:"heat_sparkline"`
+	HotScore          pgtype.Numeric     `json:"hot_score"`
+	DownloadVelocity  pgtype.Numeric     `json:"download_velocity"`
 }
 
-func (q *Queries) ListAddons(ctx context.Context, arg ListAddonsParams) ([]Addon, error) {
-	rows, err := q.db.Query(ctx, listAddons, arg.Limit, arg.Offset)
+func (q *Queries) ListHotAddons(ctx context.Context, arg ListHotAddonsParams) ([]ListHotAddonsRow, error) {
+	rows, err := q.db.Query(ctx, listHotAddons, arg.MinDownloads, arg.LimitCount)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Addon{}
+	items := []ListHotAddonsRow{}
 	for rows.Next() {
-		var i Addon
+		var i ListHotAddonsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -840,6 +3733,11 @@ func (q *Queries) ListAddons(ctx context.Context, arg ListAddonsParams) ([]Addon
 			&i.PopularityRank,
 			&i.Rating,
 			&i.LatestFileDate,
+			&i.ComebackAt,
+			&i.HeatIndex,
+			&i.HeatSparkline,
+			&i.HotScore,
+			&i.DownloadVelocity,
 		); err != nil {
 			return nil, err
 		}
@@ -851,29 +3749,332 @@ func (q *Queries) ListAddons(ctx context.Context, arg ListAddonsParams) ([]Addon
 	return items, nil
 }
 
-const listAddonsByCategory = `-- name: ListAddonsByCategory :many
-SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date FROM addons a
+const listHotAddonsPaginated = `-- name: ListHotAddonsPaginated :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.hot_score, t.download_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND $3::int = ANY(a.categories)
-ORDER BY a.download_count DESC
-LIMIT $1 OFFSET $2
+  AND a.download_count >= $1::bigint
+  AND t.hot_score > 0
+ORDER BY CASE WHEN $2::boolean THEN t.hot_raw_rank ELSE t.hot_rank END NULLS LAST,
+    t.hot_score DESC
+LIMIT $3 OFFSET $4
 `
 
-type ListAddonsByCategoryParams struct {
-	Limit   int32 `json:"limit"`
-	Offset  int32 `json:"offset"`
-	Column3 int32 `json:"column_3"`
+type ListHotAddonsPaginatedParams struct {
+	MinDownloads int64 `json:"min_downloads"`
+	RawOrder     bool  `json:"raw_order"`
+	PageSize     int32 `json:"page_size"`
+	PageOffset   int32 `json:"page_offset"`
 }
 
-func (q *Queries) ListAddonsByCategory(ctx context.Context, arg ListAddonsByCategoryParams) ([]Addon, error) {
-	rows, err := q.db.Query(ctx, listAddonsByCategory, arg.Limit, arg.Offset, arg.Column3)
+type ListHotAddonsPaginatedRow struct {
+	Addon            Addon          `json:"addon"`
+	HotScore         pgtype.Numeric `json:"hot_score"`
+	DownloadVelocity pgtype.Numeric `json:"download_velocity"`
+}
+
+func (q *Queries) ListHotAddonsPaginated(ctx context.Context, arg ListHotAddonsPaginatedParams) ([]ListHotAddonsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listHotAddonsPaginated, arg.MinDownloads, arg.RawOrder, arg.PageSize, arg.PageOffset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Addon{}
+	items := []ListHotAddonsPaginatedRow{}
 	for rows.Next() {
-		var i Addon
+		var i ListHotAddonsPaginatedRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.HotScore,
+			&i.DownloadVelocity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHourlyDownloads = `-- name: ListHourlyDownloads :many
+SELECT
+    h.addon_id,
+    array_agg(h.hour ORDER BY h.hour)::bigint[] AS hours,
+    array_agg(h.download_count ORDER BY h.hour)::bigint[] AS download_counts
+FROM (
+    SELECT
+        s.addon_id,
+        FLOOR(EXTRACT(EPOCH FROM s.recorded_at) / 3600)::bigint AS hour,
+        MAX(s.download_count) AS download_count
+    FROM snapshots s
+    JOIN addons a ON a.id = s.addon_id
+    WHERE a.status = 'active'
+      AND s.recorded_at >= $1::timestamptz
+    GROUP BY s.addon_id, hour
+) h
+GROUP BY h.addon_id
+`
+
+type ListHourlyDownloadsRow struct {
+	AddonID        int32   `json:"addon_id"`
+	Hours          []int64 `json:"hours"`
+	DownloadCounts []int64 `json:"download_counts"`
+}
+
+// Highest download count in each hour since the given time, for every active
+// addon, as arrays in hour order. Hours are counted from the Unix epoch.
+func (q *Queries) ListHourlyDownloads(ctx context.Context, since pgtype.Timestamptz) ([]ListHourlyDownloadsRow, error) {
+	rows, err := q.db.Query(ctx, listHourlyDownloads, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListHourlyDownloadsRow{}
+	for rows.Next() {
+		var i ListHourlyDownloadsRow
+		if err := rows.Scan(&i.AddonID, &i.Hours, &i.DownloadCounts); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInactiveAddonIDs = `-- name: ListInactiveAddonIDs :many
+SELECT id FROM addons WHERE status = 'inactive'
+`
+
+func (q *Queries) ListInactiveAddonIDs(ctx context.Context) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listInactiveAddonIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobLocks = `-- name: ListJobLocks :many
+SELECT
+    l.job_name, l.holder, l.backend_pid, l.acquired_at, l.heartbeat_at,
+    EXISTS (SELECT 1 FROM pg_stat_activity a WHERE a.pid = l.backend_pid) AS holder_connected
+FROM job_locks l
+ORDER BY l.job_name
+`
+
+type ListJobLocksRow struct {
+	JobName         string             `json:"job_name"`
+	Holder          string             `json:"holder"`
+	BackendPid      int32              `json:"backend_pid"`
+	AcquiredAt      pgtype.Timestamptz `json:"acquired_at"`
+	HeartbeatAt     pgtype.Timestamptz `json:"heartbeat_at"`
+	HolderConnected bool               `json:"holder_connected"`
+}
+
+func (q *Queries) ListJobLocks(ctx context.Context) ([]ListJobLocksRow, error) {
+	rows, err := q.db.Query(ctx, listJobLocks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListJobLocksRow{}
+	for rows.Next() {
+		var i ListJobLocksRow
+		if err := rows.Scan(
+			&i.JobName,
+			&i.Holder,
+			&i.BackendPid,
+			&i.AcquiredAt,
+			&i.HeartbeatAt,
+			&i.HolderConnected,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLongestStints = `-- name: ListLongestStints :many
+WITH longest AS (
+    SELECT DISTINCT ON (addon_id)
+        addon_id, entered_at, exited_at, best_rank, hours_at_top,
+        (EXTRACT(EPOCH FROM COALESCE(exited_at, last_seen_at) - entered_at) / 3600)::float8 AS hours
+    FROM trending_stints
+    WHERE category = $1
+    ORDER BY addon_id, COALESCE(exited_at, last_seen_at) - entered_at DESC, id
+)
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, l.entered_at, l.exited_at, l.hours, l.best_rank, l.hours_at_top
+FROM longest l
+JOIN addons a ON a.id = l.addon_id
+WHERE a.status = 'active'
+ORDER BY l.hours DESC, l.addon_id
+LIMIT $2 OFFSET $3
+`
+
+type ListLongestStintsParams struct {
+	Category   string `json:"category"`
+	PageSize   int32  `json:"page_size"`
+	PageOffset int32  `json:"page_offset"`
+}
+
+type ListLongestStintsRow struct {
+	Addon      Addon              `json:"addon"`
+	EnteredAt  pgtype.Timestamptz `json:"entered_at"`
+	ExitedAt   pgtype.Timestamptz `json:"exited_at"`
+	Hours      float64            `json:"hours"`
+	BestRank   int16              `json:"best_rank"`
+	HoursAtTop pgtype.Numeric     `json:"hours_at_top"`
+}
+
+// Each active addon's longest stint on a list, longest first. Stints still
+// running count up to the last calculation that listed the addon.
+func (q *Queries) ListLongestStints(ctx context.Context, arg ListLongestStintsParams) ([]ListLongestStintsRow, error) {
+	rows, err := q.db.Query(ctx, listLongestStints, arg.Category, arg.PageSize, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLongestStintsRow{}
+	for rows.Next() {
+		var i ListLongestStintsRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.EnteredAt,
+			&i.ExitedAt,
+			&i.Hours,
+			&i.BestRank,
+			&i.HoursAtTop,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLovedAddons = `-- name: ListLovedAddons :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.loved_score, t.download_velocity, t.thumbs_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND t.loved_score > 0
+ORDER BY t.loved_rank NULLS LAST, t.loved_score DESC
+LIMIT $2
+`
+
+type ListLovedAddonsParams struct {
+	MinDownloads int64 `json:"min_downloads"`
+	LimitCount   int32 `json:"limit_count"`
+}
+
+type ListLovedAddonsRow struct {
+	ID                int32              `json:"id"`
+	Name              string             `json:"name"`
+	Slug              string             `json:"slug"`
+	Summary           pgtype.Text        `json:"summary"`
+	AuthorName        pgtype.Text        `json:"author_name"`
+	AuthorID          pgtype.Int4        `json:"author_id"`
+	LogoUrl           pgtype.Text        `json:"logo_url"`
+	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
+	Categories        []int32            `json:"categories"`
+	GameVersions      []string           `json:"game_versions"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	LastUpdatedAt     pgtype.Timestamptz `json:"last_updated_at"`
+	LastSyncedAt      pgtype.Timestamptz `json:"last_synced_at"`
+	IsHot             pgtype.Bool        `json:"is_hot"`
+	HotUntil          pgtype.Timestamptz `json:"hot_until"`
+	Status            pgtype.Text        `json:"status"`
+	DownloadCount     pgtype.Int8        `json:"download_count"`
+	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
+	PopularityRank    pgtype.Int4        `json:"popularity_rank"`
+	Rating            pgtype.Numeric     `json:"rating"`
+	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
+	ComebackAt        pgtype.Timestamptz `json:"comeback_at"`
+	HeatIndex         pgtype.Int2        `json:"heat_index"`
+	HeatSparkline     []int16            `json:"heat_sparkline"`
+	LovedScore        pgtype.Numeric     `json:"loved_score"`
+	DownloadVelocity  pgtype.Numeric     `json:"download_velocity"`
+	ThumbsVelocity    pgtype.Numeric     `json:"thumbs_velocity"`
+}
+
+func (q *Queries) ListLovedAddons(ctx context.Context, arg ListLovedAddonsParams) ([]ListLovedAddonsRow, error) {
+	rows, err := q.db.Query(ctx, listLovedAddons, arg.MinDownloads, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLovedAddonsRow{}
+	for rows.Next() {
+		var i ListLovedAddonsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -896,6 +4097,12 @@ func (q *Queries) ListAddonsByCategory(ctx context.Context, arg ListAddonsByCate
 			&i.PopularityRank,
 			&i.Rating,
 			&i.LatestFileDate,
+			&i.ComebackAt,
+			&i.HeatIndex,
+			&i.HeatSparkline,
+			&i.LovedScore,
+			&i.DownloadVelocity,
+			&i.ThumbsVelocity,
 		); err != nil {
 			return nil, err
 		}
@@ -907,36 +4114,142 @@ func (q *Queries) ListAddonsByCategory(ctx context.Context, arg ListAddonsByCate
 	return items, nil
 }
 
-const listAddonsForTrendingCalc = `-- name: ListAddonsForTrendingCalc :many
-SELECT id, download_count, thumbs_up_count, latest_file_date, created_at
-FROM addons
-WHERE status = 'active'
+const listLovedAddonsPaginated = `-- name: ListLovedAddonsPaginated :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.loved_score, t.download_velocity, t.thumbs_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= $1::bigint
+  AND t.loved_score > 0
+ORDER BY CASE WHEN $2::boolean THEN t.loved_raw_rank ELSE t.loved_rank END NULLS LAST,
+    t.loved_score DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListLovedAddonsPaginatedParams struct {
+	MinDownloads int64 `json:"min_downloads"`
+	RawOrder     bool  `json:"raw_order"`
+	PageSize     int32 `json:"page_size"`
+	PageOffset   int32 `json:"page_offset"`
+}
+
+type ListLovedAddonsPaginatedRow struct {
+	Addon            Addon          `json:"addon"`
+	LovedScore       pgtype.Numeric `json:"loved_score"`
+	DownloadVelocity pgtype.Numeric `json:"download_velocity"`
+	ThumbsVelocity   pgtype.Numeric `json:"thumbs_velocity"`
+}
+
+func (q *Queries) ListLovedAddonsPaginated(ctx context.Context, arg ListLovedAddonsPaginatedParams) ([]ListLovedAddonsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listLovedAddonsPaginated, arg.MinDownloads, arg.RawOrder, arg.PageSize, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLovedAddonsPaginatedRow{}
+	for rows.Next() {
+		var i ListLovedAddonsPaginatedRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.LovedScore,
+			&i.DownloadVelocity,
+			&i.ThumbsVelocity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMilestones = `-- name: ListMilestones :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, m.metric, m.threshold, m.crossed_at
+FROM milestones m
+JOIN addons a ON a.id = m.addon_id
+WHERE a.status = 'active'
+  AND ($1::text = '' OR m.metric = $1::text)
+ORDER BY m.crossed_at DESC, m.id DESC
+LIMIT $2 OFFSET $3
 `
 
-type ListAddonsForTrendingCalcRow struct {
-	ID             int32              `json:"id"`
-	DownloadCount  pgtype.Int8        `json:"download_count"`
-	ThumbsUpCount  pgtype.Int4        `json:"thumbs_up_count"`
-	LatestFileDate pgtype.Timestamptz `json:"latest_file_date"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+type ListMilestonesParams struct {
+	Metric     string `json:"metric"`
+	PageSize   int32  `json:"page_size"`
+	PageOffset int32  `json:"page_offset"`
 }
 
-// Get addons with basic info needed for trending calculation
-func (q *Queries) ListAddonsForTrendingCalc(ctx context.Context) ([]ListAddonsForTrendingCalcRow, error) {
-	rows, err := q.db.Query(ctx, listAddonsForTrendingCalc)
+type ListMilestonesRow struct {
+	Addon     Addon              `json:"addon"`
+	Metric    string             `json:"metric"`
+	Threshold int64              `json:"threshold"`
+	CrossedAt pgtype.Timestamptz `json:"crossed_at"`
+}
+
+// Milestones of active addons, most recently crossed first. An empty metric
+// lists every metric.
+func (q *Queries) ListMilestones(ctx context.Context, arg ListMilestonesParams) ([]ListMilestonesRow, error) {
+	rows, err := q.db.Query(ctx, listMilestones, arg.Metric, arg.PageSize, arg.PageOffset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListAddonsForTrendingCalcRow{}
+	items := []ListMilestonesRow{}
 	for rows.Next() {
-		var i ListAddonsForTrendingCalcRow
+		var i ListMilestonesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.DownloadCount,
-			&i.ThumbsUpCount,
-			&i.LatestFileDate,
-			&i.CreatedAt,
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.Metric,
+			&i.Threshold,
+			&i.CrossedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -948,25 +4261,35 @@ func (q *Queries) ListAddonsForTrendingCalc(ctx context.Context) ([]ListAddonsFo
 	return items, nil
 }
 
-const listCategories = `-- name: ListCategories :many
-SELECT id, name, slug, parent_id, icon_url FROM categories ORDER BY name
+const listOpenStints = `-- name: ListOpenStints :many
+SELECT id, addon_id, category, last_rank, last_seen_at
+FROM trending_stints
+WHERE exited_at IS NULL
 `
 
-func (q *Queries) ListCategories(ctx context.Context) ([]Category, error) {
-	rows, err := q.db.Query(ctx, listCategories)
+type ListOpenStintsRow struct {
+	ID         int64              `json:"id"`
+	AddonID    int32              `json:"addon_id"`
+	Category   string             `json:"category"`
+	LastRank   int16              `json:"last_rank"`
+	LastSeenAt pgtype.Timestamptz `json:"last_seen_at"`
+}
+
+func (q *Queries) ListOpenStints(ctx context.Context) ([]ListOpenStintsRow, error) {
+	rows, err := q.db.Query(ctx, listOpenStints)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Category{}
+	items := []ListOpenStintsRow{}
 	for rows.Next() {
-		var i Category
+		var i ListOpenStintsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.Slug,
-			&i.ParentID,
-			&i.IconUrl,
+			&i.AddonID,
+			&i.Category,
+			&i.LastRank,
+			&i.LastSeenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -978,76 +4301,70 @@ func (q *Queries) ListCategories(ctx context.Context) ([]Category, error) {
 	return items, nil
 }
 
-const listHotAddons = `-- name: ListHotAddons :many
-SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, t.hot_score, t.download_velocity
-FROM addons a
-JOIN trending_scores t ON a.id = t.addon_id
-WHERE a.status = 'active'
-  AND a.download_count >= 500
-  AND t.hot_score > 0
-ORDER BY t.hot_score DESC
-LIMIT $1
+const listReactivatedAddons = `-- name: ListReactivatedAddons :many
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, e.occurred_at AS returned_at,
+    (
+        SELECT MAX(p.occurred_at) FROM addon_status_events p
+        WHERE p.addon_id = e.addon_id
+          AND p.to_status = 'inactive'
+          AND p.occurred_at < e.occurred_at
+    )::timestamptz AS removed_at
+FROM addon_status_events e
+JOIN addons a ON a.id = e.addon_id
+WHERE e.to_status = 'active'
+  AND a.status = 'active'
+ORDER BY e.occurred_at DESC, e.id DESC
+LIMIT $1 OFFSET $2
 `
 
-type ListHotAddonsRow struct {
-	ID                int32              `json:"id"`
-	Name              string             `json:"name"`
-	Slug              string             `json:"slug"`
-	Summary           pgtype.Text        `json:"summary"`
-	AuthorName        pgtype.Text        `json:"author_name"`
-	AuthorID          pgtype.Int4        `json:"author_id"`
-	LogoUrl           pgtype.Text        `json:"logo_url"`
-	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
-	Categories        []int32            `json:"categories"`
-	GameVersions      []string           `json:"game_versions"`
-	CreatedAt         pgtype.Timestamptz `json:"created_at"`
-	LastUpdatedAt     pgtype.Timestamptz `json:"last_updated_at"`
-	LastSyncedAt      pgtype.Timestamptz `json:"last_synced_at"`
-	IsHot             pgtype.Bool        `json:"is_hot"`
-	HotUntil          pgtype.Timestamptz `json:"hot_until"`
-	Status            pgtype.Text        `json:"status"`
-	DownloadCount     pgtype.Int8        `json:"download_count"`
-	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
-	PopularityRank    pgtype.Int4        `json:"popularity_rank"`
-	Rating            pgtype.Numeric     `json:"rating"`
-	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
-	HotScore          pgtype.Numeric     `json:"hot_score"`
-	DownloadVelocity  pgtype.Numeric     `json:"download_velocity"`
+type ListReactivatedAddonsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListReactivatedAddonsRow struct {
+	Addon      Addon              `json:"addon"`
+	ReturnedAt pgtype.Timestamptz `json:"returned_at"`
+	RemovedAt  pgtype.Timestamptz `json:"removed_at"`
 }
 
-func (q *Queries) ListHotAddons(ctx context.Context, limit int32) ([]ListHotAddonsRow, error) {
-	rows, err := q.db.Query(ctx, listHotAddons, limit)
+// Addons that came back after being missing, most recent return first
+func (q *Queries) ListReactivatedAddons(ctx context.Context, arg ListReactivatedAddonsParams) ([]ListReactivatedAddonsRow, error) {
+	rows, err := q.db.Query(ctx, listReactivatedAddons, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListHotAddonsRow{}
+	items := []ListReactivatedAddonsRow{}
 	for rows.Next() {
-		var i ListHotAddonsRow
+		var i ListReactivatedAddonsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Slug,
-			&i.Summary,
-			&i.AuthorName,
-			&i.AuthorID,
-			&i.LogoUrl,
-			&i.PrimaryCategoryID,
-			&i.Categories,
-			&i.GameVersions,
-			&i.CreatedAt,
-			&i.LastUpdatedAt,
-			&i.LastSyncedAt,
-			&i.IsHot,
-			&i.HotUntil,
-			&i.Status,
-			&i.DownloadCount,
-			&i.ThumbsUpCount,
-			&i.PopularityRank,
-			&i.Rating,
-			&i.LatestFileDate,
-			&i.HotScore,
-			&i.DownloadVelocity,
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.ReturnedAt,
+			&i.RemovedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1059,81 +4376,68 @@ func (q *Queries) ListHotAddons(ctx context.Context, limit int32) ([]ListHotAddo
 	return items, nil
 }
 
-const listHotAddonsPaginated = `-- name: ListHotAddonsPaginated :many
-SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, t.hot_score, t.download_velocity
+const listRemovedAddons = `-- name: ListRemovedAddons :many
+WITH last_removal AS (
+    SELECT DISTINCT ON (addon_id) addon_id, occurred_at
+    FROM addon_status_events
+    WHERE to_status = 'inactive'
+    ORDER BY addon_id, occurred_at DESC
+)
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, COALESCE(r.occurred_at, a.last_synced_at)::timestamptz AS removed_at
 FROM addons a
-JOIN trending_scores t ON a.id = t.addon_id
-WHERE a.status = 'active'
-  AND a.download_count >= 500
-  AND t.hot_score > 0
-ORDER BY t.hot_score DESC
+LEFT JOIN last_removal r ON r.addon_id = a.id
+WHERE a.status = 'inactive'
+ORDER BY removed_at DESC, a.id
 LIMIT $1 OFFSET $2
 `
 
-type ListHotAddonsPaginatedParams struct {
+type ListRemovedAddonsParams struct {
 	Limit  int32 `json:"limit"`
 	Offset int32 `json:"offset"`
 }
 
-type ListHotAddonsPaginatedRow struct {
-	ID                int32              `json:"id"`
-	Name              string             `json:"name"`
-	Slug              string             `json:"slug"`
-	Summary           pgtype.Text        `json:"summary"`
-	AuthorName        pgtype.Text        `json:"author_name"`
-	AuthorID          pgtype.Int4        `json:"author_id"`
-	LogoUrl           pgtype.Text        `json:"logo_url"`
-	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
-	Categories        []int32            `json:"categories"`
-	GameVersions      []string           `json:"game_versions"`
-	CreatedAt         pgtype.Timestamptz `json:"created_at"`
-	LastUpdatedAt     pgtype.Timestamptz `json:"last_updated_at"`
-	LastSyncedAt      pgtype.Timestamptz `json:"last_synced_at"`
-	IsHot             pgtype.Bool        `json:"is_hot"`
-	HotUntil          pgtype.Timestamptz `json:"hot_until"`
-	Status            pgtype.Text        `json:"status"`
-	DownloadCount     pgtype.Int8        `json:"download_count"`
-	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
-	PopularityRank    pgtype.Int4        `json:"popularity_rank"`
-	Rating            pgtype.Numeric     `json:"rating"`
-	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
-	HotScore          pgtype.Numeric     `json:"hot_score"`
-	DownloadVelocity  pgtype.Numeric     `json:"download_velocity"`
+type ListRemovedAddonsRow struct {
+	Addon     Addon              `json:"addon"`
+	RemovedAt pgtype.Timestamptz `json:"removed_at"`
 }
 
-func (q *Queries) ListHotAddonsPaginated(ctx context.Context, arg ListHotAddonsPaginatedParams) ([]ListHotAddonsPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listHotAddonsPaginated, arg.Limit, arg.Offset)
+// Addons that vanished from CurseForge, most recently removed first.
+// Falls back to last_synced_at for addons marked inactive before events were recorded.
+func (q *Queries) ListRemovedAddons(ctx context.Context, arg ListRemovedAddonsParams) ([]ListRemovedAddonsRow, error) {
+	rows, err := q.db.Query(ctx, listRemovedAddons, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListHotAddonsPaginatedRow{}
+	items := []ListRemovedAddonsRow{}
 	for rows.Next() {
-		var i ListHotAddonsPaginatedRow
+		var i ListRemovedAddonsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Slug,
-			&i.Summary,
-			&i.AuthorName,
-			&i.AuthorID,
-			&i.LogoUrl,
-			&i.PrimaryCategoryID,
-			&i.Categories,
-			&i.GameVersions,
-			&i.CreatedAt,
-			&i.LastUpdatedAt,
-			&i.LastSyncedAt,
-			&i.IsHot,
-			&i.HotUntil,
-			&i.Status,
-			&i.DownloadCount,
-			&i.ThumbsUpCount,
-			&i.PopularityRank,
-			&i.Rating,
-			&i.LatestFileDate,
-			&i.HotScore,
-			&i.DownloadVelocity,
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.RemovedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1146,23 +4450,30 @@ func (q *Queries) ListHotAddonsPaginated(ctx context.Context, arg ListHotAddonsP
 }
 
 const listRisingAddons = `-- name: ListRisingAddons :many
-SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, t.rising_score, t.download_velocity
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.rising_score, t.download_velocity
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 50
-  AND a.download_count <= 10000
+  AND a.download_count >= $1::bigint
+  AND a.download_count <= $2::bigint
   AND t.rising_score > 0
   AND a.id NOT IN (
       SELECT addon_id FROM trending_scores
       WHERE hot_score > 0
-      ORDER BY hot_score DESC
-      LIMIT 20
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT $3
   )
-ORDER BY t.rising_score DESC
-LIMIT $1
+ORDER BY t.rising_rank NULLS LAST, t.rising_score DESC
+LIMIT $4
 `
 
+type ListRisingAddonsParams struct {
+	MinDownloads int64 `json:"min_downloads"`
+	MaxDownloads int64 `json:"max_downloads"`
+	HotListSize  int32 `json:"hot_list_size"`
+	LimitCount   int32 `json:"limit_count"`
+}
+
 type ListRisingAddonsRow struct {
 	ID                int32              `json:"id"`
 	Name              string             `json:"name"`
@@ -1185,12 +4496,20 @@ type ListRisingAddonsRow struct {
 	PopularityRank    pgtype.Int4        `json:"popularity_rank"`
 	Rating            pgtype.Numeric     `json:"rating"`
 	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
+	ComebackAt        pgtype.Timestamptz `json:"comeback_at"`
+	HeatIndex         pgtype.Int2        `json:"heat_index"`
+	HeatSparkline     []int16            `json:"heat_sparkline"`
 	RisingScore       pgtype.Numeric     `json:"rising_score"`
 	DownloadVelocity  pgtype.Numeric     `json:"download_velocity"`
 }
 
-func (q *Queries) ListRisingAddons(ctx context.Context, limit int32) ([]ListRisingAddonsRow, error) {
-	rows, err := q.db.Query(ctx, listRisingAddons, limit)
+func (q *Queries) ListRisingAddons(ctx context.Context, arg ListRisingAddonsParams) ([]ListRisingAddonsRow, error) {
+	rows, err := q.db.Query(ctx, listRisingAddons,
+		arg.MinDownloads,
+		arg.MaxDownloads,
+		arg.HotListSize,
+		arg.LimitCount,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -1220,6 +4539,9 @@ func (q *Queries) ListRisingAddons(ctx context.Context, limit int32) ([]ListRisi
 			&i.PopularityRank,
 			&i.Rating,
 			&i.LatestFileDate,
+			&i.ComebackAt,
+			&i.HeatIndex,
+			&i.HeatSparkline,
 			&i.RisingScore,
 			&i.DownloadVelocity,
 		); err != nil {
@@ -1234,63 +4556,270 @@ func (q *Queries) ListRisingAddons(ctx context.Context, limit int32) ([]ListRisi
 }
 
 const listRisingAddonsPaginated = `-- name: ListRisingAddonsPaginated :many
-SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, t.rising_score, t.download_velocity
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, t.rising_score, t.download_velocity
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 50
-  AND a.download_count <= 10000
+  AND a.download_count >= $1::bigint
+  AND a.download_count <= $2::bigint
   AND t.rising_score > 0
   AND a.id NOT IN (
       SELECT addon_id FROM trending_scores
       WHERE hot_score > 0
-      ORDER BY hot_score DESC
-      LIMIT 20
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT $3
   )
-ORDER BY t.rising_score DESC
-LIMIT $1 OFFSET $2
+ORDER BY CASE WHEN $4::boolean THEN t.rising_raw_rank ELSE t.rising_rank END NULLS LAST,
+    t.rising_score DESC
+LIMIT $5 OFFSET $6
 `
 
 type ListRisingAddonsPaginatedParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	MinDownloads int64 `json:"min_downloads"`
+	MaxDownloads int64 `json:"max_downloads"`
+	HotListSize  int32 `json:"hot_list_size"`
+	RawOrder     bool  `json:"raw_order"`
+	PageSize     int32 `json:"page_size"`
+	PageOffset   int32 `json:"page_offset"`
 }
 
 type ListRisingAddonsPaginatedRow struct {
-	ID                int32              `json:"id"`
-	Name              string             `json:"name"`
-	Slug              string             `json:"slug"`
-	Summary           pgtype.Text        `json:"summary"`
-	AuthorName        pgtype.Text        `json:"author_name"`
-	AuthorID          pgtype.Int4        `json:"author_id"`
-	LogoUrl           pgtype.Text        `json:"logo_url"`
-	PrimaryCategoryID pgtype.Int4        `json:"primary_category_id"`
-	Categories        []int32            `json:"categories"`
-	GameVersions      []string           `json:"game_versions"`
-	CreatedAt         pgtype.Timestamptz `json:"created_at"`
-	LastUpdatedAt     pgtype.Timestamptz `json:"last_updated_at"`
-	LastSyncedAt      pgtype.Timestamptz `json:"last_synced_at"`
-	IsHot             pgtype.Bool        `json:"is_hot"`
-	HotUntil          pgtype.Timestamptz `json:"hot_until"`
-	Status            pgtype.Text        `json:"status"`
-	DownloadCount     pgtype.Int8        `json:"download_count"`
-	ThumbsUpCount     pgtype.Int4        `json:"thumbs_up_count"`
-	PopularityRank    pgtype.Int4        `json:"popularity_rank"`
-	Rating            pgtype.Numeric     `json:"rating"`
-	LatestFileDate    pgtype.Timestamptz `json:"latest_file_date"`
-	RisingScore       pgtype.Numeric     `json:"rising_score"`
-	DownloadVelocity  pgtype.Numeric     `json:"download_velocity"`
+	Addon            Addon          `json:"addon"`
+	RisingScore      pgtype.Numeric `json:"rising_score"`
+	DownloadVelocity pgtype.Numeric `json:"download_velocity"`
+}
+
+func (q *Queries) ListRisingAddonsPaginated(ctx context.Context, arg ListRisingAddonsPaginatedParams) ([]ListRisingAddonsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listRisingAddonsPaginated,
+		arg.MinDownloads,
+		arg.MaxDownloads,
+		arg.HotListSize,
+		arg.RawOrder,
+		arg.PageSize,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRisingAddonsPaginatedRow{}
+	for rows.Next() {
+		var i ListRisingAddonsPaginatedRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.RisingScore,
+			&i.DownloadVelocity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledJobRuns = `-- name: ListScheduledJobRuns :many
+SELECT job_name, last_started_at, last_finished_at, last_success_at, last_error FROM scheduled_job_runs
+`
+
+func (q *Queries) ListScheduledJobRuns(ctx context.Context) ([]ScheduledJobRun, error) {
+	rows, err := q.db.Query(ctx, listScheduledJobRuns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ScheduledJobRun{}
+	for rows.Next() {
+		var i ScheduledJobRun
+		if err := rows.Scan(
+			&i.JobName,
+			&i.LastStartedAt,
+			&i.LastFinishedAt,
+			&i.LastSuccessAt,
+			&i.LastError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShadowComparisons = `-- name: ListShadowComparisons :many
+SELECT calculated_at, live_version, shadow_version, overlap, rank_correlation, live_churn, shadow_churn
+FROM trending_shadow_comparisons
+WHERE list = $1 AND calculated_at >= $2::timestamptz
+ORDER BY calculated_at, id
+`
+
+type ListShadowComparisonsParams struct {
+	List  string             `json:"list"`
+	Since pgtype.Timestamptz `json:"since"`
+}
+
+type ListShadowComparisonsRow struct {
+	CalculatedAt    pgtype.Timestamptz `json:"calculated_at"`
+	LiveVersion     string             `json:"live_version"`
+	ShadowVersion   string             `json:"shadow_version"`
+	Overlap         pgtype.Numeric     `json:"overlap"`
+	RankCorrelation pgtype.Numeric     `json:"rank_correlation"`
+	LiveChurn       pgtype.Numeric     `json:"live_churn"`
+	ShadowChurn     pgtype.Numeric     `json:"shadow_churn"`
+}
+
+// A list's comparisons since the given time, oldest first
+func (q *Queries) ListShadowComparisons(ctx context.Context, arg ListShadowComparisonsParams) ([]ListShadowComparisonsRow, error) {
+	rows, err := q.db.Query(ctx, listShadowComparisons, arg.List, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListShadowComparisonsRow{}
+	for rows.Next() {
+		var i ListShadowComparisonsRow
+		if err := rows.Scan(
+			&i.CalculatedAt,
+			&i.LiveVersion,
+			&i.ShadowVersion,
+			&i.Overlap,
+			&i.RankCorrelation,
+			&i.LiveChurn,
+			&i.ShadowChurn,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShadowDiff = `-- name: ListShadowDiff :many
+WITH live AS (
+    SELECT addon_id,
+        CASE $1::text WHEN 'hot' THEN hot_rank WHEN 'rising' THEN rising_rank ELSE loved_rank END AS rank
+    FROM trending_scores
+),
+shadow AS (
+    SELECT addon_id,
+        CASE $1::text WHEN 'hot' THEN hot_rank WHEN 'rising' THEN rising_rank ELSE loved_rank END AS rank
+    FROM trending_shadow_scores
+)
+SELECT a.id, a.name, a.slug, a.summary, a.author_name, a.author_id, a.logo_url, a.primary_category_id, a.categories, a.game_versions, a.created_at, a.last_updated_at, a.last_synced_at, a.is_hot, a.hot_until, a.status, a.download_count, a.thumbs_up_count, a.popularity_rank, a.rating, a.latest_file_date, a.comeback_at, a.heat_index, a.heat_sparkline, l.rank AS live_rank, s.rank AS shadow_rank
+FROM addons a
+LEFT JOIN live l ON l.addon_id = a.id
+LEFT JOIN shadow s ON s.addon_id = a.id
+WHERE l.rank IS NOT NULL OR s.rank IS NOT NULL
+ORDER BY l.rank NULLS LAST, s.rank NULLS LAST, a.id
+`
+
+type ListShadowDiffRow struct {
+	Addon      Addon       `json:"addon"`
+	LiveRank   pgtype.Int2 `json:"live_rank"`
+	ShadowRank pgtype.Int2 `json:"shadow_rank"`
+}
+
+// Addons on a live or shadow list with their rank on each, by live rank then
+// shadow rank
+func (q *Queries) ListShadowDiff(ctx context.Context, list string) ([]ListShadowDiffRow, error) {
+	rows, err := q.db.Query(ctx, listShadowDiff, list)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListShadowDiffRow{}
+	for rows.Next() {
+		var i ListShadowDiffRow
+		if err := rows.Scan(
+			&i.Addon.ID,
+			&i.Addon.Name,
+			&i.Addon.Slug,
+			&i.Addon.Summary,
+			&i.Addon.AuthorName,
+			&i.Addon.AuthorID,
+			&i.Addon.LogoUrl,
+			&i.Addon.PrimaryCategoryID,
+			&i.Addon.Categories,
+			&i.Addon.GameVersions,
+			&i.Addon.CreatedAt,
+			&i.Addon.LastUpdatedAt,
+			&i.Addon.LastSyncedAt,
+			&i.Addon.IsHot,
+			&i.Addon.HotUntil,
+			&i.Addon.Status,
+			&i.Addon.DownloadCount,
+			&i.Addon.ThumbsUpCount,
+			&i.Addon.PopularityRank,
+			&i.Addon.Rating,
+			&i.Addon.LatestFileDate,
+			&i.Addon.ComebackAt,
+			&i.Addon.HeatIndex,
+			&i.Addon.HeatSparkline,
+			&i.LiveRank,
+			&i.ShadowRank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) ListRisingAddonsPaginated(ctx context.Context, arg ListRisingAddonsPaginatedParams) ([]ListRisingAddonsPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listRisingAddonsPaginated, arg.Limit, arg.Offset)
+const listTopAddonsInCategories = `-- name: ListTopAddonsInCategories :many
+SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date, comeback_at, heat_index, heat_sparkline FROM addons
+WHERE status = 'active'
+  AND categories && $1::integer[]
+ORDER BY download_count DESC
+LIMIT $2
+`
+
+type ListTopAddonsInCategoriesParams struct {
+	CategoryIds []int32 `json:"category_ids"`
+	LimitCount  int32   `json:"limit_count"`
+}
+
+func (q *Queries) ListTopAddonsInCategories(ctx context.Context, arg ListTopAddonsInCategoriesParams) ([]Addon, error) {
+	rows, err := q.db.Query(ctx, listTopAddonsInCategories, arg.CategoryIds, arg.LimitCount)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListRisingAddonsPaginatedRow{}
+	items := []Addon{}
 	for rows.Next() {
-		var i ListRisingAddonsPaginatedRow
+		var i Addon
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -1313,8 +4842,99 @@ func (q *Queries) ListRisingAddonsPaginated(ctx context.Context, arg ListRisingA
 			&i.PopularityRank,
 			&i.Rating,
 			&i.LatestFileDate,
-			&i.RisingScore,
-			&i.DownloadVelocity,
+			&i.ComebackAt,
+			&i.HeatIndex,
+			&i.HeatSparkline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopDownloadGains = `-- name: ListTopDownloadGains :many
+SELECT
+    addon_id,
+    MIN(download_count)::bigint AS start_downloads,
+    (MAX(download_count) - MIN(download_count))::bigint AS gain
+FROM snapshots
+WHERE recorded_at >= $1::timestamptz
+  AND recorded_at <= $2::timestamptz
+GROUP BY addon_id
+ORDER BY gain DESC
+LIMIT $3
+`
+
+type ListTopDownloadGainsParams struct {
+	FromTime   pgtype.Timestamptz `json:"from_time"`
+	ToTime     pgtype.Timestamptz `json:"to_time"`
+	LimitCount int32              `json:"limit_count"`
+}
+
+type ListTopDownloadGainsRow struct {
+	AddonID        int32 `json:"addon_id"`
+	StartDownloads int64 `json:"start_downloads"`
+	Gain           int64 `json:"gain"`
+}
+
+// Addons with the largest download gains between two times
+func (q *Queries) ListTopDownloadGains(ctx context.Context, arg ListTopDownloadGainsParams) ([]ListTopDownloadGainsRow, error) {
+	rows, err := q.db.Query(ctx, listTopDownloadGains, arg.FromTime, arg.ToTime, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTopDownloadGainsRow{}
+	for rows.Next() {
+		var i ListTopDownloadGainsRow
+		if err := rows.Scan(&i.AddonID, &i.StartDownloads, &i.Gain); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrendingGenerations = `-- name: ListTrendingGenerations :many
+SELECT g.slot, g.run_id, g.published_at, r.params_version, r.finished_at, r.processed_count
+FROM trending_generations g
+JOIN trending_calculation_runs r ON r.id = g.run_id
+ORDER BY g.published_at DESC
+`
+
+type ListTrendingGenerationsRow struct {
+	Slot           string             `json:"slot"`
+	RunID          int64              `json:"run_id"`
+	PublishedAt    pgtype.Timestamptz `json:"published_at"`
+	ParamsVersion  string             `json:"params_version"`
+	FinishedAt     pgtype.Timestamptz `json:"finished_at"`
+	ProcessedCount int32              `json:"processed_count"`
+}
+
+// Live and previous generations with the calculation runs that produced them
+func (q *Queries) ListTrendingGenerations(ctx context.Context) ([]ListTrendingGenerationsRow, error) {
+	rows, err := q.db.Query(ctx, listTrendingGenerations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTrendingGenerationsRow{}
+	for rows.Next() {
+		var i ListTrendingGenerationsRow
+		if err := rows.Scan(
+			&i.Slot,
+			&i.RunID,
+			&i.PublishedAt,
+			&i.ParamsVersion,
+			&i.FinishedAt,
+			&i.ProcessedCount,
 		); err != nil {
 			return nil, err
 		}
@@ -1327,14 +4947,21 @@ func (q *Queries) ListRisingAddonsPaginated(ctx context.Context, arg ListRisingA
 }
 
 const markMissingAddonsInactive = `-- name: MarkMissingAddonsInactive :execrows
-WITH synced_ids AS (SELECT unnest($1::integer[]) AS id)
-UPDATE addons
-SET status = 'inactive', last_synced_at = NOW()
-WHERE status = 'active'
-  AND NOT EXISTS (SELECT 1 FROM synced_ids WHERE synced_ids.id = addons.id)
+WITH synced_ids AS (SELECT unnest($1::integer[]) AS id),
+marked AS (
+    UPDATE addons
+    SET status = 'inactive', last_synced_at = NOW()
+    WHERE status = 'active'
+      AND NOT EXISTS (SELECT 1 FROM synced_ids WHERE synced_ids.id = addons.id)
+    RETURNING id, download_count, thumbs_up_count
+)
+INSERT INTO addon_status_events (addon_id, from_status, to_status, reason, download_count, thumbs_up_count)
+SELECT id, 'active', 'inactive', 'missing_from_api', download_count, thumbs_up_count
+FROM marked
 `
 
 // Mark addons as inactive if they no longer appear in CurseForge API response
+// and record the transition with their last known stats
 func (q *Queries) MarkMissingAddonsInactive(ctx context.Context, dollar_1 []int32) (int64, error) {
 	result, err := q.db.Exec(ctx, markMissingAddonsInactive, dollar_1)
 	if err != nil {
@@ -1343,22 +4970,339 @@ func (q *Queries) MarkMissingAddonsInactive(ctx context.Context, dollar_1 []int3
 	return result.RowsAffected(), nil
 }
 
+const markShadowTrendingParamSet = `-- name: MarkShadowTrendingParamSet :execrows
+UPDATE trending_param_sets SET is_shadow = TRUE WHERE version = $1
+`
+
+func (q *Queries) MarkShadowTrendingParamSet(ctx context.Context, version string) (int64, error) {
+	result, err := q.db.Exec(ctx, markShadowTrendingParamSet, version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const openStint = `-- name: OpenStint :exec
+INSERT INTO trending_stints (addon_id, category, entered_at, last_seen_at, last_rank, best_rank)
+VALUES ($1, $2, $3, $3, $4, $4)
+`
+
+type OpenStintParams struct {
+	AddonID  int32              `json:"addon_id"`
+	Category string             `json:"category"`
+	SeenAt   pgtype.Timestamptz `json:"seen_at"`
+	Rank     int16              `json:"rank"`
+}
+
+func (q *Queries) OpenStint(ctx context.Context, arg OpenStintParams) error {
+	_, err := q.db.Exec(ctx, openStint,
+		arg.AddonID,
+		arg.Category,
+		arg.SeenAt,
+		arg.Rank,
+	)
+	return err
+}
+
+const publishStagedCategoryTrendingScores = `-- name: PublishStagedCategoryTrendingScores :exec
+INSERT INTO category_trending_scores (
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+)
+SELECT
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+FROM category_trending_scores_staging
+`
+
+func (q *Queries) PublishStagedCategoryTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, publishStagedCategoryTrendingScores)
+	return err
+}
+
+const publishStagedTrendingScores = `-- name: PublishStagedTrendingScores :exec
+INSERT INTO trending_scores (
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+)
+SELECT
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_scores_staging
+`
+
+func (q *Queries) PublishStagedTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, publishStagedTrendingScores)
+	return err
+}
+
+const recordAddonReactivation = `-- name: RecordAddonReactivation :execrows
+INSERT INTO addon_status_events (addon_id, from_status, to_status, reason, download_count, thumbs_up_count)
+SELECT id, status, 'active', 'reappeared_in_api', download_count, thumbs_up_count
+FROM addons
+WHERE id = $1 AND status <> 'active'
+`
+
+// Record that a previously inactive addon reappeared in the CurseForge API.
+// Must run before UpsertAddon, which flips the status back to active.
+func (q *Queries) RecordAddonReactivation(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, recordAddonReactivation, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordComeback = `-- name: RecordComeback :execrows
+WITH inserted AS (
+    INSERT INTO comeback_events (addon_id, release_date, previous_release_date, dormant_velocity, revived_velocity)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (addon_id, release_date) DO NOTHING
+    RETURNING addon_id, detected_at
+)
+UPDATE addons
+SET comeback_at = inserted.detected_at
+FROM inserted
+WHERE addons.id = inserted.addon_id
+`
+
+type RecordComebackParams struct {
+	AddonID             int32              `json:"addon_id"`
+	ReleaseDate         pgtype.Timestamptz `json:"release_date"`
+	PreviousReleaseDate pgtype.Timestamptz `json:"previous_release_date"`
+	DormantVelocity     pgtype.Numeric     `json:"dormant_velocity"`
+	RevivedVelocity     pgtype.Numeric     `json:"revived_velocity"`
+}
+
+// Record a comeback event and mark the addon; does nothing if the release was
+// already recorded
+func (q *Queries) RecordComeback(ctx context.Context, arg RecordComebackParams) (int64, error) {
+	result, err := q.db.Exec(ctx, recordComeback,
+		arg.AddonID,
+		arg.ReleaseDate,
+		arg.PreviousReleaseDate,
+		arg.DormantVelocity,
+		arg.RevivedVelocity,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordMilestone = `-- name: RecordMilestone :execrows
+INSERT INTO milestones (addon_id, metric, threshold, crossed_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (addon_id, metric, threshold) DO NOTHING
+`
+
+type RecordMilestoneParams struct {
+	AddonID   int32              `json:"addon_id"`
+	Metric    string             `json:"metric"`
+	Threshold int64              `json:"threshold"`
+	CrossedAt pgtype.Timestamptz `json:"crossed_at"`
+}
+
+// Record a crossed milestone; does nothing if the addon already crossed it
+func (q *Queries) RecordMilestone(ctx context.Context, arg RecordMilestoneParams) (int64, error) {
+	result, err := q.db.Exec(ctx, recordMilestone,
+		arg.AddonID,
+		arg.Metric,
+		arg.Threshold,
+		arg.CrossedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordScheduledJobRun = `-- name: RecordScheduledJobRun :exec
+INSERT INTO scheduled_job_runs (job_name, last_started_at, last_finished_at, last_success_at, last_error)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (job_name) DO UPDATE SET
+    last_started_at = EXCLUDED.last_started_at,
+    last_finished_at = EXCLUDED.last_finished_at,
+    last_success_at = COALESCE(EXCLUDED.last_success_at, scheduled_job_runs.last_success_at),
+    last_error = EXCLUDED.last_error
+`
+
+type RecordScheduledJobRunParams struct {
+	JobName        string             `json:"job_name"`
+	LastStartedAt  pgtype.Timestamptz `json:"last_started_at"`
+	LastFinishedAt pgtype.Timestamptz `json:"last_finished_at"`
+	LastSuccessAt  pgtype.Timestamptz `json:"last_success_at"`
+	LastError      pgtype.Text        `json:"last_error"`
+}
+
+// Record the outcome of a daemon job run; last_success_at is kept when the run failed
+func (q *Queries) RecordScheduledJobRun(ctx context.Context, arg RecordScheduledJobRunParams) error {
+	_, err := q.db.Exec(ctx, recordScheduledJobRun,
+		arg.JobName,
+		arg.LastStartedAt,
+		arg.LastFinishedAt,
+		arg.LastSuccessAt,
+		arg.LastError,
+	)
+	return err
+}
+
+const registerTrendingParamSet = `-- name: RegisterTrendingParamSet :exec
+INSERT INTO trending_param_sets (version, params)
+VALUES ($1, $2)
+ON CONFLICT (version) DO NOTHING
+`
+
+type RegisterTrendingParamSetParams struct {
+	Version string `json:"version"`
+	Params  []byte `json:"params"`
+}
+
+// Record a parameter set; an existing version is left untouched
+func (q *Queries) RegisterTrendingParamSet(ctx context.Context, arg RegisterTrendingParamSetParams) error {
+	_, err := q.db.Exec(ctx, registerTrendingParamSet, arg.Version, arg.Params)
+	return err
+}
+
+const releaseJobAdvisoryLock = `-- name: ReleaseJobAdvisoryLock :one
+SELECT pg_advisory_unlock(hashtextextended($1::text, 0)) AS released
+`
+
+func (q *Queries) ReleaseJobAdvisoryLock(ctx context.Context, jobName string) (bool, error) {
+	row := q.db.QueryRow(ctx, releaseJobAdvisoryLock, jobName)
+	var released bool
+	err := row.Scan(&released)
+	return released, err
+}
+
+const restorePreviousCategoryTrendingScores = `-- name: RestorePreviousCategoryTrendingScores :exec
+INSERT INTO category_trending_scores (
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+)
+SELECT
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+FROM category_trending_scores_previous
+`
+
+func (q *Queries) RestorePreviousCategoryTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, restorePreviousCategoryTrendingScores)
+	return err
+}
+
+const restorePreviousHeatIndex = `-- name: RestorePreviousHeatIndex :execrows
+INSERT INTO heat_index_daily (addon_id, day, heat_index, run_id)
+SELECT addon_id, day, heat_index, run_id
+FROM heat_index_previous
+WHERE replaced_by_run_id = $1
+`
+
+// Put back the heat index rows the given run replaced
+func (q *Queries) RestorePreviousHeatIndex(ctx context.Context, replacedByRunID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, restorePreviousHeatIndex, replacedByRunID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restorePreviousLeaderboards = `-- name: RestorePreviousLeaderboards :execrows
+INSERT INTO leaderboard_archive (period, period_start, list, rank, addon_id, score, recorded_at, run_id)
+SELECT period, period_start, list, rank, addon_id, score, recorded_at, run_id
+FROM leaderboard_archive_previous
+WHERE replaced_by_run_id = $1
+`
+
+// Put back the archived lists the given run replaced
+func (q *Queries) RestorePreviousLeaderboards(ctx context.Context, replacedByRunID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, restorePreviousLeaderboards, replacedByRunID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restorePreviousStints = `-- name: RestorePreviousStints :execrows
+UPDATE trending_stints s
+SET exited_at = p.exited_at,
+    last_seen_at = p.last_seen_at,
+    last_rank = p.last_rank,
+    best_rank = p.best_rank,
+    hours_at_top = p.hours_at_top
+FROM trending_stints_previous p
+WHERE p.id = s.id AND p.replaced_by_run_id = $1
+`
+
+// Undo how the given run advanced the stints it kept
+func (q *Queries) RestorePreviousStints(ctx context.Context, runID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, restorePreviousStints, runID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restorePreviousTrendingScores = `-- name: RestorePreviousTrendingScores :exec
+INSERT INTO trending_scores (
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+)
+SELECT
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_scores_previous
+`
+
+func (q *Queries) RestorePreviousTrendingScores(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, restorePreviousTrendingScores)
+	return err
+}
+
 const searchAddons = `-- name: SearchAddons :many
-SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date FROM addons
+SELECT id, name, slug, summary, author_name, author_id, logo_url, primary_category_id, categories, game_versions, created_at, last_updated_at, last_synced_at, is_hot, hot_until, status, download_count, thumbs_up_count, popularity_rank, rating, latest_file_date, comeback_at, heat_index, heat_sparkline FROM addons
 WHERE status = 'active'
-  AND (name ILIKE '%' || $3 || '%' OR summary ILIKE '%' || $3 || '%')
-ORDER BY download_count DESC
-LIMIT $1 OFFSET $2
+  AND (name ILIKE '%' || $1 || '%' OR summary ILIKE '%' || $1 || '%')
+ORDER BY
+    CASE WHEN $2::text = 'heat' THEN heat_index END DESC NULLS LAST,
+    download_count DESC
+LIMIT $3 OFFSET $4
 `
 
 type SearchAddonsParams struct {
-	Limit   int32       `json:"limit"`
-	Offset  int32       `json:"offset"`
-	Column3 pgtype.Text `json:"column_3"`
+	Search pgtype.Text `json:"search"`
+	Sort   string      `json:"sort"`
+	Limit  int32       `json:"limit"`
+	Offset int32       `json:"offset"`
 }
 
 func (q *Queries) SearchAddons(ctx context.Context, arg SearchAddonsParams) ([]Addon, error) {
-	rows, err := q.db.Query(ctx, searchAddons, arg.Limit, arg.Offset, arg.Column3)
+	rows, err := q.db.Query(ctx, searchAddons,
+		arg.Search,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -1388,6 +5332,9 @@ func (q *Queries) SearchAddons(ctx context.Context, arg SearchAddonsParams) ([]A
 			&i.PopularityRank,
 			&i.Rating,
 			&i.LatestFileDate,
+			&i.ComebackAt,
+			&i.HeatIndex,
+			&i.HeatSparkline,
 		); err != nil {
 			return nil, err
 		}
@@ -1399,6 +5346,75 @@ func (q *Queries) SearchAddons(ctx context.Context, arg SearchAddonsParams) ([]A
 	return items, nil
 }
 
+const setTrendingGeneration = `-- name: SetTrendingGeneration :exec
+INSERT INTO trending_generations (slot, run_id, published_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (slot) DO UPDATE SET
+    run_id = EXCLUDED.run_id,
+    published_at = EXCLUDED.published_at
+`
+
+type SetTrendingGenerationParams struct {
+	Slot        string             `json:"slot"`
+	RunID       int64              `json:"run_id"`
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+}
+
+func (q *Queries) SetTrendingGeneration(ctx context.Context, arg SetTrendingGenerationParams) error {
+	_, err := q.db.Exec(ctx, setTrendingGeneration, arg.Slot, arg.RunID, arg.PublishedAt)
+	return err
+}
+
+const softDeleteMissingCategories = `-- name: SoftDeleteMissingCategories :execrows
+UPDATE categories
+SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND NOT (id = ANY($1::integer[]))
+`
+
+// Soft-delete categories that CurseForge no longer returns
+func (q *Queries) SoftDeleteMissingCategories(ctx context.Context, categoryIds []int32) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteMissingCategories, categoryIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const tryJobAdvisoryLock = `-- name: TryJobAdvisoryLock :one
+SELECT pg_try_advisory_lock(hashtextextended($1::text, 0)) AS acquired
+`
+
+// Session-level lock: must run on a dedicated connection that is held for the whole job
+func (q *Queries) TryJobAdvisoryLock(ctx context.Context, jobName string) (bool, error) {
+	row := q.db.QueryRow(ctx, tryJobAdvisoryLock, jobName)
+	var acquired bool
+	err := row.Scan(&acquired)
+	return acquired, err
+}
+
+const updateAddonHeat = `-- name: UpdateAddonHeat :execrows
+UPDATE addons a
+SET heat_index = cur.heat_index,
+    heat_sparkline = (
+        SELECT array_agg(COALESCE(d.heat_index, 0)::smallint ORDER BY o.n)
+        FROM generate_series(-6, 0) AS o(n)
+        LEFT JOIN heat_index_daily d ON d.addon_id = a.id AND d.day = $1::date + o.n
+    )
+FROM heat_index_daily cur
+WHERE cur.addon_id = a.id AND cur.day = $1
+`
+
+// Set the heat index of every addon scored on the given day, with a sparkline
+// of the 7 days up to it; days without a heat index count as 0
+func (q *Queries) UpdateAddonHeat(ctx context.Context, day pgtype.Date) (int64, error) {
+	result, err := q.db.Exec(ctx, updateAddonHeat, day)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const upsertAddon = `-- name: UpsertAddon :exec
 INSERT INTO addons (
     id, name, slug, summary, author_name, author_id, logo_url,
@@ -1472,21 +5488,28 @@ func (q *Queries) UpsertAddon(ctx context.Context, arg UpsertAddonParams) error
 }
 
 const upsertCategory = `-- name: UpsertCategory :exec
-INSERT INTO categories (id, name, slug, parent_id, icon_url)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO categories (id, name, slug, parent_id, icon_url, class_id, is_class, display_index)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (id) DO UPDATE SET
     name = EXCLUDED.name,
     slug = EXCLUDED.slug,
     parent_id = EXCLUDED.parent_id,
-    icon_url = EXCLUDED.icon_url
+    icon_url = EXCLUDED.icon_url,
+    class_id = EXCLUDED.class_id,
+    is_class = EXCLUDED.is_class,
+    display_index = EXCLUDED.display_index,
+    deleted_at = NULL
 `
 
 type UpsertCategoryParams struct {
-	ID       int32       `json:"id"`
-	Name     string      `json:"name"`
-	Slug     string      `json:"slug"`
-	ParentID pgtype.Int4 `json:"parent_id"`
-	IconUrl  pgtype.Text `json:"icon_url"`
+	ID           int32       `json:"id"`
+	Name         string      `json:"name"`
+	Slug         string      `json:"slug"`
+	ParentID     pgtype.Int4 `json:"parent_id"`
+	IconUrl      pgtype.Text `json:"icon_url"`
+	ClassID      pgtype.Int4 `json:"class_id"`
+	IsClass      bool        `json:"is_class"`
+	DisplayIndex int32       `json:"display_index"`
 }
 
 func (q *Queries) UpsertCategory(ctx context.Context, arg UpsertCategoryParams) error {
@@ -1496,59 +5519,9 @@ func (q *Queries) UpsertCategory(ctx context.Context, arg UpsertCategoryParams)
 		arg.Slug,
 		arg.ParentID,
 		arg.IconUrl,
-	)
-	return err
-}
-
-const upsertTrendingScore = `-- name: UpsertTrendingScore :exec
-INSERT INTO trending_scores (
-    addon_id, hot_score, rising_score,
-    download_velocity, thumbs_velocity,
-    download_growth_pct, thumbs_growth_pct,
-    size_multiplier, maintenance_multiplier,
-    first_hot_at, first_rising_at, calculated_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
-ON CONFLICT (addon_id) DO UPDATE SET
-    hot_score = EXCLUDED.hot_score,
-    rising_score = EXCLUDED.rising_score,
-    download_velocity = EXCLUDED.download_velocity,
-    thumbs_velocity = EXCLUDED.thumbs_velocity,
-    download_growth_pct = EXCLUDED.download_growth_pct,
-    thumbs_growth_pct = EXCLUDED.thumbs_growth_pct,
-    size_multiplier = EXCLUDED.size_multiplier,
-    maintenance_multiplier = EXCLUDED.maintenance_multiplier,
-    first_hot_at = COALESCE(EXCLUDED.first_hot_at, trending_scores.first_hot_at),
-    first_rising_at = COALESCE(EXCLUDED.first_rising_at, trending_scores.first_rising_at),
-    calculated_at = NOW()
-`
-
-type UpsertTrendingScoreParams struct {
-	AddonID               int32              `json:"addon_id"`
-	HotScore              pgtype.Numeric     `json:"hot_score"`
-	RisingScore           pgtype.Numeric     `json:"rising_score"`
-	DownloadVelocity      pgtype.Numeric     `json:"download_velocity"`
-	ThumbsVelocity        pgtype.Numeric     `json:"thumbs_velocity"`
-	DownloadGrowthPct     pgtype.Numeric     `json:"download_growth_pct"`
-	ThumbsGrowthPct       pgtype.Numeric     `json:"thumbs_growth_pct"`
-	SizeMultiplier        pgtype.Numeric     `json:"size_multiplier"`
-	MaintenanceMultiplier pgtype.Numeric     `json:"maintenance_multiplier"`
-	FirstHotAt            pgtype.Timestamptz `json:"first_hot_at"`
-	FirstRisingAt         pgtype.Timestamptz `json:"first_rising_at"`
-}
-
-func (q *Queries) UpsertTrendingScore(ctx context.Context, arg UpsertTrendingScoreParams) error {
-	_, err := q.db.Exec(ctx, upsertTrendingScore,
-		arg.AddonID,
-		arg.HotScore,
-		arg.RisingScore,
-		arg.DownloadVelocity,
-		arg.ThumbsVelocity,
-		arg.DownloadGrowthPct,
-		arg.ThumbsGrowthPct,
-		arg.SizeMultiplier,
-		arg.MaintenanceMultiplier,
-		arg.FirstHotAt,
-		arg.FirstRisingAt,
+		arg.ClassID,
+		arg.IsClass,
+		arg.DisplayIndex,
 	)
 	return err
 }
@@ -0,0 +1,277 @@
+package joblock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"addon-radar/internal/database"
+)
+
+// Job types that must never run concurrently with themselves
+const (
+	JobSync     = "sync"     // Addon upserts, snapshots and cleanup
+	JobTrending = "trending" // Trending score calculation
+)
+
+const (
+	// HeartbeatInterval is how often a held lock refreshes its heartbeat
+	HeartbeatInterval = 30 * time.Second
+
+	// StaleAfter is how long without a heartbeat before a holder is considered stuck
+	StaleAfter = 3 * HeartbeatInterval
+)
+
+// LockedError is returned when another process holds the job's lock
+type LockedError struct {
+	Job    string
+	Status Status
+}
+
+func (e *LockedError) Error() string {
+	msg := fmt.Sprintf("job %q is locked by %s since %s (last heartbeat %s)",
+		e.Job, e.Status.Holder, e.Status.AcquiredAt.Format(time.RFC3339), e.Status.HeartbeatAt.Format(time.RFC3339))
+	if e.Status.Stale {
+		msg += ", holder looks stale; use --force to run anyway"
+	}
+	return msg
+}
+
+// Status describes the recorded holder of a job lock
+type Status struct {
+	Job             string    `json:"job"`
+	Holder          string    `json:"holder"`
+	BackendPID      int32     `json:"backend_pid"`
+	AcquiredAt      time.Time `json:"acquired_at"`
+	HeartbeatAt     time.Time `json:"heartbeat_at"`
+	HolderConnected bool      `json:"holder_connected"`
+	Stale           bool      `json:"stale"`
+}
+
+// isStale reports whether a lock holder is gone or has stopped heartbeating
+func isStale(heartbeatAt time.Time, holderConnected bool, now time.Time) bool {
+	return !holderConnected || now.Sub(heartbeatAt) > StaleAfter
+}
+
+// Locker takes per-job Postgres advisory locks on behalf of one process
+type Locker struct {
+	pool   *pgxpool.Pool
+	holder string
+}
+
+// NewLocker creates a locker that identifies itself as command@host:pid
+func NewLocker(pool *pgxpool.Pool, command string) *Locker {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return &Locker{
+		pool:   pool,
+		holder: fmt.Sprintf("%s@%s:%d", command, host, os.Getpid()),
+	}
+}
+
+// Lock is a held job lock. Release must be called when the job finishes.
+type Lock struct {
+	job    string
+	holder string
+	forced bool // Running without the advisory lock or the holder record
+
+	mu   sync.Mutex // Serializes use of conn between heartbeat and Release
+	conn *pgxpool.Conn
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Acquire takes the advisory lock for job. If another process holds it, a
+// *LockedError is returned unless force is set, in which case the job runs
+// without the advisory lock and leaves the holder record to the process that
+// holds it.
+func (l *Locker) Acquire(ctx context.Context, job string, force bool) (*Lock, error) {
+	// Session-level advisory locks belong to a connection, so hold one for the whole job
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+	q := database.New(conn)
+
+	acquired, err := q.TryJobAdvisoryLock(ctx, job)
+	if err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("try advisory lock: %w", err)
+	}
+
+	if !acquired {
+		status, err := getStatus(ctx, q, job)
+		if err != nil {
+			conn.Release()
+			return nil, err
+		}
+		if !force {
+			conn.Release()
+			return nil, &LockedError{Job: job, Status: status}
+		}
+		slog.Warn("forcing job despite held lock",
+			"job", job,
+			"holder", status.Holder,
+			"acquired_at", status.AcquiredAt,
+			"heartbeat_at", status.HeartbeatAt,
+			"stale", status.Stale,
+		)
+	}
+
+	if acquired {
+		if err := q.ClaimJobLock(ctx, database.ClaimJobLockParams{JobName: job, Holder: l.holder}); err != nil {
+			_, _ = q.ReleaseJobAdvisoryLock(ctx, job)
+			conn.Release()
+			return nil, fmt.Errorf("record lock holder: %w", err)
+		}
+	}
+
+	lock := &Lock{
+		job:    job,
+		holder: l.holder,
+		forced: !acquired,
+		conn:   conn,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if lock.forced {
+		// Nothing to heartbeat without a holder record
+		close(lock.done)
+	} else {
+		go lock.heartbeat()
+	}
+
+	slog.Info("job lock acquired", "job", job, "holder", l.holder, "forced", lock.forced)
+	return lock, nil
+}
+
+// heartbeat refreshes heartbeat_at until the lock is released
+func (lk *Lock) heartbeat() {
+	defer close(lk.done)
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lk.stop:
+			return
+		case <-ticker.C:
+			lk.mu.Lock()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			rows, err := database.New(lk.conn).HeartbeatJobLock(ctx, database.HeartbeatJobLockParams{
+				JobName: lk.job,
+				Holder:  lk.holder,
+			})
+			cancel()
+			lk.mu.Unlock()
+
+			if err != nil {
+				slog.Warn("job lock heartbeat failed", "job", lk.job, "error", err)
+			} else if rows == 0 {
+				slog.Warn("job lock holder record is missing", "job", lk.job)
+			}
+		}
+	}
+}
+
+// Release stops the heartbeat, removes the holder record and unlocks the job.
+// A forced lock has neither to give up. It uses its own context so the lock
+// is released even during shutdown.
+func (lk *Lock) Release() error {
+	close(lk.stop)
+	<-lk.done
+
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+	defer lk.conn.Release()
+
+	if lk.forced {
+		slog.Info("job lock released", "job", lk.job, "forced", true)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	q := database.New(lk.conn)
+	err := q.DeleteJobLock(ctx, database.DeleteJobLockParams{JobName: lk.job, Holder: lk.holder})
+	if err != nil {
+		err = fmt.Errorf("delete lock holder: %w", err)
+	}
+
+	if _, unlockErr := q.ReleaseJobAdvisoryLock(ctx, lk.job); unlockErr != nil {
+		// Closing the connection drops the session and with it the advisory lock
+		_ = lk.conn.Conn().Close(ctx)
+		err = errors.Join(err, fmt.Errorf("release advisory lock: %w", unlockErr))
+	}
+
+	slog.Info("job lock released", "job", lk.job)
+	return err
+}
+
+// Run acquires the job lock, runs fn and releases the lock
+func (l *Locker) Run(ctx context.Context, job string, force bool, fn func(ctx context.Context) error) error {
+	lock, err := l.Acquire(ctx, job, force)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			slog.Warn("failed to release job lock", "job", job, "error", err)
+		}
+	}()
+	return fn(ctx)
+}
+
+// List returns the recorded holder of every job lock
+func (l *Locker) List(ctx context.Context) ([]Status, error) {
+	rows, err := database.New(l.pool).ListJobLocks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list job locks: %w", err)
+	}
+
+	now := time.Now()
+	statuses := make([]Status, len(rows))
+	for i, row := range rows {
+		statuses[i] = Status{
+			Job:             row.JobName,
+			Holder:          row.Holder,
+			BackendPID:      row.BackendPid,
+			AcquiredAt:      row.AcquiredAt.Time,
+			HeartbeatAt:     row.HeartbeatAt.Time,
+			HolderConnected: row.HolderConnected,
+			Stale:           isStale(row.HeartbeatAt.Time, row.HolderConnected, now),
+		}
+	}
+	return statuses, nil
+}
+
+// getStatus loads the holder record for a job that failed to lock
+func getStatus(ctx context.Context, q *database.Queries, job string) (Status, error) {
+	row, err := q.GetJobLock(ctx, job)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Locked by a process that has not recorded itself yet, or by an older binary
+		return Status{Job: job, Holder: "unknown", HolderConnected: true}, nil
+	}
+	if err != nil {
+		return Status{}, fmt.Errorf("get lock holder: %w", err)
+	}
+	return Status{
+		Job:             row.JobName,
+		Holder:          row.Holder,
+		BackendPID:      row.BackendPid,
+		AcquiredAt:      row.AcquiredAt.Time,
+		HeartbeatAt:     row.HeartbeatAt.Time,
+		HolderConnected: row.HolderConnected,
+		Stale:           isStale(row.HeartbeatAt.Time, row.HolderConnected, time.Now()),
+	}, nil
+}
@@ -0,0 +1,121 @@
+package joblock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/testutil"
+)
+
+func TestAcquire(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	first := NewLocker(tdb.Pool, "first")
+	second := NewLocker(tdb.Pool, "second")
+
+	t.Run("second holder is rejected", func(t *testing.T) {
+		lock, err := first.Acquire(ctx, JobSync, false)
+		require.NoError(t, err)
+
+		_, err = second.Acquire(ctx, JobSync, false)
+		var locked *LockedError
+		require.ErrorAs(t, err, &locked)
+		assert.Equal(t, JobSync, locked.Job)
+		assert.Contains(t, locked.Status.Holder, "first@")
+		assert.True(t, locked.Status.HolderConnected)
+		assert.False(t, locked.Status.Stale)
+
+		// Other job types are independent
+		other, err := second.Acquire(ctx, JobTrending, false)
+		require.NoError(t, err)
+		require.NoError(t, other.Release())
+
+		require.NoError(t, lock.Release())
+	})
+
+	t.Run("released lock can be reacquired", func(t *testing.T) {
+		lock, err := second.Acquire(ctx, JobSync, false)
+		require.NoError(t, err)
+
+		statuses, err := second.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		assert.Contains(t, statuses[0].Holder, "second@")
+
+		require.NoError(t, lock.Release())
+
+		statuses, err = second.List(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, statuses)
+	})
+
+	t.Run("force runs despite held lock", func(t *testing.T) {
+		lock, err := first.Acquire(ctx, JobSync, false)
+		require.NoError(t, err)
+
+		forced, err := second.Acquire(ctx, JobSync, true)
+		require.NoError(t, err)
+		assert.True(t, forced.forced)
+
+		// The holder record stays with the process holding the advisory lock
+		statuses, err := second.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		assert.Contains(t, statuses[0].Holder, "first@")
+
+		require.NoError(t, forced.Release())
+		statuses, err = second.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		assert.Contains(t, statuses[0].Holder, "first@")
+
+		_, err = second.Acquire(ctx, JobSync, false)
+		var locked *LockedError
+		require.ErrorAs(t, err, &locked)
+		assert.Contains(t, locked.Status.Holder, "first@")
+
+		require.NoError(t, lock.Release())
+
+		// The original advisory lock is gone too
+		again, err := second.Acquire(ctx, JobSync, false)
+		require.NoError(t, err)
+		require.NoError(t, again.Release())
+	})
+}
+
+func TestRun(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	locker := NewLocker(tdb.Pool, "test")
+	jobErr := errors.New("job failed")
+
+	err := locker.Run(ctx, JobTrending, false, func(ctx context.Context) error {
+		return jobErr
+	})
+	require.ErrorIs(t, err, jobErr)
+
+	// Lock is released even when the job fails
+	err = locker.Run(ctx, JobTrending, false, func(ctx context.Context) error { return nil })
+	require.NoError(t, err)
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Now()
+
+	assert.False(t, isStale(now.Add(-HeartbeatInterval), true, now))
+	assert.True(t, isStale(now.Add(-StaleAfter-time.Second), true, now))
+	assert.True(t, isStale(now, false, now))
+}
+
+func TestLockedErrorMessage(t *testing.T) {
+	err := &LockedError{Job: JobSync, Status: Status{Holder: "sync@host:1", Stale: true}}
+	assert.Contains(t, err.Error(), "sync@host:1")
+	assert.Contains(t, err.Error(), "--force")
+}
@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// Job is a recurring task run by the scheduler
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Jitter   time.Duration // Up to this much random delay is added to each run
+
+	// Run performs the job. lastSuccess is the start of the previous successful
+	// run, or zero if the job has never succeeded.
+	Run func(ctx context.Context, lastSuccess time.Time) error
+}
+
+// RunRecord is the outcome of a job's most recent run
+type RunRecord struct {
+	Job         string
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	LastSuccess time.Time // Zero if the job has never succeeded
+	Err         string    // Empty if the run succeeded
+}
+
+// Store persists run records so missed runs are detected across restarts
+type Store interface {
+	LastRuns(ctx context.Context) (map[string]RunRecord, error)
+	RecordRun(ctx context.Context, record RunRecord) error
+}
+
+// Scheduler runs jobs one at a time on their own intervals.
+// Jobs never overlap, so a slow job delays the others rather than competing with them.
+type Scheduler struct {
+	jobs   []Job
+	store  Store
+	now    func() time.Time
+	jitter func(max time.Duration) time.Duration
+}
+
+// New creates a scheduler for the given jobs. Earlier jobs win ties.
+func New(store Store, jobs ...Job) *Scheduler {
+	return &Scheduler{
+		jobs:   jobs,
+		store:  store,
+		now:    time.Now,
+		jitter: randomJitter,
+	}
+}
+
+// jobState tracks when a job is next due
+type jobState struct {
+	job         Job
+	next        time.Time
+	lastSuccess time.Time
+}
+
+// Run blocks until ctx is cancelled. A job that is already running when ctx is
+// cancelled is allowed to finish so no phase is left half done.
+func (s *Scheduler) Run(ctx context.Context) error {
+	records, err := s.store.LastRuns(ctx)
+	if err != nil {
+		slog.Warn("failed to load previous job runs, scheduling all jobs now", "error", err)
+		records = nil
+	}
+
+	now := s.now()
+	states := make([]*jobState, len(s.jobs))
+	for i, job := range s.jobs {
+		record, ok := records[job.Name]
+		state := &jobState{job: job, next: now}
+		if ok {
+			state.lastSuccess = record.LastSuccess
+			next, missed := nextRun(job.Interval, record.StartedAt, now)
+			if missed > 0 {
+				slog.Warn("missed scheduled runs, running now",
+					"job", job.Name,
+					"missed", missed,
+					"last_started_at", record.StartedAt,
+				)
+			}
+			state.next = next
+		}
+		states[i] = state
+		slog.Info("job scheduled", "job", job.Name, "interval", job.Interval, "jitter", job.Jitter, "next", state.next)
+	}
+
+	for {
+		state := earliest(states)
+
+		if wait := state.next.Sub(s.now()); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				slog.Info("scheduler stopped")
+				return nil
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			slog.Info("scheduler stopped")
+			return nil
+		}
+
+		started := s.now()
+		if late := started.Sub(state.next); late > state.job.Interval {
+			slog.Warn("missed scheduled runs while other jobs were running",
+				"job", state.job.Name,
+				"missed", int(late/state.job.Interval),
+			)
+		}
+
+		s.runJob(ctx, state, started)
+		state.next = started.Add(state.job.Interval + s.jitter(state.job.Jitter))
+
+		if ctx.Err() != nil {
+			slog.Info("scheduler stopped after finishing current job", "job", state.job.Name)
+			return nil
+		}
+	}
+}
+
+// runJob runs a job to completion even if ctx is cancelled mid-run, then records the outcome
+func (s *Scheduler) runJob(ctx context.Context, state *jobState, started time.Time) {
+	runCtx := context.WithoutCancel(ctx)
+
+	slog.Info("job starting", "job", state.job.Name)
+	err := state.job.Run(runCtx, state.lastSuccess)
+	finished := s.now()
+
+	record := RunRecord{
+		Job:         state.job.Name,
+		StartedAt:   started,
+		FinishedAt:  finished,
+		LastSuccess: state.lastSuccess,
+	}
+	if err != nil {
+		record.Err = err.Error()
+		slog.Error("job failed", "job", state.job.Name, "duration", finished.Sub(started), "error", err)
+	} else {
+		record.LastSuccess = started
+		state.lastSuccess = started
+		slog.Info("job finished", "job", state.job.Name, "duration", finished.Sub(started))
+	}
+
+	if err := s.store.RecordRun(runCtx, record); err != nil {
+		slog.Warn("failed to record job run", "job", state.job.Name, "error", err)
+	}
+}
+
+// nextRun returns when a job that last started at lastStarted is due, and how many
+// runs were missed. Missed runs are not caught up; the job simply runs now.
+func nextRun(interval time.Duration, lastStarted, now time.Time) (time.Time, int) {
+	due := lastStarted.Add(interval)
+	if !due.Before(now) {
+		return due, 0
+	}
+	return now, int(now.Sub(lastStarted) / interval)
+}
+
+// earliest returns the job due soonest, preferring earlier jobs on ties
+func earliest(states []*jobState) *jobState {
+	best := states[0]
+	for _, state := range states[1:] {
+		if state.next.Before(best.next) {
+			best = state
+		}
+	}
+	return best
+}
+
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max) //nolint:gosec // Jitter does not need a cryptographic source
+}
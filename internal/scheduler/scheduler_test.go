@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore implements Store for testing
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]RunRecord
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]RunRecord)}
+}
+
+func (m *memoryStore) LastRuns(ctx context.Context) (map[string]RunRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records := make(map[string]RunRecord, len(m.records))
+	for k, v := range m.records {
+		records[k] = v
+	}
+	return records, nil
+}
+
+func (m *memoryStore) RecordRun(ctx context.Context, record RunRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[record.Job] = record
+	return nil
+}
+
+func (m *memoryStore) get(job string) RunRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.records[job]
+}
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("not yet due", func(t *testing.T) {
+		next, missed := nextRun(time.Hour, now.Add(-30*time.Minute), now)
+		assert.Equal(t, now.Add(30*time.Minute), next)
+		assert.Equal(t, 0, missed)
+	})
+
+	t.Run("missed runs run now", func(t *testing.T) {
+		next, missed := nextRun(time.Hour, now.Add(-3*time.Hour-time.Minute), now)
+		assert.Equal(t, now, next)
+		assert.Equal(t, 3, missed)
+	})
+}
+
+func TestSchedulerRunsJobsUntilCancelled(t *testing.T) {
+	store := newMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var runs []string
+	var successes []time.Time
+	record := func(name string) func(context.Context, time.Time) error {
+		return func(ctx context.Context, lastSuccess time.Time) error {
+			mu.Lock()
+			defer mu.Unlock()
+			runs = append(runs, name)
+			if name == "fast" {
+				successes = append(successes, lastSuccess)
+				if len(successes) == 3 {
+					cancel()
+				}
+			}
+			return nil
+		}
+	}
+
+	s := New(store,
+		Job{Name: "fast", Interval: 5 * time.Millisecond, Run: record("fast")},
+		Job{Name: "slow", Interval: time.Hour, Run: record("slow")},
+	)
+	require.NoError(t, s.Run(ctx))
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Both jobs run at startup, in order, then only the fast one repeats
+	assert.Equal(t, []string{"fast", "slow", "fast", "fast"}, runs)
+	assert.True(t, successes[0].IsZero())
+	assert.False(t, successes[1].IsZero())
+	assert.False(t, store.get("slow").StartedAt.IsZero())
+}
+
+func TestSchedulerFinishesCurrentJobOnShutdown(t *testing.T) {
+	store := newMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	finished := false
+	s := New(store, Job{
+		Name:     "sync",
+		Interval: time.Hour,
+		Run: func(ctx context.Context, lastSuccess time.Time) error {
+			cancel()
+			time.Sleep(10 * time.Millisecond)
+			// The job context must survive the shutdown signal
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			finished = true
+			return nil
+		},
+	})
+
+	require.NoError(t, s.Run(ctx))
+	assert.True(t, finished)
+
+	record := store.get("sync")
+	assert.Empty(t, record.Err)
+	assert.Equal(t, record.StartedAt, record.LastSuccess)
+}
+
+func TestSchedulerRecordsFailures(t *testing.T) {
+	store := newMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	previous := time.Now().Add(-2 * time.Hour)
+	store.records["sync"] = RunRecord{Job: "sync", StartedAt: previous, LastSuccess: previous}
+
+	var gotLastSuccess time.Time
+	s := New(store, Job{
+		Name:     "sync",
+		Interval: time.Hour,
+		Run: func(ctx context.Context, lastSuccess time.Time) error {
+			gotLastSuccess = lastSuccess
+			cancel()
+			return errors.New("api down")
+		},
+	})
+
+	// Overdue from the previous process, so it runs immediately
+	require.NoError(t, s.Run(ctx))
+	assert.Equal(t, previous, gotLastSuccess)
+
+	record := store.get("sync")
+	assert.Equal(t, "api down", record.Err)
+	assert.Equal(t, previous, record.LastSuccess)
+	assert.True(t, record.StartedAt.After(previous))
+}
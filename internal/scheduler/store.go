@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// PostgresStore persists run records in the scheduled_job_runs table
+type PostgresStore struct {
+	db *database.Queries
+}
+
+// NewPostgresStore creates a store backed by the database
+func NewPostgresStore(db *database.Queries) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// LastRuns returns the most recent run of every job, keyed by job name
+func (p *PostgresStore) LastRuns(ctx context.Context) (map[string]RunRecord, error) {
+	rows, err := p.db.ListScheduledJobRuns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list job runs: %w", err)
+	}
+
+	records := make(map[string]RunRecord, len(rows))
+	for _, row := range rows {
+		record := RunRecord{
+			Job:        row.JobName,
+			StartedAt:  row.LastStartedAt.Time,
+			FinishedAt: row.LastFinishedAt.Time,
+			Err:        row.LastError.String,
+		}
+		if row.LastSuccessAt.Valid {
+			record.LastSuccess = row.LastSuccessAt.Time
+		}
+		records[row.JobName] = record
+	}
+	return records, nil
+}
+
+// RecordRun stores the outcome of a job run
+func (p *PostgresStore) RecordRun(ctx context.Context, record RunRecord) error {
+	err := p.db.RecordScheduledJobRun(ctx, database.RecordScheduledJobRunParams{
+		JobName:        record.Job,
+		LastStartedAt:  pgtype.Timestamptz{Time: record.StartedAt, Valid: true},
+		LastFinishedAt: pgtype.Timestamptz{Time: record.FinishedAt, Valid: true},
+		LastSuccessAt:  optionalTime(record.LastSuccess),
+		LastError:      pgtype.Text{String: record.Err, Valid: record.Err != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("record job run: %w", err)
+	}
+	return nil
+}
+
+func optionalTime(t time.Time) pgtype.Timestamptz {
+	if t.IsZero() {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
@@ -0,0 +1,271 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"addon-radar/internal/curseforge"
+	"addon-radar/internal/database"
+)
+
+// DefaultDiffTopDeltas is the number of largest download deltas included in a dry-run report
+const DefaultDiffTopDeltas = 25
+
+// DiffReport describes what a full sync would change without writing anything
+type DiffReport struct {
+	GeneratedAt     time.Time        `json:"generated_at"`
+	FetchedCount    int              `json:"fetched_count"`
+	ActiveCount     int              `json:"active_count"`
+	NewAddons       []AddonRef       `json:"new_addons"`
+	WouldDeactivate []AddonRef       `json:"would_deactivate"`
+	Reactivated     []AddonRef       `json:"reactivated"`
+	Renamed         []RenameChange   `json:"renamed"`
+	CategoryChanges []CategoryChange `json:"category_changes"`
+	LargestDeltas   []DownloadDelta  `json:"largest_deltas"`
+}
+
+// AddonRef identifies an addon in a diff report
+type AddonRef struct {
+	ID            int32  `json:"id"`
+	Name          string `json:"name"`
+	Slug          string `json:"slug"`
+	DownloadCount int64  `json:"download_count"`
+}
+
+// RenameChange is an addon whose name or slug differs from the stored one
+type RenameChange struct {
+	ID      int32  `json:"id"`
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+	OldSlug string `json:"old_slug"`
+	NewSlug string `json:"new_slug"`
+}
+
+// CategoryChange is an addon whose category IDs differ from the stored ones
+type CategoryChange struct {
+	ID      int32   `json:"id"`
+	Name    string  `json:"name"`
+	Added   []int32 `json:"added"`
+	Removed []int32 `json:"removed"`
+}
+
+// DownloadDelta is the difference between fetched and stored download counts
+type DownloadDelta struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Previous  int64  `json:"previous"`
+	Current   int64  `json:"current"`
+	Delta     int64  `json:"delta"`
+	Decreased bool   `json:"decreased"` // Download counts should only grow; usually an API glitch
+}
+
+// RunDryRun fetches all addons from CurseForge and compares them against the
+// database without writing anything.
+func (s *Service) RunDryRun(ctx context.Context, topDeltas int) (*DiffReport, error) {
+	mods, err := s.client.GetAllWoWAddons(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch addons: %w", err)
+	}
+
+	existing, err := s.db.ListAddonsForDiff(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load existing addons: %w", err)
+	}
+
+	return BuildDiffReport(existing, mods, topDeltas, time.Now()), nil
+}
+
+// BuildDiffReport compares fetched mods against stored addons.
+// Lists are sorted by addon ID, except deltas which are sorted by magnitude.
+func BuildDiffReport(existing []database.ListAddonsForDiffRow, mods []curseforge.Mod, topDeltas int, now time.Time) *DiffReport {
+	report := &DiffReport{
+		GeneratedAt:     now,
+		FetchedCount:    len(mods),
+		NewAddons:       []AddonRef{},
+		WouldDeactivate: []AddonRef{},
+		Reactivated:     []AddonRef{},
+		Renamed:         []RenameChange{},
+		CategoryChanges: []CategoryChange{},
+		LargestDeltas:   []DownloadDelta{},
+	}
+
+	stored := make(map[int32]database.ListAddonsForDiffRow, len(existing))
+	for _, a := range existing {
+		stored[a.ID] = a
+		if a.Status.String == "active" {
+			report.ActiveCount++
+		}
+	}
+
+	fetched := make(map[int32]bool, len(mods))
+	var deltas []DownloadDelta
+	for _, mod := range mods {
+		id := int32(mod.ID) //nolint:gosec // CurseForge API IDs are always valid int32
+		fetched[id] = true
+
+		prev, ok := stored[id]
+		if !ok {
+			report.NewAddons = append(report.NewAddons, AddonRef{
+				ID: id, Name: mod.Name, Slug: mod.Slug, DownloadCount: mod.DownloadCount,
+			})
+			continue
+		}
+
+		if prev.Status.String != "active" {
+			report.Reactivated = append(report.Reactivated, AddonRef{
+				ID: id, Name: mod.Name, Slug: mod.Slug, DownloadCount: mod.DownloadCount,
+			})
+		}
+
+		if prev.Name != mod.Name || prev.Slug != mod.Slug {
+			report.Renamed = append(report.Renamed, RenameChange{
+				ID: id, OldName: prev.Name, NewName: mod.Name, OldSlug: prev.Slug, NewSlug: mod.Slug,
+			})
+		}
+
+		added, removed := diffCategoryIDs(prev.Categories, modCategoryIDs(mod))
+		if len(added) > 0 || len(removed) > 0 {
+			report.CategoryChanges = append(report.CategoryChanges, CategoryChange{
+				ID: id, Name: mod.Name, Added: added, Removed: removed,
+			})
+		}
+
+		if delta := mod.DownloadCount - prev.DownloadCount.Int64; delta != 0 {
+			deltas = append(deltas, DownloadDelta{
+				ID:        id,
+				Name:      mod.Name,
+				Previous:  prev.DownloadCount.Int64,
+				Current:   mod.DownloadCount,
+				Delta:     delta,
+				Decreased: delta < 0,
+			})
+		}
+	}
+
+	for _, a := range existing {
+		if a.Status.String == "active" && !fetched[a.ID] {
+			report.WouldDeactivate = append(report.WouldDeactivate, AddonRef{
+				ID: a.ID, Name: a.Name, Slug: a.Slug, DownloadCount: a.DownloadCount.Int64,
+			})
+		}
+	}
+
+	sortAddonRefs(report.NewAddons)
+	sortAddonRefs(report.WouldDeactivate)
+	sortAddonRefs(report.Reactivated)
+	sort.Slice(report.Renamed, func(i, j int) bool { return report.Renamed[i].ID < report.Renamed[j].ID })
+	sort.Slice(report.CategoryChanges, func(i, j int) bool {
+		return report.CategoryChanges[i].ID < report.CategoryChanges[j].ID
+	})
+
+	// Largest absolute deltas first; ties broken by ID for stable output
+	sort.Slice(deltas, func(i, j int) bool {
+		ai, aj := absInt64(deltas[i].Delta), absInt64(deltas[j].Delta)
+		if ai != aj {
+			return ai > aj
+		}
+		return deltas[i].ID < deltas[j].ID
+	})
+	if topDeltas > 0 && len(deltas) > topDeltas {
+		deltas = deltas[:topDeltas]
+	}
+	report.LargestDeltas = append(report.LargestDeltas, deltas...)
+
+	return report
+}
+
+// WriteText writes a human-readable summary of the report
+func (r *DiffReport) WriteText(w io.Writer) error {
+	p := &errWriter{w: w}
+
+	p.printf("Dry-run sync report (%s)\n", r.GeneratedAt.UTC().Format(time.RFC3339))
+	p.printf("Fetched: %d addons, currently active: %d\n\n", r.FetchedCount, r.ActiveCount)
+
+	p.printf("New addons (%d)\n", len(r.NewAddons))
+	for _, a := range r.NewAddons {
+		p.printf("  + %d %s (%s) downloads=%d\n", a.ID, a.Name, a.Slug, a.DownloadCount)
+	}
+
+	p.printf("\nWould be marked inactive (%d)\n", len(r.WouldDeactivate))
+	for _, a := range r.WouldDeactivate {
+		p.printf("  - %d %s (%s) downloads=%d\n", a.ID, a.Name, a.Slug, a.DownloadCount)
+	}
+
+	p.printf("\nReactivated (%d)\n", len(r.Reactivated))
+	for _, a := range r.Reactivated {
+		p.printf("  ^ %d %s (%s)\n", a.ID, a.Name, a.Slug)
+	}
+
+	p.printf("\nRenamed (%d)\n", len(r.Renamed))
+	for _, c := range r.Renamed {
+		p.printf("  ~ %d %q -> %q (%s -> %s)\n", c.ID, c.OldName, c.NewName, c.OldSlug, c.NewSlug)
+	}
+
+	p.printf("\nCategory changes (%d)\n", len(r.CategoryChanges))
+	for _, c := range r.CategoryChanges {
+		p.printf("  ~ %d %s added=%v removed=%v\n", c.ID, c.Name, c.Added, c.Removed)
+	}
+
+	p.printf("\nLargest download deltas (%d)\n", len(r.LargestDeltas))
+	for _, d := range r.LargestDeltas {
+		marker := ""
+		if d.Decreased {
+			marker = " (decreased)"
+		}
+		p.printf("  %+d %d %s: %d -> %d%s\n", d.Delta, d.ID, d.Name, d.Previous, d.Current, marker)
+	}
+
+	return p.err
+}
+
+// errWriter remembers the first write error so WriteText can stay linear
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, args ...any) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
+
+// modCategoryIDs extracts category IDs from a mod in API order
+func modCategoryIDs(mod curseforge.Mod) []int32 {
+	ids := make([]int32, len(mod.Categories))
+	for i, cat := range mod.Categories {
+		ids[i] = int32(cat.ID) //nolint:gosec // CurseForge API IDs are always valid int32
+	}
+	return ids
+}
+
+// diffCategoryIDs returns IDs present only in next (added) and only in prev (removed)
+func diffCategoryIDs(prev, next []int32) (added, removed []int32) {
+	for _, id := range next {
+		if !slices.Contains(prev, id) {
+			added = append(added, id)
+		}
+	}
+	for _, id := range prev {
+		if !slices.Contains(next, id) {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
+
+func sortAddonRefs(refs []AddonRef) {
+	sort.Slice(refs, func(i, j int) bool { return refs[i].ID < refs[j].ID })
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
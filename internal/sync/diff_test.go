@@ -0,0 +1,107 @@
+package sync
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/curseforge"
+	"addon-radar/internal/database"
+)
+
+func storedAddon(id int32, slug, name, status string, downloads int64, categories ...int32) database.ListAddonsForDiffRow {
+	return database.ListAddonsForDiffRow{
+		ID:            id,
+		Name:          name,
+		Slug:          slug,
+		Status:        pgtype.Text{String: status, Valid: true},
+		Categories:    categories,
+		DownloadCount: pgtype.Int8{Int64: downloads, Valid: true},
+	}
+}
+
+func TestBuildDiffReport(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	existing := []database.ListAddonsForDiffRow{
+		storedAddon(1, "addon-one", "Addon One", "active", 1000, 1001),
+		storedAddon(2, "addon-two", "Addon Two", "active", 2000, 1001),
+		storedAddon(3, "addon-three", "Addon Three", "active", 3000, 1001),
+		storedAddon(4, "addon-four", "Addon Four", "inactive", 4000, 1001),
+	}
+
+	renamed := createTestMod(1, "addon-one-reborn", "Addon One Reborn")
+	renamed.DownloadCount = 1000
+
+	recategorized := createTestMod(2, "addon-two", "Addon Two")
+	recategorized.DownloadCount = 2500
+	recategorized.Categories = []curseforge.Category{{ID: 1002}}
+
+	returning := createTestMod(4, "addon-four", "Addon Four")
+	returning.DownloadCount = 3900
+
+	brandNew := createTestMod(5, "addon-five", "Addon Five")
+
+	report := BuildDiffReport(existing, []curseforge.Mod{renamed, recategorized, returning, brandNew}, 10, now)
+
+	assert.Equal(t, now, report.GeneratedAt)
+	assert.Equal(t, 4, report.FetchedCount)
+	assert.Equal(t, 3, report.ActiveCount)
+
+	require.Len(t, report.NewAddons, 1)
+	assert.Equal(t, int32(5), report.NewAddons[0].ID)
+
+	require.Len(t, report.WouldDeactivate, 1)
+	assert.Equal(t, int32(3), report.WouldDeactivate[0].ID)
+
+	require.Len(t, report.Reactivated, 1)
+	assert.Equal(t, int32(4), report.Reactivated[0].ID)
+
+	require.Len(t, report.Renamed, 1)
+	assert.Equal(t, "addon-one", report.Renamed[0].OldSlug)
+	assert.Equal(t, "addon-one-reborn", report.Renamed[0].NewSlug)
+
+	require.Len(t, report.CategoryChanges, 1)
+	assert.Equal(t, int32(2), report.CategoryChanges[0].ID)
+	assert.Equal(t, []int32{1002}, report.CategoryChanges[0].Added)
+	assert.Equal(t, []int32{1001}, report.CategoryChanges[0].Removed)
+
+	// Unchanged addon 1 is omitted; largest magnitude first
+	require.Len(t, report.LargestDeltas, 2)
+	assert.Equal(t, int32(2), report.LargestDeltas[0].ID)
+	assert.Equal(t, int64(500), report.LargestDeltas[0].Delta)
+	assert.Equal(t, int32(4), report.LargestDeltas[1].ID)
+	assert.True(t, report.LargestDeltas[1].Decreased)
+}
+
+func TestBuildDiffReportLimitsDeltas(t *testing.T) {
+	var existing []database.ListAddonsForDiffRow
+	var mods []curseforge.Mod
+	for i := 1; i <= 5; i++ {
+		existing = append(existing, storedAddon(int32(i), "a", "A", "active", 0, 1001)) //nolint:gosec // Test data with small known values
+		mod := createTestMod(i, "a", "A")
+		mod.DownloadCount = int64(i * 10)
+		mods = append(mods, mod)
+	}
+
+	report := BuildDiffReport(existing, mods, 2, time.Now())
+
+	require.Len(t, report.LargestDeltas, 2)
+	assert.Equal(t, int32(5), report.LargestDeltas[0].ID)
+	assert.Equal(t, int32(4), report.LargestDeltas[1].ID)
+}
+
+func TestDiffReportWriteText(t *testing.T) {
+	report := BuildDiffReport(nil, []curseforge.Mod{createTestMod(1, "addon-one", "Addon One")}, 10, time.Now())
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+
+	assert.Contains(t, buf.String(), "New addons (1)")
+	assert.Contains(t, buf.String(), "addon-one")
+	assert.Contains(t, buf.String(), "Would be marked inactive (0)")
+}
@@ -0,0 +1,91 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/curseforge"
+	"addon-radar/internal/database"
+)
+
+// Milestone metrics
+const (
+	MetricDownloads = "downloads"
+	MetricThumbsUp  = "thumbs_up"
+)
+
+// Counts recorded as milestones when an addon crosses them
+var (
+	DownloadMilestones = []int64{10_000, 100_000, 1_000_000, 10_000_000}
+	ThumbsUpMilestones = []int64{100, 1_000, 10_000}
+)
+
+// Milestone is a count an addon crossed between two snapshots
+type Milestone struct {
+	Metric    string
+	Threshold int64
+	CrossedAt time.Time
+}
+
+// CrossedMilestones returns the thresholds crossed going from prev at prevAt
+// to cur at curAt, in ascending order. Crossing times are interpolated
+// linearly between the two snapshots.
+func CrossedMilestones(metric string, thresholds []int64, prev, cur int64, prevAt, curAt time.Time) []Milestone {
+	var crossed []Milestone
+	for _, threshold := range thresholds {
+		if prev >= threshold || cur < threshold {
+			continue
+		}
+		frac := float64(threshold-prev) / float64(cur-prev)
+		crossed = append(crossed, Milestone{
+			Metric:    metric,
+			Threshold: threshold,
+			CrossedAt: prevAt.Add(time.Duration(frac * float64(curAt.Sub(prevAt)))),
+		})
+	}
+	return crossed
+}
+
+// recordMilestonesWithTx records the milestones a mod crossed since the addon's
+// latest snapshot. It must run before the mod's snapshot is created. Addons
+// without a snapshot have nothing to compare against, so a new addon's
+// existing counts don't count as crossings.
+func (s *Service) recordMilestonesWithTx(ctx context.Context, qtx *database.Queries, mod curseforge.Mod, now time.Time) error {
+	id := int32(mod.ID) //nolint:gosec // CurseForge API IDs are always valid int32
+	prev, err := qtx.GetLatestSnapshot(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get latest snapshot: %w", err)
+	}
+
+	prevAt := prev.RecordedAt.Time
+	crossed := CrossedMilestones(MetricDownloads, DownloadMilestones, prev.DownloadCount, mod.DownloadCount, prevAt, now)
+	if prev.ThumbsUpCount.Valid {
+		crossed = append(crossed, CrossedMilestones(MetricThumbsUp, ThumbsUpMilestones,
+			int64(prev.ThumbsUpCount.Int32), int64(mod.ThumbsUpCount), prevAt, now)...)
+	}
+
+	for _, m := range crossed {
+		recorded, err := qtx.RecordMilestone(ctx, database.RecordMilestoneParams{
+			AddonID:   id,
+			Metric:    m.Metric,
+			Threshold: m.Threshold,
+			CrossedAt: pgtype.Timestamptz{Time: m.CrossedAt, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("record %s milestone %d: %w", m.Metric, m.Threshold, err)
+		}
+		if recorded > 0 {
+			slog.Info("addon crossed milestone", "id", mod.ID, "name", mod.Name, "metric", m.Metric, "threshold", m.Threshold)
+		}
+	}
+	return nil
+}
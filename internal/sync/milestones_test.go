@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/curseforge"
+	"addon-radar/internal/testutil"
+)
+
+func TestCrossedMilestones(t *testing.T) {
+	prevAt := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	curAt := prevAt.Add(time.Hour)
+
+	t.Run("interpolates the crossing time", func(t *testing.T) {
+		crossed := CrossedMilestones(MetricDownloads, DownloadMilestones, 99_000, 103_000, prevAt, curAt)
+		require.Len(t, crossed, 1)
+		assert.Equal(t, Milestone{Metric: MetricDownloads, Threshold: 100_000, CrossedAt: prevAt.Add(15 * time.Minute)}, crossed[0])
+	})
+
+	t.Run("several thresholds in one step", func(t *testing.T) {
+		crossed := CrossedMilestones(MetricThumbsUp, ThumbsUpMilestones, 50, 1_500, prevAt, curAt)
+		require.Len(t, crossed, 2)
+		assert.Equal(t, int64(100), crossed[0].Threshold)
+		assert.Equal(t, int64(1_000), crossed[1].Threshold)
+		assert.True(t, crossed[0].CrossedAt.Before(crossed[1].CrossedAt))
+	})
+
+	t.Run("landing exactly on a threshold crosses it", func(t *testing.T) {
+		crossed := CrossedMilestones(MetricDownloads, DownloadMilestones, 9_000, 10_000, prevAt, curAt)
+		require.Len(t, crossed, 1)
+		assert.Equal(t, curAt, crossed[0].CrossedAt)
+	})
+
+	t.Run("nothing crossed", func(t *testing.T) {
+		assert.Empty(t, CrossedMilestones(MetricDownloads, DownloadMilestones, 10_000, 50_000, prevAt, curAt))
+		assert.Empty(t, CrossedMilestones(MetricDownloads, DownloadMilestones, 120_000, 90_000, prevAt, curAt), "a drop is not a crossing")
+	})
+}
+
+func TestRecordMilestones(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+	service := NewServiceWithClient(tdb.Pool, tdb.Queries, &mockCurseForgeClient{})
+
+	// The first sync has no snapshot to compare against
+	mod := createTestMod(1, "milestone-test", "Milestone Test")
+	mod.DownloadCount = 95_000
+	mod.ThumbsUpCount = 90
+	_, err := service.syncMods(ctx, []curseforge.Mod{mod})
+	require.NoError(t, err)
+
+	milestones, err := tdb.Queries.ListAddonMilestones(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, milestones)
+
+	// Crossing 100k downloads and 100 thumbs up
+	mod.DownloadCount = 105_000
+	mod.ThumbsUpCount = 110
+	_, err = service.syncMods(ctx, []curseforge.Mod{mod})
+	require.NoError(t, err)
+
+	// Dropping back and crossing again doesn't record it twice
+	mod.DownloadCount = 99_000
+	_, err = service.syncMods(ctx, []curseforge.Mod{mod})
+	require.NoError(t, err)
+	mod.DownloadCount = 101_000
+	_, err = service.syncMods(ctx, []curseforge.Mod{mod})
+	require.NoError(t, err)
+
+	milestones, err = tdb.Queries.ListAddonMilestones(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, milestones, 2)
+	metrics := map[string]int64{}
+	for _, m := range milestones {
+		metrics[m.Metric] = m.Threshold
+	}
+	assert.Equal(t, map[string]int64{MetricDownloads: 100_000, MetricThumbsUp: 100}, metrics)
+
+	total, err := tdb.Queries.CountMilestones(ctx, MetricDownloads)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
@@ -0,0 +1,108 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"addon-radar/internal/database"
+)
+
+// SafetyThresholds decide when a full sync looks too suspicious to act on
+type SafetyThresholds struct {
+	MinAddons         int     // Absolute floor on synced addons
+	MinRatioOfLastRun float64 // Synced addons must be at least this fraction of the last healthy run
+	MaxErrorRate      float64 // Fraction of fetched addons allowed to fail
+}
+
+// DefaultSafetyThresholds returns the thresholds used when none are configured
+func DefaultSafetyThresholds() SafetyThresholds {
+	return SafetyThresholds{
+		MinAddons:         1000,
+		MinRatioOfLastRun: 0.9,
+		MaxErrorRate:      0.01,
+	}
+}
+
+// FullSyncResult is the outcome of a full sync.
+// A quarantined run kept its snapshots, but callers must not mark missing addons
+// inactive or publish trending scores based on it.
+type FullSyncResult struct {
+	SyncedIDs        []int32
+	FetchedCount     int
+	ErrorCount       int
+	BaselineCount    int // 0 if there was nothing to compare against
+	Quarantined      bool
+	QuarantineReason string
+}
+
+// quarantineReasons returns every threshold a run breached, or nil if it looks healthy.
+// baseline is the addon count of the last healthy run, or 0 if unknown.
+func quarantineReasons(t SafetyThresholds, fetched, synced, errorCount, baseline int) []string {
+	var reasons []string
+
+	if synced < t.MinAddons {
+		reasons = append(reasons, fmt.Sprintf("synced %d addons, below minimum of %d", synced, t.MinAddons))
+	}
+
+	if baseline > 0 && float64(synced) < t.MinRatioOfLastRun*float64(baseline) {
+		reasons = append(reasons, fmt.Sprintf("synced %d addons, below %.0f%% of last run's %d",
+			synced, t.MinRatioOfLastRun*100, baseline))
+	}
+
+	if fetched > 0 {
+		if rate := float64(errorCount) / float64(fetched); rate > t.MaxErrorRate {
+			reasons = append(reasons, fmt.Sprintf("error rate %.1f%% (%d/%d) exceeds %.1f%%",
+				rate*100, errorCount, fetched, t.MaxErrorRate*100))
+		}
+	}
+
+	return reasons
+}
+
+// syncBaseline returns the addon count to compare a new run against: the last
+// healthy run, or the current active addon count if no run has been recorded yet.
+func (s *Service) syncBaseline(ctx context.Context) (int, error) {
+	run, err := s.db.GetLastHealthySyncRun(ctx)
+	if err == nil {
+		return int(run.SyncedCount), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("load last sync run: %w", err)
+	}
+
+	count, err := s.db.CountAddons(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("count addons: %w", err)
+	}
+	return int(count), nil
+}
+
+// QuarantineReason evaluates counts against the thresholds and the current baseline.
+// It returns "" if a run with these counts would be acted on.
+func (s *Service) QuarantineReason(ctx context.Context, fetched, synced, errorCount int) (string, error) {
+	baseline, err := s.syncBaseline(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(quarantineReasons(s.thresholds, fetched, synced, errorCount, baseline), "; "), nil
+}
+
+// LatestSyncQuarantine returns why the most recent full sync was quarantined,
+// or "" if it was healthy or no sync has been recorded.
+func LatestSyncQuarantine(ctx context.Context, db *database.Queries) (string, error) {
+	run, err := db.GetLatestSyncRun(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("load latest sync run: %w", err)
+	}
+	if !run.Quarantined {
+		return "", nil
+	}
+	return run.QuarantineReason.String, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -11,42 +12,54 @@ import (
 
 	"addon-radar/internal/curseforge"
 	"addon-radar/internal/database"
+	"addon-radar/internal/trending"
 )
 
 // CurseForgeClient defines the interface for CurseForge API operations
 type CurseForgeClient interface {
 	GetAllWoWAddons(ctx context.Context) ([]curseforge.Mod, error)
+	GetWoWAddonsUpdatedSince(ctx context.Context, since time.Time) ([]curseforge.Mod, error)
+	GetMods(ctx context.Context, modIDs []int) ([]curseforge.Mod, error)
 	GetCategories(ctx context.Context, gameID int) ([]curseforge.Category, error)
 }
 
 // Service handles the sync process
 type Service struct {
-	pool   *pgxpool.Pool
-	db     *database.Queries
-	client CurseForgeClient
+	pool       *pgxpool.Pool
+	db         *database.Queries
+	client     CurseForgeClient
+	thresholds SafetyThresholds
 }
 
 // NewService creates a new sync service
 func NewService(pool *pgxpool.Pool, apiKey string) *Service {
 	return &Service{
-		pool:   pool,
-		db:     database.New(pool),
-		client: curseforge.NewClient(apiKey),
+		pool:       pool,
+		db:         database.New(pool),
+		client:     curseforge.NewClient(apiKey),
+		thresholds: DefaultSafetyThresholds(),
 	}
 }
 
 // NewServiceWithClient creates a sync service with a custom client (for testing)
 func NewServiceWithClient(pool *pgxpool.Pool, db *database.Queries, client CurseForgeClient) *Service {
 	return &Service{
-		pool:   pool,
-		db:     db,
-		client: client,
+		pool:       pool,
+		db:         db,
+		client:     client,
+		thresholds: DefaultSafetyThresholds(),
 	}
 }
 
+// SetSafetyThresholds overrides the thresholds used to quarantine suspicious syncs
+func (s *Service) SetSafetyThresholds(t SafetyThresholds) {
+	s.thresholds = t
+}
+
 // RunFullSync performs a full sync of all WoW addons.
-// Returns the IDs of all successfully synced addons for cleanup purposes.
-func (s *Service) RunFullSync(ctx context.Context) ([]int32, error) {
+// Snapshots are always kept, but a run that breaches the safety thresholds is
+// quarantined: the result says so and the reason is recorded in sync_runs.
+func (s *Service) RunFullSync(ctx context.Context) (*FullSyncResult, error) {
 	startTime := time.Now()
 	slog.Info("starting full sync")
 
@@ -58,6 +71,12 @@ func (s *Service) RunFullSync(ctx context.Context) ([]int32, error) {
 
 	slog.Info("fetched all addons", "count", len(mods))
 
+	// Load the baseline before syncing, since syncing changes the active addon count
+	baseline, err := s.syncBaseline(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Sync categories first
 	if err := s.syncCategories(ctx); err != nil {
 		slog.Warn("failed to sync categories", "error", err)
@@ -65,17 +84,9 @@ func (s *Service) RunFullSync(ctx context.Context) ([]int32, error) {
 	}
 
 	// Upsert each addon and create snapshot atomically
-	// Track successfully synced IDs for stale addon detection
-	syncedIDs := make([]int32, 0, len(mods))
-	var successCount, errorCount int
-	for _, mod := range mods {
-		if err := s.syncAddon(ctx, mod); err != nil {
-			slog.Error("failed to sync addon", "id", mod.ID, "name", mod.Name, "error", err)
-			errorCount++
-			continue
-		}
-		syncedIDs = append(syncedIDs, int32(mod.ID)) //nolint:gosec // CurseForge API IDs are always valid int32
-		successCount++
+	synced, err := s.syncMods(ctx, mods)
+	if err != nil {
+		return nil, err
 	}
 
 	duration := time.Since(startTime)
@@ -91,21 +102,218 @@ func (s *Service) RunFullSync(ctx context.Context) ([]int32, error) {
 	slog.Info("full sync complete",
 		"duration", duration,
 		"total", len(mods),
-		"success", successCount,
-		"errors", errorCount,
+		"success", len(synced.syncedIDs),
+		"errors", synced.errors,
+		"reactivated", synced.reactivated,
+	)
+
+	result := &FullSyncResult{
+		SyncedIDs:     synced.syncedIDs,
+		FetchedCount:  len(mods),
+		ErrorCount:    synced.errors,
+		BaselineCount: baseline,
+	}
+	if reasons := quarantineReasons(s.thresholds, len(mods), len(synced.syncedIDs), synced.errors, baseline); len(reasons) > 0 {
+		result.Quarantined = true
+		result.QuarantineReason = strings.Join(reasons, "; ")
+		slog.Error("sync quarantined: skipping inactive marking and trending publication",
+			"reason", result.QuarantineReason,
+		)
+	}
+
+	if err := s.recordSyncRun(ctx, startTime, result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// recordSyncRun stores the outcome of a full sync for baselines and auditing
+func (s *Service) recordSyncRun(ctx context.Context, startTime time.Time, result *FullSyncResult) error {
+	var baseline pgtype.Int4
+	if result.BaselineCount > 0 {
+		baseline = pgtype.Int4{Int32: int32(result.BaselineCount), Valid: true} //nolint:gosec // Addon counts fit in int32
+	}
+	var reason pgtype.Text
+	if result.Quarantined {
+		reason = pgtype.Text{String: result.QuarantineReason, Valid: true}
+	}
+
+	err := s.db.InsertSyncRun(ctx, database.InsertSyncRunParams{
+		StartedAt:        pgtype.Timestamptz{Time: startTime, Valid: true},
+		FetchedCount:     int32(result.FetchedCount),   //nolint:gosec // Addon counts fit in int32
+		SyncedCount:      int32(len(result.SyncedIDs)), //nolint:gosec // Addon counts fit in int32
+		ErrorCount:       int32(result.ErrorCount),     //nolint:gosec // Addon counts fit in int32
+		BaselineCount:    baseline,
+		Quarantined:      result.Quarantined,
+		QuarantineReason: reason,
+	})
+	if err != nil {
+		return fmt.Errorf("record sync run: %w", err)
+	}
+	return nil
+}
+
+// RunIncrementalSync syncs only addons CurseForge reports as modified after since.
+// It never marks addons inactive, so a partial fetch cannot hide addons.
+func (s *Service) RunIncrementalSync(ctx context.Context, since time.Time) (int, error) {
+	mods, err := s.client.GetWoWAddonsUpdatedSince(ctx, since)
+	if err != nil {
+		return 0, fmt.Errorf("fetch updated addons: %w", err)
+	}
+
+	result, err := s.syncMods(ctx, mods)
+	if err != nil {
+		return 0, err
+	}
+
+	slog.Info("incremental sync complete",
+		"since", since,
+		"fetched", len(mods),
+		"success", len(result.syncedIDs),
+		"errors", result.errors,
+	)
+
+	if err := checkErrorRate(result.errors, len(mods), s.thresholds.MaxErrorRate); err != nil {
+		return len(result.syncedIDs), err
+	}
+	return len(result.syncedIDs), nil
+}
+
+// RefreshTrendingAddons re-fetches the addons on every trending list so their
+// snapshots are fresher than the full sync interval.
+func (s *Service) RefreshTrendingAddons(ctx context.Context, limit int32) (int, error) {
+	params, err := trending.CurrentParams(ctx, s.db)
+	if err != nil {
+		return 0, err
+	}
+	hot, err := s.db.ListHotAddons(ctx, trending.HotListParams(params, limit))
+	if err != nil {
+		return 0, fmt.Errorf("list hot addons: %w", err)
+	}
+	rising, err := s.db.ListRisingAddons(ctx, trending.RisingListParams(params, limit))
+	if err != nil {
+		return 0, fmt.Errorf("list rising addons: %w", err)
+	}
+	loved, err := s.db.ListLovedAddons(ctx, trending.LovedListParams(params, limit))
+	if err != nil {
+		return 0, fmt.Errorf("list loved addons: %w", err)
+	}
+	fresh, err := s.db.ListFreshAddons(ctx, trending.FreshListParams(params, limit))
+	if err != nil {
+		return 0, fmt.Errorf("list fresh addons: %w", err)
+	}
+
+	ids := uniqueIDs(
+		listIDs(hot, func(a database.ListHotAddonsRow) int32 { return a.ID }),
+		listIDs(rising, func(a database.ListRisingAddonsRow) int32 { return a.ID }),
+		listIDs(loved, func(a database.ListLovedAddonsRow) int32 { return a.ID }),
+		listIDs(fresh, func(a database.ListFreshAddonsRow) int32 { return a.ID }),
 	)
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	mods, err := s.client.GetMods(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("fetch trending addons: %w", err)
+	}
+
+	result, err := s.syncMods(ctx, mods)
+	if err != nil {
+		return 0, err
+	}
+
+	slog.Info("trending refresh complete", "requested", len(ids), "success", len(result.syncedIDs), "errors", result.errors)
+
+	if err := checkErrorRate(result.errors, len(mods), s.thresholds.MaxErrorRate); err != nil {
+		return len(result.syncedIDs), err
+	}
+	return len(result.syncedIDs), nil
+}
+
+// listIDs returns the addon IDs of a list's rows.
+func listIDs[T any](rows []T, id func(T) int32) []int32 {
+	ids := make([]int32, len(rows))
+	for i, r := range rows {
+		ids[i] = id(r)
+	}
+	return ids
+}
+
+// uniqueIDs merges lists of addon IDs in order, dropping repeats.
+func uniqueIDs(lists ...[]int32) []int {
+	seen := make(map[int32]bool)
+	var ids []int
+	for _, list := range lists {
+		for _, id := range list {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, int(id))
+			}
+		}
+	}
+	return ids
+}
+
+// syncResult summarizes a batch of addon syncs
+type syncResult struct {
+	syncedIDs   []int32
+	errors      int
+	reactivated int
+}
+
+// syncMods upserts each mod with a snapshot. Individual failures are logged and counted.
+func (s *Service) syncMods(ctx context.Context, mods []curseforge.Mod) (syncResult, error) {
+	// Load inactive addons so reappearing ones get a reactivation event
+	inactive, err := s.loadInactiveAddonIDs(ctx)
+	if err != nil {
+		return syncResult{}, err
+	}
+
+	// Track successfully synced IDs for stale addon detection
+	result := syncResult{syncedIDs: make([]int32, 0, len(mods))}
+	for _, mod := range mods {
+		wasInactive := inactive[int32(mod.ID)] //nolint:gosec // CurseForge API IDs are always valid int32
+		if err := s.syncAddon(ctx, mod, wasInactive); err != nil {
+			slog.Error("failed to sync addon", "id", mod.ID, "name", mod.Name, "error", err)
+			result.errors++
+			continue
+		}
+		result.syncedIDs = append(result.syncedIDs, int32(mod.ID)) //nolint:gosec // CurseForge API IDs are always valid int32
+		if wasInactive {
+			result.reactivated++
+		}
+	}
+	return result, nil
+}
 
-	// Fail if error rate exceeds 1%
-	if errorCount > 0 && float64(errorCount)/float64(len(mods)) > 0.01 {
-		return syncedIDs, fmt.Errorf("sync had too many errors: %d/%d (%.1f%%)",
-			errorCount, len(mods), float64(errorCount)/float64(len(mods))*100)
+// checkErrorRate fails if more than maxRate of addons could not be synced
+func checkErrorRate(errorCount, total int, maxRate float64) error {
+	if errorCount > 0 && float64(errorCount)/float64(total) > maxRate {
+		return fmt.Errorf("sync had too many errors: %d/%d (%.1f%%)",
+			errorCount, total, float64(errorCount)/float64(total)*100)
 	}
+	return nil
+}
 
-	return syncedIDs, nil
+// loadInactiveAddonIDs returns the set of addons currently marked inactive
+func (s *Service) loadInactiveAddonIDs(ctx context.Context) (map[int32]bool, error) {
+	ids, err := s.db.ListInactiveAddonIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load inactive addons: %w", err)
+	}
+	inactive := make(map[int32]bool, len(ids))
+	for _, id := range ids {
+		inactive[id] = true
+	}
+	return inactive, nil
 }
 
-// syncAddon upserts an addon and creates a snapshot atomically
-func (s *Service) syncAddon(ctx context.Context, mod curseforge.Mod) error {
+// syncAddon upserts an addon, records the milestones it crossed and creates a
+// snapshot atomically.
+// If the addon was inactive, its return is recorded in the same transaction.
+func (s *Service) syncAddon(ctx context.Context, mod curseforge.Mod, wasInactive bool) error {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -114,10 +322,23 @@ func (s *Service) syncAddon(ctx context.Context, mod curseforge.Mod) error {
 
 	qtx := s.db.WithTx(tx)
 
+	// Must happen before the upsert, which flips the status back to active
+	if wasInactive {
+		if _, err := qtx.RecordAddonReactivation(ctx, int32(mod.ID)); err != nil { //nolint:gosec // CurseForge API IDs are always valid int32
+			return fmt.Errorf("record reactivation: %w", err)
+		}
+		slog.Info("addon reappeared", "id", mod.ID, "name", mod.Name)
+	}
+
 	if err := s.upsertAddonWithTx(ctx, qtx, mod); err != nil {
 		return fmt.Errorf("upsert addon: %w", err)
 	}
 
+	// Must happen before the snapshot, which becomes the latest one
+	if err := s.recordMilestonesWithTx(ctx, qtx, mod, time.Now()); err != nil {
+		return fmt.Errorf("record milestones: %w", err)
+	}
+
 	if err := s.createSnapshotWithTx(ctx, qtx, mod); err != nil {
 		return fmt.Errorf("create snapshot: %w", err)
 	}
@@ -129,56 +350,109 @@ func (s *Service) syncAddon(ctx context.Context, mod curseforge.Mod) error {
 	return nil
 }
 
-// syncCategories fetches and stores all WoW addon categories
+// syncCategories fetches all WoW addon categories and replaces the stored taxonomy
+// in a single transaction. Categories CurseForge no longer returns are soft-deleted.
 func (s *Service) syncCategories(ctx context.Context) error {
 	categories, err := s.client.GetCategories(ctx, curseforge.GameIDWoW)
 	if err != nil {
 		return fmt.Errorf("fetch categories: %w", err)
 	}
 
-	// Sort categories so parents come before children
-	// First pass: insert all categories without parent references
-	for _, cat := range categories {
+	// Guard against an empty API response soft-deleting the whole taxonomy
+	if len(categories) == 0 {
+		return fmt.Errorf("no categories returned, keeping existing taxonomy")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := s.db.WithTx(tx)
+
+	// Parents are inserted before children so parent_id references are always valid
+	ordered := orderCategoriesByParent(categories)
+	known := make(map[int]bool, len(ordered))
+	ids := make([]int32, 0, len(ordered))
+	for _, cat := range ordered {
 		var iconURL pgtype.Text
 		if cat.IconURL != "" {
 			iconURL = pgtype.Text{String: cat.IconURL, Valid: true}
 		}
 
-		err := s.db.UpsertCategory(ctx, database.UpsertCategoryParams{
-			ID:       int32(cat.ID), //nolint:gosec // CurseForge API IDs are always valid int32
-			Name:     cat.Name,
-			Slug:     cat.Slug,
-			ParentID: pgtype.Int4{}, // No parent reference initially
-			IconUrl:  iconURL,
+		var parentID pgtype.Int4
+		if cat.ParentID > 0 && known[cat.ParentID] {
+			parentID = pgtype.Int4{Int32: int32(cat.ParentID), Valid: true} //nolint:gosec // CurseForge API IDs are always valid int32
+		} else if cat.ParentID > 0 {
+			slog.Warn("category parent not returned by API, storing as root", "id", cat.ID, "parentId", cat.ParentID)
+		}
+
+		var classID pgtype.Int4
+		if cat.ClassID > 0 {
+			classID = pgtype.Int4{Int32: int32(cat.ClassID), Valid: true} //nolint:gosec // CurseForge API IDs are always valid int32
+		}
+
+		err := qtx.UpsertCategory(ctx, database.UpsertCategoryParams{
+			ID:           int32(cat.ID), //nolint:gosec // CurseForge API IDs are always valid int32
+			Name:         cat.Name,
+			Slug:         cat.Slug,
+			ParentID:     parentID,
+			IconUrl:      iconURL,
+			ClassID:      classID,
+			IsClass:      cat.IsClass,
+			DisplayIndex: int32(cat.DisplayIndex), //nolint:gosec // CurseForge API values are always valid int32
 		})
 		if err != nil {
-			slog.Warn("failed to upsert category", "id", cat.ID, "error", err)
+			return fmt.Errorf("upsert category %d: %w", cat.ID, err)
 		}
+		known[cat.ID] = true
+		ids = append(ids, int32(cat.ID)) //nolint:gosec // CurseForge API IDs are always valid int32
+	}
+
+	removed, err := qtx.SoftDeleteMissingCategories(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("soft-delete missing categories: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
 	}
 
-	// Second pass: update parent references
+	slog.Info("synced categories", "count", len(ids), "removed", removed)
+	return nil
+}
+
+// orderCategoriesByParent returns categories with every parent ahead of its children.
+// Categories whose parent is missing or part of a cycle are kept and treated as roots.
+func orderCategoriesByParent(categories []curseforge.Category) []curseforge.Category {
+	byID := make(map[int]curseforge.Category, len(categories))
 	for _, cat := range categories {
-		if cat.ParentID > 0 {
-			var iconURL pgtype.Text
-			if cat.IconURL != "" {
-				iconURL = pgtype.Text{String: cat.IconURL, Valid: true}
-			}
+		byID[cat.ID] = cat
+	}
 
-			err := s.db.UpsertCategory(ctx, database.UpsertCategoryParams{
-				ID:       int32(cat.ID), //nolint:gosec // CurseForge API IDs are always valid int32
-				Name:     cat.Name,
-				Slug:     cat.Slug,
-				ParentID: pgtype.Int4{Int32: int32(cat.ParentID), Valid: true}, //nolint:gosec // CurseForge API IDs are always valid int32
-				IconUrl:  iconURL,
-			})
-			if err != nil {
-				slog.Warn("failed to update category parent", "id", cat.ID, "parentId", cat.ParentID, "error", err)
-			}
+	ordered := make([]curseforge.Category, 0, len(categories))
+	placed := make(map[int]bool, len(categories))
+	visiting := make(map[int]bool)
+
+	var place func(cat curseforge.Category)
+	place = func(cat curseforge.Category) {
+		if placed[cat.ID] || visiting[cat.ID] {
+			return
 		}
+		visiting[cat.ID] = true
+		if parent, ok := byID[cat.ParentID]; ok && cat.ParentID != cat.ID {
+			place(parent)
+		}
+		visiting[cat.ID] = false
+		placed[cat.ID] = true
+		ordered = append(ordered, cat)
 	}
 
-	slog.Info("synced categories", "count", len(categories))
-	return nil
+	for _, cat := range categories {
+		place(cat)
+	}
+	return ordered
 }
 
 // upsertAddonWithTx inserts or updates an addon within a transaction
@@ -3,6 +3,7 @@ package sync
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -29,6 +30,32 @@ func (m *mockCurseForgeClient) GetAllWoWAddons(ctx context.Context) ([]curseforg
 	return m.addons, nil
 }
 
+func (m *mockCurseForgeClient) GetWoWAddonsUpdatedSince(ctx context.Context, since time.Time) ([]curseforge.Mod, error) {
+	if m.addonsErr != nil {
+		return nil, m.addonsErr
+	}
+	var mods []curseforge.Mod
+	for _, mod := range m.addons {
+		if mod.DateModified.After(since) {
+			mods = append(mods, mod)
+		}
+	}
+	return mods, nil
+}
+
+func (m *mockCurseForgeClient) GetMods(ctx context.Context, modIDs []int) ([]curseforge.Mod, error) {
+	if m.addonsErr != nil {
+		return nil, m.addonsErr
+	}
+	var mods []curseforge.Mod
+	for _, mod := range m.addons {
+		if slices.Contains(modIDs, mod.ID) {
+			mods = append(mods, mod)
+		}
+	}
+	return mods, nil
+}
+
 func (m *mockCurseForgeClient) GetCategories(ctx context.Context, gameID int) ([]curseforge.Category, error) {
 	if m.categoriesErr != nil {
 		return nil, m.categoriesErr
@@ -84,10 +111,10 @@ func TestRunFullSync(t *testing.T) {
 		}
 
 		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
-		syncedIDs, err := service.RunFullSync(ctx)
+		result, err := service.RunFullSync(ctx)
 
 		require.NoError(t, err)
-		assert.Len(t, syncedIDs, 3)
+		assert.Len(t, result.SyncedIDs, 3)
 
 		// Verify addons were created
 		addons, err := tdb.Queries.ListAddons(ctx, database.ListAddonsParams{Limit: 10, Offset: 0})
@@ -125,10 +152,11 @@ func TestRunFullSync(t *testing.T) {
 		}
 
 		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
-		syncedIDs, err := service.RunFullSync(ctx)
+		result, err := service.RunFullSync(ctx)
 
 		require.NoError(t, err)
-		assert.Empty(t, syncedIDs)
+		assert.Empty(t, result.SyncedIDs)
+		assert.True(t, result.Quarantined)
 
 		addons, err := tdb.Queries.ListAddons(ctx, database.ListAddonsParams{Limit: 10, Offset: 0})
 		require.NoError(t, err)
@@ -144,11 +172,11 @@ func TestRunFullSync(t *testing.T) {
 		}
 
 		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
-		syncedIDs, err := service.RunFullSync(ctx)
+		result, err := service.RunFullSync(ctx)
 
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "fetch addons")
-		assert.Nil(t, syncedIDs)
+		assert.Nil(t, result)
 	})
 
 	t.Run("continues on category sync failure", func(t *testing.T) {
@@ -163,11 +191,11 @@ func TestRunFullSync(t *testing.T) {
 		}
 
 		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
-		syncedIDs, err := service.RunFullSync(ctx)
+		result, err := service.RunFullSync(ctx)
 
 		// Should not return error - category sync failure is non-critical
 		require.NoError(t, err)
-		assert.Len(t, syncedIDs, 1)
+		assert.Len(t, result.SyncedIDs, 1)
 
 		// Addon should still be synced
 		addons, err := tdb.Queries.ListAddons(ctx, database.ListAddonsParams{Limit: 10, Offset: 0})
@@ -221,6 +249,124 @@ func TestRunFullSync(t *testing.T) {
 	})
 }
 
+func TestRunFullSyncQuarantine(t *testing.T) {
+	t.Run("healthy run is recorded and becomes the baseline", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		mockClient := &mockCurseForgeClient{
+			addons: []curseforge.Mod{
+				createTestMod(1, "addon-one", "Addon One"),
+				createTestMod(2, "addon-two", "Addon Two"),
+				createTestMod(3, "addon-three", "Addon Three"),
+			},
+		}
+
+		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
+		service.SetSafetyThresholds(SafetyThresholds{MinAddons: 1, MinRatioOfLastRun: 0.9, MaxErrorRate: 0.01})
+
+		result, err := service.RunFullSync(ctx)
+		require.NoError(t, err)
+		assert.False(t, result.Quarantined)
+
+		run, err := tdb.Queries.GetLastHealthySyncRun(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), run.SyncedCount)
+
+		// Two thirds of the previous run is below the 90% rule
+		mockClient.addons = mockClient.addons[:2]
+		result, err = service.RunFullSync(ctx)
+		require.NoError(t, err)
+		assert.True(t, result.Quarantined)
+		assert.Contains(t, result.QuarantineReason, "below 90% of last run's 3")
+		assert.Equal(t, 3, result.BaselineCount)
+
+		reason, err := LatestSyncQuarantine(ctx, tdb.Queries)
+		require.NoError(t, err)
+		assert.Equal(t, result.QuarantineReason, reason)
+
+		// Snapshots are still kept for the quarantined run
+		snapshots, err := tdb.Queries.GetAddonSnapshots(ctx, database.GetAddonSnapshotsParams{AddonID: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, snapshots, 2)
+
+		// The quarantined run does not lower the baseline
+		run, err = tdb.Queries.GetLastHealthySyncRun(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), run.SyncedCount)
+	})
+
+	t.Run("default thresholds quarantine small runs", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		mockClient := &mockCurseForgeClient{
+			addons: []curseforge.Mod{createTestMod(1, "addon-one", "Addon One")},
+		}
+
+		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
+		result, err := service.RunFullSync(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, result.Quarantined)
+		assert.Contains(t, result.QuarantineReason, "below minimum of 1000")
+	})
+}
+
+func TestQuarantineReasons(t *testing.T) {
+	thresholds := SafetyThresholds{MinAddons: 100, MinRatioOfLastRun: 0.9, MaxErrorRate: 0.01}
+
+	tests := []struct {
+		name                              string
+		fetched, synced, errors, baseline int
+		wantReasons                       int
+	}{
+		{"healthy", 1000, 1000, 0, 1000, 0},
+		{"no baseline", 1000, 1000, 0, 0, 0},
+		{"exactly at ratio", 1000, 900, 0, 1000, 0},
+		{"below ratio", 1000, 899, 0, 1000, 1},
+		{"below minimum", 50, 50, 0, 0, 1},
+		{"too many errors", 1000, 980, 20, 1000, 1},
+		{"everything wrong", 50, 10, 40, 1000, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons := quarantineReasons(thresholds, tt.fetched, tt.synced, tt.errors, tt.baseline)
+			assert.Len(t, reasons, tt.wantReasons, reasons)
+		})
+	}
+}
+
+func TestUniqueIDs(t *testing.T) {
+	assert.Equal(t, []int{3, 1, 2, 4}, uniqueIDs([]int32{3, 1}, []int32{1, 2}, nil, []int32{4, 3}))
+	assert.Empty(t, uniqueIDs(nil, nil))
+}
+
+func TestRunIncrementalSync(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	stale := createTestMod(1, "stale-addon", "Stale Addon")
+	stale.DateModified = time.Now().Add(-48 * time.Hour)
+	fresh := createTestMod(2, "fresh-addon", "Fresh Addon")
+
+	mockClient := &mockCurseForgeClient{
+		addons: []curseforge.Mod{stale, fresh},
+	}
+
+	service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
+	synced, err := service.RunIncrementalSync(ctx, time.Now().Add(-24*time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, synced)
+
+	_, err = tdb.Queries.GetAddonBySlug(ctx, "fresh-addon")
+	require.NoError(t, err)
+	_, err = tdb.Queries.GetAddonBySlug(ctx, "stale-addon")
+	require.Error(t, err)
+}
+
 func TestSyncCategories(t *testing.T) {
 	t.Run("syncs categories with parent hierarchy", func(t *testing.T) {
 		tdb := testutil.SetupTestDB(t)
@@ -253,6 +399,108 @@ func TestSyncCategories(t *testing.T) {
 		assert.True(t, childCat.ParentID.Valid)
 		assert.Equal(t, int32(1), childCat.ParentID.Int32)
 	})
+
+	t.Run("stores classes and display order", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		mockClient := &mockCurseForgeClient{
+			categories: []curseforge.Category{
+				// Child listed before its parent
+				{ID: 2, Name: "Bags", Slug: "bags", ParentID: 1, ClassID: 1, DisplayIndex: 3},
+				{ID: 1, Name: "Addons", Slug: "addons", IsClass: true},
+			},
+		}
+
+		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
+		require.NoError(t, service.syncCategories(ctx))
+
+		class, err := tdb.Queries.GetCategoryBySlug(ctx, "addons")
+		require.NoError(t, err)
+		assert.True(t, class.IsClass)
+
+		bags, err := tdb.Queries.GetCategoryBySlug(ctx, "bags")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), bags.ClassID.Int32)
+		assert.Equal(t, int32(1), bags.ParentID.Int32)
+		assert.Equal(t, int32(3), bags.DisplayIndex)
+	})
+
+	t.Run("soft-deletes removed categories and restores returning ones", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		mockClient := &mockCurseForgeClient{
+			categories: []curseforge.Category{
+				{ID: 1, Name: "Keep", Slug: "keep"},
+				{ID: 2, Name: "Gone", Slug: "gone"},
+			},
+		}
+
+		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
+		require.NoError(t, service.syncCategories(ctx))
+
+		mockClient.categories = mockClient.categories[:1]
+		require.NoError(t, service.syncCategories(ctx))
+
+		categories, err := tdb.Queries.ListCategories(ctx)
+		require.NoError(t, err)
+		require.Len(t, categories, 1)
+		assert.Equal(t, "keep", categories[0].Slug)
+
+		_, err = tdb.Queries.GetCategoryBySlug(ctx, "gone")
+		require.Error(t, err)
+
+		mockClient.categories = append(mockClient.categories, curseforge.Category{ID: 2, Name: "Gone", Slug: "gone"})
+		require.NoError(t, service.syncCategories(ctx))
+
+		categories, err = tdb.Queries.ListCategories(ctx)
+		require.NoError(t, err)
+		assert.Len(t, categories, 2)
+	})
+
+	t.Run("empty response keeps existing categories", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		mockClient := &mockCurseForgeClient{
+			categories: []curseforge.Category{{ID: 1, Name: "Keep", Slug: "keep"}},
+		}
+
+		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
+		require.NoError(t, service.syncCategories(ctx))
+
+		mockClient.categories = []curseforge.Category{}
+		require.Error(t, service.syncCategories(ctx))
+
+		categories, err := tdb.Queries.ListCategories(ctx)
+		require.NoError(t, err)
+		assert.Len(t, categories, 1)
+	})
+}
+
+func TestOrderCategoriesByParent(t *testing.T) {
+	categories := []curseforge.Category{
+		{ID: 3, ParentID: 2},
+		{ID: 2, ParentID: 1},
+		{ID: 1},
+		{ID: 4, ParentID: 99}, // Unknown parent
+		{ID: 5, ParentID: 6},  // Cycle
+		{ID: 6, ParentID: 5},
+	}
+
+	ordered := orderCategoriesByParent(categories)
+	require.Len(t, ordered, len(categories))
+
+	position := make(map[int]int, len(ordered))
+	for i, cat := range ordered {
+		position[cat.ID] = i
+	}
+	assert.Less(t, position[1], position[2])
+	assert.Less(t, position[2], position[3])
+	assert.Contains(t, position, 4)
+	assert.Contains(t, position, 5)
+	assert.Contains(t, position, 6)
 }
 
 func TestUpsertAddon(t *testing.T) {
@@ -445,7 +693,7 @@ func TestMarkMissingAddonsInactive(t *testing.T) {
 		// With empty array, all active addons would be marked inactive
 		assert.Equal(t, int64(1), affected)
 
-		// This test documents the dangerous behavior that the guard in main.go prevents
+		// This test documents the dangerous behavior that sync quarantine prevents
 	})
 
 	t.Run("marks only missing addons as inactive", func(t *testing.T) {
@@ -514,3 +762,52 @@ func TestUpsertAddonReactivation(t *testing.T) {
 		assert.Equal(t, "active", addon.Status.String)
 	})
 }
+
+func TestAddonStatusEvents(t *testing.T) {
+	t.Run("records removal and return with timestamps", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		mockClient := &mockCurseForgeClient{
+			addons: []curseforge.Mod{
+				createTestMod(1, "addon-one", "Addon One"),
+				createTestMod(2, "addon-two", "Addon Two"),
+			},
+		}
+		service := NewServiceWithClient(tdb.Pool, tdb.Queries, mockClient)
+		_, err := service.RunFullSync(ctx)
+		require.NoError(t, err)
+
+		// Addon 2 vanishes from the API
+		affected, err := tdb.Queries.MarkMissingAddonsInactive(ctx, []int32{1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), affected)
+
+		var toStatus, reason string
+		var downloads int64
+		err = tdb.Pool.QueryRow(ctx, `
+			SELECT to_status, reason, download_count FROM addon_status_events WHERE addon_id = 2
+		`).Scan(&toStatus, &reason, &downloads)
+		require.NoError(t, err)
+		assert.Equal(t, "inactive", toStatus)
+		assert.Equal(t, "missing_from_api", reason)
+		assert.Equal(t, int64(2000), downloads)
+
+		// Addon 2 comes back on the next sync
+		_, err = service.RunFullSync(ctx)
+		require.NoError(t, err)
+
+		var events int
+		err = tdb.Pool.QueryRow(ctx, `
+			SELECT COUNT(*) FROM addon_status_events
+			WHERE addon_id = 2 AND to_status = 'active' AND from_status = 'inactive'
+		`).Scan(&events)
+		require.NoError(t, err)
+		assert.Equal(t, 1, events)
+
+		// A regular sync of an active addon records nothing
+		err = tdb.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM addon_status_events WHERE addon_id = 1`).Scan(&events)
+		require.NoError(t, err)
+		assert.Equal(t, 0, events)
+	})
+}
@@ -23,7 +23,7 @@ type TestDB struct {
 
 // SetupTestDB creates a PostgreSQL container and returns a TestDB instance.
 // The container and connection pool are automatically cleaned up when the test finishes.
-func SetupTestDB(t *testing.T) *TestDB {
+func SetupTestDB(t testing.TB) *TestDB {
 	ctx := context.Background()
 
 	postgresContainer, err := postgres.Run(ctx,
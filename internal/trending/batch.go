@@ -0,0 +1,105 @@
+package trending
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"addon-radar/internal/database"
+)
+
+// parallel calls fn for every index in [0, n) across GOMAXPROCS workers.
+// fn must only write to state owned by its index.
+func parallel(n int, fn func(i int)) {
+	workers := min(runtime.GOMAXPROCS(0), n)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// scoreAll scores every addon as of now across a pool of workers. An addon
+// that scored zero last run and whose inputs haven't changed since would score
+// zero again, so it is returned as unchanged instead of being rescored.
+func (c *Calculator) scoreAll(
+	stats []database.GetAllSnapshotStatsRow,
+	percentile95 float64,
+	scoreMap map[int32]database.GetAllTrendingScoresRow,
+	updateMap map[int32]int32,
+	now time.Time,
+) (scores []Breakdown, unchanged []int32) {
+	results := make([]Breakdown, len(stats))
+	skipped := make([]bool, len(stats))
+	parallel(len(stats), func(i int) {
+		stat := stats[i]
+		updateCount := updateMap[stat.AddonID]
+		hash := c.inputsHash(stat, updateCount)
+		existing := scoreMap[stat.AddonID]
+		if existing.Unscored && existing.InputsHash.Valid && existing.InputsHash.Int64 == hash {
+			skipped[i] = true
+			return
+		}
+		results[i] = c.scoreAddon(stat, percentile95, updateCount, existing, now)
+		results[i].inputsHash = hash
+	})
+
+	scores = make([]Breakdown, 0, len(stats))
+	for i, r := range results {
+		if skipped[i] {
+			unchanged = append(unchanged, stats[i].AddonID)
+		} else {
+			scores = append(scores, r)
+		}
+	}
+	return scores, unchanged
+}
+
+// inputsHash fingerprints an addon's scoring inputs and the parameter set.
+// The download percentile is left out: it drifts every run but only matters
+// to addons that score, which are never skipped.
+func (c *Calculator) inputsHash(stat database.GetAllSnapshotStatsRow, updateCount int32) int64 {
+	downloads, thumbs, latestFile, created := int64(-1), int64(-1), int64(-1), int64(-1)
+	if stat.DownloadCount.Valid {
+		downloads = stat.DownloadCount.Int64
+	}
+	if stat.ThumbsUpCount.Valid {
+		thumbs = int64(stat.ThumbsUpCount.Int32)
+	}
+	if stat.LatestFileDate.Valid {
+		latestFile = stat.LatestFileDate.Time.UnixNano()
+	}
+	if stat.CreatedAt.Valid {
+		created = stat.CreatedAt.Time.UnixNano()
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(c.params.Version)) //nolint:errcheck // hash writes never fail
+	var buf [8]byte
+	for _, v := range []int64{
+		int64(stat.AddonID), downloads, thumbs, latestFile, created,
+		stat.DownloadChange24h, int64(stat.ThumbsChange24h), int64(stat.SnapshotCount24h),
+		stat.DownloadChange7d, int64(stat.ThumbsChange7d), stat.MinDownloads7d,
+		int64(updateCount),
+	} {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v)) //nolint:gosec // bit pattern only
+		h.Write(buf[:])                                  //nolint:errcheck // hash writes never fail
+	}
+	for _, v := range []float64{
+		stat.DownloadSlope24h, stat.ThumbsSlope24h, stat.DownloadFit24h, stat.ThumbsFit24h,
+		stat.SpanHours24h, stat.DownloadSlope7d, stat.ThumbsSlope7d,
+	} {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		h.Write(buf[:]) //nolint:errcheck // hash writes never fail
+	}
+	return int64(h.Sum64()) //nolint:gosec // stored as a BIGINT, only compared for equality
+}
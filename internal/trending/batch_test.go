@@ -0,0 +1,120 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/database"
+	"addon-radar/internal/testutil"
+)
+
+// syntheticStats builds n addons spread across the download range, a third of
+// them without any recent activity.
+func syntheticStats(n int) []database.GetAllSnapshotStatsRow {
+	stats := make([]database.GetAllSnapshotStatsRow, n)
+	for i := range stats {
+		downloads := int64(50 + (i*7919)%2000000)
+		var change int64
+		if i%3 != 0 {
+			change = int64(1 + i%500)
+		}
+		stats[i] = database.GetAllSnapshotStatsRow{
+			AddonID:           int32(i + 1), //nolint:gosec // Test data with small known values
+			DownloadCount:     pgtype.Int8{Int64: downloads, Valid: true},
+			ThumbsUpCount:     pgtype.Int4{Int32: int32(i % 300), Valid: true}, //nolint:gosec // Test data with small known values
+			DownloadChange24h: change,
+			SnapshotCount24h:  24,
+			DownloadChange7d:  change * 7,
+			MinDownloads7d:    downloads - change*7,
+			DownloadSlope24h:  float64(change) / 24,
+			DownloadFit24h:    1,
+			SpanHours24h:      23,
+			DownloadSlope7d:   float64(change) / 24,
+		}
+	}
+	return stats
+}
+
+func TestScoreAll(t *testing.T) {
+	c := &Calculator{params: DefaultParams()}
+	now := time.Now()
+	stats := syntheticStats(300)
+
+	scores, unchanged := c.scoreAll(stats, 500000, nil, nil, now)
+	require.Len(t, scores, len(stats))
+	assert.Empty(t, unchanged)
+	for i, s := range scores {
+		assert.Equal(t, Explain(c.params, stats[i], 500000, 0, database.GetAllTrendingScoresRow{}, now).HotScore, s.HotScore)
+	}
+
+	t.Run("skips unscored addons whose inputs are unchanged", func(t *testing.T) {
+		existing := make(map[int32]database.GetAllTrendingScoresRow)
+		for _, s := range scores {
+			existing[s.AddonID] = database.GetAllTrendingScoresRow{
+				AddonID:    s.AddonID,
+				InputsHash: pgtype.Int8{Int64: s.inputsHash, Valid: true},
+				Unscored:   s.HotScore <= 0 && s.RisingScore <= 0 && s.LovedScore <= 0 && s.FreshScore <= 0,
+			}
+		}
+
+		rescored, skipped := c.scoreAll(stats, 500000, existing, nil, now)
+		assert.Len(t, rescored, len(stats)-len(skipped))
+		assert.NotEmpty(t, skipped)
+		for _, id := range skipped {
+			assert.True(t, existing[id].Unscored)
+		}
+
+		// A new update or parameter set changes the inputs
+		updates := map[int32]int32{skipped[0]: 1}
+		_, skippedAfterUpdate := c.scoreAll(stats, 500000, existing, updates, now)
+		assert.NotContains(t, skippedAfterUpdate, skipped[0])
+
+		other := &Calculator{params: DefaultParams()}
+		other.params.Version = "other"
+		_, skippedOtherParams := other.scoreAll(stats, 500000, existing, nil, now)
+		assert.Empty(t, skippedOtherParams)
+	})
+}
+
+func BenchmarkScoreAll(b *testing.B) {
+	c := &Calculator{params: DefaultParams()}
+	stats := syntheticStats(100000)
+	now := time.Now()
+
+	for b.Loop() {
+		c.scoreAll(stats, 500000, nil, nil, now)
+	}
+}
+
+func BenchmarkCalculateAll(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping database benchmark in short mode")
+	}
+	tdb := testutil.SetupTestDB(b)
+	ctx := context.Background()
+
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count, thumbs_up_count, latest_file_date, categories)
+		SELECT i, 'addon-' || i, 'Addon ' || i, 'active', 50 + (i * 7919) % 2000000, i % 300,
+			NOW() - INTERVAL '2 days', ARRAY[i % 50]
+		FROM generate_series(1, 100000) AS i
+	`)
+	require.NoError(b, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO snapshots (addon_id, recorded_at, download_count, thumbs_up_count)
+		SELECT i, NOW() - h * INTERVAL '6 hours',
+			50 + (i * 7919) % 2000000 - CASE WHEN i % 3 = 0 THEN 0 ELSE h * (1 + i % 500) END, i % 300
+		FROM generate_series(1, 100000) AS i, generate_series(0, 4) AS h
+	`)
+	require.NoError(b, err)
+
+	calc := NewCalculator(NewPostgresStore(tdb.Pool))
+	for b.Loop() {
+		require.NoError(b, calc.CalculateAll(ctx))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
 	"time"
 
 	"addon-radar/internal/database"
@@ -11,135 +12,155 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// Calculator computes and stores trending scores for all addons.
+// Calculator computes trending scores for all addons. It reads and writes
+// through a Store, and each run's results are published as one generation.
 type Calculator struct {
-	db *database.Queries
+	store      Store
+	paramsFile string
+	params     Params // Parameters for the run in progress
 }
 
-// NewCalculator creates a new trending calculator.
-func NewCalculator(db *database.Queries) *Calculator {
-	return &Calculator{db: db}
+// NewCalculator creates a new trending calculator backed by store.
+func NewCalculator(store Store) *Calculator {
+	return &Calculator{store: store, params: DefaultParams()}
 }
 
-// CalculateAll recalculates trending scores for all active addons using bulk queries.
+// SetParamsFile makes every run use the parameter set in path instead of
+// the active set in the store.
+func (c *Calculator) SetParamsFile(path string) {
+	c.paramsFile = path
+}
+
+// CalculateAll recalculates trending scores for all active addons.
+// Parameters are resolved at the start of each run, so a newly activated set
+// takes effect without a restart.
 func (c *Calculator) CalculateAll(ctx context.Context) error {
-	slog.Info("starting trending calculation")
 	start := time.Now()
 
-	// Step 1: Load all data
-	percentile95, scoreMap, updateMap, allStats, err := c.loadAllData(ctx)
+	params, source, err := c.store.ResolveParams(ctx, c.paramsFile)
 	if err != nil {
 		return err
 	}
+	c.params = params
+	slog.Info("starting trending calculation", "params_version", params.Version, "params_source", source)
 
-	// Step 2: Calculate and upsert scores
-	processed := c.processAllAddons(ctx, allStats, percentile95, scoreMap, updateMap)
-
-	// Step 3: Clear ages for dropped addons
-	c.clearDroppedAddonAges(ctx)
-
-	// Step 4: Record and cleanup history
-	if err := c.recordAndCleanupHistory(ctx); err != nil {
+	// Step 1: Load all data
+	in, err := c.store.LoadInputs(ctx)
+	if err != nil {
 		return err
 	}
 
-	slog.Info("trending calculation complete", "duration", time.Since(start), "processed", processed)
-	return nil
-}
-
-func (c *Calculator) loadAllData(ctx context.Context) (float64, map[int32]database.GetAllTrendingScoresRow, map[int32]int32, []database.GetAllSnapshotStatsRow, error) {
-	// Get 95th percentile
-	percentile95, err := c.db.GetDownloadPercentile(ctx)
-	if err != nil {
-		return 0, nil, nil, nil, err
+	// Step 2: Score every addon, overall and within each category
+	g := c.generate(in)
+	if err := checkScores(g); err != nil {
+		return err
 	}
-	if percentile95 <= 0 {
-		percentile95 = 500000
+	processed := len(g.Scores) + len(g.Unchanged)
+	slog.Info("scored addons", "scored", len(g.Scores), "unchanged", len(g.Unchanged), "categories", len(g.Categories))
+	g.Run = database.InsertTrendingCalculationRunParams{
+		ParamsVersion:  params.Version,
+		ParamsSource:   source,
+		StartedAt:      pgtype.Timestamptz{Time: start, Valid: true},
+		ProcessedCount: int32(processed), //nolint:gosec // bounded by addon count
 	}
-	slog.Info("percentile", "p95", percentile95)
 
-	// Bulk fetch all snapshot stats
-	allStats, err := c.db.GetAllSnapshotStats(ctx)
-	if err != nil {
-		return 0, nil, nil, nil, err
+	// Step 3: Publish the generation
+	if err := c.store.Publish(ctx, g); err != nil {
+		return err
 	}
-	slog.Info("loaded snapshot stats", "count", len(allStats))
 
-	// Bulk fetch existing trending scores
-	existingScores, err := c.db.GetAllTrendingScores(ctx)
-	if err != nil {
-		return 0, nil, nil, nil, err
-	}
-	scoreMap := make(map[int32]database.GetAllTrendingScoresRow)
-	for _, s := range existingScores {
-		scoreMap[s.AddonID] = s
+	// Step 4: Record history
+	if err := c.store.RecordRankHistory(ctx, g); err != nil {
+		return err
 	}
-	slog.Info("loaded existing scores", "count", len(existingScores))
 
-	// Bulk fetch update counts
-	updateCounts, err := c.db.CountAllRecentFileUpdates(ctx)
-	if err != nil {
-		return 0, nil, nil, nil, err
-	}
-	updateMap := make(map[int32]int32)
-	for _, u := range updateCounts {
-		updateMap[u.AddonID] = u.UpdateCount
+	// Step 5: Score the shadow parameter set, if any. It is never published,
+	// so a failure doesn't fail the run.
+	if err := c.runShadow(ctx, in, g); err != nil {
+		slog.Warn("shadow calculation failed", "error", err)
 	}
-	slog.Info("loaded update counts", "count", len(updateCounts))
 
-	return percentile95, scoreMap, updateMap, allStats, nil
+	slog.Info("trending calculation complete", "duration", time.Since(start), "processed", processed)
+	return nil
 }
 
-func (c *Calculator) processAllAddons(ctx context.Context, allStats []database.GetAllSnapshotStatsRow, percentile95 float64, scoreMap map[int32]database.GetAllTrendingScoresRow, updateMap map[int32]int32) int {
-	processed := 0
-	for _, stat := range allStats {
-		if err := c.calculateAndUpsert(ctx, stat, percentile95, scoreMap, updateMap); err != nil {
-			slog.Warn("failed addon", "id", stat.AddonID, "err", err)
-			continue
-		}
-		processed++
-		if processed%1000 == 0 {
-			slog.Info("progress", "processed", processed, "total", len(allStats))
-		}
-	}
-	return processed
+// generate scores every addon from in and builds the lists. It has no side
+// effects, so Replay uses it too.
+func (c *Calculator) generate(in Inputs) Generation {
+	g := c.generateOverall(in)
+	g.Categories = c.generateCategories(in)
+	return g
 }
 
-func (c *Calculator) clearDroppedAddonAges(ctx context.Context) {
-	if err := c.db.ClearTrendingAgeForDroppedAddons(ctx); err != nil {
-		slog.Warn("clear hot age failed", "err", err)
-	}
-	if err := c.db.ClearRisingAgeForDroppedAddons(ctx); err != nil {
-		slog.Warn("clear rising age failed", "err", err)
+// generateOverall is generate without the category lists.
+func (c *Calculator) generateOverall(in Inputs) Generation {
+	scores, unchanged := c.scoreAll(in.Stats, in.Percentile95, in.Existing, in.Updates, in.Now)
+	lists, state := rankLists(scores, in.Existing, c.params)
+	applyListState(scores, state)
+
+	return Generation{
+		CalculatedAt: in.Now,
+		Scores:       scores,
+		Unchanged:    unchanged,
+		Hot:          lists.Hot,
+		Rising:       lists.Rising,
+		Loved:        lists.Loved,
+		Fresh:        lists.Fresh,
 	}
 }
 
-func (c *Calculator) recordAndCleanupHistory(ctx context.Context) error {
-	hotAddons, err := c.db.ListHotAddons(ctx, 20)
-	if err != nil {
-		return fmt.Errorf("list hot addons for history: %w", err)
+// checkScores fails a generation with a score that isn't a finite number, so
+// the live generation stays in place instead of the addon dropping off the
+// lists or outranking every other.
+func checkScores(g Generation) error {
+	var bad []int32
+	check := func(scores []Breakdown) {
+		for _, b := range scores {
+			for _, score := range []float64{b.HotScore, b.RisingScore, b.LovedScore, b.FreshScore} {
+				if math.IsNaN(score) || math.IsInf(score, 0) {
+					bad = append(bad, b.AddonID)
+					break
+				}
+			}
+		}
 	}
-	risingAddons, err := c.db.ListRisingAddons(ctx, 20)
-	if err != nil {
-		return fmt.Errorf("list rising addons for history: %w", err)
+	check(g.Scores)
+	for _, cat := range g.Categories {
+		check(cat.Scores)
 	}
-	if err := c.recordRankHistory(ctx, hotAddons, risingAddons); err != nil {
-		return fmt.Errorf("record rank history: %w", err)
+	if len(bad) > 0 {
+		return fmt.Errorf("scoring failed for %d addons, including %d: score is not a finite number", len(bad), bad[0])
 	}
+	return nil
+}
 
-	if err := c.cleanupOldRankHistory(ctx); err != nil {
-		slog.Warn("failed to cleanup rank history", "error", err)
+// applyListState copies list ranks into scores, and keeps list ages only for
+// addons still on a list.
+func applyListState(scores []Breakdown, state map[int32]database.GetAllTrendingScoresRow) {
+	for i := range scores {
+		row := state[scores[i].AddonID]
+		scores[i].FirstHotAt = row.FirstHotAt
+		scores[i].FirstRisingAt = row.FirstRisingAt
+		scores[i].FirstLovedAt = row.FirstLovedAt
+		scores[i].HotRank, scores[i].HotMisses = row.HotRank.Int16, row.HotMisses
+		scores[i].RisingRank, scores[i].RisingMisses = row.RisingRank.Int16, row.RisingMisses
+		scores[i].LovedRank, scores[i].LovedMisses = row.LovedRank.Int16, row.LovedMisses
+		scores[i].HotRawRank = row.HotRawRank.Int16
+		scores[i].RisingRawRank = row.RisingRawRank.Int16
+		scores[i].LovedRawRank = row.LovedRawRank.Int16
 	}
-	return nil
 }
 
-func (c *Calculator) calculateAndUpsert(
-	ctx context.Context,
+// scoreAddon computes an addon's scores as of now, keeping every intermediate
+// value. It has no side effects so it can be replayed at past points in time
+// and used to explain scores.
+func (c *Calculator) scoreAddon(
 	stat database.GetAllSnapshotStatsRow,
 	percentile95 float64,
-	scoreMap map[int32]database.GetAllTrendingScoresRow,
-	updateMap map[int32]int32,
-) error {
+	updateCount int32,
+	existing database.GetAllTrendingScoresRow,
+	now time.Time,
+) Breakdown {
 	// Extract downloads
 	var downloads float64
 	if stat.DownloadCount.Valid {
@@ -147,53 +168,134 @@ func (c *Calculator) calculateAndUpsert(
 	}
 
 	// Calculate velocities and growth
+	velocity24h, velocity7d := downloadVelocityWindows(stat)
+	confidence := VelocityConfidence(int(stat.SnapshotCount24h), stat.SpanHours24h, stat.DownloadFit24h, stat.DownloadChange24h)
 	downloadVelocity, thumbsVelocity := c.calculateVelocities(stat)
 	downloadGrowthPct, thumbsGrowthPct := c.calculateGrowthPercentages(stat)
 
 	// Multipliers
 	sizeMultiplier := CalculateSizeMultiplier(downloads, percentile95)
-	updateCount := updateMap[stat.AddonID]
 	maintenanceMultiplier := CalculateMaintenanceMultiplier(int(updateCount))
 
 	// Recent update check
 	hasRecentUpdate := false
 	if stat.LatestFileDate.Valid {
-		hasRecentUpdate = time.Since(stat.LatestFileDate.Time) < 7*24*time.Hour
+		hasRecentUpdate = now.Sub(stat.LatestFileDate.Time) < 7*24*time.Hour
 	}
 
 	// Calculate signals using new v2 functions
-	hotSignal := CalculateHotSignal(downloadVelocity, hasRecentUpdate)
+	hotSignal := CalculateHotSignal(c.params, downloadVelocity, hasRecentUpdate)
 	relativeGrowth := CalculateRelativeGrowth(stat.DownloadChange7d, stat.MinDownloads7d)
-	risingSignal := CalculateRisingSignal(relativeGrowth, maintenanceMultiplier)
+	risingSignal := CalculateRisingSignal(c.params, relativeGrowth, maintenanceMultiplier)
+	lovedSignal := CalculateLovedSignal(c.params, thumbsVelocity, downloadVelocity)
+
+	// Fresh Releases: traction since the addon was created
+	var thumbs int32
+	if stat.ThumbsUpCount.Valid {
+		thumbs = stat.ThumbsUpCount.Int32
+	}
+	createdAgeHours := createdAge(stat.CreatedAt, now)
+	var earlyVelocity float64
+	if stat.CreatedAt.Valid {
+		earlyVelocity = downloads / math.Max(createdAgeHours, 1)
+	}
+	freshSignal := CalculateFreshSignal(c.params, earlyVelocity, int(thumbs), int(updateCount))
 
 	// Calculate age and timestamps
-	existing := scoreMap[stat.AddonID]
-	hotAgeHours, firstHotAt := c.calculateHotAge(downloads, hotSignal, existing)
-	risingAgeHours, firstRisingAt := c.calculateRisingAge(downloads, risingSignal, existing)
+	hotAgeHours, firstHotAt := c.calculateHotAge(downloads, hotSignal, existing, now)
+	risingAgeHours, firstRisingAt := c.calculateRisingAge(downloads, risingSignal, existing, now)
+	lovedAgeHours, firstLovedAt := c.calculateLovedAge(downloads, lovedSignal, existing, now)
 
 	// Final scores
 	hotScore := c.calculateHotScore(downloads, hotSignal, sizeMultiplier, maintenanceMultiplier, hotAgeHours)
 	risingScore := c.calculateRisingScore(downloads, risingSignal, risingAgeHours)
+	lovedScore := c.calculateLovedScore(downloads, lovedSignal, lovedAgeHours)
+	freshEligible := c.isFreshCandidate(downloads, stat.CreatedAt, createdAgeHours) && freshSignal > 0
+	var freshScore float64
+	if freshEligible {
+		freshScore = CalculateFreshScore(c.params, freshSignal, createdAgeHours)
+	}
 
-	// Upsert
-	return c.upsertScore(ctx, stat.AddonID, hotScore, risingScore, downloadVelocity, thumbsVelocity,
-		downloadGrowthPct, thumbsGrowthPct, sizeMultiplier, maintenanceMultiplier, firstHotAt, firstRisingAt)
+	return Breakdown{
+		AddonID:               stat.AddonID,
+		Downloads:             int64(downloads),
+		ThumbsUp:              thumbs,
+		CreatedAt:             stat.CreatedAt,
+		AuthorID:              stat.AuthorID.Int32,
+		PrimaryCategoryID:     stat.PrimaryCategoryID.Int32,
+		DownloadChange24h:     stat.DownloadChange24h,
+		DownloadChange7d:      stat.DownloadChange7d,
+		SnapshotCount24h:      stat.SnapshotCount24h,
+		MinDownloads7d:        stat.MinDownloads7d,
+		SpanHours24h:          stat.SpanHours24h,
+		DownloadFit24h:        stat.DownloadFit24h,
+		UpdatesIn90Days:       updateCount,
+		Percentile95:          percentile95,
+		Velocity24h:           velocity24h,
+		Velocity7d:            velocity7d,
+		VelocityConfidence:    confidence,
+		DownloadVelocity:      downloadVelocity,
+		ThumbsVelocity:        thumbsVelocity,
+		EarlyVelocity:         earlyVelocity,
+		DownloadGrowthPct:     downloadGrowthPct,
+		ThumbsGrowthPct:       thumbsGrowthPct,
+		SizeMultiplier:        sizeMultiplier,
+		MaintenanceMultiplier: maintenanceMultiplier,
+		HasRecentUpdate:       hasRecentUpdate,
+		HotSignal:             hotSignal,
+		RelativeGrowth:        relativeGrowth,
+		RisingSignal:          risingSignal,
+		LovedSignal:           lovedSignal,
+		FreshSignal:           freshSignal,
+		HotEligible:           c.isHotCandidate(downloads) && hotSignal > 0,
+		RisingEligible:        c.isRisingCandidate(downloads) && risingSignal > 0,
+		LovedEligible:         c.isLovedCandidate(downloads) && lovedSignal > 0,
+		FreshEligible:         freshEligible,
+		FirstHotAt:            firstHotAt,
+		FirstRisingAt:         firstRisingAt,
+		FirstLovedAt:          firstLovedAt,
+		HotAgeHours:           hotAgeHours,
+		RisingAgeHours:        risingAgeHours,
+		LovedAgeHours:         lovedAgeHours,
+		CreatedAgeHours:       createdAgeHours,
+		HotDecay:              math.Pow(hotAgeHours+c.params.AgeOffset, c.params.HotGravity),
+		RisingDecay:           math.Pow(risingAgeHours+c.params.AgeOffset, c.params.RisingGravity),
+		LovedDecay:            math.Pow(lovedAgeHours+c.params.AgeOffset, c.params.LovedGravity),
+		FreshDecay:            math.Pow(createdAgeHours+c.params.AgeOffset, c.params.FreshGravity),
+		HotScore:              hotScore,
+		RisingScore:           risingScore,
+		LovedScore:            lovedScore,
+		FreshScore:            freshScore,
+	}
+}
+
+// createdAge returns the hours since an addon was created, or 0 if unknown.
+func createdAge(createdAt pgtype.Timestamptz, now time.Time) float64 {
+	if !createdAt.Valid {
+		return 0
+	}
+	return math.Max(now.Sub(createdAt.Time).Hours(), 0)
+}
+
+// downloadVelocityWindows returns downloads per hour over the last 24 hours and
+// 7 days, from the regression slopes. Counts that were corrected downwards
+// give no velocity rather than a negative one.
+func downloadVelocityWindows(stat database.GetAllSnapshotStatsRow) (float64, float64) {
+	return math.Max(stat.DownloadSlope24h, 0), math.Max(stat.DownloadSlope7d, 0)
 }
 
 func (c *Calculator) calculateVelocities(stat database.GetAllSnapshotStatsRow) (float64, float64) {
-	downloadChange24h := stat.DownloadChange24h
-	thumbsChange24h := int64(stat.ThumbsChange24h)
-	downloadChange7d := stat.DownloadChange7d
-	thumbsChange7d := int64(stat.ThumbsChange7d)
-	snapshotCount24h := stat.SnapshotCount24h
+	snapshotCount24h := int(stat.SnapshotCount24h)
+
+	velocity24h, velocity7d := downloadVelocityWindows(stat)
+	thumbsVel24h := math.Max(stat.ThumbsSlope24h, 0)
+	thumbsVel7d := math.Max(stat.ThumbsSlope7d, 0)
 
-	velocity24h := float64(downloadChange24h) / 24.0
-	velocity7d := float64(downloadChange7d) / 168.0
-	thumbsVel24h := float64(thumbsChange24h) / 24.0
-	thumbsVel7d := float64(thumbsChange7d) / 168.0
+	downloadConfidence := VelocityConfidence(snapshotCount24h, stat.SpanHours24h, stat.DownloadFit24h, stat.DownloadChange24h)
+	thumbsConfidence := VelocityConfidence(snapshotCount24h, stat.SpanHours24h, stat.ThumbsFit24h, int64(stat.ThumbsChange24h))
 
-	_, downloadVelocity := CalculateVelocity(velocity24h, velocity7d, int(snapshotCount24h), downloadChange24h)
-	_, thumbsVelocity := CalculateVelocity(thumbsVel24h, thumbsVel7d, int(snapshotCount24h), thumbsChange24h)
+	downloadVelocity := CalculateVelocity(velocity24h, velocity7d, downloadConfidence)
+	thumbsVelocity := CalculateVelocity(thumbsVel24h, thumbsVel7d, thumbsConfidence)
 
 	return downloadVelocity, thumbsVelocity
 }
@@ -219,115 +321,91 @@ func (c *Calculator) calculateGrowthPercentages(stat database.GetAllSnapshotStat
 	return downloadGrowthPct, thumbsGrowthPct
 }
 
-func (c *Calculator) calculateHotAge(downloads, hotSignal float64, existing database.GetAllTrendingScoresRow) (float64, pgtype.Timestamptz) {
+func (c *Calculator) isHotCandidate(downloads float64) bool {
+	return downloads >= float64(c.params.MinHotDownloads)
+}
+
+func (c *Calculator) isRisingCandidate(downloads float64) bool {
+	return downloads >= float64(c.params.MinRisingDownloads) && downloads <= float64(c.params.MaxRisingDownloads)
+}
+
+func (c *Calculator) isLovedCandidate(downloads float64) bool {
+	return downloads >= float64(c.params.MinLovedDownloads)
+}
+
+// isFreshCandidate reports whether an addon is new enough for Fresh Releases.
+// Addons without a creation date never are.
+func (c *Calculator) isFreshCandidate(downloads float64, createdAt pgtype.Timestamptz, createdAgeHours float64) bool {
+	return createdAt.Valid &&
+		createdAgeHours <= float64(c.params.FreshMaxAgeDays)*24 &&
+		downloads >= float64(c.params.MinFreshDownloads)
+}
+
+func (c *Calculator) calculateHotAge(downloads, hotSignal float64, existing database.GetAllTrendingScoresRow, now time.Time) (float64, pgtype.Timestamptz) {
 	var hotAgeHours float64
 	var firstHotAt pgtype.Timestamptz
-	if downloads >= 500 && hotSignal > 0 {
+	if c.isHotCandidate(downloads) && hotSignal > 0 {
 		if existing.FirstHotAt.Valid {
-			hotAgeHours = time.Since(existing.FirstHotAt.Time).Hours()
+			hotAgeHours = now.Sub(existing.FirstHotAt.Time).Hours()
 			firstHotAt = existing.FirstHotAt
 		} else {
-			firstHotAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			firstHotAt = pgtype.Timestamptz{Time: now, Valid: true}
 		}
 	}
 	return hotAgeHours, firstHotAt
 }
 
-func (c *Calculator) calculateRisingAge(downloads, risingSignal float64, existing database.GetAllTrendingScoresRow) (float64, pgtype.Timestamptz) {
+func (c *Calculator) calculateRisingAge(downloads, risingSignal float64, existing database.GetAllTrendingScoresRow, now time.Time) (float64, pgtype.Timestamptz) {
 	var risingAgeHours float64
 	var firstRisingAt pgtype.Timestamptz
-	if downloads >= 50 && downloads <= 10000 && risingSignal > 0 {
+	if c.isRisingCandidate(downloads) && risingSignal > 0 {
 		if existing.FirstRisingAt.Valid {
-			risingAgeHours = time.Since(existing.FirstRisingAt.Time).Hours()
+			risingAgeHours = now.Sub(existing.FirstRisingAt.Time).Hours()
 			firstRisingAt = existing.FirstRisingAt
 		} else {
-			firstRisingAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			firstRisingAt = pgtype.Timestamptz{Time: now, Valid: true}
 		}
 	}
 	return risingAgeHours, firstRisingAt
 }
 
-func (c *Calculator) calculateHotScore(downloads, hotSignal, sizeMultiplier, maintenanceMultiplier, hotAgeHours float64) float64 {
-	if downloads >= 500 && hotSignal > 0 {
-		return CalculateHotScore(hotSignal, sizeMultiplier, maintenanceMultiplier, hotAgeHours)
+func (c *Calculator) calculateLovedAge(downloads, lovedSignal float64, existing database.GetAllTrendingScoresRow, now time.Time) (float64, pgtype.Timestamptz) {
+	var lovedAgeHours float64
+	var firstLovedAt pgtype.Timestamptz
+	if c.isLovedCandidate(downloads) && lovedSignal > 0 {
+		if existing.FirstLovedAt.Valid {
+			lovedAgeHours = now.Sub(existing.FirstLovedAt.Time).Hours()
+			firstLovedAt = existing.FirstLovedAt
+		} else {
+			firstLovedAt = pgtype.Timestamptz{Time: now, Valid: true}
+		}
 	}
-	return 0
+	return lovedAgeHours, firstLovedAt
 }
 
-func (c *Calculator) calculateRisingScore(downloads, risingSignal, risingAgeHours float64) float64 {
-	if downloads >= 50 && downloads <= 10000 && risingSignal > 0 {
-		return CalculateRisingScore(risingSignal, risingAgeHours)
+func (c *Calculator) calculateHotScore(downloads, hotSignal, sizeMultiplier, maintenanceMultiplier, hotAgeHours float64) float64 {
+	if c.isHotCandidate(downloads) && hotSignal > 0 {
+		return CalculateHotScore(c.params, hotSignal, sizeMultiplier, maintenanceMultiplier, hotAgeHours)
 	}
 	return 0
 }
 
-func (c *Calculator) upsertScore(ctx context.Context, addonID int32, hotScore, risingScore, downloadVelocity, thumbsVelocity,
-	downloadGrowthPct, thumbsGrowthPct, sizeMultiplier, maintenanceMultiplier float64,
-	firstHotAt, firstRisingAt pgtype.Timestamptz) error {
-
-	toNumeric := func(v float64) pgtype.Numeric {
-		var n pgtype.Numeric
-		n.Scan(fmt.Sprintf("%f", v)) //nolint:errcheck // Scan from formatted string is safe
-		return n
+func (c *Calculator) calculateRisingScore(downloads, risingSignal, risingAgeHours float64) float64 {
+	if c.isRisingCandidate(downloads) && risingSignal > 0 {
+		return CalculateRisingScore(c.params, risingSignal, risingAgeHours)
 	}
-
-	return c.db.UpsertTrendingScore(ctx, database.UpsertTrendingScoreParams{
-		AddonID:               addonID,
-		HotScore:              toNumeric(hotScore),
-		RisingScore:           toNumeric(risingScore),
-		DownloadVelocity:      toNumeric(downloadVelocity),
-		ThumbsVelocity:        toNumeric(thumbsVelocity),
-		DownloadGrowthPct:     toNumeric(downloadGrowthPct),
-		ThumbsGrowthPct:       toNumeric(thumbsGrowthPct),
-		SizeMultiplier:        toNumeric(sizeMultiplier),
-		MaintenanceMultiplier: toNumeric(maintenanceMultiplier),
-		FirstHotAt:            firstHotAt,
-		FirstRisingAt:         firstRisingAt,
-	})
+	return 0
 }
 
-func (c *Calculator) recordRankHistory(ctx context.Context, hotAddons []database.ListHotAddonsRow, risingAddons []database.ListRisingAddonsRow) error {
-	// Use single batch timestamp for all inserts (ensures consistent snapshots)
-	batchTime := pgtype.Timestamptz{Time: time.Now(), Valid: true}
-
-	// Record hot addon ranks
-	for i, addon := range hotAddons {
-		err := c.db.InsertRankHistoryWithTime(ctx, database.InsertRankHistoryWithTimeParams{
-			AddonID:    addon.ID,
-			Category:   "hot",
-			Rank:       int16(i + 1), //nolint:gosec // i is bounded by query limit (20)
-			Score:      addon.HotScore,
-			RecordedAt: batchTime,
-		})
-		if err != nil {
-			return fmt.Errorf("insert hot rank history: %w", err)
-		}
+func (c *Calculator) calculateLovedScore(downloads, lovedSignal, lovedAgeHours float64) float64 {
+	if c.isLovedCandidate(downloads) && lovedSignal > 0 {
+		return CalculateLovedScore(c.params, lovedSignal, lovedAgeHours)
 	}
-
-	// Record rising addon ranks
-	for i, addon := range risingAddons {
-		err := c.db.InsertRankHistoryWithTime(ctx, database.InsertRankHistoryWithTimeParams{
-			AddonID:    addon.ID,
-			Category:   "rising",
-			Rank:       int16(i + 1), //nolint:gosec // i is bounded by query limit (20)
-			Score:      addon.RisingScore,
-			RecordedAt: batchTime,
-		})
-		if err != nil {
-			return fmt.Errorf("insert rising rank history: %w", err)
-		}
-	}
-
-	return nil
+	return 0
 }
 
-func (c *Calculator) cleanupOldRankHistory(ctx context.Context) error {
-	deleted, err := c.db.DeleteOldRankHistory(ctx)
-	if err != nil {
-		return fmt.Errorf("delete old rank history: %w", err)
-	}
-	if deleted > 0 {
-		slog.Info("cleaned up old rank history", "deleted", deleted)
-	}
-	return nil
+func toNumeric(v float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	n.Scan(fmt.Sprintf("%f", v)) //nolint:errcheck // Scan from formatted string is safe
+	return n
 }
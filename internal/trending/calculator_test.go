@@ -2,6 +2,8 @@ package trending
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -47,7 +49,7 @@ func TestCalculatorCalculateAll(t *testing.T) {
 		// Seed addon with downloads in "rising" range (50-10000)
 		seedAddonWithSnapshots(t, tdb, 2, "rising-addon", 500, 20, 10)
 
-		calc := NewCalculator(tdb.Queries)
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
 		err := calc.CalculateAll(ctx)
 		require.NoError(t, err)
 
@@ -78,7 +80,7 @@ func TestCalculatorCalculateAll(t *testing.T) {
 		tdb := testutil.SetupTestDB(t)
 		ctx := context.Background()
 
-		calc := NewCalculator(tdb.Queries)
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
 		err := calc.CalculateAll(ctx)
 		require.NoError(t, err)
 
@@ -100,7 +102,7 @@ func TestCalculatorCalculateAll(t *testing.T) {
 		`, 1, "no-snapshots", "No Snapshots Addon")
 		require.NoError(t, err)
 
-		calc := NewCalculator(tdb.Queries)
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
 		err = calc.CalculateAll(ctx)
 		require.NoError(t, err)
 
@@ -114,7 +116,7 @@ func TestCalculatorCalculateAll(t *testing.T) {
 		// Seed addon
 		seedAddonWithSnapshots(t, tdb, 1, "update-test", 5000, 100, 10)
 
-		calc := NewCalculator(tdb.Queries)
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
 
 		// First calculation
 		err := calc.CalculateAll(ctx)
@@ -160,7 +162,7 @@ func TestCalculatorCalculateAll(t *testing.T) {
 		// Addon with downloads above rising max (> 10000)
 		seedAddonWithSnapshots(t, tdb, 2, "high-downloads", 50000, 1000, 10)
 
-		calc := NewCalculator(tdb.Queries)
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
 		err := calc.CalculateAll(ctx)
 		require.NoError(t, err)
 
@@ -188,7 +190,7 @@ func TestCalculatorCalculateAll(t *testing.T) {
 		// Seed addon eligible for hot
 		seedAddonWithSnapshots(t, tdb, 1, "timestamp-test", 5000, 100, 10)
 
-		calc := NewCalculator(tdb.Queries)
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
 
 		// First calculation
 		err := calc.CalculateAll(ctx)
@@ -219,6 +221,137 @@ func TestCalculatorCalculateAll(t *testing.T) {
 		}
 	})
 
+	t.Run("records parameter version on scores and runs", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		seedAddonWithSnapshots(t, tdb, 1, "version-test", 5000, 100, 10)
+
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
+		require.NoError(t, calc.CalculateAll(ctx))
+
+		var version string
+		err := tdb.Pool.QueryRow(ctx, `
+			SELECT params_version FROM trending_scores WHERE addon_id = 1
+		`).Scan(&version)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultParamsVersion, version)
+
+		var source string
+		err = tdb.Pool.QueryRow(ctx, `
+			SELECT params_version, params_source FROM trending_calculation_runs
+		`).Scan(&version, &source)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultParamsVersion, version)
+		assert.Equal(t, ParamsSourceDefault, source)
+	})
+
+	t.Run("uses parameter file over active set", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		// Below the default hot gate, above the file's
+		seedAddonWithSnapshots(t, tdb, 1, "low-gate", 300, 10, 10)
+
+		active := DefaultParams()
+		active.Version = "active-set"
+		require.NoError(t, ActivateParams(ctx, tdb.Pool, active))
+
+		path := filepath.Join(t.TempDir(), "params.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"version": "low-gate", "min_hot_downloads": 100}`), 0o600))
+
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
+		calc.SetParamsFile(path)
+		require.NoError(t, calc.CalculateAll(ctx))
+
+		var hotScore float64
+		var version string
+		err := tdb.Pool.QueryRow(ctx, `
+			SELECT COALESCE(hot_score, 0), params_version FROM trending_scores WHERE addon_id = 1
+		`).Scan(&hotScore, &version)
+		require.NoError(t, err)
+		assert.Greater(t, hotScore, 0.0, "file gate should admit the addon")
+		assert.Equal(t, "low-gate", version)
+
+		current, err := CurrentParams(ctx, tdb.Queries)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), current.MinHotDownloads)
+	})
+
+	t.Run("rejects reused version with different values", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		p := DefaultParams()
+		p.Version = "tuned"
+		require.NoError(t, RegisterParams(ctx, tdb.Queries, p))
+		require.NoError(t, RegisterParams(ctx, tdb.Queries, p), "re-registering identical values is fine")
+
+		p.HotGravity = 2.0
+		err := RegisterParams(ctx, tdb.Queries, p)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "different values")
+	})
+
+	t.Run("ranks within each category", func(t *testing.T) {
+		tdb := testutil.SetupTestDB(t)
+		ctx := context.Background()
+
+		_, err := tdb.Pool.Exec(ctx, `
+			INSERT INTO categories (id, name, slug) VALUES (10, 'Professions', 'professions'), (11, 'Pet Battles', 'pet-battles')
+		`)
+		require.NoError(t, err)
+		seedAddonWithSnapshots(t, tdb, 1, "big-addon", 50000, 100, 10)
+		seedAddonWithSnapshots(t, tdb, 2, "profession-addon", 5000, 100, 10)
+		seedAddonWithSnapshots(t, tdb, 3, "pet-addon", 2000, 100, 10)
+		_, err = tdb.Pool.Exec(ctx, `
+			UPDATE addons SET categories = CASE id WHEN 3 THEN '{11}'::int[] ELSE '{10}'::int[] END
+		`)
+		require.NoError(t, err)
+
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
+		require.NoError(t, calc.CalculateAll(ctx))
+
+		var petAddons []int32
+		rows, err := tdb.Pool.Query(ctx, `SELECT addon_id FROM category_trending_scores WHERE category_id = 11`)
+		require.NoError(t, err)
+		for rows.Next() {
+			var id int32
+			require.NoError(t, rows.Scan(&id))
+			petAddons = append(petAddons, id)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, []int32{3}, petAddons)
+
+		// Alone in its category, the pet addon is at the category's 95th percentile
+		var globalSize, categorySize float64
+		err = tdb.Pool.QueryRow(ctx, `
+			SELECT t.size_multiplier, c.size_multiplier
+			FROM trending_scores t
+			JOIN category_trending_scores c ON c.addon_id = t.addon_id
+			WHERE t.addon_id = 3
+		`).Scan(&globalSize, &categorySize)
+		require.NoError(t, err)
+		assert.Less(t, globalSize, 1.0)
+		assert.InDelta(t, 1.0, categorySize, 0.0001)
+
+		var ranks int
+		err = tdb.Pool.QueryRow(ctx, `
+			SELECT COUNT(*) FROM category_rank_history WHERE category_id = 10 AND list = 'hot'
+		`).Scan(&ranks)
+		require.NoError(t, err)
+		assert.Equal(t, 2, ranks)
+
+		// Scores an addon no longer earns in a category are removed on the next run
+		_, err = tdb.Pool.Exec(ctx, `UPDATE addons SET categories = '{}' WHERE id = 3`)
+		require.NoError(t, err)
+		require.NoError(t, calc.CalculateAll(ctx))
+		var remaining int
+		err = tdb.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM category_trending_scores WHERE category_id = 11`).Scan(&remaining)
+		require.NoError(t, err)
+		assert.Equal(t, 0, remaining)
+	})
+
 	t.Run("calculates multipliers correctly", func(t *testing.T) {
 		tdb := testutil.SetupTestDB(t)
 		ctx := context.Background()
@@ -226,7 +359,7 @@ func TestCalculatorCalculateAll(t *testing.T) {
 		// Seed addon
 		seedAddonWithSnapshots(t, tdb, 1, "multiplier-test", 5000, 100, 10)
 
-		calc := NewCalculator(tdb.Queries)
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
 		err := calc.CalculateAll(ctx)
 		require.NoError(t, err)
 
@@ -262,7 +395,7 @@ func TestCalculatorPerformance(t *testing.T) {
 			seedAddonWithSnapshots(t, tdb, int32(int64(i)), "addon-"+string(rune('a'+i)), int64(1000+i*100), int32(int64(10+i)), 5)
 		}
 
-		calc := NewCalculator(tdb.Queries)
+		calc := NewCalculator(NewPostgresStore(tdb.Pool))
 
 		start := time.Now()
 		err := calc.CalculateAll(ctx)
@@ -0,0 +1,51 @@
+package trending
+
+import (
+	"sort"
+)
+
+// generateCategories ranks hot and rising within each category, so niche
+// categories get lists that aren't crowded out by the biggest addons. The size
+// multiplier is normalized to each category's own download percentile, and list
+// ages are kept per category.
+func (c *Calculator) generateCategories(in Inputs) []CategoryGeneration {
+	statByAddon := make(map[int32]int, len(in.Stats))
+	for i, stat := range in.Stats {
+		statByAddon[stat.AddonID] = i
+	}
+
+	categoryIDs := make([]int32, 0, len(in.CategoryMembers))
+	for categoryID := range in.CategoryMembers {
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	sort.Slice(categoryIDs, func(i, j int) bool { return categoryIDs[i] < categoryIDs[j] })
+
+	// Category lists aren't diversified, so their raw and displayed ranks match
+	params := c.params
+	params.DiversityMaxPerAuthor, params.DiversityMaxPerCategory = 0, 0
+
+	categories := make([]CategoryGeneration, len(categoryIDs))
+	parallel(len(categoryIDs), func(i int) {
+		categoryID := categoryIDs[i]
+		addonIDs := in.CategoryMembers[categoryID]
+		scores := make([]Breakdown, 0, len(addonIDs))
+		for _, id := range addonIDs {
+			idx, ok := statByAddon[id]
+			if !ok {
+				continue
+			}
+			scores = append(scores, c.scoreAddon(in.Stats[idx], in.CategoryPercentiles[categoryID], in.Updates[id], in.CategoryExisting[categoryID][id], in.Now))
+		}
+
+		lists, state := rankLists(scores, in.CategoryExisting[categoryID], params)
+		applyListState(scores, state)
+		positive := make([]Breakdown, 0, len(lists.Hot)+len(lists.Rising))
+		for _, s := range scores {
+			if s.HotScore > 0 || s.RisingScore > 0 {
+				positive = append(positive, s)
+			}
+		}
+		categories[i] = CategoryGeneration{CategoryID: categoryID, Scores: positive, Hot: lists.Hot, Rising: lists.Rising}
+	})
+	return categories
+}
@@ -0,0 +1,97 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// Comeback detection thresholds.
+const (
+	ComebackWindow       = 14 * 24 * time.Hour // How recent the reviving release must be
+	ComebackListedWindow = 30 * 24 * time.Hour // How long a comeback stays listed and badged
+
+	comebackMinDormancy     = 180 * 24 * time.Hour // Gap between the reviving release and the one before
+	comebackMinDormantHours = 7 * 24               // Snapshots before the release must show this long to call downloads flat
+	comebackMinRevivedHours = 24                   // Snapshots since the release must show this long
+	comebackMinVelocity     = 1.0                  // Downloads per hour since the release
+	comebackVelocityRatio   = 3.0                  // Velocity since the release over velocity before it
+)
+
+// Comeback is an addon that released again after months without a file and
+// got its downloads back.
+type Comeback struct {
+	AddonID             int32
+	ReleaseDate         time.Time
+	PreviousReleaseDate time.Time
+	DormantVelocity     float64 // Downloads per hour before the release
+	RevivedVelocity     float64 // Downloads per hour since the release
+}
+
+// EvaluateComeback reports whether a candidate is a comeback: its release
+// ended a long dormancy, downloads were flat before it, and download velocity
+// since is well above what it was.
+func EvaluateComeback(c database.ListComebackCandidatesRow) (Comeback, bool) {
+	if !c.ReleaseDate.Valid || !c.PreviousReleaseDate.Valid {
+		return Comeback{}, false
+	}
+	if c.ReleaseDate.Time.Sub(c.PreviousReleaseDate.Time) < comebackMinDormancy {
+		return Comeback{}, false
+	}
+	if c.DormantHours < comebackMinDormantHours || c.RevivedHours < comebackMinRevivedHours {
+		return Comeback{}, false
+	}
+
+	dormant := float64(c.DormantDownloadChange) / c.DormantHours
+	revived := float64(c.RevivedDownloadChange) / c.RevivedHours
+	if revived < comebackMinVelocity || revived < comebackVelocityRatio*dormant {
+		return Comeback{}, false
+	}
+	return Comeback{
+		AddonID:             c.AddonID,
+		ReleaseDate:         c.ReleaseDate.Time,
+		PreviousReleaseDate: c.PreviousReleaseDate.Time,
+		DormantVelocity:     dormant,
+		RevivedVelocity:     revived,
+	}, true
+}
+
+// DetectComebacks records every comeback among addons that released within
+// ComebackWindow before now, and marks the addons. It returns how many new
+// comebacks were recorded; a release is only ever recorded once.
+func DetectComebacks(ctx context.Context, db *database.Queries, now time.Time) (int, error) {
+	candidates, err := db.ListComebackCandidates(ctx, pgtype.Timestamptz{Time: now.Add(-ComebackWindow), Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("list comeback candidates: %w", err)
+	}
+
+	recorded := 0
+	for _, candidate := range candidates {
+		cb, ok := EvaluateComeback(candidate)
+		if !ok {
+			continue
+		}
+		n, err := db.RecordComeback(ctx, database.RecordComebackParams{
+			AddonID:             cb.AddonID,
+			ReleaseDate:         candidate.ReleaseDate,
+			PreviousReleaseDate: candidate.PreviousReleaseDate,
+			DormantVelocity:     toNumeric(cb.DormantVelocity),
+			RevivedVelocity:     toNumeric(cb.RevivedVelocity),
+		})
+		if err != nil {
+			return recorded, fmt.Errorf("record comeback of addon %d: %w", cb.AddonID, err)
+		}
+		if n > 0 {
+			recorded++
+			slog.Info("detected comeback", "addon_id", cb.AddonID,
+				"dormant_days", int(cb.ReleaseDate.Sub(cb.PreviousReleaseDate).Hours()/24),
+				"dormant_velocity", cb.DormantVelocity, "revived_velocity", cb.RevivedVelocity)
+		}
+	}
+	return recorded, nil
+}
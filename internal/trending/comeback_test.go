@@ -0,0 +1,92 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/database"
+	"addon-radar/internal/testutil"
+)
+
+func TestEvaluateComeback(t *testing.T) {
+	released := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	candidate := func(dormantDays int, dormantChange, revivedChange int64) database.ListComebackCandidatesRow {
+		return database.ListComebackCandidatesRow{
+			AddonID:               1,
+			ReleaseDate:           pgtype.Timestamptz{Time: released, Valid: true},
+			PreviousReleaseDate:   pgtype.Timestamptz{Time: released.AddDate(0, 0, -dormantDays), Valid: true},
+			DormantDownloadChange: dormantChange,
+			DormantHours:          30 * 24,
+			RevivedDownloadChange: revivedChange,
+			RevivedHours:          48,
+		}
+	}
+
+	shortHistory := candidate(365, 72, 240)
+	shortHistory.DormantHours = 48
+
+	tests := []struct {
+		name string
+		row  database.ListComebackCandidatesRow
+		want bool
+	}{
+		{"revived after a year", candidate(365, 72, 240), true},         // 0.1/h -> 5/h
+		{"regular release", candidate(30, 72, 240), false},              // Not dormant long enough
+		{"downloads were never flat", candidate(365, 7200, 480), false}, // 10/h -> 10/h
+		{"release brought no downloads", candidate(365, 0, 24), false},  // 0.5/h is below the minimum
+		{"too little history", shortHistory, false},                     // Can't tell flat from missing
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb, ok := EvaluateComeback(tt.row)
+			assert.Equal(t, tt.want, ok)
+			if ok {
+				assert.InDelta(t, 0.1, cb.DormantVelocity, 0.001)
+				assert.InDelta(t, 5, cb.RevivedVelocity, 0.001)
+			}
+		})
+	}
+}
+
+func TestDetectComebacks(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	// Addon 1 released after a year of silence and took off; addon 2 releases regularly
+	_, err := tdb.Pool.Exec(ctx, `
+		INSERT INTO addons (id, slug, name, status, download_count, latest_file_date) VALUES
+			(1, 'revived', 'Revived', 'active', 5000, NOW() - INTERVAL '3 days'),
+			(2, 'steady', 'Steady', 'active', 5000, NOW() - INTERVAL '3 days')
+	`)
+	require.NoError(t, err)
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO snapshots (addon_id, recorded_at, download_count, latest_file_date)
+		SELECT 1, NOW() - make_interval(days => d), 4000 + CASE WHEN d > 3 THEN 30 - d ELSE (3 - d) * 300 END,
+			CASE WHEN d > 3 THEN NOW() - INTERVAL '400 days' ELSE NOW() - INTERVAL '3 days' END
+		FROM generate_series(0, 30) AS d
+		UNION ALL
+		SELECT 2, NOW() - make_interval(days => d), 4000 + (30 - d) * 30,
+			CASE WHEN d > 3 THEN NOW() - INTERVAL '20 days' ELSE NOW() - INTERVAL '3 days' END
+		FROM generate_series(0, 30) AS d
+	`)
+	require.NoError(t, err)
+
+	n, err := DetectComebacks(ctx, tdb.Queries, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	addon, err := tdb.Queries.GetAddonByID(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, addon.ComebackAt.Valid)
+
+	// A release is only recorded once
+	n, err = DetectComebacks(ctx, tdb.Queries, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
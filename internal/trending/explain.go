@@ -0,0 +1,164 @@
+package trending
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// Reasons an addon is kept off a list.
+const (
+	ExclusionNotActive         = "not_active"
+	ExclusionNotCalculated     = "not_calculated"
+	ExclusionBelowMinDownloads = "below_min_downloads"
+	ExclusionAboveMaxDownloads = "above_max_downloads"
+	ExclusionNoSignal          = "no_positive_signal"
+	ExclusionOnHotList         = "on_hot_list"
+	ExclusionNotNew            = "not_new"
+)
+
+// Breakdown is an addon's score with every input and intermediate value that
+// produced it.
+type Breakdown struct {
+	AddonID int32 `json:"addon_id"`
+
+	// Inputs
+	Downloads         int64              `json:"downloads"`
+	ThumbsUp          int32              `json:"thumbs_up"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	AuthorID          int32              `json:"author_id,omitempty"`           // Diversity group, 0 when unknown
+	PrimaryCategoryID int32              `json:"primary_category_id,omitempty"` // Diversity group, 0 when unknown
+	DownloadChange24h int64              `json:"download_change_24h"`
+	DownloadChange7d  int64              `json:"download_change_7d"`
+	SnapshotCount24h  int32              `json:"snapshot_count_24h"`
+	SpanHours24h      float64            `json:"span_hours_24h"`   // Hours the 24h snapshots cover
+	DownloadFit24h    float64            `json:"download_fit_24h"` // R² of the 24h download regression
+	MinDownloads7d    int64              `json:"min_downloads_7d"`
+	UpdatesIn90Days   int32              `json:"updates_in_90_days"`
+	Percentile95      float64            `json:"download_percentile_95"`
+
+	// Confidence-weighted velocity: regression slopes over each window, leaning
+	// on the 24h window as its confidence grows
+	Velocity24h        float64 `json:"velocity_24h"`
+	Velocity7d         float64 `json:"velocity_7d"`
+	VelocityConfidence float64 `json:"velocity_confidence"` // 0 to 1
+	DownloadVelocity   float64 `json:"download_velocity"`
+	ThumbsVelocity     float64 `json:"thumbs_velocity"`
+	EarlyVelocity      float64 `json:"early_velocity"` // Downloads per hour since creation
+
+	DownloadGrowthPct     float64 `json:"download_growth_pct"`
+	ThumbsGrowthPct       float64 `json:"thumbs_growth_pct"`
+	SizeMultiplier        float64 `json:"size_multiplier"`
+	MaintenanceMultiplier float64 `json:"maintenance_multiplier"`
+	HasRecentUpdate       bool    `json:"has_recent_update"`
+
+	// Signals
+	HotSignal      float64 `json:"hot_signal"`
+	RelativeGrowth float64 `json:"relative_growth"`
+	RisingSignal   float64 `json:"rising_signal"`
+	LovedSignal    float64 `json:"loved_signal"`
+	FreshSignal    float64 `json:"fresh_signal"`
+	HotEligible    bool    `json:"hot_eligible"`
+	RisingEligible bool    `json:"rising_eligible"`
+	LovedEligible  bool    `json:"loved_eligible"`
+	FreshEligible  bool    `json:"fresh_eligible"`
+
+	// Age and decay: score = numerator / decay
+	FirstHotAt      pgtype.Timestamptz `json:"first_hot_at"`
+	FirstRisingAt   pgtype.Timestamptz `json:"first_rising_at"`
+	FirstLovedAt    pgtype.Timestamptz `json:"first_loved_at"`
+	HotAgeHours     float64            `json:"hot_age_hours"`
+	RisingAgeHours  float64            `json:"rising_age_hours"`
+	LovedAgeHours   float64            `json:"loved_age_hours"`
+	CreatedAgeHours float64            `json:"created_age_hours"` // Fresh Releases decays from creation
+	HotDecay        float64            `json:"hot_decay"`
+	RisingDecay     float64            `json:"rising_decay"`
+	LovedDecay      float64            `json:"loved_decay"`
+	FreshDecay      float64            `json:"fresh_decay"`
+
+	HotScore    float64 `json:"hot_score"`
+	RisingScore float64 `json:"rising_score"`
+	LovedScore  float64 `json:"loved_score"`
+	FreshScore  float64 `json:"fresh_score"`
+
+	// List membership: 1-based displayed rank after the diversity caps, raw
+	// rank after hysteresis alone, 0 off the list, and the consecutive runs a
+	// member has been kept below the cut-off
+	HotRank       int16 `json:"hot_rank,omitempty"`
+	RisingRank    int16 `json:"rising_rank,omitempty"`
+	LovedRank     int16 `json:"loved_rank,omitempty"`
+	HotRawRank    int16 `json:"hot_raw_rank,omitempty"`
+	RisingRawRank int16 `json:"rising_raw_rank,omitempty"`
+	LovedRawRank  int16 `json:"loved_raw_rank,omitempty"`
+	HotMisses     int16 `json:"hot_misses,omitempty"`
+	RisingMisses  int16 `json:"rising_misses,omitempty"`
+	LovedMisses   int16 `json:"loved_misses,omitempty"`
+
+	inputsHash int64 // Set by the calculator to skip unchanged addons next run
+}
+
+// Explain scores one addon as of now with p, the same way the calculator does.
+func Explain(p Params, stat database.GetAllSnapshotStatsRow, percentile95 float64, updateCount int32, existing database.GetAllTrendingScoresRow, now time.Time) Breakdown {
+	return (&Calculator{params: p}).scoreAddon(stat, percentile95, updateCount, existing, now)
+}
+
+// FreshExclusions returns why b would be kept off the fresh list under p.
+func FreshExclusions(p Params, b Breakdown) []string {
+	reasons := []string{}
+	if b.Downloads < p.MinFreshDownloads {
+		reasons = append(reasons, ExclusionBelowMinDownloads)
+	}
+	if !b.CreatedAt.Valid || b.CreatedAgeHours > float64(p.FreshMaxAgeDays)*24 {
+		reasons = append(reasons, ExclusionNotNew)
+	}
+	if b.FreshSignal <= 0 {
+		reasons = append(reasons, ExclusionNoSignal)
+	}
+	return reasons
+}
+
+// HotExclusions returns why b would be kept off the hot list under p.
+func HotExclusions(p Params, b Breakdown) []string {
+	reasons := []string{}
+	if b.Downloads < p.MinHotDownloads {
+		reasons = append(reasons, ExclusionBelowMinDownloads)
+	}
+	if b.HotSignal <= 0 {
+		reasons = append(reasons, ExclusionNoSignal)
+	}
+	return reasons
+}
+
+// LovedExclusions returns why b would be kept off the loved list under p.
+func LovedExclusions(p Params, b Breakdown) []string {
+	reasons := []string{}
+	if b.Downloads < p.MinLovedDownloads {
+		reasons = append(reasons, ExclusionBelowMinDownloads)
+	}
+	if b.LovedSignal <= 0 {
+		reasons = append(reasons, ExclusionNoSignal)
+	}
+	return reasons
+}
+
+// RisingExclusions returns why b would be kept off the rising list under p.
+// onHotList reports whether the addon is in the top of the hot list, which
+// rising excludes.
+func RisingExclusions(p Params, b Breakdown, onHotList bool) []string {
+	reasons := []string{}
+	if b.Downloads < p.MinRisingDownloads {
+		reasons = append(reasons, ExclusionBelowMinDownloads)
+	}
+	if b.Downloads > p.MaxRisingDownloads {
+		reasons = append(reasons, ExclusionAboveMaxDownloads)
+	}
+	if b.RisingSignal <= 0 {
+		reasons = append(reasons, ExclusionNoSignal)
+	}
+	if onHotList {
+		reasons = append(reasons, ExclusionOnHotList)
+	}
+	return reasons
+}
@@ -0,0 +1,61 @@
+package trending
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+
+	"addon-radar/internal/database"
+)
+
+func TestExclusions(t *testing.T) {
+	p := DefaultParams()
+
+	assert.Empty(t, HotExclusions(p, Breakdown{Downloads: 1000, HotSignal: 5}))
+	assert.Equal(t, []string{ExclusionBelowMinDownloads, ExclusionNoSignal}, HotExclusions(p, Breakdown{Downloads: 100}))
+
+	assert.Empty(t, RisingExclusions(p, Breakdown{Downloads: 1000, RisingSignal: 0.5}, false))
+	assert.Equal(t, []string{ExclusionAboveMaxDownloads}, RisingExclusions(p, Breakdown{Downloads: 50000, RisingSignal: 0.5}, false))
+	assert.Equal(t, []string{ExclusionOnHotList}, RisingExclusions(p, Breakdown{Downloads: 1000, RisingSignal: 0.5}, true))
+
+	created := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	assert.Empty(t, FreshExclusions(p, Breakdown{Downloads: 100, CreatedAt: created, CreatedAgeHours: 48, FreshSignal: 3}))
+	assert.Equal(t, []string{ExclusionNotNew}, FreshExclusions(p, Breakdown{Downloads: 100, CreatedAt: created, CreatedAgeHours: 1000, FreshSignal: 3}))
+	assert.Equal(t, []string{ExclusionNotNew}, FreshExclusions(p, Breakdown{Downloads: 100, FreshSignal: 3}))
+}
+
+func TestExplainMatchesScores(t *testing.T) {
+	p := DefaultParams()
+	now := time.Now()
+	stat := database.GetAllSnapshotStatsRow{
+		AddonID:           1,
+		DownloadCount:     pgtype.Int8{Int64: 5000, Valid: true},
+		DownloadChange24h: 480,
+		SnapshotCount24h:  24,
+		DownloadChange7d:  1680,
+		MinDownloads7d:    3320,
+		DownloadSlope24h:  20,
+		DownloadFit24h:    1,
+		SpanHours24h:      23,
+		DownloadSlope7d:   10,
+	}
+	existing := database.GetAllTrendingScoresRow{
+		AddonID:    1,
+		FirstHotAt: pgtype.Timestamptz{Time: now.Add(-10 * time.Hour), Valid: true},
+	}
+
+	b := Explain(p, stat, 500000, 3, existing, now)
+
+	assert.InDelta(t, 1, b.VelocityConfidence, 0.001)
+	assert.InDelta(t, 20, b.Velocity24h, 0.001)
+	assert.InDelta(t, 10, b.Velocity7d, 0.001)
+	assert.InDelta(t, 18, b.DownloadVelocity, 0.001) // 0.8*20 + 0.2*10
+	assert.InDelta(t, 10, b.HotAgeHours, 0.001)
+	assert.InDelta(t, math.Pow(12, p.HotGravity), b.HotDecay, 0.001)
+	assert.InDelta(t, b.HotSignal*b.SizeMultiplier*b.MaintenanceMultiplier/b.HotDecay, b.HotScore, 0.0001)
+	assert.True(t, b.HotEligible)
+	assert.True(t, b.RisingEligible)
+}
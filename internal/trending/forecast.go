@@ -0,0 +1,192 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"addon-radar/internal/database"
+)
+
+// Forecast settings. The smoothing factors are fixed instead of fitted per
+// addon, so forecasts are deterministic and cheap enough to run for every addon.
+const (
+	ForecastHistory = 7 * 24 * time.Hour // Snapshots each fit uses
+
+	forecastSeason   = 24                 // Hours in the daily download cycle
+	forecastMinHours = 2 * forecastSeason // Hourly downloads needed to start the level, trend and season
+	forecastAlpha    = 0.2                // Level smoothing
+	forecastBeta     = 0.05               // Trend smoothing
+	forecastGamma    = 0.1                // Seasonal smoothing
+	forecastDamping  = 0.98               // Trend damping per hour, so a day's trend isn't extrapolated for a week
+	forecastZ        = 1.96               // 95% prediction interval
+)
+
+// Forecast is an addon's projected total downloads 24 hours and 7 days after
+// its last snapshot.
+type Forecast struct {
+	AddonID      int32
+	From         time.Time // Hour of the last snapshot
+	Downloads    int64     // Downloads at the last snapshot
+	HistoryHours int
+	Next24h      Projection
+	Next7d       Projection
+}
+
+// Projection is a projected download total with its 95% prediction interval.
+type Projection struct {
+	Expected int64
+	Low      int64
+	High     int64
+}
+
+// ForecastDownloads fits an additive Holt-Winters model with daily seasonality
+// to the hourly downloads implied by an addon's download counts. hours are
+// hours since the Unix epoch in ascending order, and counts the download
+// count in each; missing hours are interpolated. It reports false when the
+// counts cover too few hours to fit.
+//
+// Prediction intervals assume independent hourly errors, so they widen with
+// the square root of the horizon. Projections never fall below the current
+// download count.
+func ForecastDownloads(hours, counts []int64) (Forecast, bool) {
+	if len(hours) < 2 || len(hours) != len(counts) {
+		return Forecast{}, false
+	}
+	first, last := hours[0], hours[len(hours)-1]
+	n := int(last - first) // Hourly downloads between the first and last count
+	if n < forecastMinHours {
+		return Forecast{}, false
+	}
+
+	// Hourly downloads, spreading each gap's downloads evenly over its hours
+	downloads := make([]float64, n)
+	for i := 1; i < len(hours); i++ {
+		gap := hours[i] - hours[i-1]
+		perHour := math.Max(float64(counts[i]-counts[i-1]), 0) / float64(gap)
+		for h := hours[i-1]; h < hours[i]; h++ {
+			downloads[h-first] = perHour
+		}
+	}
+	// downloads[i] is the hour ending at first+i+1, whose place in the day
+	// picks its seasonal term
+	seasonOf := func(i int) int { return int((first + int64(i) + 1) % forecastSeason) }
+
+	// Start from the first two days: the first day's mean and seasonal
+	// offsets, and the change in mean between the days as trend
+	var day1, day2 float64
+	for i := 0; i < forecastSeason; i++ {
+		day1 += downloads[i]
+		day2 += downloads[forecastSeason+i]
+	}
+	level := day1 / forecastSeason
+	trend := (day2 - day1) / forecastSeason / forecastSeason
+	season := make([]float64, forecastSeason)
+	for i := 0; i < forecastSeason; i++ {
+		season[seasonOf(i)] = downloads[i] - level
+	}
+
+	var sumSquares float64
+	for i := forecastSeason; i < n; i++ {
+		k := seasonOf(i)
+		err := downloads[i] - (level + forecastDamping*trend + season[k])
+		sumSquares += err * err
+
+		prevLevel := level
+		level = forecastAlpha*(downloads[i]-season[k]) + (1-forecastAlpha)*(prevLevel+forecastDamping*trend)
+		trend = forecastBeta*(level-prevLevel) + (1-forecastBeta)*forecastDamping*trend
+		season[k] = forecastGamma*(downloads[i]-level) + (1-forecastGamma)*season[k]
+	}
+	sigma := math.Sqrt(sumSquares / float64(n-forecastSeason))
+
+	current := counts[len(counts)-1]
+	project := func(horizon int, total float64) Projection {
+		band := forecastZ * sigma * math.Sqrt(float64(horizon))
+		return Projection{
+			Expected: current + int64(math.Round(total)),
+			Low:      current + int64(math.Round(math.Max(total-band, 0))),
+			High:     current + int64(math.Round(total+band)),
+		}
+	}
+
+	f := Forecast{
+		From:         time.Unix(last*3600, 0).UTC(),
+		Downloads:    current,
+		HistoryHours: n,
+	}
+	var total, damping float64
+	for h := 1; h <= 7*24; h++ {
+		damping += math.Pow(forecastDamping, float64(h))
+		total += math.Max(level+damping*trend+season[seasonOf(n-1+h)], 0)
+		switch h {
+		case 24:
+			f.Next24h = project(h, total)
+		case 7 * 24:
+			f.Next7d = project(h, total)
+		}
+	}
+	return f, true
+}
+
+// UpdateForecasts forecasts every active addon with enough hourly snapshots
+// in the ForecastHistory before now, and replaces the stored forecasts with
+// them. It returns how many addons were forecast.
+func UpdateForecasts(ctx context.Context, pool *pgxpool.Pool, now time.Time) (int, error) {
+	series, err := database.New(pool).ListHourlyDownloads(ctx, pgtype.Timestamptz{Time: now.Add(-ForecastHistory), Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("list hourly downloads: %w", err)
+	}
+
+	forecasts := make([]Forecast, len(series))
+	fitted := make([]bool, len(series))
+	parallel(len(series), func(i int) {
+		forecasts[i], fitted[i] = ForecastDownloads(series[i].Hours, series[i].DownloadCounts)
+		forecasts[i].AddonID = series[i].AddonID
+	})
+
+	calculatedAt := pgtype.Timestamptz{Time: now, Valid: true}
+	rows := make([]database.InsertAddonForecastsParams, 0, len(series))
+	for i, f := range forecasts {
+		if !fitted[i] {
+			continue
+		}
+		rows = append(rows, database.InsertAddonForecastsParams{
+			AddonID:       f.AddonID,
+			ForecastFrom:  pgtype.Timestamptz{Time: f.From, Valid: true},
+			DownloadCount: f.Downloads,
+			HistoryHours:  int32(f.HistoryHours), //nolint:gosec // bounded by ForecastHistory
+			Projected24h:  f.Next24h.Expected,
+			Low24h:        f.Next24h.Low,
+			High24h:       f.Next24h.High,
+			Projected7d:   f.Next7d.Expected,
+			Low7d:         f.Next7d.Low,
+			High7d:        f.Next7d.High,
+			CalculatedAt:  calculatedAt,
+		})
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := database.New(tx)
+	if err := qtx.DeleteAddonForecasts(ctx); err != nil {
+		return 0, fmt.Errorf("clear forecasts: %w", err)
+	}
+	if _, err := qtx.InsertAddonForecasts(ctx, rows); err != nil {
+		return 0, fmt.Errorf("insert forecasts: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	slog.Info("updated download forecasts", "forecast", len(rows), "skipped", len(series)-len(rows))
+	return len(rows), nil
+}
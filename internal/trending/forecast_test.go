@@ -0,0 +1,107 @@
+package trending
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/testutil"
+)
+
+// hourlySeries returns a week of hourly download counts that grow by 100 an
+// hour on average, busier in the evening than in the morning, with a little
+// repeatable noise.
+func hourlySeries(start int64) (hours, counts []int64) {
+	count := int64(10000)
+	for h := start; h <= start+7*24; h++ {
+		if h > start {
+			count += int64(math.Round(100+50*math.Sin(2*math.Pi*float64(h%24)/24))) + h*37%11 - 5
+		}
+		hours = append(hours, h)
+		counts = append(counts, count)
+	}
+	return hours, counts
+}
+
+func TestForecastDownloads(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC).Unix() / 3600
+
+	t.Run("projects a daily cycle", func(t *testing.T) {
+		hours, counts := hourlySeries(start)
+		f, ok := ForecastDownloads(hours, counts)
+		require.True(t, ok)
+
+		assert.Equal(t, counts[len(counts)-1], f.Downloads)
+		assert.Equal(t, 7*24, f.HistoryHours)
+		assert.Equal(t, time.Date(2026, 9, 8, 0, 0, 0, 0, time.UTC), f.From)
+		assert.InDelta(t, f.Downloads+24*100, f.Next24h.Expected, 50)
+		assert.InDelta(t, f.Downloads+7*24*100, f.Next7d.Expected, 350)
+		for _, p := range []Projection{f.Next24h, f.Next7d} {
+			assert.LessOrEqual(t, p.Low, p.Expected)
+			assert.GreaterOrEqual(t, p.High, p.Expected)
+		}
+		assert.Greater(t, f.Next7d.High-f.Next7d.Low, f.Next24h.High-f.Next24h.Low, "intervals widen with the horizon")
+
+		again, _ := ForecastDownloads(hours, counts)
+		assert.Equal(t, f, again, "forecasts are deterministic")
+	})
+
+	t.Run("interpolates missing hours", func(t *testing.T) {
+		hours, counts := hourlySeries(start)
+		hours = append(hours[:50:50], hours[56:]...)
+		counts = append(counts[:50:50], counts[56:]...)
+
+		f, ok := ForecastDownloads(hours, counts)
+		require.True(t, ok)
+		assert.Equal(t, 7*24, f.HistoryHours)
+		assert.InDelta(t, f.Downloads+24*100, f.Next24h.Expected, 100)
+	})
+
+	t.Run("never projects fewer downloads", func(t *testing.T) {
+		hours, counts := hourlySeries(start)
+		for i := range counts {
+			counts[i] = 10000 - int64(i) // Corrections only
+		}
+
+		f, ok := ForecastDownloads(hours, counts)
+		require.True(t, ok)
+		assert.Equal(t, f.Downloads, f.Next7d.Expected)
+		assert.Equal(t, f.Downloads, f.Next7d.Low)
+	})
+
+	t.Run("needs two days of history", func(t *testing.T) {
+		hours, counts := hourlySeries(start)
+		_, ok := ForecastDownloads(hours[:forecastMinHours], counts[:forecastMinHours])
+		assert.False(t, ok)
+	})
+}
+
+func TestUpdateForecasts(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	// Addon 1 has three days of hourly snapshots; addon 2 only a few hours
+	seedAddonWithSnapshots(t, tdb, 1, "steady", 50000, 100, 72)
+	seedAddonWithSnapshots(t, tdb, 2, "new", 5000, 100, 5)
+
+	n, err := UpdateForecasts(ctx, tdb.Pool, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	f, err := tdb.Queries.GetAddonForecast(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50000), f.DownloadCount)
+	assert.InDelta(t, 50000+24*100, f.Projected24h, 100)
+
+	// Each update replaces the stored forecasts
+	n, err = UpdateForecasts(ctx, tdb.Pool, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	var count int
+	require.NoError(t, tdb.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM addon_forecasts`).Scan(&count))
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,134 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// heatIndexes turns the hot and rising scores of every addon into a 0-100
+// heat index: the higher of its percentiles for either score, where the
+// percentile is the share of other addons scoring lower. An addon scoring 0
+// on both has no heat.
+func heatIndexes(scores []Breakdown) map[int32]int16 {
+	percentile := func(score func(Breakdown) float64) func(float64) float64 {
+		sorted := make([]float64, len(scores))
+		for i, b := range scores {
+			sorted[i] = score(b)
+		}
+		sort.Float64s(sorted)
+		return func(s float64) float64 {
+			if s <= 0 {
+				return 0
+			}
+			if len(sorted) == 1 {
+				return 100
+			}
+			below := sort.SearchFloat64s(sorted, s)
+			return 100 * float64(below) / float64(len(sorted)-1)
+		}
+	}
+	hot := percentile(func(b Breakdown) float64 { return b.HotScore })
+	rising := percentile(func(b Breakdown) float64 { return b.RisingScore })
+
+	heat := make(map[int32]int16, len(scores))
+	for _, b := range scores {
+		heat[b.AddonID] = int16(math.Round(math.Max(hot(b.HotScore), rising(b.RisingScore))))
+	}
+	return heat
+}
+
+// heatInputs is every addon in g: the scored ones, plus the unchanged ones
+// carried forward with the zero scores they were skipped for.
+func heatInputs(g Generation) []Breakdown {
+	inputs := make([]Breakdown, 0, len(g.Scores)+len(g.Unchanged))
+	inputs = append(inputs, g.Scores...)
+	for _, id := range g.Unchanged {
+		inputs = append(inputs, Breakdown{AddonID: id})
+	}
+	return inputs
+}
+
+// recordHeat records the heat index of g's addons under runID as the heat
+// index of their UTC day, and updates each addon's heat index and sparkline.
+// The rows it replaces are kept so Rollback can restore them.
+func (s *PostgresStore) recordHeat(ctx context.Context, g Generation, runID int64, recordedAt pgtype.Timestamptz) error {
+	day := pgtype.Date{Time: PeriodStart(PeriodDaily, recordedAt.Time), Valid: true}
+	heat := heatIndexes(heatInputs(g))
+	rows := make([]database.InsertHeatIndexDailyParams, 0, len(heat))
+	for id, h := range heat {
+		rows = append(rows, database.InsertHeatIndexDailyParams{AddonID: id, Day: day, HeatIndex: h, RunID: runID})
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := s.db.WithTx(tx)
+	if err := qtx.DeletePreviousHeatIndex(ctx); err != nil {
+		return fmt.Errorf("clear previous heat index: %w", err)
+	}
+	err = qtx.KeepHeatIndexDay(ctx, database.KeepHeatIndexDayParams{ReplacedByRunID: runID, Day: day})
+	if err != nil {
+		return fmt.Errorf("keep heat index day: %w", err)
+	}
+	if err := qtx.DeleteHeatIndexDay(ctx, day); err != nil {
+		return fmt.Errorf("clear heat index day: %w", err)
+	}
+	if _, err := qtx.InsertHeatIndexDaily(ctx, rows); err != nil {
+		return fmt.Errorf("insert heat index: %w", err)
+	}
+	if err := updateAddonHeat(ctx, qtx, day); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if deleted, err := s.db.DeleteOldHeatIndex(ctx); err != nil {
+		slog.Warn("failed to cleanup heat index history", "error", err)
+	} else if deleted > 0 {
+		slog.Info("cleaned up old heat index history", "deleted", deleted)
+	}
+	return nil
+}
+
+// updateAddonHeat sets each addon's heat index and sparkline from the heat
+// index recorded for day, clearing them for addons without one.
+func updateAddonHeat(ctx context.Context, qtx *database.Queries, day pgtype.Date) error {
+	if _, err := qtx.UpdateAddonHeat(ctx, day); err != nil {
+		return fmt.Errorf("update addon heat: %w", err)
+	}
+	if _, err := qtx.ClearStaleAddonHeat(ctx, day); err != nil {
+		return fmt.Errorf("clear stale addon heat: %w", err)
+	}
+	return nil
+}
+
+// rollbackHeat discards the heat index recorded by runID, restores the rows
+// it replaced, and sets each addon's heat index from the latest day left.
+func rollbackHeat(ctx context.Context, qtx *database.Queries, runID int64) error {
+	if _, err := qtx.DeleteHeatIndexOfRun(ctx, runID); err != nil {
+		return fmt.Errorf("delete heat index: %w", err)
+	}
+	if _, err := qtx.RestorePreviousHeatIndex(ctx, runID); err != nil {
+		return fmt.Errorf("restore previous heat index: %w", err)
+	}
+	if err := qtx.DeletePreviousHeatIndex(ctx); err != nil {
+		return fmt.Errorf("clear previous heat index: %w", err)
+	}
+	day, err := qtx.GetLatestHeatIndexDay(ctx)
+	if err != nil {
+		return fmt.Errorf("get latest heat index day: %w", err)
+	}
+	// With no heat index left, day is NULL and every addon's heat is cleared
+	return updateAddonHeat(ctx, qtx, day)
+}
@@ -0,0 +1,92 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/testutil"
+)
+
+func TestHeatIndexes(t *testing.T) {
+	heat := heatIndexes([]Breakdown{
+		{AddonID: 1, HotScore: 10},
+		{AddonID: 2, HotScore: 5},
+		{AddonID: 3, RisingScore: 3},
+		{AddonID: 4},
+		{AddonID: 5, HotScore: 5, RisingScore: 1},
+	})
+
+	assert.Equal(t, map[int32]int16{
+		1: 100, // Highest hot score
+		2: 50,  // Ties share the lower percentile
+		3: 100, // Highest rising score, though not hot at all
+		4: 0,
+		5: 75, // Its rising percentile beats its hot one
+	}, heat)
+
+	assert.Equal(t, map[int32]int16{1: 100}, heatIndexes([]Breakdown{{AddonID: 1, HotScore: 1}}))
+	assert.Empty(t, heatIndexes(nil))
+}
+
+func TestHeatInputs(t *testing.T) {
+	g := Generation{
+		Scores:    []Breakdown{{AddonID: 1, HotScore: 10}, {AddonID: 2}},
+		Unchanged: []int32{3},
+	}
+
+	// Unchanged addons scored zero, so they count towards the percentiles
+	// and get a heat index of 0
+	assert.Equal(t, map[int32]int16{1: 100, 2: 0, 3: 0}, heatIndexes(heatInputs(g)))
+}
+
+func TestRecordHeat(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	seedAddonWithSnapshots(t, tdb, 1, "heat-big", 500000, 100, 10)
+	seedAddonWithSnapshots(t, tdb, 2, "heat-small", 5000, 100, 10)
+	seedAddonWithSnapshots(t, tdb, 3, "heat-idle", 5, 0, 1) // Below every list's download gate
+	calc := NewCalculator(NewPostgresStore(tdb.Pool))
+
+	// Recalculating within a day replaces the day's heat index, including for
+	// the idle addon, which is skipped as unchanged the second time
+	require.NoError(t, calc.CalculateAll(ctx))
+	require.NoError(t, calc.CalculateAll(ctx))
+	for _, id := range []int32{1, 2, 3} {
+		addon, err := tdb.Queries.GetAddonByID(ctx, id)
+		require.NoError(t, err)
+		require.True(t, addon.HeatIndex.Valid)
+		assert.Equal(t, []int16{0, 0, 0, 0, 0, 0, addon.HeatIndex.Int16}, addon.HeatSparkline, "days before the first calculation count as 0")
+	}
+	idle, err := tdb.Queries.GetAddonByID(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int16(0), idle.HeatIndex.Int16)
+
+	// Gaps keep their place in the sparkline, and days past the window are dropped
+	today := PeriodStart(PeriodDaily, time.Now())
+	_, err = tdb.Pool.Exec(ctx, `
+		INSERT INTO heat_index_daily (addon_id, day, heat_index, run_id)
+		SELECT 1, d, 40, run_id FROM trending_generations, unnest($1::date[]) AS d WHERE slot = 'live'
+	`, []time.Time{today.AddDate(0, 0, -3), today.AddDate(0, 0, -9)})
+	require.NoError(t, err)
+	require.NoError(t, calc.CalculateAll(ctx))
+	big, err := tdb.Queries.GetAddonByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int16{0, 0, 0, 40, 0, 0, big.HeatIndex.Int16}, big.HeatSparkline)
+	var old int
+	require.NoError(t, tdb.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM heat_index_daily WHERE day < $1`, today.AddDate(0, 0, -7)).Scan(&old))
+	assert.Zero(t, old)
+
+	// Addons no longer scored lose their heat index
+	_, err = tdb.Pool.Exec(ctx, `UPDATE addons SET status = 'inactive' WHERE id = 2`)
+	require.NoError(t, err)
+	require.NoError(t, calc.CalculateAll(ctx))
+	addon, err := tdb.Queries.GetAddonByID(ctx, 2)
+	require.NoError(t, err)
+	assert.False(t, addon.HeatIndex.Valid)
+	assert.Nil(t, addon.HeatSparkline)
+}
@@ -0,0 +1,121 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// Leaderboard archive periods.
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+// PeriodStart returns the first day of the daily or weekly period containing
+// t, in UTC. Weeks are ISO weeks starting on Monday.
+func PeriodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if period == PeriodWeekly {
+		// Sunday is the last day of an ISO week
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+// ParseISOWeek parses an ISO week such as "2026-W35" and returns its Monday.
+func ParseISOWeek(s string) (time.Time, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(s, "%4d-W%2d", &year, &week); err != nil || len(s) != len("2026-W35") {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q: want YYYY-Www", s)
+	}
+	// January 4th is always in week 1
+	monday := PeriodStart(PeriodWeekly, time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)).AddDate(0, 0, 7*(week-1))
+	if y, w := monday.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q: %d has no week %d", s, year, week)
+	}
+	return monday, nil
+}
+
+// FormatISOWeek formats the ISO week containing t, such as "2026-W35".
+func FormatISOWeek(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// archiveLeaderboards archives g's hot and rising lists under runID as the
+// lists of its day and week, replacing what an earlier calculation in the same
+// period archived. The lists it replaces are kept so Rollback can restore them.
+func (s *PostgresStore) archiveLeaderboards(ctx context.Context, g Generation, runID int64, scores map[int32]Breakdown, recordedAt pgtype.Timestamptz) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := s.db.WithTx(tx)
+	if err := qtx.DeletePreviousLeaderboards(ctx); err != nil {
+		return fmt.Errorf("clear previous leaderboards: %w", err)
+	}
+	lists := []struct {
+		name string
+		ids  []int32
+	}{{"hot", g.Hot}, {"rising", g.Rising}}
+	for _, period := range []string{PeriodDaily, PeriodWeekly} {
+		start := pgtype.Date{Time: PeriodStart(period, recordedAt.Time), Valid: true}
+		for _, list := range lists {
+			err := qtx.KeepArchivedLeaderboard(ctx, database.KeepArchivedLeaderboardParams{
+				ReplacedByRunID: runID,
+				Period:          period,
+				PeriodStart:     start,
+				List:            list.name,
+			})
+			if err != nil {
+				return fmt.Errorf("keep %s %s leaderboard: %w", period, list.name, err)
+			}
+			key := database.DeleteArchivedLeaderboardParams{Period: period, PeriodStart: start, List: list.name}
+			if err := qtx.DeleteArchivedLeaderboard(ctx, key); err != nil {
+				return fmt.Errorf("clear %s %s leaderboard: %w", period, list.name, err)
+			}
+			for i, id := range list.ids {
+				err := qtx.InsertArchivedLeaderboardEntry(ctx, database.InsertArchivedLeaderboardEntryParams{
+					Period:      period,
+					PeriodStart: start,
+					List:        list.name,
+					Rank:        int16(i + 1), //nolint:gosec // i is bounded by the list size
+					AddonID:     id,
+					Score:       toNumeric(listScore(list.name, scores[id])),
+					RecordedAt:  recordedAt,
+					RunID:       runID,
+				})
+				if err != nil {
+					return fmt.Errorf("archive %s %s leaderboard: %w", period, list.name, err)
+				}
+			}
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// rollbackLeaderboards discards the lists archived by runID and restores the
+// ones it replaced.
+func rollbackLeaderboards(ctx context.Context, qtx *database.Queries, runID int64) error {
+	if _, err := qtx.DeleteArchivedLeaderboardsOfRun(ctx, runID); err != nil {
+		return fmt.Errorf("delete archived leaderboards: %w", err)
+	}
+	if _, err := qtx.RestorePreviousLeaderboards(ctx, runID); err != nil {
+		return fmt.Errorf("restore previous leaderboards: %w", err)
+	}
+	if err := qtx.DeletePreviousLeaderboards(ctx); err != nil {
+		return fmt.Errorf("clear previous leaderboards: %w", err)
+	}
+	return nil
+}
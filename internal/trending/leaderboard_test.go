@@ -0,0 +1,84 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/testutil"
+)
+
+func TestPeriodStart(t *testing.T) {
+	// Sunday evening in New York is Monday in UTC
+	at := time.Date(2026, 8, 30, 22, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+
+	assert.Equal(t, time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), PeriodStart(PeriodDaily, at))
+	assert.Equal(t, time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), PeriodStart(PeriodWeekly, at))
+	assert.Equal(t, time.Date(2026, 8, 24, 0, 0, 0, 0, time.UTC),
+		PeriodStart(PeriodWeekly, time.Date(2026, 8, 30, 12, 0, 0, 0, time.UTC)), "Sunday ends the week")
+}
+
+func TestParseISOWeek(t *testing.T) {
+	tests := []struct {
+		week    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2026-W35", time.Date(2026, 8, 24, 0, 0, 0, 0, time.UTC), false},
+		{"2026-W01", time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), false}, // Week 1 starts in the previous year
+		{"2026-W53", time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC), false},
+		{"2025-W53", time.Time{}, true}, // 2025 has 52 weeks
+		{"2026-W00", time.Time{}, true},
+		{"2026-35", time.Time{}, true},
+		{"2026-W5", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.week, func(t *testing.T) {
+			got, err := ParseISOWeek(tt.week)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.week, FormatISOWeek(got))
+		})
+	}
+}
+
+func TestArchiveLeaderboards(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	seedAddonWithSnapshots(t, tdb, 1, "archive-test", 5000, 100, 10)
+	calc := NewCalculator(NewPostgresStore(tdb.Pool))
+
+	count := func(query string, args ...any) int {
+		var n int
+		require.NoError(t, tdb.Pool.QueryRow(ctx, query, args...).Scan(&n))
+		return n
+	}
+
+	// A second calculation in the same day and week replaces the archived lists
+	require.NoError(t, calc.CalculateAll(ctx))
+	require.NoError(t, calc.CalculateAll(ctx))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM leaderboard_archive WHERE period = 'daily' AND list = 'hot'`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM leaderboard_archive WHERE period = 'weekly' AND list = 'hot'`))
+
+	// The replaced lists are kept under the run that replaced them
+	live, err := tdb.Queries.GetTrendingGeneration(ctx, GenerationLive)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM leaderboard_archive WHERE run_id <> $1`, live.RunID))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM leaderboard_archive_previous WHERE period = 'daily' AND list = 'hot' AND replaced_by_run_id = $1`, live.RunID))
+
+	// The archive outlives rank history
+	_, err = tdb.Pool.Exec(ctx, `UPDATE trending_rank_history SET recorded_at = recorded_at - INTERVAL '30 days'`)
+	require.NoError(t, err)
+	_, err = tdb.Queries.DeleteOldRankHistory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM leaderboard_archive WHERE period = 'daily' AND list = 'hot'`))
+}
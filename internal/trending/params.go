@@ -0,0 +1,347 @@
+package trending
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"addon-radar/internal/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultParamsVersion identifies the built-in parameter set.
+const DefaultParamsVersion = "v3-default"
+
+// Where the parameters for a calculation came from.
+const (
+	ParamsSourceFile    = "file"
+	ParamsSourceTable   = "table"
+	ParamsSourceDefault = "default"
+)
+
+// Params is a versioned set of trending algorithm parameters.
+// A version must never be reused for different values; change parameters by
+// publishing a new version.
+type Params struct {
+	Version string `json:"version"`
+
+	// Hot Right Now signal weights (total = 1.0)
+	HotDownloadWeight float64 `json:"hot_download_weight"`
+	HotUpdateWeight   float64 `json:"hot_update_weight"`
+	UpdateBoost       float64 `json:"update_boost"` // Boost value when addon has recent update
+
+	// Rising Stars signal weights (total = 1.0)
+	RisingGrowthWeight      float64 `json:"rising_growth_weight"`
+	RisingMaintenanceWeight float64 `json:"rising_maintenance_weight"`
+
+	// Most Loved signal: thumbs-up per 1000 downloads, with the download
+	// velocity padded by a prior so a few thumbs on a tiny addon don't dominate
+	LovedDownloadPrior float64 `json:"loved_download_prior"`
+
+	// Fresh Releases signal: early traction of addons created in the last
+	// fresh_max_age_days, decayed by time since creation instead of time listed
+	FreshVelocityWeight float64 `json:"fresh_velocity_weight"` // Per download per hour since creation
+	FreshThumbsWeight   float64 `json:"fresh_thumbs_weight"`   // Per thumbs-up
+	FreshReleaseWeight  float64 `json:"fresh_release_weight"`  // Per file released
+	FreshMaxAgeDays     int32   `json:"fresh_max_age_days"`
+
+	HotGravity    float64 `json:"hot_gravity"`
+	RisingGravity float64 `json:"rising_gravity"`
+	LovedGravity  float64 `json:"loved_gravity"`
+	FreshGravity  float64 `json:"fresh_gravity"`
+	AgeOffset     float64 `json:"age_offset"` // Prevents division by zero and smooths early decay
+
+	// Download gates for each list
+	MinHotDownloads    int64 `json:"min_hot_downloads"`
+	MinRisingDownloads int64 `json:"min_rising_downloads"`
+	MaxRisingDownloads int64 `json:"max_rising_downloads"`
+	MinLovedDownloads  int64 `json:"min_loved_downloads"`
+	MinFreshDownloads  int64 `json:"min_fresh_downloads"`
+
+	// Number of addons on each list, used for rank history and age resets
+	ListSize int32 `json:"list_size"`
+
+	// Hysteresis keeps a list member that slips just below the cut-off for a
+	// few runs instead of dropping it at once. 0 runs disables it.
+	HysteresisMargin float64 `json:"hysteresis_margin"` // Fraction of the cut-off score a member may fall below
+	HysteresisRuns   int32   `json:"hysteresis_runs"`   // Consecutive runs a member may stay below the cut-off
+
+	// Diversity caps on the hot, rising and loved lists: entries per author and
+	// per primary category, with the next-best addons promoted in their place.
+	// 0 leaves that group uncapped.
+	DiversityMaxPerAuthor   int32 `json:"diversity_max_per_author"`
+	DiversityMaxPerCategory int32 `json:"diversity_max_per_category"`
+}
+
+// DefaultParams returns the built-in parameter set.
+func DefaultParams() Params {
+	return Params{
+		Version:                 DefaultParamsVersion,
+		HotDownloadWeight:       0.85,
+		HotUpdateWeight:         0.15,
+		UpdateBoost:             10.0,
+		RisingGrowthWeight:      0.70,
+		RisingMaintenanceWeight: 0.30,
+		LovedDownloadPrior:      5.0,
+		FreshVelocityWeight:     0.70,
+		FreshThumbsWeight:       0.20,
+		FreshReleaseWeight:      0.10,
+		FreshMaxAgeDays:         30,
+		HotGravity:              1.5,
+		RisingGravity:           1.8,
+		LovedGravity:            1.5,
+		FreshGravity:            0.8,
+		AgeOffset:               2.0,
+		MinHotDownloads:         500,
+		MinRisingDownloads:      50,
+		MaxRisingDownloads:      10000,
+		MinLovedDownloads:       1000,
+		MinFreshDownloads:       10,
+		ListSize:                20,
+		HysteresisMargin:        0.15,
+		HysteresisRuns:          3,
+		DiversityMaxPerAuthor:   3,
+		DiversityMaxPerCategory: 5,
+	}
+}
+
+// Validate rejects parameter sets that would produce meaningless scores.
+func (p Params) Validate() error {
+	switch {
+	case p.Version == "":
+		return errors.New("version is required")
+	case p.HotDownloadWeight < 0 || p.HotUpdateWeight < 0 || p.UpdateBoost < 0:
+		return errors.New("hot weights must not be negative")
+	case p.RisingGrowthWeight < 0 || p.RisingMaintenanceWeight < 0:
+		return errors.New("rising weights must not be negative")
+	case p.LovedDownloadPrior <= 0:
+		return errors.New("loved download prior must be positive")
+	case p.FreshVelocityWeight < 0 || p.FreshThumbsWeight < 0 || p.FreshReleaseWeight < 0:
+		return errors.New("fresh weights must not be negative")
+	case p.FreshMaxAgeDays <= 0 || p.FreshMaxAgeDays > 90:
+		// Releases are counted from the 90-day file update window
+		return errors.New("fresh max age must be between 1 and 90 days")
+	case p.HotGravity <= 0 || p.RisingGravity <= 0 || p.LovedGravity <= 0 || p.FreshGravity <= 0:
+		return errors.New("gravity must be positive")
+	case p.AgeOffset <= 0:
+		return errors.New("age offset must be positive")
+	case p.MinHotDownloads < 0 || p.MinRisingDownloads < 0 || p.MinLovedDownloads < 0 || p.MinFreshDownloads < 0:
+		return errors.New("download gates must not be negative")
+	case p.MaxRisingDownloads < p.MinRisingDownloads:
+		return errors.New("max rising downloads must not be below min rising downloads")
+	case p.ListSize <= 0:
+		return errors.New("list size must be positive")
+	case p.HysteresisMargin < 0 || p.HysteresisMargin >= 1:
+		return errors.New("hysteresis margin must be between 0 and 1")
+	case p.HysteresisRuns < 0:
+		return errors.New("hysteresis runs must not be negative")
+	case p.DiversityMaxPerAuthor < 0 || p.DiversityMaxPerCategory < 0:
+		return errors.New("diversity caps must not be negative")
+	}
+	return nil
+}
+
+// ParseParams decodes a JSON parameter set. Omitted fields keep their
+// default values; unknown fields are rejected to catch typos.
+func ParseParams(data []byte) (Params, error) {
+	p := DefaultParams()
+	p.Version = ""
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return Params{}, fmt.Errorf("decode trending params: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return Params{}, fmt.Errorf("invalid trending params %q: %w", p.Version, err)
+	}
+	return p, nil
+}
+
+// LoadParamsFile reads a JSON parameter set from disk.
+func LoadParamsFile(path string) (Params, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Params{}, fmt.Errorf("read trending params: %w", err)
+	}
+	return ParseParams(data)
+}
+
+// ResolveParams picks the parameters for a calculation: the file at path if
+// set, otherwise the active set in trending_param_sets, otherwise the defaults.
+// It returns the parameters and where they came from.
+func ResolveParams(ctx context.Context, db *database.Queries, path string) (Params, string, error) {
+	if path != "" {
+		p, err := LoadParamsFile(path)
+		if err != nil {
+			return Params{}, "", err
+		}
+		return p, ParamsSourceFile, nil
+	}
+
+	row, err := db.GetActiveTrendingParamSet(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DefaultParams(), ParamsSourceDefault, nil
+	}
+	if err != nil {
+		return Params{}, "", fmt.Errorf("get active trending params: %w", err)
+	}
+	p, err := ParseParams(row.Params)
+	if err != nil {
+		return Params{}, "", err
+	}
+	return p, ParamsSourceTable, nil
+}
+
+// CurrentParams returns the parameters of the live trending generation, which
+// are the ones the stored scores and list gates match. It falls back to the
+// defaults before the first generation is published.
+func CurrentParams(ctx context.Context, db *database.Queries) (Params, error) {
+	row, err := db.GetCurrentTrendingParamSet(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DefaultParams(), nil
+	}
+	if err != nil {
+		return Params{}, fmt.Errorf("get current trending params: %w", err)
+	}
+	return ParseParams(row.Params)
+}
+
+// RegisterParams records a parameter set so scores can reference its version.
+// It fails if the version was already recorded with different values.
+func RegisterParams(ctx context.Context, db *database.Queries, p Params) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encode trending params: %w", err)
+	}
+	if err := db.RegisterTrendingParamSet(ctx, database.RegisterTrendingParamSetParams{
+		Version: p.Version,
+		Params:  data,
+	}); err != nil {
+		return fmt.Errorf("register trending params: %w", err)
+	}
+
+	stored, err := db.GetTrendingParamSet(ctx, p.Version)
+	if err != nil {
+		return fmt.Errorf("get trending params %q: %w", p.Version, err)
+	}
+	existing, err := ParseParams(stored.Params)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(existing, p) {
+		return fmt.Errorf("trending params version %q already recorded with different values; use a new version", p.Version)
+	}
+	return nil
+}
+
+// ActivateParams records p and makes it the set used when no parameter file
+// is configured. Running calculations pick it up on their next run.
+func ActivateParams(ctx context.Context, pool *pgxpool.Pool, p Params) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := database.New(tx)
+	if err := RegisterParams(ctx, qtx, p); err != nil {
+		return err
+	}
+	if err := qtx.DeactivateTrendingParamSets(ctx); err != nil {
+		return fmt.Errorf("deactivate trending params: %w", err)
+	}
+	if _, err := qtx.ActivateTrendingParamSet(ctx, p.Version); err != nil {
+		return fmt.Errorf("activate trending params: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// ShadowParams returns the shadow parameter set, which every calculation
+// scores alongside the live one for comparison. It reports false when no
+// shadow set is configured.
+func ShadowParams(ctx context.Context, db *database.Queries) (Params, bool, error) {
+	row, err := db.GetShadowTrendingParamSet(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Params{}, false, nil
+	}
+	if err != nil {
+		return Params{}, false, fmt.Errorf("get shadow trending params: %w", err)
+	}
+	p, err := ParseParams(row.Params)
+	if err != nil {
+		return Params{}, false, err
+	}
+	return p, true, nil
+}
+
+// SetShadowParams records p and makes it the shadow parameter set. Running
+// calculations pick it up on their next run.
+func SetShadowParams(ctx context.Context, pool *pgxpool.Pool, p Params) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := database.New(tx)
+	if err := RegisterParams(ctx, qtx, p); err != nil {
+		return err
+	}
+	if err := qtx.ClearShadowTrendingParamSets(ctx); err != nil {
+		return fmt.Errorf("clear shadow trending params: %w", err)
+	}
+	if _, err := qtx.MarkShadowTrendingParamSet(ctx, p.Version); err != nil {
+		return fmt.Errorf("mark shadow trending params: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// ClearShadowParams stops shadow scoring from the next calculation on.
+func ClearShadowParams(ctx context.Context, db *database.Queries) error {
+	if err := db.ClearShadowTrendingParamSets(ctx); err != nil {
+		return fmt.Errorf("clear shadow trending params: %w", err)
+	}
+	return nil
+}
+
+// HotListParams builds the hot list query for p's download gate.
+func HotListParams(p Params, limit int32) database.ListHotAddonsParams {
+	return database.ListHotAddonsParams{
+		MinDownloads: p.MinHotDownloads,
+		LimitCount:   limit,
+	}
+}
+
+// LovedListParams builds the loved list query for p's download gate.
+func LovedListParams(p Params, limit int32) database.ListLovedAddonsParams {
+	return database.ListLovedAddonsParams{
+		MinDownloads: p.MinLovedDownloads,
+		LimitCount:   limit,
+	}
+}
+
+// FreshListParams builds the fresh list query for p's download gate.
+func FreshListParams(p Params, limit int32) database.ListFreshAddonsParams {
+	return database.ListFreshAddonsParams{
+		MinDownloads: p.MinFreshDownloads,
+		LimitCount:   limit,
+	}
+}
+
+// RisingListParams builds the rising list query for p's download gates.
+// Addons on the hot list are excluded.
+func RisingListParams(p Params, limit int32) database.ListRisingAddonsParams {
+	return database.ListRisingAddonsParams{
+		MinDownloads: p.MinRisingDownloads,
+		MaxDownloads: p.MaxRisingDownloads,
+		HotListSize:  p.ListSize,
+		LimitCount:   limit,
+	}
+}
@@ -0,0 +1,134 @@
+package trending
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultParamsValid(t *testing.T) {
+	require.NoError(t, DefaultParams().Validate())
+}
+
+// A version must never name two parameter sets. If this fails after a change
+// to DefaultParams, bump DefaultParamsVersion and update the pinned values.
+func TestDefaultParamsVersion(t *testing.T) {
+	pinned := map[string]string{
+		"v3-default": `{
+			"version": "v3-default",
+			"hot_download_weight": 0.85,
+			"hot_update_weight": 0.15,
+			"update_boost": 10,
+			"rising_growth_weight": 0.7,
+			"rising_maintenance_weight": 0.3,
+			"loved_download_prior": 5,
+			"fresh_velocity_weight": 0.7,
+			"fresh_thumbs_weight": 0.2,
+			"fresh_release_weight": 0.1,
+			"fresh_max_age_days": 30,
+			"hot_gravity": 1.5,
+			"rising_gravity": 1.8,
+			"loved_gravity": 1.5,
+			"fresh_gravity": 0.8,
+			"age_offset": 2,
+			"min_hot_downloads": 500,
+			"min_rising_downloads": 50,
+			"max_rising_downloads": 10000,
+			"min_loved_downloads": 1000,
+			"min_fresh_downloads": 10,
+			"list_size": 20,
+			"hysteresis_margin": 0.15,
+			"hysteresis_runs": 3,
+			"diversity_max_per_author": 3,
+			"diversity_max_per_category": 5
+		}`,
+	}
+
+	data, ok := pinned[DefaultParamsVersion]
+	require.True(t, ok, "no pinned values for %s", DefaultParamsVersion)
+	// Decoded into a zero value so a new field must be pinned too
+	var want Params
+	require.NoError(t, json.Unmarshal([]byte(data), &want))
+	assert.Equal(t, want, DefaultParams())
+}
+
+func TestParseParams(t *testing.T) {
+	t.Run("omitted fields keep defaults", func(t *testing.T) {
+		p, err := ParseParams([]byte(`{"version": "tuned", "hot_gravity": 1.2, "list_size": 25}`))
+		require.NoError(t, err)
+
+		want := DefaultParams()
+		want.Version = "tuned"
+		want.HotGravity = 1.2
+		want.ListSize = 25
+		assert.Equal(t, want, p)
+	})
+
+	t.Run("requires a version", func(t *testing.T) {
+		_, err := ParseParams([]byte(`{"hot_gravity": 1.2}`))
+		assert.ErrorContains(t, err, "version is required")
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		_, err := ParseParams([]byte(`{"version": "tuned", "hot_gravty": 1.2}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		tests := map[string]string{
+			"zero gravity":      `{"version": "x", "rising_gravity": 0}`,
+			"inverted gates":    `{"version": "x", "min_rising_downloads": 500, "max_rising_downloads": 100}`,
+			"empty list":        `{"version": "x", "list_size": 0}`,
+			"negative weight":   `{"version": "x", "hot_download_weight": -1}`,
+			"zero age offset":   `{"version": "x", "age_offset": 0}`,
+			"negative hot gate": `{"version": "x", "min_hot_downloads": -1}`,
+			"zero loved prior":  `{"version": "x", "loved_download_prior": 0}`,
+			"fresh too old":     `{"version": "x", "fresh_max_age_days": 120}`,
+		}
+		for name, input := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := ParseParams([]byte(input))
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestLoadParamsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "params.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": "file-set", "min_hot_downloads": 1000}`), 0o600))
+
+	p, err := LoadParamsFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "file-set", p.Version)
+	assert.Equal(t, int64(1000), p.MinHotDownloads)
+
+	_, err = LoadParamsFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestListParams(t *testing.T) {
+	p := DefaultParams()
+
+	hot := HotListParams(p, 100)
+	assert.Equal(t, int64(500), hot.MinDownloads)
+	assert.Equal(t, int32(100), hot.LimitCount)
+
+	rising := RisingListParams(p, 100)
+	assert.Equal(t, int64(50), rising.MinDownloads)
+	assert.Equal(t, int64(10000), rising.MaxDownloads)
+	assert.Equal(t, int32(20), rising.HotListSize)
+	assert.Equal(t, int32(100), rising.LimitCount)
+
+	loved := LovedListParams(p, 100)
+	assert.Equal(t, int64(1000), loved.MinDownloads)
+	assert.Equal(t, int32(100), loved.LimitCount)
+
+	fresh := FreshListParams(p, 100)
+	assert.Equal(t, int64(10), fresh.MinDownloads)
+	assert.Equal(t, int32(100), fresh.LimitCount)
+}
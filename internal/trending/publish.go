@@ -0,0 +1,176 @@
+package trending
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"addon-radar/internal/database"
+)
+
+// Slots in trending_generations.
+const (
+	GenerationLive     = "live"
+	GenerationPrevious = "previous"
+)
+
+// ErrNoPreviousGeneration is returned by Rollback when no earlier generation is kept.
+var ErrNoPreviousGeneration = errors.New("no previous trending generation to roll back to")
+
+type step struct {
+	name string
+	run  func(context.Context) error
+}
+
+func runSteps(ctx context.Context, steps []step) error {
+	for _, s := range steps {
+		if err := s.run(ctx); err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+// publish records run and makes the staged scores live in one transaction.
+// The generation it replaces is kept for Rollback.
+func (s *PostgresStore) publish(ctx context.Context, run database.InsertTrendingCalculationRunParams) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := s.db.WithTx(tx)
+	runID, err := qtx.InsertTrendingCalculationRun(ctx, run)
+	if err != nil {
+		return fmt.Errorf("record trending calculation run: %w", err)
+	}
+	live, err := qtx.GetTrendingGeneration(ctx, GenerationLive)
+	hasLive := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("get live generation: %w", err)
+	}
+
+	err = runSteps(ctx, []step{
+		{"clear previous scores", qtx.DeletePreviousTrendingScores},
+		{"keep live scores", qtx.CopyTrendingScoresToPrevious},
+		{"clear live scores", qtx.DeleteTrendingScores},
+		{"publish staged scores", qtx.PublishStagedTrendingScores},
+		{"clear staged scores", qtx.DeleteStagedTrendingScores},
+		{"clear previous category scores", qtx.DeletePreviousCategoryTrendingScores},
+		{"keep live category scores", qtx.CopyCategoryTrendingScoresToPrevious},
+		{"clear live category scores", qtx.DeleteCategoryTrendingScores},
+		{"publish staged category scores", qtx.PublishStagedCategoryTrendingScores},
+		{"clear staged category scores", qtx.DeleteStagedCategoryTrendingScores},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Scores published before generations were tracked have no run to roll back to
+	if hasLive {
+		err = qtx.SetTrendingGeneration(ctx, database.SetTrendingGenerationParams{
+			Slot:        GenerationPrevious,
+			RunID:       live.RunID,
+			PublishedAt: live.PublishedAt,
+		})
+	} else {
+		err = qtx.DeleteTrendingGeneration(ctx, GenerationPrevious)
+	}
+	if err != nil {
+		return fmt.Errorf("record previous generation: %w", err)
+	}
+	err = qtx.SetTrendingGeneration(ctx, database.SetTrendingGenerationParams{
+		Slot:        GenerationLive,
+		RunID:       runID,
+		PublishedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("record live generation: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit trending generation: %w", err)
+	}
+	slog.Info("published trending generation", "run_id", runID, "replaced_run_id", live.RunID)
+	return nil
+}
+
+// Rollback makes the previous generation live again and discards the current
+// one, including the rank history, archived leaderboards, stints and heat
+// index recorded from it. It returns the restored generation. Only one
+// generation is kept, so a second rollback fails with ErrNoPreviousGeneration
+// until the next calculation is published.
+func Rollback(ctx context.Context, pool *pgxpool.Pool) (database.TrendingGeneration, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return database.TrendingGeneration{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := database.New(tx)
+	previous, err := qtx.GetTrendingGeneration(ctx, GenerationPrevious)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return database.TrendingGeneration{}, ErrNoPreviousGeneration
+	}
+	if err != nil {
+		return database.TrendingGeneration{}, fmt.Errorf("get previous generation: %w", err)
+	}
+	live, err := qtx.GetTrendingGeneration(ctx, GenerationLive)
+	if err != nil {
+		return database.TrendingGeneration{}, fmt.Errorf("get live generation: %w", err)
+	}
+
+	err = runSteps(ctx, []step{
+		{"clear live scores", qtx.DeleteTrendingScores},
+		{"restore previous scores", qtx.RestorePreviousTrendingScores},
+		{"clear previous scores", qtx.DeletePreviousTrendingScores},
+		{"clear live category scores", qtx.DeleteCategoryTrendingScores},
+		{"restore previous category scores", qtx.RestorePreviousCategoryTrendingScores},
+		{"clear previous category scores", qtx.DeletePreviousCategoryTrendingScores},
+	})
+	if err != nil {
+		return database.TrendingGeneration{}, err
+	}
+
+	// Rank history of the discarded generation is recorded after it was published
+	if _, err := qtx.DeleteRankHistorySince(ctx, live.PublishedAt); err != nil {
+		return database.TrendingGeneration{}, fmt.Errorf("delete rank history: %w", err)
+	}
+	if _, err := qtx.DeleteCategoryRankHistorySince(ctx, live.PublishedAt); err != nil {
+		return database.TrendingGeneration{}, fmt.Errorf("delete category rank history: %w", err)
+	}
+	if err := rollbackLeaderboards(ctx, qtx, live.RunID); err != nil {
+		return database.TrendingGeneration{}, err
+	}
+	if err := rollbackStints(ctx, qtx, live.RunID, live.PublishedAt); err != nil {
+		return database.TrendingGeneration{}, err
+	}
+	if err := rollbackHeat(ctx, qtx, live.RunID); err != nil {
+		return database.TrendingGeneration{}, err
+	}
+
+	err = qtx.SetTrendingGeneration(ctx, database.SetTrendingGenerationParams{
+		Slot:        GenerationLive,
+		RunID:       previous.RunID,
+		PublishedAt: previous.PublishedAt,
+	})
+	if err != nil {
+		return database.TrendingGeneration{}, fmt.Errorf("record live generation: %w", err)
+	}
+	if err := qtx.DeleteTrendingGeneration(ctx, GenerationPrevious); err != nil {
+		return database.TrendingGeneration{}, fmt.Errorf("clear previous generation: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return database.TrendingGeneration{}, fmt.Errorf("commit rollback: %w", err)
+	}
+	slog.Info("rolled back trending generation", "discarded_run_id", live.RunID, "restored_run_id", previous.RunID)
+	return database.TrendingGeneration{Slot: GenerationLive, RunID: previous.RunID, PublishedAt: previous.PublishedAt}, nil
+}
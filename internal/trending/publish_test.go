@@ -0,0 +1,145 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/database"
+	"addon-radar/internal/testutil"
+)
+
+func TestPublishAndRollback(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	seedAddonWithSnapshots(t, tdb, 1, "publish-test", 5000, 100, 10)
+	calc := NewCalculator(NewPostgresStore(tdb.Pool))
+
+	hotScore := func() float64 {
+		var score float64
+		err := tdb.Pool.QueryRow(ctx, `SELECT hot_score FROM trending_scores WHERE addon_id = 1`).Scan(&score)
+		require.NoError(t, err)
+		return score
+	}
+	count := func(query string) int {
+		var n int
+		require.NoError(t, tdb.Pool.QueryRow(ctx, query).Scan(&n))
+		return n
+	}
+
+	require.NoError(t, calc.CalculateAll(ctx))
+	first := hotScore()
+	firstLive, err := tdb.Queries.GetTrendingGeneration(ctx, GenerationLive)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM trending_scores_staging`), "staging is emptied on publish")
+
+	_, err = Rollback(ctx, tdb.Pool)
+	assert.ErrorIs(t, err, ErrNoPreviousGeneration, "the first generation has nothing to roll back to")
+
+	// A burst of downloads changes the next generation's score
+	for i := 0; i < 5; i++ {
+		_, err := tdb.Pool.Exec(ctx, `
+			INSERT INTO snapshots (addon_id, recorded_at, download_count, thumbs_up_count)
+			VALUES (1, $1, $2, 100)
+		`, time.Now().Add(-time.Duration(i)*time.Minute), 9000+i*500)
+		require.NoError(t, err)
+	}
+	require.NoError(t, calc.CalculateAll(ctx))
+	require.NotEqual(t, first, hotScore())
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM trending_scores_previous`))
+
+	restored, err := Rollback(ctx, tdb.Pool)
+	require.NoError(t, err)
+	assert.Equal(t, firstLive.RunID, restored.RunID)
+	assert.InDelta(t, first, hotScore(), 0.0001)
+
+	live, err := tdb.Queries.GetTrendingGeneration(ctx, GenerationLive)
+	require.NoError(t, err)
+	assert.Equal(t, firstLive.RunID, live.RunID)
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM trending_rank_history WHERE category = 'hot'`),
+		"rank history of the discarded generation is removed")
+
+	_, err = Rollback(ctx, tdb.Pool)
+	assert.ErrorIs(t, err, ErrNoPreviousGeneration, "only one generation is kept")
+}
+
+func TestRollbackRecordedHistory(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	seedAddonWithSnapshots(t, tdb, 1, "rollback-stays", 5000, 100, 10)
+	seedAddonWithSnapshots(t, tdb, 2, "rollback-enters", 8000, 100, 10)
+	seedAddonWithSnapshots(t, tdb, 3, "rollback-leaves", 6000, 100, 10)
+	calc := NewCalculator(NewPostgresStore(tdb.Pool))
+	setStatus := func(id int32, status string) {
+		_, err := tdb.Pool.Exec(ctx, `UPDATE addons SET status = $2 WHERE id = $1`, id, status)
+		require.NoError(t, err)
+	}
+	type state struct {
+		Stints        []database.ListAddonStintsRow
+		HeatIndex     pgtype.Int2
+		HeatSparkline []int16
+	}
+	archived := func(period string) []int32 {
+		rows, err := tdb.Queries.ListArchivedLeaderboard(ctx, database.ListArchivedLeaderboardParams{
+			Period:      period,
+			PeriodStart: pgtype.Date{Time: PeriodStart(period, time.Now()), Valid: true},
+			List:        "hot",
+		})
+		require.NoError(t, err)
+		ids := make([]int32, len(rows))
+		for i, r := range rows {
+			ids[i] = r.Addon.ID
+		}
+		return ids
+	}
+	snapshot := func() map[int32]state {
+		states := make(map[int32]state)
+		for _, id := range []int32{1, 2, 3} {
+			stints, err := tdb.Queries.ListAddonStints(ctx, id)
+			require.NoError(t, err)
+			addon, err := tdb.Queries.GetAddonByID(ctx, id)
+			require.NoError(t, err)
+			states[id] = state{Stints: stints, HeatIndex: addon.HeatIndex, HeatSparkline: addon.HeatSparkline}
+		}
+		return states
+	}
+
+	setStatus(2, "inactive")
+	require.NoError(t, calc.CalculateAll(ctx))
+	before := snapshot()
+	archivedDaily, archivedWeekly := archived(PeriodDaily), archived(PeriodWeekly)
+	require.ElementsMatch(t, []int32{1, 3}, archivedDaily)
+	require.NotEmpty(t, before[1].Stints)
+	require.Empty(t, before[2].Stints)
+	require.True(t, before[3].HeatIndex.Valid)
+
+	// The next generation extends addon 1's stints, opens addon 2's and
+	// closes addon 3's, all within the same day's heat index and archived lists
+	setStatus(2, "active")
+	setStatus(3, "inactive")
+	require.NoError(t, calc.CalculateAll(ctx))
+	after := snapshot()
+	require.NotEqual(t, before, after)
+	require.NotEmpty(t, after[2].Stints)
+	require.False(t, after[3].HeatIndex.Valid)
+	require.ElementsMatch(t, []int32{1, 2}, archived(PeriodDaily))
+
+	_, err := Rollback(ctx, tdb.Pool)
+	require.NoError(t, err)
+	assert.Equal(t, before, snapshot())
+	assert.Equal(t, archivedDaily, archived(PeriodDaily))
+	assert.Equal(t, archivedWeekly, archived(PeriodWeekly))
+
+	live, err := tdb.Queries.GetTrendingGeneration(ctx, GenerationLive)
+	require.NoError(t, err)
+	var discarded int
+	err = tdb.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM heat_index_daily WHERE run_id <> $1`, live.RunID).Scan(&discarded)
+	require.NoError(t, err)
+	assert.Zero(t, discarded, "only the restored generation's heat index is left")
+}
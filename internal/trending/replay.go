@@ -0,0 +1,264 @@
+package trending
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// Replay runs the calculator's scoring at successive points in time. List ages
+// are kept in memory instead of trending_scores, so it never writes to the
+// database and can be used to backtest parameter sets.
+type Replay struct {
+	calc  *Calculator
+	state map[int32]database.GetAllTrendingScoresRow
+}
+
+// Lists are the addon IDs on each list, in rank order, at one point in time.
+type Lists struct {
+	At     time.Time
+	Hot    []int32
+	Rising []int32
+	Loved  []int32
+	Fresh  []int32
+}
+
+// NewReplay creates a replay that scores with p.
+func NewReplay(p Params) *Replay {
+	return &Replay{
+		calc:  &Calculator{params: p},
+		state: make(map[int32]database.GetAllTrendingScoresRow),
+	}
+}
+
+// Params returns the parameter set being replayed.
+func (r *Replay) Params() Params {
+	return r.calc.params
+}
+
+// Step scores every addon as of now and returns the resulting lists. Steps must
+// be taken in time order, and stats must only reflect snapshots recorded at or
+// before now.
+func (r *Replay) Step(now time.Time, stats []database.GetAllSnapshotStatsRow, percentile95 float64, updateMap map[int32]int32) Lists {
+	g := r.calc.generate(Inputs{
+		Now:          now,
+		Percentile95: percentile95,
+		Stats:        stats,
+		Updates:      updateMap,
+		Existing:     r.state,
+	})
+
+	state := make(map[int32]database.GetAllTrendingScoresRow, len(g.Scores)+len(g.Unchanged))
+	for _, s := range g.Scores {
+		state[s.AddonID] = existingRow(s)
+	}
+	for _, id := range g.Unchanged {
+		state[id] = r.state[id]
+	}
+	r.state = state
+
+	return g.Lists()
+}
+
+// rankLists builds every list from scores, and the list state to carry into
+// the next calculation. Hot, rising and loved keep members that slipped just
+// below the cut-off, going by their raw ranks in existing (see holdList), and
+// are then diversified for display (see diversify). Ages survive on either
+// list, or for rising in the top of its score before the hot exclusion. Fresh
+// Releases ages from creation, so it keeps no list state.
+func rankLists(scores []Breakdown, existing map[int32]database.GetAllTrendingScoresRow, p Params) (lists Lists, state map[int32]database.GetAllTrendingScoresRow) {
+	listSize := int(p.ListSize)
+	hotScore := func(s Breakdown) float64 { return s.HotScore }
+	risingScore := func(s Breakdown) float64 { return s.RisingScore }
+	lovedScore := func(s Breakdown) float64 { return s.LovedScore }
+	freshScore := func(s Breakdown) float64 { return s.FreshScore }
+	groups := byAddon(scores)
+
+	hotRaw, hotMisses := holdList(scores, hotScore, nil, func(id int32) (pgtype.Int2, int16) {
+		return existing[id].HotRawRank, existing[id].HotMisses
+	}, p)
+	hot := diversify(hotRaw, topAddons(scores, hotScore, nil, len(scores)), groups, p)
+	onHot := make(map[int32]bool, len(hot))
+	for _, id := range hot {
+		onHot[id] = true
+	}
+	risingRaw, risingMisses := holdList(scores, risingScore, onHot, func(id int32) (pgtype.Int2, int16) {
+		return existing[id].RisingRawRank, existing[id].RisingMisses
+	}, p)
+	rising := diversify(risingRaw, topAddons(scores, risingScore, onHot, len(scores)), groups, p)
+	lovedRaw, lovedMisses := holdList(scores, lovedScore, nil, func(id int32) (pgtype.Int2, int16) {
+		return existing[id].LovedRawRank, existing[id].LovedMisses
+	}, p)
+	loved := diversify(lovedRaw, topAddons(scores, lovedScore, nil, len(scores)), groups, p)
+
+	topRising := make(map[int32]bool, listSize)
+	for _, id := range topAddons(scores, risingScore, nil, listSize) {
+		topRising[id] = true
+	}
+	state = make(map[int32]database.GetAllTrendingScoresRow, len(scores))
+	for _, s := range scores {
+		state[s.AddonID] = database.GetAllTrendingScoresRow{
+			AddonID:       s.AddonID,
+			HotMisses:     hotMisses[s.AddonID],
+			RisingMisses:  risingMisses[s.AddonID],
+			LovedMisses:   lovedMisses[s.AddonID],
+			HotRank:       rankIn(hot, s.AddonID),
+			RisingRank:    rankIn(rising, s.AddonID),
+			LovedRank:     rankIn(loved, s.AddonID),
+			HotRawRank:    rankIn(hotRaw, s.AddonID),
+			RisingRawRank: rankIn(risingRaw, s.AddonID),
+			LovedRawRank:  rankIn(lovedRaw, s.AddonID),
+		}
+	}
+	for _, s := range scores {
+		row := state[s.AddonID]
+		if row.HotRank.Valid || row.HotRawRank.Valid {
+			row.FirstHotAt = s.FirstHotAt
+		}
+		if row.RisingRank.Valid || row.RisingRawRank.Valid || topRising[s.AddonID] {
+			row.FirstRisingAt = s.FirstRisingAt
+		}
+		if row.LovedRank.Valid || row.LovedRawRank.Valid {
+			row.FirstLovedAt = s.FirstLovedAt
+		}
+		state[s.AddonID] = row
+	}
+	lists = Lists{Hot: hot, Rising: rising, Loved: loved, Fresh: topAddons(scores, freshScore, nil, listSize)}
+	return lists, state
+}
+
+// rankIn returns the 1-based position of id in list, NULL when it's absent.
+func rankIn(list []int32, id int32) pgtype.Int2 {
+	for i, member := range list {
+		if member == id {
+			return pgtype.Int2{Int16: int16(i + 1), Valid: true} //nolint:gosec // i is bounded by the list size
+		}
+	}
+	return pgtype.Int2{}
+}
+
+// diversify builds the displayed list from the raw members and then the other
+// candidates, both in order, skipping addons whose author or primary category
+// already fills its cap. The next-best candidates take the seats of the
+// skipped members. Addons with an unknown author or category are never capped
+// by it.
+func diversify(members, candidates []int32, groups map[int32]Breakdown, p Params) []int32 {
+	if p.DiversityMaxPerAuthor == 0 && p.DiversityMaxPerCategory == 0 {
+		return members
+	}
+
+	limit := int(p.ListSize)
+	listed := make([]int32, 0, limit)
+	seen := make(map[int32]bool, limit)
+	perAuthor := make(map[int32]int32)
+	perCategory := make(map[int32]int32)
+	for _, id := range append(append([]int32(nil), members...), candidates...) {
+		if len(listed) == limit {
+			break
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		b := groups[id]
+		if atCap(perAuthor, b.AuthorID, p.DiversityMaxPerAuthor) || atCap(perCategory, b.PrimaryCategoryID, p.DiversityMaxPerCategory) {
+			continue
+		}
+		if b.AuthorID != 0 {
+			perAuthor[b.AuthorID]++
+		}
+		if b.PrimaryCategoryID != 0 {
+			perCategory[b.PrimaryCategoryID]++
+		}
+		listed = append(listed, id)
+	}
+	return listed
+}
+
+// atCap reports whether group already has limit entries. Group 0 (unknown)
+// and limit 0 are uncapped.
+func atCap(counts map[int32]int32, group, limit int32) bool {
+	return group != 0 && limit > 0 && counts[group] >= limit
+}
+
+// holdList ranks a list like topAddons, with hysteresis: a previous member
+// that falls below the cut-off keeps its seat for up to p.HysteresisRuns
+// consecutive runs while its score stays within p.HysteresisMargin of the
+// cut-off, taking the seat of the lowest newcomer. prev returns an addon's
+// rank and runs held below the cut-off after the previous calculation.
+// Members stay in score order; the returned map holds the runs below the
+// cut-off of each held member.
+func holdList(scores []Breakdown, score func(Breakdown) float64, exclude map[int32]bool, prev func(int32) (pgtype.Int2, int16), p Params) ([]int32, map[int32]int16) {
+	limit := int(p.ListSize)
+	ranked := topAddons(scores, score, exclude, len(scores))
+	misses := make(map[int32]int16)
+	if len(ranked) <= limit {
+		return ranked, misses
+	}
+
+	values := make(map[int32]float64, len(scores))
+	for _, s := range scores {
+		values[s.AddonID] = score(s)
+	}
+	floor := (1 - p.HysteresisMargin) * values[ranked[limit-1]]
+
+	seats := limit
+	for _, id := range ranked[:limit] {
+		if rank, _ := prev(id); rank.Valid {
+			seats--
+		}
+	}
+	for _, id := range ranked[limit:] {
+		rank, runs := prev(id)
+		if rank.Valid && int32(runs) < p.HysteresisRuns && values[id] >= floor && seats > 0 {
+			misses[id] = runs + 1
+			seats--
+		}
+	}
+
+	members := make([]int32, 0, limit)
+	for i, id := range ranked {
+		rank, _ := prev(id)
+		switch {
+		case i < limit && rank.Valid:
+			members = append(members, id)
+		case i < limit && seats > 0:
+			members = append(members, id)
+			seats--
+		case i >= limit && misses[id] > 0:
+			members = append(members, id)
+		}
+	}
+	return members, misses
+}
+
+// topAddons returns up to limit addon IDs with a positive score, highest first.
+// Ties are broken by ID so replays are deterministic.
+func topAddons(scores []Breakdown, score func(Breakdown) float64, exclude map[int32]bool, limit int) []int32 {
+	candidates := make([]Breakdown, 0, len(scores))
+	for _, s := range scores {
+		if score(s) > 0 && !exclude[s.AddonID] {
+			candidates = append(candidates, s)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := score(candidates[i]), score(candidates[j])
+		if a != b {
+			return a > b
+		}
+		return candidates[i].AddonID < candidates[j].AddonID
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	ids := make([]int32, len(candidates))
+	for i, s := range candidates {
+		ids[i] = s.AddonID
+	}
+	return ids
+}
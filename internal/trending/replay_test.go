@@ -0,0 +1,141 @@
+package trending
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+
+	"addon-radar/internal/database"
+)
+
+func replayStat(id int32, downloads, change24h, change7d int64) database.GetAllSnapshotStatsRow {
+	return database.GetAllSnapshotStatsRow{
+		AddonID:           id,
+		DownloadCount:     pgtype.Int8{Int64: downloads, Valid: true},
+		DownloadChange24h: change24h,
+		SnapshotCount24h:  24,
+		DownloadChange7d:  change7d,
+		MinDownloads7d:    downloads - change7d,
+		DownloadSlope24h:  float64(change24h) / 24,
+		DownloadFit24h:    1,
+		SpanHours24h:      23,
+		DownloadSlope7d:   float64(change7d) / 168,
+	}
+}
+
+func TestReplayStep(t *testing.T) {
+	p := DefaultParams()
+	p.ListSize = 2
+	replay := NewReplay(p)
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	stats := []database.GetAllSnapshotStatsRow{
+		replayStat(1, 100000, 2400, 10000), // Hot leader
+		replayStat(2, 50000, 1200, 5000),   // Hot runner-up
+		replayStat(3, 20000, 240, 1000),    // Hot, but past the list size
+		replayStat(4, 2000, 240, 1000),     // Rising candidate
+		replayStat(5, 20, 0, 0),            // Below every gate
+	}
+
+	lists := replay.Step(start, stats, 500000, nil)
+	assert.Equal(t, start, lists.At)
+	assert.Equal(t, []int32{1, 2}, lists.Hot)
+	assert.NotContains(t, lists.Rising, int32(1), "hot addons are excluded from rising")
+	assert.NotContains(t, lists.Rising, int32(5))
+	assert.Contains(t, lists.Rising, int32(4))
+
+	// Ages carry over between steps, so an unchanged leader decays
+	first := replay.state[1].FirstHotAt
+	assert.True(t, first.Valid)
+	replay.Step(start.Add(time.Hour), stats, 500000, nil)
+	assert.Equal(t, first, replay.state[1].FirstHotAt)
+
+	// Addons beyond the list size don't keep an age
+	assert.False(t, replay.state[3].FirstHotAt.Valid)
+}
+
+func TestHoldList(t *testing.T) {
+	hotScores := func(values map[int32]float64) []Breakdown {
+		scores := make([]Breakdown, 0, len(values))
+		for id, v := range values {
+			scores = append(scores, Breakdown{AddonID: id, HotScore: v})
+		}
+		return scores
+	}
+	members := func(misses map[int32]int16) func(int32) (pgtype.Int2, int16) {
+		return func(id int32) (pgtype.Int2, int16) {
+			m, ok := misses[id]
+			return pgtype.Int2{Int16: 1, Valid: ok}, m
+		}
+	}
+
+	tests := []struct {
+		name       string
+		scores     map[int32]float64
+		prev       map[int32]int16 // Previous members and their runs below the cut-off
+		runs       int32
+		want       []int32
+		wantMisses map[int32]int16
+	}{
+		{"member within margin is kept", map[int32]float64{1: 100, 3: 90, 2: 80}, map[int32]int16{1: 0, 2: 0}, 3, []int32{1, 2}, map[int32]int16{2: 1}},
+		{"member far below the cut-off leaves", map[int32]float64{1: 100, 3: 90, 2: 70}, map[int32]int16{1: 0, 2: 0}, 3, []int32{1, 3}, map[int32]int16{}},
+		{"member leaves after its runs", map[int32]float64{1: 100, 3: 90, 2: 80}, map[int32]int16{1: 0, 2: 3}, 3, []int32{1, 3}, map[int32]int16{}},
+		{"member back in the top resets its runs", map[int32]float64{1: 100, 2: 95, 3: 90}, map[int32]int16{1: 0, 2: 2}, 3, []int32{1, 2}, map[int32]int16{}},
+		{"member displaces the lowest newcomer", map[int32]float64{1: 100, 3: 90, 2: 80}, map[int32]int16{2: 0}, 3, []int32{1, 2}, map[int32]int16{2: 1}},
+		{"disabled", map[int32]float64{1: 100, 3: 90, 2: 80}, map[int32]int16{1: 0, 2: 0}, 0, []int32{1, 3}, map[int32]int16{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DefaultParams()
+			p.ListSize = 2
+			p.HysteresisMargin = 0.15
+			p.HysteresisRuns = tt.runs
+
+			got, misses := holdList(hotScores(tt.scores), func(s Breakdown) float64 { return s.HotScore }, nil, members(tt.prev), p)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantMisses, misses)
+		})
+	}
+}
+
+func TestDiversify(t *testing.T) {
+	groups := map[int32]Breakdown{
+		1: {AddonID: 1, AuthorID: 10, PrimaryCategoryID: 100},
+		2: {AddonID: 2, AuthorID: 10, PrimaryCategoryID: 100},
+		3: {AddonID: 3, AuthorID: 10, PrimaryCategoryID: 200},
+		4: {AddonID: 4, AuthorID: 20, PrimaryCategoryID: 100},
+		5: {AddonID: 5, AuthorID: 30, PrimaryCategoryID: 300},
+		6: {AddonID: 6}, // Unknown author and category
+		7: {AddonID: 7},
+	}
+	candidates := []int32{1, 2, 3, 4, 5, 6, 7}
+
+	tests := []struct {
+		name        string
+		members     []int32
+		perAuthor   int32
+		perCategory int32
+		want        []int32
+	}{
+		{"author cap promotes the next-best", []int32{1, 2, 3}, 2, 0, []int32{1, 2, 4}},
+		{"category cap promotes the next-best", []int32{1, 2, 4}, 0, 2, []int32{1, 2, 3}},
+		{"both caps", []int32{1, 2, 3}, 1, 1, []int32{1, 5, 6}},
+		{"raw members come first", []int32{5, 1, 2}, 2, 0, []int32{5, 1, 2}},
+		{"unknown groups are uncapped", []int32{6, 7, 1}, 1, 1, []int32{6, 7, 1}},
+		{"disabled", []int32{1, 2, 3}, 0, 0, []int32{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DefaultParams()
+			p.ListSize = 3
+			p.DiversityMaxPerAuthor = tt.perAuthor
+			p.DiversityMaxPerCategory = tt.perCategory
+
+			assert.Equal(t, tt.want, diversify(tt.members, candidates, groups, p))
+		})
+	}
+}
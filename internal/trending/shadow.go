@@ -0,0 +1,286 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// runShadow scores the shadow parameter set from the same inputs as the live
+// generation g, with the shadow set's own list state, and records how its
+// lists compare. It does nothing without a shadow set, or when the shadow set
+// is the live one.
+func (c *Calculator) runShadow(ctx context.Context, in Inputs, g Generation) error {
+	params, existing, ok, err := c.store.LoadShadow(ctx)
+	if err != nil || !ok || params.Version == c.params.Version {
+		return err
+	}
+
+	shadowIn := in
+	shadowIn.Existing = existing
+	shadow := (&Calculator{params: params}).generateOverall(shadowIn)
+	shadow.Run.ParamsVersion = params.Version
+
+	comparisons := compareShadow(g, shadow, listsFromState(in.Existing), listsFromState(existing))
+	for i := range comparisons {
+		comparisons[i].LiveVersion = c.params.Version
+		comparisons[i].ShadowVersion = params.Version
+	}
+	if err := c.store.RecordShadow(ctx, shadow, comparisons); err != nil {
+		return err
+	}
+	slog.Info("scored shadow params", "params_version", params.Version, "scored", len(shadow.Scores))
+	return nil
+}
+
+// compareShadow compares the hot, rising and loved lists of the live and
+// shadow generations. prevLive and prevShadow are the lists of the previous
+// calculation, for churn.
+func compareShadow(live, shadow Generation, prevLive, prevShadow Lists) []database.InsertShadowComparisonParams {
+	calculatedAt := pgtype.Timestamptz{Time: live.CalculatedAt, Valid: true}
+	lists := []struct {
+		name                               string
+		live, shadow, prevLive, prevShadow []int32
+	}{
+		{"hot", live.Hot, shadow.Hot, prevLive.Hot, prevShadow.Hot},
+		{"rising", live.Rising, shadow.Rising, prevLive.Rising, prevShadow.Rising},
+		{"loved", live.Loved, shadow.Loved, prevLive.Loved, prevShadow.Loved},
+	}
+
+	comparisons := make([]database.InsertShadowComparisonParams, len(lists))
+	for i, l := range lists {
+		comparisons[i] = database.InsertShadowComparisonParams{
+			CalculatedAt:    calculatedAt,
+			List:            l.name,
+			Overlap:         toNumeric(listOverlap(l.live, l.shadow)),
+			RankCorrelation: toNumeric(rankCorrelation(l.live, l.shadow)),
+		}
+		if churn, ok := listChurn(l.prevLive, l.live); ok {
+			comparisons[i].LiveChurn = toNumeric(churn)
+		}
+		if churn, ok := listChurn(l.prevShadow, l.shadow); ok {
+			comparisons[i].ShadowChurn = toNumeric(churn)
+		}
+	}
+	return comparisons
+}
+
+// listsFromState rebuilds the hot, rising and loved lists from list state.
+func listsFromState(state map[int32]database.GetAllTrendingScoresRow) Lists {
+	byRank := func(rank func(database.GetAllTrendingScoresRow) pgtype.Int2) []int32 {
+		var ids []int32
+		for id, row := range state {
+			if rank(row).Valid {
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return rank(state[ids[i]]).Int16 < rank(state[ids[j]]).Int16 })
+		return ids
+	}
+	return Lists{
+		Hot:    byRank(func(r database.GetAllTrendingScoresRow) pgtype.Int2 { return r.HotRank }),
+		Rising: byRank(func(r database.GetAllTrendingScoresRow) pgtype.Int2 { return r.RisingRank }),
+		Loved:  byRank(func(r database.GetAllTrendingScoresRow) pgtype.Int2 { return r.LovedRank }),
+	}
+}
+
+// listOverlap is the Jaccard similarity of two lists; two empty lists match.
+func listOverlap(a, b []int32) float64 {
+	union := make(map[int32]bool, len(a)+len(b))
+	for _, id := range a {
+		union[id] = true
+	}
+	shared := 0
+	for _, id := range b {
+		if union[id] {
+			shared++
+		}
+		union[id] = true
+	}
+	if len(union) == 0 {
+		return 1
+	}
+	return float64(shared) / float64(len(union))
+}
+
+// rankCorrelation is the Spearman correlation between two ranked lists over
+// every addon on either. Addons missing from a list share the rank after its
+// last. Lists too short to vary correlate 1 if they're equal and 0 otherwise.
+func rankCorrelation(a, b []int32) float64 {
+	missing := float64(max(len(a), len(b)) + 1)
+	rankOf := func(list []int32) map[int32]float64 {
+		ranks := make(map[int32]float64, len(list))
+		for i, id := range list {
+			ranks[id] = float64(i + 1)
+		}
+		return ranks
+	}
+	ra, rb := rankOf(a), rankOf(b)
+
+	var xs, ys []float64
+	seen := make(map[int32]bool, len(a)+len(b))
+	for _, id := range append(append([]int32(nil), a...), b...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		x, ok := ra[id]
+		if !ok {
+			x = missing
+		}
+		y, ok := rb[id]
+		if !ok {
+			y = missing
+		}
+		xs, ys = append(xs, x), append(ys, y)
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i] / float64(len(xs))
+		meanY += ys[i] / float64(len(ys))
+	}
+	var cov, varX, varY float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		varX += (xs[i] - meanX) * (xs[i] - meanX)
+		varY += (ys[i] - meanY) * (ys[i] - meanY)
+	}
+	if varX == 0 || varY == 0 {
+		if listOverlap(a, b) == 1 {
+			return 1
+		}
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// listChurn is the share of cur that wasn't on prev. It reports false when
+// there is no previous list to compare with.
+func listChurn(prev, cur []int32) (float64, bool) {
+	if len(prev) == 0 || len(cur) == 0 {
+		return 0, false
+	}
+	onPrev := make(map[int32]bool, len(prev))
+	for _, id := range prev {
+		onPrev[id] = true
+	}
+	entered := 0
+	for _, id := range cur {
+		if !onPrev[id] {
+			entered++
+		}
+	}
+	return float64(entered) / float64(len(cur)), true
+}
+
+// LoadShadow loads the shadow parameter set and the list state of its last
+// calculation. State left by an earlier shadow set is ignored.
+func (s *PostgresStore) LoadShadow(ctx context.Context) (Params, map[int32]database.GetAllTrendingScoresRow, bool, error) {
+	params, ok, err := ShadowParams(ctx, s.db)
+	if err != nil || !ok {
+		return Params{}, nil, false, err
+	}
+
+	rows, err := s.db.GetAllShadowScores(ctx)
+	if err != nil {
+		return Params{}, nil, false, fmt.Errorf("get shadow scores: %w", err)
+	}
+	existing := make(map[int32]database.GetAllTrendingScoresRow, len(rows))
+	for _, r := range rows {
+		if r.ParamsVersion != params.Version {
+			continue
+		}
+		existing[r.AddonID] = database.GetAllTrendingScoresRow{
+			AddonID:       r.AddonID,
+			FirstHotAt:    r.FirstHotAt,
+			FirstRisingAt: r.FirstRisingAt,
+			FirstLovedAt:  r.FirstLovedAt,
+			HotRank:       r.HotRank,
+			RisingRank:    r.RisingRank,
+			LovedRank:     r.LovedRank,
+			HotMisses:     r.HotMisses,
+			RisingMisses:  r.RisingMisses,
+			LovedMisses:   r.LovedMisses,
+			HotRawRank:    r.HotRawRank,
+			RisingRawRank: r.RisingRawRank,
+			LovedRawRank:  r.LovedRawRank,
+		}
+	}
+	return params, existing, true, nil
+}
+
+// RecordShadow replaces the stored shadow scores with g's and records the
+// comparisons, dropping comparisons past their retention.
+func (s *PostgresStore) RecordShadow(ctx context.Context, g Generation, comparisons []database.InsertShadowComparisonParams) error {
+	calculatedAt := pgtype.Timestamptz{Time: g.CalculatedAt, Valid: true}
+	rows := make([]database.InsertShadowScoresParams, 0, len(g.Scores))
+	for _, b := range g.Scores {
+		if !hasShadowState(b) {
+			continue
+		}
+		rows = append(rows, database.InsertShadowScoresParams{
+			AddonID:       b.AddonID,
+			ParamsVersion: g.Run.ParamsVersion,
+			HotScore:      toNumeric(b.HotScore),
+			RisingScore:   toNumeric(b.RisingScore),
+			LovedScore:    toNumeric(b.LovedScore),
+			FreshScore:    toNumeric(b.FreshScore),
+			FirstHotAt:    b.FirstHotAt,
+			FirstRisingAt: b.FirstRisingAt,
+			FirstLovedAt:  b.FirstLovedAt,
+			HotRank:       listRank(b.HotRank),
+			RisingRank:    listRank(b.RisingRank),
+			LovedRank:     listRank(b.LovedRank),
+			HotMisses:     b.HotMisses,
+			RisingMisses:  b.RisingMisses,
+			LovedMisses:   b.LovedMisses,
+			HotRawRank:    listRank(b.HotRawRank),
+			RisingRawRank: listRank(b.RisingRawRank),
+			LovedRawRank:  listRank(b.LovedRawRank),
+			CalculatedAt:  calculatedAt,
+		})
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := s.db.WithTx(tx)
+	if err := qtx.DeleteShadowScores(ctx); err != nil {
+		return fmt.Errorf("clear shadow scores: %w", err)
+	}
+	if _, err := qtx.InsertShadowScores(ctx, rows); err != nil {
+		return fmt.Errorf("insert shadow scores: %w", err)
+	}
+	for _, cmp := range comparisons {
+		if err := qtx.InsertShadowComparison(ctx, cmp); err != nil {
+			return fmt.Errorf("insert %s shadow comparison: %w", cmp.List, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if deleted, err := s.db.DeleteOldShadowComparisons(ctx); err != nil {
+		slog.Warn("failed to cleanup shadow comparisons", "error", err)
+	} else if deleted > 0 {
+		slog.Info("cleaned up old shadow comparisons", "deleted", deleted)
+	}
+	return nil
+}
+
+// hasShadowState reports whether b has a score or list state worth keeping.
+func hasShadowState(b Breakdown) bool {
+	return b.HotScore > 0 || b.RisingScore > 0 || b.LovedScore > 0 || b.FreshScore > 0 ||
+		b.FirstHotAt.Valid || b.FirstRisingAt.Valid || b.FirstLovedAt.Valid ||
+		b.HotRawRank > 0 || b.RisingRawRank > 0 || b.LovedRawRank > 0
+}
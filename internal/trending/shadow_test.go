@@ -0,0 +1,86 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/database"
+	"addon-radar/internal/testutil"
+)
+
+func TestRankCorrelation(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int32
+		want float64
+	}{
+		{"same order", []int32{1, 2, 3, 4}, []int32{1, 2, 3, 4}, 1},
+		{"reversed", []int32{1, 2, 3, 4}, []int32{4, 3, 2, 1}, -1},
+		{"one swap", []int32{1, 2, 3, 4}, []int32{2, 1, 3, 4}, 0.8},
+		{"both empty", nil, nil, 1},
+		{"single different addon", []int32{1}, []int32{2}, -1},
+		{"one list empty", []int32{1}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, rankCorrelation(tt.a, tt.b), 0.0001)
+		})
+	}
+}
+
+func TestListOverlapAndChurn(t *testing.T) {
+	assert.InDelta(t, 0.5, listOverlap([]int32{1, 2, 3}, []int32{2, 3, 4}), 0.0001)
+	assert.Equal(t, 1.0, listOverlap(nil, nil))
+
+	churn, ok := listChurn([]int32{1, 2, 3, 4}, []int32{1, 2, 5, 6})
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, churn, 0.0001)
+	_, ok = listChurn(nil, []int32{1})
+	assert.False(t, ok, "nothing to churn against")
+}
+
+func TestPostgresShadow(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	seedAddonWithSnapshots(t, tdb, 1, "shadow-test", 5000, 100, 10)
+	shadow := DefaultParams()
+	shadow.Version = "shadow-test"
+	shadow.MinHotDownloads = 10000
+	require.NoError(t, SetShadowParams(ctx, tdb.Pool, shadow))
+
+	calc := NewCalculator(NewPostgresStore(tdb.Pool))
+	require.NoError(t, calc.CalculateAll(ctx))
+	require.NoError(t, calc.CalculateAll(ctx))
+
+	// The live scores are untouched by the shadow set's stricter gate
+	live, err := tdb.Queries.GetTrendingScore(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, live.HotRank.Valid)
+
+	comparisons, err := tdb.Queries.ListShadowComparisons(ctx, database.ListShadowComparisonsParams{
+		List:  "hot",
+		Since: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, comparisons, 2)
+	assert.Equal(t, "shadow-test", comparisons[1].ShadowVersion)
+	overlap, err := comparisons[1].Overlap.Float64Value()
+	require.NoError(t, err)
+	assert.Zero(t, overlap.Float64)
+
+	// Clearing the shadow set stops shadow scoring
+	require.NoError(t, ClearShadowParams(ctx, tdb.Queries))
+	require.NoError(t, calc.CalculateAll(ctx))
+	comparisons, err = tdb.Queries.ListShadowComparisons(ctx, database.ListShadowComparisonsParams{
+		List:  "hot",
+		Since: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Len(t, comparisons, 2)
+}
@@ -0,0 +1,127 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// stintUpdate is how a calculation changes an addon's stint on a list.
+type stintUpdate struct {
+	ID       int64 // 0 opens a new stint
+	AddonID  int32
+	Category string
+	Rank     int16   // 0 closes the stint
+	TopHours float64 // Hours at #1 to add
+}
+
+// advanceStints compares the open stints with lists calculated at now. Stints
+// of addons still listed are extended, the rest are closed, and addons new
+// to a list open a stint. Hours since an open stint was last seen count as
+// hours at #1 when it was #1 then.
+func advanceStints(open []database.ListOpenStintsRow, lists Lists, now time.Time) []stintUpdate {
+	type key struct {
+		addonID  int32
+		category string
+	}
+	ranks := make(map[key]int16)
+	for _, list := range []struct {
+		name string
+		ids  []int32
+	}{{"hot", lists.Hot}, {"rising", lists.Rising}, {"loved", lists.Loved}, {"fresh", lists.Fresh}} {
+		for i, id := range list.ids {
+			ranks[key{id, list.name}] = int16(i + 1) //nolint:gosec // i is bounded by the list size
+		}
+	}
+
+	updates := make([]stintUpdate, 0, len(open)+len(ranks))
+	seen := make(map[key]bool, len(open))
+	for _, s := range open {
+		k := key{s.AddonID, s.Category}
+		seen[k] = true
+		var topHours float64
+		if s.LastRank == 1 {
+			topHours = now.Sub(s.LastSeenAt.Time).Hours()
+		}
+		updates = append(updates, stintUpdate{ID: s.ID, AddonID: s.AddonID, Category: s.Category, Rank: ranks[k], TopHours: topHours})
+	}
+	for k, rank := range ranks {
+		if !seen[k] {
+			updates = append(updates, stintUpdate{AddonID: k.addonID, Category: k.category, Rank: rank})
+		}
+	}
+	return updates
+}
+
+// recordStints advances the stints of g's overall lists, keeping the open
+// stints as they were under runID so Rollback can restore them.
+func (s *PostgresStore) recordStints(ctx context.Context, g Generation, runID int64, recordedAt pgtype.Timestamptz) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback in defer is safe to ignore
+
+	qtx := s.db.WithTx(tx)
+	if err := qtx.DeletePreviousStints(ctx); err != nil {
+		return fmt.Errorf("clear previous stints: %w", err)
+	}
+	if err := qtx.KeepOpenStints(ctx, runID); err != nil {
+		return fmt.Errorf("keep open stints: %w", err)
+	}
+	open, err := qtx.ListOpenStints(ctx)
+	if err != nil {
+		return fmt.Errorf("list open stints: %w", err)
+	}
+
+	for _, u := range advanceStints(open, g.Lists(), recordedAt.Time) {
+		switch {
+		case u.ID == 0:
+			err = qtx.OpenStint(ctx, database.OpenStintParams{
+				AddonID:  u.AddonID,
+				Category: u.Category,
+				SeenAt:   recordedAt,
+				Rank:     u.Rank,
+			})
+		case u.Rank == 0:
+			err = qtx.CloseStint(ctx, database.CloseStintParams{
+				ExitedAt: recordedAt,
+				TopHours: toNumeric(u.TopHours),
+				ID:       u.ID,
+			})
+		default:
+			err = qtx.ExtendStint(ctx, database.ExtendStintParams{
+				Rank:     u.Rank,
+				SeenAt:   recordedAt,
+				TopHours: toNumeric(u.TopHours),
+				ID:       u.ID,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("update %s stint of addon %d: %w", u.Category, u.AddonID, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// rollbackStints discards the stints opened since the discarded generation
+// was published and restores the ones runID advanced.
+func rollbackStints(ctx context.Context, qtx *database.Queries, runID int64, since pgtype.Timestamptz) error {
+	if _, err := qtx.DeleteStintsEnteredSince(ctx, since); err != nil {
+		return fmt.Errorf("delete stints: %w", err)
+	}
+	if _, err := qtx.RestorePreviousStints(ctx, runID); err != nil {
+		return fmt.Errorf("restore previous stints: %w", err)
+	}
+	if err := qtx.DeletePreviousStints(ctx); err != nil {
+		return fmt.Errorf("clear previous stints: %w", err)
+	}
+	return nil
+}
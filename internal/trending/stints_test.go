@@ -0,0 +1,72 @@
+package trending
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/database"
+	"addon-radar/internal/testutil"
+)
+
+func TestAdvanceStints(t *testing.T) {
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	lastRun := pgtype.Timestamptz{Time: now.Add(-30 * time.Minute), Valid: true}
+	open := []database.ListOpenStintsRow{
+		{ID: 1, AddonID: 10, Category: "hot", LastRank: 1, LastSeenAt: lastRun},
+		{ID: 2, AddonID: 11, Category: "hot", LastRank: 2, LastSeenAt: lastRun},
+		{ID: 3, AddonID: 12, Category: "rising", LastRank: 1, LastSeenAt: lastRun},
+	}
+	lists := Lists{Hot: []int32{11, 10}, Rising: []int32{13}, Fresh: []int32{10}}
+
+	updates := advanceStints(open, lists, now)
+	sort.Slice(updates, func(i, j int) bool {
+		if updates[i].ID != updates[j].ID {
+			return updates[i].ID < updates[j].ID
+		}
+		return updates[i].Category < updates[j].Category
+	})
+
+	assert.Equal(t, []stintUpdate{
+		{AddonID: 10, Category: "fresh", Rank: 1},                     // Opened
+		{AddonID: 13, Category: "rising", Rank: 1},                    // Opened
+		{ID: 1, AddonID: 10, Category: "hot", Rank: 2, TopHours: 0.5}, // Was #1 until now
+		{ID: 2, AddonID: 11, Category: "hot", Rank: 1},                // Reached #1 now
+		{ID: 3, AddonID: 12, Category: "rising", TopHours: 0.5},       // Closed
+	}, updates)
+}
+
+func TestRecordStints(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	ctx := context.Background()
+
+	seedAddonWithSnapshots(t, tdb, 1, "stint-test", 5000, 100, 10)
+	calc := NewCalculator(NewPostgresStore(tdb.Pool))
+
+	// Consecutive calculations extend one stint per list
+	require.NoError(t, calc.CalculateAll(ctx))
+	require.NoError(t, calc.CalculateAll(ctx))
+	stints, err := tdb.Queries.ListAddonStints(ctx, 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, stints)
+	lists := map[string]bool{}
+	for _, s := range stints {
+		assert.False(t, lists[s.Category], "one stint per list")
+		lists[s.Category] = true
+		assert.False(t, s.ExitedAt.Valid)
+		assert.Equal(t, int16(1), s.BestRank)
+	}
+
+	// Dropping off every list closes its stints
+	_, err = tdb.Pool.Exec(ctx, `UPDATE addons SET status = 'inactive' WHERE id = 1`)
+	require.NoError(t, err)
+	require.NoError(t, calc.CalculateAll(ctx))
+	open, err := tdb.Queries.ListOpenStints(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, open)
+}
@@ -0,0 +1,105 @@
+package trending
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// Store is where the calculator reads its inputs and writes its results.
+// PostgresStore backs production runs; MemoryStore keeps everything in memory
+// for tests and simulations.
+type Store interface {
+	// ResolveParams returns the parameters for a run and where they came from.
+	// path, if set, is a parameter file that overrides the active set.
+	ResolveParams(ctx context.Context, path string) (Params, string, error)
+	// LoadInputs returns everything a run scores from.
+	LoadInputs(ctx context.Context) (Inputs, error)
+	// Publish makes a generation's scores live, replacing the previous ones.
+	Publish(ctx context.Context, g Generation) error
+	// RecordRankHistory records a published generation's lists.
+	RecordRankHistory(ctx context.Context, g Generation) error
+	// LoadShadow returns the shadow parameter set and the list state of its
+	// last calculation, or false when no shadow set is configured.
+	LoadShadow(ctx context.Context) (Params, map[int32]database.GetAllTrendingScoresRow, bool, error)
+	// RecordShadow stores a shadow generation, which is never published, and
+	// how its lists compared with the live generation's.
+	RecordShadow(ctx context.Context, g Generation, comparisons []database.InsertShadowComparisonParams) error
+}
+
+// Inputs are the data a run scores from.
+type Inputs struct {
+	Now          time.Time // Point in time the stats describe
+	Percentile95 float64
+	Stats        []database.GetAllSnapshotStatsRow
+	Updates      map[int32]int32 // File updates in the last 90 days, by addon
+	Existing     map[int32]database.GetAllTrendingScoresRow
+
+	CategoryMembers     map[int32][]int32 // Active addon IDs, by category
+	CategoryPercentiles map[int32]float64
+	CategoryExisting    map[int32]map[int32]database.GetAllTrendingScoresRow
+}
+
+// Generation is the result of one run, published as a whole.
+type Generation struct {
+	Run          database.InsertTrendingCalculationRunParams
+	CalculatedAt time.Time
+	Scores       []Breakdown // List ages already cleared for addons off the lists
+	Unchanged    []int32     // Skipped because their inputs didn't change; keep their live scores
+	Hot          []int32
+	Rising       []int32
+	Loved        []int32
+	Fresh        []int32
+	Categories   []CategoryGeneration
+}
+
+// Lists returns g's overall lists.
+func (g Generation) Lists() Lists {
+	return Lists{At: g.CalculatedAt, Hot: g.Hot, Rising: g.Rising, Loved: g.Loved, Fresh: g.Fresh}
+}
+
+// CategoryGeneration is one category's part of a generation.
+type CategoryGeneration struct {
+	CategoryID int32
+	Scores     []Breakdown // Only addons with a positive hot or rising score
+	Hot        []int32
+	Rising     []int32
+}
+
+// existingRow is what the next run needs to know about a stored score.
+func existingRow(b Breakdown) database.GetAllTrendingScoresRow {
+	return database.GetAllTrendingScoresRow{
+		AddonID:       b.AddonID,
+		FirstHotAt:    b.FirstHotAt,
+		FirstRisingAt: b.FirstRisingAt,
+		FirstLovedAt:  b.FirstLovedAt,
+		InputsHash:    pgtype.Int8{Int64: b.inputsHash, Valid: true},
+		Unscored:      b.HotScore <= 0 && b.RisingScore <= 0 && b.LovedScore <= 0 && b.FreshScore <= 0,
+		HotRank:       listRank(b.HotRank),
+		RisingRank:    listRank(b.RisingRank),
+		LovedRank:     listRank(b.LovedRank),
+		HotMisses:     b.HotMisses,
+		RisingMisses:  b.RisingMisses,
+		LovedMisses:   b.LovedMisses,
+		HotRawRank:    listRank(b.HotRawRank),
+		RisingRawRank: listRank(b.RisingRawRank),
+		LovedRawRank:  listRank(b.LovedRawRank),
+	}
+}
+
+// listRank converts a Breakdown rank to its column value, NULL off the list.
+func listRank(rank int16) pgtype.Int2 {
+	return pgtype.Int2{Int16: rank, Valid: rank > 0}
+}
+
+// byAddon indexes scores by addon ID.
+func byAddon(scores []Breakdown) map[int32]Breakdown {
+	m := make(map[int32]Breakdown, len(scores))
+	for _, s := range scores {
+		m[s.AddonID] = s
+	}
+	return m
+}
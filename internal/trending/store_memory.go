@@ -0,0 +1,183 @@
+package trending
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"addon-radar/internal/database"
+)
+
+// MemoryStore is a Store that keeps scores and history in memory. It serves
+// the inputs it was last given and uses the parameter set it was created
+// with, so the calculator can be run without a database.
+type MemoryStore struct {
+	mu         sync.Mutex
+	params     Params
+	inputs     Inputs
+	scores     map[int32]Breakdown
+	categories map[int32]map[int32]Breakdown
+	runs       []database.InsertTrendingCalculationRunParams
+	history    []Lists
+
+	shadowParams      *Params
+	shadow            map[int32]Breakdown
+	shadowComparisons []database.InsertShadowComparisonParams
+}
+
+// NewMemoryStore creates an empty store whose active parameter set is p.
+func NewMemoryStore(p Params) *MemoryStore {
+	return &MemoryStore{
+		params:     p,
+		scores:     make(map[int32]Breakdown),
+		categories: make(map[int32]map[int32]Breakdown),
+	}
+}
+
+// SetInputs sets the inputs of the next run. Existing scores come from the
+// store itself, so in.Existing and in.CategoryExisting are ignored. A zero
+// in.Now means the time of the run.
+func (s *MemoryStore) SetInputs(in Inputs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inputs = in
+}
+
+// ResolveParams returns the parameter file at path if set, otherwise the
+// store's parameter set.
+func (s *MemoryStore) ResolveParams(_ context.Context, path string) (Params, string, error) {
+	if path != "" {
+		p, err := LoadParamsFile(path)
+		if err != nil {
+			return Params{}, "", err
+		}
+		return p, ParamsSourceFile, nil
+	}
+	return s.params, ParamsSourceTable, nil
+}
+
+// LoadInputs returns the inputs set with SetInputs and the stored scores.
+func (s *MemoryStore) LoadInputs(_ context.Context) (Inputs, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	in := s.inputs
+	if in.Now.IsZero() {
+		in.Now = time.Now()
+	}
+	in.Existing = make(map[int32]database.GetAllTrendingScoresRow, len(s.scores))
+	for id, b := range s.scores {
+		in.Existing[id] = existingRow(b)
+	}
+	in.CategoryExisting = make(map[int32]map[int32]database.GetAllTrendingScoresRow, len(s.categories))
+	for categoryID, scores := range s.categories {
+		in.CategoryExisting[categoryID] = make(map[int32]database.GetAllTrendingScoresRow, len(scores))
+		for id, b := range scores {
+			in.CategoryExisting[categoryID][id] = existingRow(b)
+		}
+	}
+	return in, nil
+}
+
+// Publish replaces the stored scores with g's.
+func (s *MemoryStore) Publish(_ context.Context, g Generation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scores := byAddon(g.Scores)
+	for _, id := range g.Unchanged {
+		if b, ok := s.scores[id]; ok {
+			scores[id] = b
+		}
+	}
+	s.scores = scores
+
+	s.categories = make(map[int32]map[int32]Breakdown, len(g.Categories))
+	for _, cat := range g.Categories {
+		s.categories[cat.CategoryID] = byAddon(cat.Scores)
+	}
+	s.runs = append(s.runs, g.Run)
+	return nil
+}
+
+// RecordRankHistory appends g's overall lists to the history.
+func (s *MemoryStore) RecordRankHistory(_ context.Context, g Generation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = append(s.history, g.Lists())
+	return nil
+}
+
+// Score returns the stored score of an addon.
+func (s *MemoryStore) Score(addonID int32) (Breakdown, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.scores[addonID]
+	return b, ok
+}
+
+// CategoryScore returns the stored score of an addon within a category.
+func (s *MemoryStore) CategoryScore(categoryID, addonID int32) (Breakdown, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.categories[categoryID][addonID]
+	return b, ok
+}
+
+// Runs returns every published run, oldest first.
+func (s *MemoryStore) Runs() []database.InsertTrendingCalculationRunParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]database.InsertTrendingCalculationRunParams(nil), s.runs...)
+}
+
+// RankHistory returns the recorded lists, oldest first.
+func (s *MemoryStore) RankHistory() []Lists {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Lists(nil), s.history...)
+}
+
+// SetShadowParams makes p the shadow parameter set of the next runs.
+func (s *MemoryStore) SetShadowParams(p Params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shadowParams = &p
+	s.shadow = nil
+}
+
+// LoadShadow returns the shadow parameter set and the list state of its last
+// run, or false when none is set.
+func (s *MemoryStore) LoadShadow(_ context.Context) (Params, map[int32]database.GetAllTrendingScoresRow, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shadowParams == nil {
+		return Params{}, nil, false, nil
+	}
+	existing := make(map[int32]database.GetAllTrendingScoresRow, len(s.shadow))
+	for id, b := range s.shadow {
+		row := existingRow(b)
+		row.InputsHash = pgtype.Int8{}
+		existing[id] = row
+	}
+	return *s.shadowParams, existing, true, nil
+}
+
+// RecordShadow replaces the stored shadow scores with g's and appends the
+// comparisons.
+func (s *MemoryStore) RecordShadow(_ context.Context, g Generation, comparisons []database.InsertShadowComparisonParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shadow = byAddon(g.Scores)
+	s.shadowComparisons = append(s.shadowComparisons, comparisons...)
+	return nil
+}
+
+// ShadowComparisons returns every recorded shadow comparison, oldest first.
+func (s *MemoryStore) ShadowComparisons() []database.InsertShadowComparisonParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]database.InsertShadowComparisonParams(nil), s.shadowComparisons...)
+}
@@ -0,0 +1,232 @@
+package trending
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"addon-radar/internal/database"
+)
+
+// memoryStat builds snapshot stats of an addon gaining perHour downloads
+// steadily over the last week.
+func memoryStat(id int32, downloads, perHour int64) database.GetAllSnapshotStatsRow {
+	return database.GetAllSnapshotStatsRow{
+		AddonID:           id,
+		DownloadCount:     pgtype.Int8{Int64: downloads, Valid: true},
+		ThumbsUpCount:     pgtype.Int4{Int32: 10, Valid: true},
+		DownloadChange24h: perHour * 24,
+		SnapshotCount24h:  24,
+		DownloadChange7d:  perHour * 24 * 7,
+		MinDownloads7d:    downloads - perHour*24*7,
+		DownloadSlope24h:  float64(perHour),
+		DownloadFit24h:    1,
+		SpanHours24h:      23,
+		DownloadSlope7d:   float64(perHour),
+	}
+}
+
+func TestCalculatorWithMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("scores, publishes and records lists", func(t *testing.T) {
+		store := NewMemoryStore(DefaultParams())
+		store.SetInputs(Inputs{
+			Now:          now,
+			Percentile95: 500000,
+			Stats: []database.GetAllSnapshotStatsRow{
+				memoryStat(1, 5000, 20),
+				memoryStat(2, 2000, 10),
+				memoryStat(3, 20, 0),
+			},
+		})
+
+		require.NoError(t, NewCalculator(store).CalculateAll(ctx))
+
+		hot, ok := store.Score(1)
+		require.True(t, ok)
+		assert.Greater(t, hot.HotScore, 0.0)
+		assert.Equal(t, now, hot.FirstHotAt.Time)
+
+		quiet, ok := store.Score(3)
+		require.True(t, ok)
+		assert.Zero(t, quiet.HotScore)
+		assert.Zero(t, quiet.RisingScore)
+
+		history := store.RankHistory()
+		require.Len(t, history, 1)
+		assert.Equal(t, []int32{1, 2}, history[0].Hot)
+
+		runs := store.Runs()
+		require.Len(t, runs, 1)
+		assert.Equal(t, DefaultParamsVersion, runs[0].ParamsVersion)
+		assert.Equal(t, ParamsSourceTable, runs[0].ParamsSource)
+		assert.Equal(t, int32(3), runs[0].ProcessedCount)
+	})
+
+	t.Run("keeps the live generation when an addon fails scoring", func(t *testing.T) {
+		store := NewMemoryStore(DefaultParams())
+		calc := NewCalculator(store)
+		store.SetInputs(Inputs{Now: now, Percentile95: 500000, Stats: []database.GetAllSnapshotStatsRow{
+			memoryStat(1, 5000, 20), memoryStat(2, 2000, 10),
+		}})
+		require.NoError(t, calc.CalculateAll(ctx))
+		live, ok := store.Score(2)
+		require.True(t, ok)
+
+		broken := memoryStat(2, 2000, 10)
+		broken.DownloadSlope24h = math.Inf(1)
+		store.SetInputs(Inputs{Now: now.Add(time.Hour), Percentile95: 500000, Stats: []database.GetAllSnapshotStatsRow{
+			memoryStat(1, 5000, 40), broken,
+		}})
+		require.ErrorContains(t, calc.CalculateAll(ctx), "including 2")
+
+		assert.Len(t, store.Runs(), 1, "nothing published")
+		kept, ok := store.Score(2)
+		require.True(t, ok)
+		assert.Equal(t, live.HotScore, kept.HotScore)
+		assert.Equal(t, []int32{1, 2}, store.RankHistory()[0].Hot)
+	})
+
+	t.Run("ranks loved by thumbs relative to downloads", func(t *testing.T) {
+		endorsed := memoryStat(1, 3000, 5)
+		endorsed.ThumbsChange24h, endorsed.ThumbsChange7d = 12, 84
+		endorsed.ThumbsSlope24h, endorsed.ThumbsSlope7d = 0.5, 0.5
+		passive := memoryStat(2, 400000, 400)
+		passive.ThumbsChange24h, passive.ThumbsChange7d = 24, 168
+		passive.ThumbsSlope24h, passive.ThumbsSlope7d = 1, 1
+		tiny := memoryStat(3, 300, 1)
+		tiny.ThumbsChange24h, tiny.ThumbsChange7d = 12, 84
+		tiny.ThumbsSlope24h, tiny.ThumbsSlope7d = 0.5, 0.5
+
+		store := NewMemoryStore(DefaultParams())
+		store.SetInputs(Inputs{Now: now, Percentile95: 500000, Stats: []database.GetAllSnapshotStatsRow{endorsed, passive, tiny}})
+		require.NoError(t, NewCalculator(store).CalculateAll(ctx))
+
+		assert.Equal(t, []int32{1, 2}, store.RankHistory()[0].Loved, "tiny addon is below the loved download gate")
+		b, _ := store.Score(1)
+		assert.Equal(t, now, b.FirstLovedAt.Time)
+	})
+
+	t.Run("ranks fresh by traction since creation", func(t *testing.T) {
+		created := func(s database.GetAllSnapshotStatsRow, age time.Duration) database.GetAllSnapshotStatsRow {
+			s.CreatedAt = pgtype.Timestamptz{Time: now.Add(-age), Valid: true}
+			return s
+		}
+		stats := []database.GetAllSnapshotStatsRow{
+			created(memoryStat(1, 600, 5), 3*24*time.Hour),
+			created(memoryStat(2, 600, 5), 20*24*time.Hour),
+			created(memoryStat(3, 600, 5), 60*24*time.Hour),
+			memoryStat(4, 600, 5),
+		}
+
+		store := NewMemoryStore(DefaultParams())
+		store.SetInputs(Inputs{Now: now, Percentile95: 500000, Stats: stats, Updates: map[int32]int32{2: 4}})
+		require.NoError(t, NewCalculator(store).CalculateAll(ctx))
+
+		assert.Equal(t, []int32{1, 2}, store.RankHistory()[0].Fresh, "older and undated addons aren't new")
+		b, _ := store.Score(1)
+		assert.InDelta(t, 600.0/72, b.EarlyVelocity, 0.01)
+	})
+
+	t.Run("keeps list ages and carries unchanged addons forward", func(t *testing.T) {
+		store := NewMemoryStore(DefaultParams())
+		calc := NewCalculator(store)
+		stats := []database.GetAllSnapshotStatsRow{memoryStat(1, 5000, 20), memoryStat(3, 20, 0)}
+
+		store.SetInputs(Inputs{Now: now, Percentile95: 500000, Stats: stats})
+		require.NoError(t, calc.CalculateAll(ctx))
+		quiet, _ := store.Score(3)
+
+		store.SetInputs(Inputs{Now: now.Add(time.Hour), Percentile95: 500000, Stats: stats})
+		require.NoError(t, calc.CalculateAll(ctx))
+
+		hot, _ := store.Score(1)
+		assert.Equal(t, now, hot.FirstHotAt.Time, "age should persist while on the list")
+		assert.InDelta(t, 1, hot.HotAgeHours, 0.001)
+
+		carried, ok := store.Score(3)
+		require.True(t, ok)
+		assert.Equal(t, quiet, carried)
+	})
+
+	t.Run("uses parameter file over the store's set", func(t *testing.T) {
+		p := DefaultParams()
+		p.Version = "file-set"
+		p.MinHotDownloads = 10000
+		data, err := json.Marshal(p)
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "params.json")
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		store := NewMemoryStore(DefaultParams())
+		store.SetInputs(Inputs{Now: now, Percentile95: 500000, Stats: []database.GetAllSnapshotStatsRow{memoryStat(1, 5000, 20)}})
+		calc := NewCalculator(store)
+		calc.SetParamsFile(path)
+		require.NoError(t, calc.CalculateAll(ctx))
+
+		b, _ := store.Score(1)
+		assert.Zero(t, b.HotScore, "below the file's hot download gate")
+		assert.Equal(t, ParamsSourceFile, store.Runs()[0].ParamsSource)
+	})
+
+	t.Run("scores the shadow params without publishing them", func(t *testing.T) {
+		shadow := DefaultParams()
+		shadow.Version = "shadow-set"
+		shadow.MinHotDownloads = 4000
+
+		store := NewMemoryStore(DefaultParams())
+		store.SetShadowParams(shadow)
+		calc := NewCalculator(store)
+		stats := []database.GetAllSnapshotStatsRow{memoryStat(1, 5000, 20), memoryStat(2, 2000, 10)}
+		for i := 0; i < 2; i++ {
+			store.SetInputs(Inputs{Now: now.Add(time.Duration(i) * time.Hour), Percentile95: 500000, Stats: stats})
+			require.NoError(t, calc.CalculateAll(ctx))
+		}
+
+		assert.Equal(t, []int32{1, 2}, store.RankHistory()[1].Hot, "live lists use the live params")
+		comparisons := store.ShadowComparisons()
+		require.Len(t, comparisons, 6)
+		hot := comparisons[3]
+		assert.Equal(t, "hot", hot.List)
+		assert.Equal(t, DefaultParamsVersion, hot.LiveVersion)
+		assert.Equal(t, "shadow-set", hot.ShadowVersion)
+		overlap, err := hot.Overlap.Float64Value()
+		require.NoError(t, err)
+		assert.InDelta(t, 0.5, overlap.Float64, 0.0001, "shadow hot list drops the addon below its gate")
+		assert.False(t, comparisons[0].ShadowChurn.Valid, "no previous shadow list on the first run")
+		assert.True(t, hot.ShadowChurn.Valid)
+	})
+
+	t.Run("ranks within each category", func(t *testing.T) {
+		store := NewMemoryStore(DefaultParams())
+		store.SetInputs(Inputs{
+			Now:          now,
+			Percentile95: 500000,
+			Stats: []database.GetAllSnapshotStatsRow{
+				memoryStat(1, 500000, 400),
+				memoryStat(2, 3000, 10),
+			},
+			CategoryMembers:     map[int32][]int32{7: {2}},
+			CategoryPercentiles: map[int32]float64{7: 3000},
+		})
+
+		require.NoError(t, NewCalculator(store).CalculateAll(ctx))
+
+		overall, _ := store.Score(2)
+		inCategory, ok := store.CategoryScore(7, 2)
+		require.True(t, ok)
+		assert.Greater(t, inCategory.HotScore, overall.HotScore, "size multiplier uses the category percentile")
+		_, ok = store.CategoryScore(7, 1)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,330 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"addon-radar/internal/database"
+)
+
+// PostgresStore is the Store used in production. Generations are staged and
+// then published in one transaction, so readers never see a partially updated
+// generation.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+	db   *database.Queries
+}
+
+// NewPostgresStore creates a store backed by pool.
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool, db: database.New(pool)}
+}
+
+// ResolveParams resolves the parameters with ResolveParams and records them
+// so scores can reference their version.
+func (s *PostgresStore) ResolveParams(ctx context.Context, path string) (Params, string, error) {
+	params, source, err := ResolveParams(ctx, s.db, path)
+	if err != nil {
+		return Params{}, "", err
+	}
+	if err := RegisterParams(ctx, s.db, params); err != nil {
+		return Params{}, "", err
+	}
+	return params, source, nil
+}
+
+// LoadInputs bulk loads snapshot stats, update counts and the live scores.
+func (s *PostgresStore) LoadInputs(ctx context.Context) (Inputs, error) {
+	in := Inputs{Now: time.Now()}
+
+	// Get 95th percentile
+	percentile95, err := s.db.GetDownloadPercentile(ctx)
+	if err != nil {
+		return Inputs{}, err
+	}
+	if percentile95 <= 0 {
+		percentile95 = 500000
+	}
+	in.Percentile95 = percentile95
+	slog.Info("percentile", "p95", percentile95)
+
+	// Bulk fetch all snapshot stats
+	in.Stats, err = s.db.GetAllSnapshotStats(ctx)
+	if err != nil {
+		return Inputs{}, err
+	}
+	slog.Info("loaded snapshot stats", "count", len(in.Stats))
+
+	// Bulk fetch existing trending scores
+	existingScores, err := s.db.GetAllTrendingScores(ctx)
+	if err != nil {
+		return Inputs{}, err
+	}
+	in.Existing = make(map[int32]database.GetAllTrendingScoresRow, len(existingScores))
+	for _, e := range existingScores {
+		in.Existing[e.AddonID] = e
+	}
+	slog.Info("loaded existing scores", "count", len(existingScores))
+
+	// Bulk fetch update counts
+	updateCounts, err := s.db.CountAllRecentFileUpdates(ctx)
+	if err != nil {
+		return Inputs{}, err
+	}
+	in.Updates = make(map[int32]int32, len(updateCounts))
+	for _, u := range updateCounts {
+		in.Updates[u.AddonID] = u.UpdateCount
+	}
+	slog.Info("loaded update counts", "count", len(updateCounts))
+
+	if err := s.loadCategories(ctx, &in); err != nil {
+		return Inputs{}, err
+	}
+	return in, nil
+}
+
+func (s *PostgresStore) loadCategories(ctx context.Context, in *Inputs) error {
+	percentiles, err := s.db.GetCategoryDownloadPercentiles(ctx)
+	if err != nil {
+		return fmt.Errorf("get category percentiles: %w", err)
+	}
+	in.CategoryPercentiles = make(map[int32]float64, len(percentiles))
+	for _, p := range percentiles {
+		in.CategoryPercentiles[p.CategoryID] = p.Percentile95
+	}
+
+	memberships, err := s.db.ListActiveAddonCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("list addon categories: %w", err)
+	}
+	in.CategoryMembers = make(map[int32][]int32)
+	for _, m := range memberships {
+		in.CategoryMembers[m.CategoryID] = append(in.CategoryMembers[m.CategoryID], m.AddonID)
+	}
+
+	existing, err := s.db.GetAllCategoryTrendingScores(ctx)
+	if err != nil {
+		return fmt.Errorf("get category trending scores: %w", err)
+	}
+	in.CategoryExisting = make(map[int32]map[int32]database.GetAllTrendingScoresRow)
+	for _, e := range existing {
+		if in.CategoryExisting[e.CategoryID] == nil {
+			in.CategoryExisting[e.CategoryID] = make(map[int32]database.GetAllTrendingScoresRow)
+		}
+		in.CategoryExisting[e.CategoryID][e.AddonID] = database.GetAllTrendingScoresRow{
+			AddonID:       e.AddonID,
+			FirstHotAt:    e.FirstHotAt,
+			FirstRisingAt: e.FirstRisingAt,
+			HotRank:       e.HotRank,
+			RisingRank:    e.RisingRank,
+			HotMisses:     e.HotMisses,
+			RisingMisses:  e.RisingMisses,
+			// Category lists aren't diversified, so their raw ranks are the displayed ones
+			HotRawRank:    e.HotRank,
+			RisingRawRank: e.RisingRank,
+		}
+	}
+	return nil
+}
+
+// Publish stages g with COPY and then publishes it. The generation it
+// replaces is kept for Rollback.
+func (s *PostgresStore) Publish(ctx context.Context, g Generation) error {
+	if err := s.stage(ctx, g); err != nil {
+		return err
+	}
+	return s.publish(ctx, g.Run)
+}
+
+// stage writes g to the staging tables. Addons skipped as unchanged keep
+// their live row.
+func (s *PostgresStore) stage(ctx context.Context, g Generation) error {
+	calculatedAt := pgtype.Timestamptz{Time: g.CalculatedAt, Valid: true}
+	paramsVersion := pgtype.Text{String: g.Run.ParamsVersion, Valid: true}
+
+	if err := s.db.DeleteStagedTrendingScores(ctx); err != nil {
+		return fmt.Errorf("clear staged scores: %w", err)
+	}
+	rows := make([]database.InsertStagedTrendingScoresParams, len(g.Scores))
+	parallel(len(g.Scores), func(i int) {
+		rows[i] = stagedScore(g.Scores[i], calculatedAt, paramsVersion)
+	})
+	if _, err := s.db.InsertStagedTrendingScores(ctx, rows); err != nil {
+		return fmt.Errorf("stage scores: %w", err)
+	}
+	if len(g.Unchanged) > 0 {
+		if err := s.db.CarryForwardTrendingScores(ctx, g.Unchanged); err != nil {
+			return fmt.Errorf("carry forward unchanged scores: %w", err)
+		}
+	}
+
+	if err := s.db.DeleteStagedCategoryTrendingScores(ctx); err != nil {
+		return fmt.Errorf("clear staged category scores: %w", err)
+	}
+	var categoryRows []database.InsertStagedCategoryTrendingScoresParams
+	for _, cat := range g.Categories {
+		for _, score := range cat.Scores {
+			categoryRows = append(categoryRows, database.InsertStagedCategoryTrendingScoresParams{
+				CategoryID:       cat.CategoryID,
+				AddonID:          score.AddonID,
+				HotScore:         toNumeric(score.HotScore),
+				RisingScore:      toNumeric(score.RisingScore),
+				DownloadVelocity: toNumeric(score.DownloadVelocity),
+				SizeMultiplier:   toNumeric(score.SizeMultiplier),
+				FirstHotAt:       score.FirstHotAt,
+				FirstRisingAt:    score.FirstRisingAt,
+				CalculatedAt:     calculatedAt,
+				HotRank:          listRank(score.HotRank),
+				RisingRank:       listRank(score.RisingRank),
+				HotMisses:        score.HotMisses,
+				RisingMisses:     score.RisingMisses,
+			})
+		}
+	}
+	if _, err := s.db.InsertStagedCategoryTrendingScores(ctx, categoryRows); err != nil {
+		return fmt.Errorf("stage category scores: %w", err)
+	}
+
+	slog.Info("staged trending scores", "scores", len(rows), "unchanged", len(g.Unchanged), "category_scores", len(categoryRows))
+	return nil
+}
+
+func stagedScore(score Breakdown, calculatedAt pgtype.Timestamptz, paramsVersion pgtype.Text) database.InsertStagedTrendingScoresParams {
+	return database.InsertStagedTrendingScoresParams{
+		AddonID:               score.AddonID,
+		HotScore:              toNumeric(score.HotScore),
+		RisingScore:           toNumeric(score.RisingScore),
+		DownloadVelocity:      toNumeric(score.DownloadVelocity),
+		ThumbsVelocity:        toNumeric(score.ThumbsVelocity),
+		DownloadGrowthPct:     toNumeric(score.DownloadGrowthPct),
+		ThumbsGrowthPct:       toNumeric(score.ThumbsGrowthPct),
+		SizeMultiplier:        toNumeric(score.SizeMultiplier),
+		MaintenanceMultiplier: toNumeric(score.MaintenanceMultiplier),
+		FirstHotAt:            score.FirstHotAt,
+		FirstRisingAt:         score.FirstRisingAt,
+		LovedScore:            toNumeric(score.LovedScore),
+		FirstLovedAt:          score.FirstLovedAt,
+		FreshScore:            toNumeric(score.FreshScore),
+		CalculatedAt:          calculatedAt,
+		ParamsVersion:         paramsVersion,
+		InputsHash:            pgtype.Int8{Int64: score.inputsHash, Valid: true},
+		HotRank:               listRank(score.HotRank),
+		RisingRank:            listRank(score.RisingRank),
+		LovedRank:             listRank(score.LovedRank),
+		HotMisses:             score.HotMisses,
+		RisingMisses:          score.RisingMisses,
+		LovedMisses:           score.LovedMisses,
+		HotRawRank:            listRank(score.HotRawRank),
+		RisingRawRank:         listRank(score.RisingRawRank),
+		LovedRawRank:          listRank(score.LovedRawRank),
+	}
+}
+
+// RecordRankHistory records the overall and category lists of g, archives
+// the day's and week's leaderboards, advances list stints, and drops history
+// past its retention.
+func (s *PostgresStore) RecordRankHistory(ctx context.Context, g Generation) error {
+	// Use single batch timestamp for all inserts (ensures consistent snapshots)
+	batchTime := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	// Archives, stints and heat keep what they replace under the live run, for Rollback
+	live, err := s.db.GetTrendingGeneration(ctx, GenerationLive)
+	if err != nil {
+		return fmt.Errorf("get live generation: %w", err)
+	}
+
+	scores := byAddon(g.Scores)
+	if err := s.recordRanks(ctx, "hot", g.Hot, scores, batchTime); err != nil {
+		return err
+	}
+	if err := s.recordRanks(ctx, "rising", g.Rising, scores, batchTime); err != nil {
+		return err
+	}
+	if err := s.recordRanks(ctx, "loved", g.Loved, scores, batchTime); err != nil {
+		return err
+	}
+	if err := s.recordRanks(ctx, "fresh", g.Fresh, scores, batchTime); err != nil {
+		return err
+	}
+	if err := s.archiveLeaderboards(ctx, g, live.RunID, scores, batchTime); err != nil {
+		return err
+	}
+	if err := s.recordStints(ctx, g, live.RunID, batchTime); err != nil {
+		return err
+	}
+	if err := s.recordHeat(ctx, g, live.RunID, batchTime); err != nil {
+		return err
+	}
+	if deleted, err := s.db.DeleteOldRankHistory(ctx); err != nil {
+		slog.Warn("failed to cleanup rank history", "error", err)
+	} else if deleted > 0 {
+		slog.Info("cleaned up old rank history", "deleted", deleted)
+	}
+
+	for _, cat := range g.Categories {
+		scores := byAddon(cat.Scores)
+		if err := s.recordCategoryRanks(ctx, cat.CategoryID, "hot", cat.Hot, scores, batchTime); err != nil {
+			return err
+		}
+		if err := s.recordCategoryRanks(ctx, cat.CategoryID, "rising", cat.Rising, scores, batchTime); err != nil {
+			return err
+		}
+	}
+	if deleted, err := s.db.DeleteOldCategoryRankHistory(ctx); err != nil {
+		slog.Warn("failed to cleanup category rank history", "error", err)
+	} else if deleted > 0 {
+		slog.Info("cleaned up old category rank history", "deleted", deleted)
+	}
+	return nil
+}
+
+func (s *PostgresStore) recordRanks(ctx context.Context, list string, ids []int32, scores map[int32]Breakdown, recordedAt pgtype.Timestamptz) error {
+	for i, id := range ids {
+		err := s.db.InsertRankHistoryWithTime(ctx, database.InsertRankHistoryWithTimeParams{
+			AddonID:    id,
+			Category:   list,
+			Rank:       int16(i + 1), //nolint:gosec // i is bounded by the list size
+			Score:      toNumeric(listScore(list, scores[id])),
+			RecordedAt: recordedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("insert %s rank history: %w", list, err)
+		}
+	}
+	return nil
+}
+
+func (s *PostgresStore) recordCategoryRanks(ctx context.Context, categoryID int32, list string, ids []int32, scores map[int32]Breakdown, recordedAt pgtype.Timestamptz) error {
+	for i, id := range ids {
+		err := s.db.InsertCategoryRankHistory(ctx, database.InsertCategoryRankHistoryParams{
+			CategoryID: categoryID,
+			AddonID:    id,
+			List:       list,
+			Rank:       int16(i + 1), //nolint:gosec // i is bounded by the list size
+			Score:      toNumeric(listScore(list, scores[id])),
+			RecordedAt: recordedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("insert %s rank history for category %d: %w", list, categoryID, err)
+		}
+	}
+	return nil
+}
+
+// listScore is the score b is ranked by on list.
+func listScore(list string, b Breakdown) float64 {
+	switch list {
+	case "rising":
+		return b.RisingScore
+	case "loved":
+		return b.LovedScore
+	case "fresh":
+		return b.FreshScore
+	}
+	return b.HotScore
+}
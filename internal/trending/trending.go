@@ -2,21 +2,6 @@ package trending
 
 import "math"
 
-const (
-	// Hot Right Now weights (total = 1.0)
-	HotDownloadWeight = 0.85
-	HotUpdateWeight   = 0.15
-	UpdateBoost       = 10.0 // Boost value when addon has recent update
-
-	// Rising Stars weights (total = 1.0)
-	RisingGrowthWeight      = 0.70
-	RisingMaintenanceWeight = 0.30
-
-	HotGravity    = 1.5
-	RisingGravity = 1.8
-	AgeOffset     = 2.0 // Prevents division by zero and smooths early decay
-)
-
 // CalculateSizeMultiplier returns a value between 0.1 and 1.0
 // based on logarithmic scaling of downloads against the 95th percentile.
 func CalculateSizeMultiplier(downloads, percentile95 float64) float64 {
@@ -52,45 +37,64 @@ func CalculateMaintenanceMultiplier(updatesIn90Days int) float64 {
 	}
 }
 
-// CalculateVelocity uses confidence-based adaptive windows.
-// Returns (isConfident24h, blendedVelocity).
-func CalculateVelocity(velocity24h, velocity7d float64, dataPoints24h int, change24h int64) (bool, float64) {
-	// Confident if we have enough data points AND meaningful change
-	confident := dataPoints24h >= 5 && change24h >= 10
+// VelocityConfidence rates from 0 to 1 how far a 24h velocity can be trusted.
+// Full confidence takes at least 5 snapshots spread over 18 of the 24 hours,
+// a change of at least 10, and counts that grew steadily (fit is the R² of
+// the regression) rather than in one jump. Each shortfall scales it down.
+func VelocityConfidence(snapshots int, spanHours, fit float64, change int64) float64 {
+	points := clamp(float64(snapshots-1)/4, 0, 1)
+	coverage := clamp(spanHours/18, 0, 1)
+	magnitude := clamp(float64(change)/10, 0, 1)
+	return points * coverage * magnitude * clamp(fit, 0, 1)
+}
 
-	if confident {
-		// Weight toward fresh data
-		return true, (0.8 * velocity24h) + (0.2 * velocity7d)
-	}
-	// Fall back to longer window
-	return false, (0.3 * velocity24h) + (0.7 * velocity7d)
+// CalculateVelocity blends the 24h and 7d velocities, weighting the 24h window
+// from 30% with no confidence up to 80% with full confidence.
+func CalculateVelocity(velocity24h, velocity7d, confidence float64) float64 {
+	weight24h := 0.3 + 0.5*clamp(confidence, 0, 1)
+	return (weight24h * velocity24h) + ((1 - weight24h) * velocity7d)
 }
 
 // CalculateHotScore computes the "Hot Right Now" score.
-// Formula: (hot_signal * size_multiplier * maintenance_multiplier) / (age_hours + 2)^1.5
-func CalculateHotScore(hotSignal, sizeMultiplier, maintenanceMultiplier, ageHours float64) float64 {
+// Formula: (hot_signal * size_multiplier * maintenance_multiplier) / (age_hours + age_offset)^hot_gravity
+func CalculateHotScore(p Params, hotSignal, sizeMultiplier, maintenanceMultiplier, ageHours float64) float64 {
 	numerator := hotSignal * sizeMultiplier * maintenanceMultiplier
-	denominator := math.Pow(ageHours+AgeOffset, HotGravity)
+	denominator := math.Pow(ageHours+p.AgeOffset, p.HotGravity)
 	return numerator / denominator
 }
 
 // CalculateRisingScore computes the "Rising Stars" score.
-// Formula: rising_signal / (age_hours + 2)^1.8
+// Formula: rising_signal / (age_hours + age_offset)^rising_gravity
 // Note: No size multiplier - relative growth already handles this.
 // Note: Maintenance is included in rising_signal, not separate.
-func CalculateRisingScore(risingSignal, ageHours float64) float64 {
-	denominator := math.Pow(ageHours+AgeOffset, RisingGravity)
+func CalculateRisingScore(p Params, risingSignal, ageHours float64) float64 {
+	denominator := math.Pow(ageHours+p.AgeOffset, p.RisingGravity)
 	return risingSignal / denominator
 }
 
+// CalculateLovedScore computes the "Most Loved" score.
+// Formula: loved_signal / (age_hours + age_offset)^loved_gravity
+func CalculateLovedScore(p Params, lovedSignal, ageHours float64) float64 {
+	denominator := math.Pow(ageHours+p.AgeOffset, p.LovedGravity)
+	return lovedSignal / denominator
+}
+
+// CalculateFreshScore computes the "Fresh Releases" score. Its age is the time
+// since the addon was created, not the time it has been listed.
+// Formula: fresh_signal / (hours_since_creation + age_offset)^fresh_gravity
+func CalculateFreshScore(p Params, freshSignal, createdAgeHours float64) float64 {
+	denominator := math.Pow(createdAgeHours+p.AgeOffset, p.FreshGravity)
+	return freshSignal / denominator
+}
+
 // CalculateHotSignal computes the signal for Hot Right Now.
-// Signal blend: 85% downloads + 15% update boost.
-func CalculateHotSignal(downloadSignal float64, hasRecentUpdate bool) float64 {
+// Signal blend: downloads + update boost (85%/15% by default).
+func CalculateHotSignal(p Params, downloadSignal float64, hasRecentUpdate bool) float64 {
 	updateBoost := 0.0
 	if hasRecentUpdate {
-		updateBoost = UpdateBoost
+		updateBoost = p.UpdateBoost
 	}
-	return (HotDownloadWeight * downloadSignal) + (HotUpdateWeight * updateBoost)
+	return (p.HotDownloadWeight * downloadSignal) + (p.HotUpdateWeight * updateBoost)
 }
 
 // CalculateRelativeGrowth computes growth as a fraction of total downloads.
@@ -103,10 +107,28 @@ func CalculateRelativeGrowth(downloadsGained, totalDownloads int64) float64 {
 }
 
 // CalculateRisingSignal computes the signal for Rising Stars.
-// Signal blend: 70% relative growth + 30% maintenance multiplier.
+// Signal blend: relative growth + maintenance multiplier (70%/30% by default).
 // Maintenance is included in signal (not as separate multiplier) for Rising.
-func CalculateRisingSignal(relativeGrowth, maintenanceMultiplier float64) float64 {
-	return (RisingGrowthWeight * relativeGrowth) + (RisingMaintenanceWeight * maintenanceMultiplier)
+func CalculateRisingSignal(p Params, relativeGrowth, maintenanceMultiplier float64) float64 {
+	return (p.RisingGrowthWeight * relativeGrowth) + (p.RisingMaintenanceWeight * maintenanceMultiplier)
+}
+
+// CalculateLovedSignal computes the signal for Most Loved: thumbs-up gained
+// per 1000 downloads gained. The download velocity is padded by
+// loved_download_prior, so endorsement only counts once it is backed by usage.
+func CalculateLovedSignal(p Params, thumbsVelocity, downloadVelocity float64) float64 {
+	if thumbsVelocity <= 0 {
+		return 0.0
+	}
+	return thumbsVelocity * 1000 / (math.Max(downloadVelocity, 0) + p.LovedDownloadPrior)
+}
+
+// CalculateFreshSignal computes the signal for Fresh Releases from an addon's
+// traction since it was created: downloads per hour, thumbs-up and releases.
+func CalculateFreshSignal(p Params, earlyVelocity float64, thumbs, releases int) float64 {
+	return (p.FreshVelocityWeight * earlyVelocity) +
+		(p.FreshThumbsWeight * float64(thumbs)) +
+		(p.FreshReleaseWeight * float64(releases))
 }
 
 func clamp(v, min, max float64) float64 {
@@ -52,51 +52,67 @@ func TestCalculateMaintenanceMultiplier(t *testing.T) {
 	}
 }
 
+func TestVelocityConfidence(t *testing.T) {
+	tests := []struct {
+		name      string
+		snapshots int
+		spanHours float64
+		fit       float64
+		change    int64
+		want      float64
+	}{
+		{"hourly snapshots, steady growth", 24, 23, 0.98, 500, 0.98},
+		{"few snapshots", 3, 23, 1, 500, 0.5},
+		{"snapshots cover a third of the day", 24, 6, 1, 500, 1.0 / 3},
+		{"small change", 24, 23, 1, 5, 0.5},
+		{"one jump", 24, 23, 0.25, 500, 0.25},
+		{"no snapshots", 0, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VelocityConfidence(tt.snapshots, tt.spanHours, tt.fit, tt.change)
+			if math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("VelocityConfidence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCalculateVelocity(t *testing.T) {
 	tests := []struct {
-		name          string
-		velocity24h   float64
-		velocity7d    float64
-		dataPoints24h int
-		change24h     int64
-		wantConfident bool
-		wantVelocity  float64
+		name         string
+		velocity24h  float64
+		velocity7d   float64
+		confidence   float64
+		wantVelocity float64
 	}{
 		{
-			name:          "confident 24h - enough data and change",
-			velocity24h:   100.0,
-			velocity7d:    50.0,
-			dataPoints24h: 10,
-			change24h:     500,
-			wantConfident: true,
-			wantVelocity:  90.0, // 0.8 * 100 + 0.2 * 50
+			name:         "full confidence leans on 24h",
+			velocity24h:  100.0,
+			velocity7d:   50.0,
+			confidence:   1,
+			wantVelocity: 90.0, // 0.8 * 100 + 0.2 * 50
 		},
 		{
-			name:          "not confident - few data points",
-			velocity24h:   100.0,
-			velocity7d:    50.0,
-			dataPoints24h: 3,
-			change24h:     500,
-			wantConfident: false,
-			wantVelocity:  65.0, // 0.3 * 100 + 0.7 * 50
+			name:         "no confidence falls back to 7d",
+			velocity24h:  100.0,
+			velocity7d:   50.0,
+			confidence:   0,
+			wantVelocity: 65.0, // 0.3 * 100 + 0.7 * 50
 		},
 		{
-			name:          "not confident - small change",
-			velocity24h:   100.0,
-			velocity7d:    50.0,
-			dataPoints24h: 10,
-			change24h:     5,
-			wantConfident: false,
-			wantVelocity:  65.0, // 0.3 * 100 + 0.7 * 50
+			name:         "half confidence",
+			velocity24h:  100.0,
+			velocity7d:   50.0,
+			confidence:   0.5,
+			wantVelocity: 77.5, // 0.55 * 100 + 0.45 * 50
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			confident, velocity := CalculateVelocity(tt.velocity24h, tt.velocity7d, tt.dataPoints24h, tt.change24h)
-			if confident != tt.wantConfident {
-				t.Errorf("confident = %v, want %v", confident, tt.wantConfident)
-			}
+			velocity := CalculateVelocity(tt.velocity24h, tt.velocity7d, tt.confidence)
 			if math.Abs(velocity-tt.wantVelocity) > 0.01 {
 				t.Errorf("velocity = %v, want %v", velocity, tt.wantVelocity)
 			}
@@ -133,7 +149,7 @@ func TestCalculateHotScore(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := CalculateHotScore(tt.hotSignal, tt.sizeMultiplier, tt.maintenanceMultiplier, tt.ageHours)
+			got := CalculateHotScore(DefaultParams(), tt.hotSignal, tt.sizeMultiplier, tt.maintenanceMultiplier, tt.ageHours)
 			if math.Abs(got-tt.want) > 0.1 {
 				t.Errorf("CalculateHotScore() = %v, want %v", got, tt.want)
 			}
@@ -164,7 +180,7 @@ func TestCalculateRisingScore(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := CalculateRisingScore(tt.risingSignal, tt.ageHours)
+			got := CalculateRisingScore(DefaultParams(), tt.risingSignal, tt.ageHours)
 			if math.Abs(got-tt.want) > 0.01 {
 				t.Errorf("CalculateRisingScore() = %v, want %v", got, tt.want)
 			}
@@ -201,7 +217,7 @@ func TestCalculateHotSignal(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := CalculateHotSignal(tt.downloadSignal, tt.hasUpdate)
+			got := CalculateHotSignal(DefaultParams(), tt.downloadSignal, tt.hasUpdate)
 			if math.Abs(got-tt.want) > 0.01 {
 				t.Errorf("CalculateHotSignal() = %v, want %v", got, tt.want)
 			}
@@ -275,10 +291,100 @@ func TestCalculateRisingSignal(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := CalculateRisingSignal(tt.relativeGrowth, tt.maintenanceMultiplier)
+			got := CalculateRisingSignal(DefaultParams(), tt.relativeGrowth, tt.maintenanceMultiplier)
 			if math.Abs(got-tt.want) > 0.01 {
 				t.Errorf("CalculateRisingSignal() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestCalculateLovedSignal(t *testing.T) {
+	tests := []struct {
+		name             string
+		thumbsVelocity   float64
+		downloadVelocity float64
+		want             float64
+	}{
+		{
+			name:             "endorsed niche addon",
+			thumbsVelocity:   0.5,
+			downloadVelocity: 45,
+			want:             10, // 0.5*1000 / (45+5)
+		},
+		{
+			name:             "large passive addon",
+			thumbsVelocity:   0.5,
+			downloadVelocity: 995,
+			want:             0.5, // 0.5*1000 / (995+5)
+		},
+		{
+			name:             "thumbs without downloads lean on the prior",
+			thumbsVelocity:   0.1,
+			downloadVelocity: 0,
+			want:             20, // 0.1*1000 / 5
+		},
+		{
+			name:             "no new thumbs",
+			thumbsVelocity:   0,
+			downloadVelocity: 100,
+			want:             0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateLovedSignal(DefaultParams(), tt.thumbsVelocity, tt.downloadVelocity)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("CalculateLovedSignal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateLovedScore(t *testing.T) {
+	got := CalculateLovedScore(DefaultParams(), 10, 0)
+	want := 3.54 // 10 / (0+2)^1.5
+	if math.Abs(got-want) > 0.01 {
+		t.Errorf("CalculateLovedScore() = %v, want %v", got, want)
+	}
+}
+
+func TestCalculateFreshSignal(t *testing.T) {
+	got := CalculateFreshSignal(DefaultParams(), 10, 5, 3)
+	want := 8.3 // 0.7*10 + 0.2*5 + 0.1*3
+	if math.Abs(got-want) > 0.01 {
+		t.Errorf("CalculateFreshSignal() = %v, want %v", got, want)
+	}
+}
+
+func TestCalculateFreshScore(t *testing.T) {
+	tests := []struct {
+		name            string
+		freshSignal     float64
+		createdAgeHours float64
+		want            float64
+	}{
+		{
+			name:            "created today",
+			freshSignal:     8.3,
+			createdAgeHours: 6,
+			want:            1.57, // 8.3 / (6+2)^0.8
+		},
+		{
+			name:            "created last week",
+			freshSignal:     8.3,
+			createdAgeHours: 166,
+			want:            0.14, // 8.3 / (166+2)^0.8
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateFreshScore(DefaultParams(), tt.freshSignal, tt.createdAgeHours)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("CalculateFreshScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -49,19 +49,33 @@ SELECT COUNT(*) FROM addons WHERE status = 'active';
 SELECT COUNT(*) FROM snapshots;
 
 -- name: UpsertCategory :exec
-INSERT INTO categories (id, name, slug, parent_id, icon_url)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO categories (id, name, slug, parent_id, icon_url, class_id, is_class, display_index)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (id) DO UPDATE SET
     name = EXCLUDED.name,
     slug = EXCLUDED.slug,
     parent_id = EXCLUDED.parent_id,
-    icon_url = EXCLUDED.icon_url;
+    icon_url = EXCLUDED.icon_url,
+    class_id = EXCLUDED.class_id,
+    is_class = EXCLUDED.is_class,
+    display_index = EXCLUDED.display_index,
+    deleted_at = NULL;
+
+-- name: SoftDeleteMissingCategories :execrows
+-- Soft-delete categories that CurseForge no longer returns
+UPDATE categories
+SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND NOT (id = ANY(sqlc.arg(category_ids)::integer[]));
 
 -- name: ListAddons :many
+-- Sort is 'heat' for heat index first, otherwise by downloads
 SELECT * FROM addons
 WHERE status = 'active'
-ORDER BY download_count DESC
-LIMIT $1 OFFSET $2;
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'heat' THEN heat_index END DESC NULLS LAST,
+    download_count DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountActiveAddons :one
 SELECT COUNT(*) FROM addons WHERE status = 'active';
@@ -69,9 +83,11 @@ SELECT COUNT(*) FROM addons WHERE status = 'active';
 -- name: ListAddonsByCategory :many
 SELECT a.* FROM addons a
 WHERE a.status = 'active'
-  AND $3::int = ANY(a.categories)
-ORDER BY a.download_count DESC
-LIMIT $1 OFFSET $2;
+  AND sqlc.arg(category_id)::int = ANY(a.categories)
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'heat' THEN a.heat_index END DESC NULLS LAST,
+    a.download_count DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountAddonsByCategory :one
 SELECT COUNT(*) FROM addons
@@ -81,9 +97,11 @@ WHERE status = 'active'
 -- name: SearchAddons :many
 SELECT * FROM addons
 WHERE status = 'active'
-  AND (name ILIKE '%' || $3 || '%' OR summary ILIKE '%' || $3 || '%')
-ORDER BY download_count DESC
-LIMIT $1 OFFSET $2;
+  AND (name ILIKE '%' || sqlc.arg(search) || '%' OR summary ILIKE '%' || sqlc.arg(search) || '%')
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'heat' THEN heat_index END DESC NULLS LAST,
+    download_count DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountSearchAddons :one
 SELECT COUNT(*) FROM addons
@@ -97,11 +115,34 @@ WHERE addon_id = $1
 ORDER BY recorded_at DESC
 LIMIT $2;
 
+-- name: GetLatestSnapshot :one
+SELECT recorded_at, download_count, thumbs_up_count
+FROM snapshots
+WHERE addon_id = $1
+ORDER BY recorded_at DESC
+LIMIT 1;
+
 -- name: ListCategories :many
-SELECT * FROM categories ORDER BY name;
+SELECT * FROM categories WHERE deleted_at IS NULL ORDER BY name;
 
 -- name: GetCategoryBySlug :one
-SELECT * FROM categories WHERE slug = $1;
+SELECT * FROM categories WHERE slug = $1 AND deleted_at IS NULL;
+
+-- name: GetCategoryAddonStats :one
+-- Addon count and total downloads for active addons in any of the given categories
+SELECT
+    COUNT(*) AS addon_count,
+    COALESCE(SUM(download_count), 0)::bigint AS total_downloads
+FROM addons
+WHERE status = 'active'
+  AND categories && sqlc.arg(category_ids)::integer[];
+
+-- name: ListTopAddonsInCategories :many
+SELECT * FROM addons
+WHERE status = 'active'
+  AND categories && sqlc.arg(category_ids)::integer[]
+ORDER BY download_count DESC
+LIMIT sqlc.arg(limit_count);
 
 -- name: GetSnapshotStats :one
 -- Gets download/thumbs changes for velocity calculation
@@ -133,26 +174,40 @@ WHERE addon_id = $1
   AND recorded_at >= NOW() - ($2 || ' days')::INTERVAL
   AND latest_file_date IS NOT NULL;
 
--- name: UpsertTrendingScore :exec
-INSERT INTO trending_scores (
+-- name: InsertStagedTrendingScores :copyfrom
+INSERT INTO trending_scores_staging (
     addon_id, hot_score, rising_score,
     download_velocity, thumbs_velocity,
     download_growth_pct, thumbs_growth_pct,
     size_multiplier, maintenance_multiplier,
-    first_hot_at, first_rising_at, calculated_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
-ON CONFLICT (addon_id) DO UPDATE SET
-    hot_score = EXCLUDED.hot_score,
-    rising_score = EXCLUDED.rising_score,
-    download_velocity = EXCLUDED.download_velocity,
-    thumbs_velocity = EXCLUDED.thumbs_velocity,
-    download_growth_pct = EXCLUDED.download_growth_pct,
-    thumbs_growth_pct = EXCLUDED.thumbs_growth_pct,
-    size_multiplier = EXCLUDED.size_multiplier,
-    maintenance_multiplier = EXCLUDED.maintenance_multiplier,
-    first_hot_at = COALESCE(EXCLUDED.first_hot_at, trending_scores.first_hot_at),
-    first_rising_at = COALESCE(EXCLUDED.first_rising_at, trending_scores.first_rising_at),
-    calculated_at = NOW();
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+    $18, $19, $20, $21, $22, $23, $24, $25, $26
+);
+
+-- name: CarryForwardTrendingScores :exec
+-- Stage the live rows of addons that were skipped because their inputs didn't change
+INSERT INTO trending_scores_staging (
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+)
+SELECT
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_scores
+WHERE addon_id = ANY(sqlc.arg(addon_ids)::integer[]);
 
 -- name: GetTrendingScore :one
 SELECT * FROM trending_scores WHERE addon_id = $1;
@@ -162,27 +217,28 @@ SELECT a.*, t.hot_score, t.download_velocity
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 500
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
   AND t.hot_score > 0
-ORDER BY t.hot_score DESC
-LIMIT $1;
+ORDER BY t.hot_rank NULLS LAST, t.hot_score DESC
+LIMIT sqlc.arg(limit_count);
 
 -- name: ListHotAddonsPaginated :many
-SELECT a.*, t.hot_score, t.download_velocity
+SELECT sqlc.embed(a), t.hot_score, t.download_velocity
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 500
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
   AND t.hot_score > 0
-ORDER BY t.hot_score DESC
-LIMIT $1 OFFSET $2;
+ORDER BY CASE WHEN sqlc.arg(raw_order)::boolean THEN t.hot_raw_rank ELSE t.hot_rank END NULLS LAST,
+    t.hot_score DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
 
 -- name: CountHotAddons :one
 SELECT COUNT(*)
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 500
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
   AND t.hot_score > 0;
 
 -- name: ListRisingAddons :many
@@ -190,71 +246,110 @@ SELECT a.*, t.rising_score, t.download_velocity
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 50
-  AND a.download_count <= 10000
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND a.download_count <= sqlc.arg(max_downloads)::bigint
   AND t.rising_score > 0
   AND a.id NOT IN (
       SELECT addon_id FROM trending_scores
       WHERE hot_score > 0
-      ORDER BY hot_score DESC
-      LIMIT 20
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT sqlc.arg(hot_list_size)
   )
-ORDER BY t.rising_score DESC
-LIMIT $1;
+ORDER BY t.rising_rank NULLS LAST, t.rising_score DESC
+LIMIT sqlc.arg(limit_count);
 
 -- name: ListRisingAddonsPaginated :many
-SELECT a.*, t.rising_score, t.download_velocity
+SELECT sqlc.embed(a), t.rising_score, t.download_velocity
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 50
-  AND a.download_count <= 10000
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND a.download_count <= sqlc.arg(max_downloads)::bigint
   AND t.rising_score > 0
   AND a.id NOT IN (
       SELECT addon_id FROM trending_scores
       WHERE hot_score > 0
-      ORDER BY hot_score DESC
-      LIMIT 20
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT sqlc.arg(hot_list_size)
   )
-ORDER BY t.rising_score DESC
-LIMIT $1 OFFSET $2;
+ORDER BY CASE WHEN sqlc.arg(raw_order)::boolean THEN t.rising_raw_rank ELSE t.rising_rank END NULLS LAST,
+    t.rising_score DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
 
 -- name: CountRisingAddons :one
 SELECT COUNT(*)
 FROM addons a
 JOIN trending_scores t ON a.id = t.addon_id
 WHERE a.status = 'active'
-  AND a.download_count >= 50
-  AND a.download_count <= 10000
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND a.download_count <= sqlc.arg(max_downloads)::bigint
   AND t.rising_score > 0
   AND a.id NOT IN (
       SELECT addon_id FROM trending_scores
       WHERE hot_score > 0
-      ORDER BY hot_score DESC
-      LIMIT 20
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT sqlc.arg(hot_list_size)
   );
 
--- name: ClearTrendingAgeForDroppedAddons :exec
--- Reset first_hot_at for addons that dropped out of hot list
-UPDATE trending_scores
-SET first_hot_at = NULL
-WHERE addon_id NOT IN (
-    SELECT addon_id FROM trending_scores
-    WHERE hot_score > 0
-    ORDER BY hot_score DESC
-    LIMIT 20
-);
+-- name: ListLovedAddons :many
+SELECT a.*, t.loved_score, t.download_velocity, t.thumbs_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.loved_score > 0
+ORDER BY t.loved_rank NULLS LAST, t.loved_score DESC
+LIMIT sqlc.arg(limit_count);
 
--- name: ClearRisingAgeForDroppedAddons :exec
--- Reset first_rising_at for addons that dropped out of rising list
-UPDATE trending_scores
-SET first_rising_at = NULL
-WHERE addon_id NOT IN (
-    SELECT addon_id FROM trending_scores
-    WHERE rising_score > 0
-    ORDER BY rising_score DESC
-    LIMIT 20
-);
+-- name: ListLovedAddonsPaginated :many
+SELECT sqlc.embed(a), t.loved_score, t.download_velocity, t.thumbs_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.loved_score > 0
+ORDER BY CASE WHEN sqlc.arg(raw_order)::boolean THEN t.loved_raw_rank ELSE t.loved_rank END NULLS LAST,
+    t.loved_score DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: CountLovedAddons :one
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.loved_score > 0;
+
+-- name: ListFreshAddons :many
+SELECT a.*, t.fresh_score, t.download_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.fresh_score > 0
+ORDER BY t.fresh_score DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListFreshAddonsPaginated :many
+-- Fresh list entries created since the given time
+SELECT sqlc.embed(a), t.fresh_score, t.download_velocity
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.fresh_score > 0
+  AND a.created_at >= sqlc.arg(created_after)::timestamptz
+ORDER BY t.fresh_score DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: CountFreshAddons :one
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.fresh_score > 0
+  AND a.created_at >= sqlc.arg(created_after)::timestamptz;
 
 -- name: ListAddonsForTrendingCalc :many
 -- Get addons with basic info needed for trending calculation
@@ -263,14 +358,21 @@ FROM addons
 WHERE status = 'active';
 
 -- name: GetAllSnapshotStats :many
--- Bulk fetch snapshot stats for all addons in both time windows
+-- Bulk fetch snapshot stats for all addons in both time windows. Slopes are
+-- least-squares fits of the counts against snapshot time, in units per hour;
+-- fits are their R² and span_hours_24h how much of the day the snapshots cover.
 WITH stats_24h AS (
     SELECT
         addon_id,
         COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
         COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
         COUNT(*)::int AS snapshot_count,
-        MIN(download_count)::bigint AS min_downloads
+        MIN(download_count)::bigint AS min_downloads,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope,
+        regr_r2(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_fit,
+        regr_r2(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_fit,
+        EXTRACT(EPOCH FROM MAX(recorded_at) - MIN(recorded_at))::float8 / 3600 AS span_hours
     FROM snapshots
     WHERE recorded_at >= NOW() - INTERVAL '24 hours'
     GROUP BY addon_id
@@ -280,7 +382,9 @@ stats_7d AS (
         addon_id,
         COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
         COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
-        MIN(download_count)::bigint AS min_downloads
+        MIN(download_count)::bigint AS min_downloads,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope
     FROM snapshots
     WHERE recorded_at >= NOW() - INTERVAL '7 days'
     GROUP BY addon_id
@@ -291,12 +395,21 @@ SELECT
     a.thumbs_up_count,
     a.latest_file_date,
     a.created_at,
+    a.author_id,
+    a.primary_category_id,
     COALESCE(s24.download_change, 0) AS download_change_24h,
     COALESCE(s24.thumbs_change, 0) AS thumbs_change_24h,
     COALESCE(s24.snapshot_count, 0) AS snapshot_count_24h,
     COALESCE(s7.download_change, 0) AS download_change_7d,
     COALESCE(s7.thumbs_change, 0) AS thumbs_change_7d,
-    COALESCE(s7.min_downloads, a.download_count) AS min_downloads_7d
+    COALESCE(s7.min_downloads, a.download_count) AS min_downloads_7d,
+    COALESCE(s24.download_slope, 0)::float8 AS download_slope_24h,
+    COALESCE(s24.thumbs_slope, 0)::float8 AS thumbs_slope_24h,
+    COALESCE(s24.download_fit, 0)::float8 AS download_fit_24h,
+    COALESCE(s24.thumbs_fit, 0)::float8 AS thumbs_fit_24h,
+    COALESCE(s24.span_hours, 0)::float8 AS span_hours_24h,
+    COALESCE(s7.download_slope, 0)::float8 AS download_slope_7d,
+    COALESCE(s7.thumbs_slope, 0)::float8 AS thumbs_slope_7d
 FROM addons a
 LEFT JOIN stats_24h s24 ON a.id = s24.addon_id
 LEFT JOIN stats_7d s7 ON a.id = s7.addon_id
@@ -304,7 +417,23 @@ WHERE a.status = 'active';
 
 -- name: GetAllTrendingScores :many
 -- Bulk fetch all existing trending scores
-SELECT addon_id, first_hot_at, first_rising_at
+SELECT
+    addon_id,
+    first_hot_at,
+    first_rising_at,
+    first_loved_at,
+    inputs_hash,
+    hot_rank,
+    rising_rank,
+    loved_rank,
+    hot_misses,
+    rising_misses,
+    loved_misses,
+    hot_raw_rank,
+    rising_raw_rank,
+    loved_raw_rank,
+    (COALESCE(hot_score, 0) = 0 AND COALESCE(rising_score, 0) = 0 AND COALESCE(loved_score, 0) = 0
+     AND COALESCE(fresh_score, 0) = 0)::boolean AS unscored
 FROM trending_scores;
 
 -- name: CountAllRecentFileUpdates :many
@@ -333,13 +462,240 @@ WHERE id IN (
 SELECT COUNT(*) FROM snapshots
 WHERE recorded_at < NOW() - INTERVAL '95 days';
 
+-- name: ListAddonsForDiff :many
+-- Current addon state for comparing against a dry-run sync
+SELECT id, name, slug, status, categories, download_count
+FROM addons;
+
 -- name: MarkMissingAddonsInactive :execrows
 -- Mark addons as inactive if they no longer appear in CurseForge API response
-WITH synced_ids AS (SELECT unnest($1::integer[]) AS id)
+-- and record the transition with their last known stats
+WITH synced_ids AS (SELECT unnest($1::integer[]) AS id),
+marked AS (
+    UPDATE addons
+    SET status = 'inactive', last_synced_at = NOW()
+    WHERE status = 'active'
+      AND NOT EXISTS (SELECT 1 FROM synced_ids WHERE synced_ids.id = addons.id)
+    RETURNING id, download_count, thumbs_up_count
+)
+INSERT INTO addon_status_events (addon_id, from_status, to_status, reason, download_count, thumbs_up_count)
+SELECT id, 'active', 'inactive', 'missing_from_api', download_count, thumbs_up_count
+FROM marked;
+
+-- name: ListInactiveAddonIDs :many
+SELECT id FROM addons WHERE status = 'inactive';
+
+-- name: RecordAddonReactivation :execrows
+-- Record that a previously inactive addon reappeared in the CurseForge API.
+-- Must run before UpsertAddon, which flips the status back to active.
+INSERT INTO addon_status_events (addon_id, from_status, to_status, reason, download_count, thumbs_up_count)
+SELECT id, status, 'active', 'reappeared_in_api', download_count, thumbs_up_count
+FROM addons
+WHERE id = $1 AND status <> 'active';
+
+-- name: ListRemovedAddons :many
+-- Addons that vanished from CurseForge, most recently removed first.
+-- Falls back to last_synced_at for addons marked inactive before events were recorded.
+WITH last_removal AS (
+    SELECT DISTINCT ON (addon_id) addon_id, occurred_at
+    FROM addon_status_events
+    WHERE to_status = 'inactive'
+    ORDER BY addon_id, occurred_at DESC
+)
+SELECT sqlc.embed(a), COALESCE(r.occurred_at, a.last_synced_at)::timestamptz AS removed_at
+FROM addons a
+LEFT JOIN last_removal r ON r.addon_id = a.id
+WHERE a.status = 'inactive'
+ORDER BY removed_at DESC, a.id
+LIMIT $1 OFFSET $2;
+
+-- name: CountRemovedAddons :one
+SELECT COUNT(*) FROM addons WHERE status = 'inactive';
+
+-- name: ListReactivatedAddons :many
+-- Addons that came back after being missing, most recent return first
+SELECT sqlc.embed(a), e.occurred_at AS returned_at,
+    (
+        SELECT MAX(p.occurred_at) FROM addon_status_events p
+        WHERE p.addon_id = e.addon_id
+          AND p.to_status = 'inactive'
+          AND p.occurred_at < e.occurred_at
+    )::timestamptz AS removed_at
+FROM addon_status_events e
+JOIN addons a ON a.id = e.addon_id
+WHERE e.to_status = 'active'
+  AND a.status = 'active'
+ORDER BY e.occurred_at DESC, e.id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountReactivatedAddons :one
+SELECT COUNT(*)
+FROM addon_status_events e
+JOIN addons a ON a.id = e.addon_id
+WHERE e.to_status = 'active'
+  AND a.status = 'active';
+
+-- name: ListComebackCandidates :many
+-- Addons with a file released since released_after, with their downloads
+-- before and since that release as seen in snapshots. Releases already
+-- recorded as comebacks are skipped.
+WITH released AS (
+    SELECT id AS addon_id, latest_file_date
+    FROM addons
+    WHERE status = 'active'
+      AND latest_file_date >= sqlc.arg(released_after)::timestamptz
+),
+before_release AS (
+    SELECT
+        s.addon_id,
+        MAX(s.latest_file_date)::timestamptz AS previous_release_date,
+        (MAX(s.download_count) - MIN(s.download_count))::bigint AS download_change,
+        (EXTRACT(EPOCH FROM MAX(s.recorded_at) - MIN(s.recorded_at)) / 3600)::float8 AS hours
+    FROM snapshots s
+    JOIN released r ON r.addon_id = s.addon_id
+    WHERE s.latest_file_date < r.latest_file_date
+    GROUP BY s.addon_id
+),
+since_release AS (
+    SELECT
+        s.addon_id,
+        (MAX(s.download_count) - MIN(s.download_count))::bigint AS download_change,
+        (EXTRACT(EPOCH FROM MAX(s.recorded_at) - MIN(s.recorded_at)) / 3600)::float8 AS hours
+    FROM snapshots s
+    JOIN released r ON r.addon_id = s.addon_id
+    WHERE s.latest_file_date >= r.latest_file_date
+    GROUP BY s.addon_id
+)
+SELECT
+    r.addon_id,
+    r.latest_file_date AS release_date,
+    b.previous_release_date,
+    b.download_change AS dormant_download_change,
+    b.hours AS dormant_hours,
+    s.download_change AS revived_download_change,
+    s.hours AS revived_hours
+FROM released r
+JOIN before_release b ON b.addon_id = r.addon_id
+JOIN since_release s ON s.addon_id = r.addon_id
+WHERE NOT EXISTS (
+    SELECT 1 FROM comeback_events e
+    WHERE e.addon_id = r.addon_id AND e.release_date = r.latest_file_date
+);
+
+-- name: RecordComeback :execrows
+-- Record a comeback event and mark the addon; does nothing if the release was
+-- already recorded
+WITH inserted AS (
+    INSERT INTO comeback_events (addon_id, release_date, previous_release_date, dormant_velocity, revived_velocity)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (addon_id, release_date) DO NOTHING
+    RETURNING addon_id, detected_at
+)
 UPDATE addons
-SET status = 'inactive', last_synced_at = NOW()
-WHERE status = 'active'
-  AND NOT EXISTS (SELECT 1 FROM synced_ids WHERE synced_ids.id = addons.id);
+SET comeback_at = inserted.detected_at
+FROM inserted
+WHERE addons.id = inserted.addon_id;
+
+-- name: ListComebacks :many
+-- Comebacks detected since the given time, most recent first
+SELECT sqlc.embed(a), e.release_date, e.previous_release_date, e.dormant_velocity, e.revived_velocity, e.detected_at
+FROM comeback_events e
+JOIN addons a ON a.id = e.addon_id
+WHERE a.status = 'active'
+  AND e.detected_at >= sqlc.arg(since)::timestamptz
+ORDER BY e.detected_at DESC, e.id DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: CountComebacks :one
+SELECT COUNT(*)
+FROM comeback_events e
+JOIN addons a ON a.id = e.addon_id
+WHERE a.status = 'active'
+  AND e.detected_at >= sqlc.arg(since)::timestamptz;
+
+-- name: ListHourlyDownloads :many
+-- Highest download count in each hour since the given time, for every active
+-- addon, as arrays in hour order. Hours are counted from the Unix epoch.
+SELECT
+    h.addon_id,
+    array_agg(h.hour ORDER BY h.hour)::bigint[] AS hours,
+    array_agg(h.download_count ORDER BY h.hour)::bigint[] AS download_counts
+FROM (
+    SELECT
+        s.addon_id,
+        FLOOR(EXTRACT(EPOCH FROM s.recorded_at) / 3600)::bigint AS hour,
+        MAX(s.download_count) AS download_count
+    FROM snapshots s
+    JOIN addons a ON a.id = s.addon_id
+    WHERE a.status = 'active'
+      AND s.recorded_at >= sqlc.arg(since)::timestamptz
+    GROUP BY s.addon_id, hour
+) h
+GROUP BY h.addon_id;
+
+-- name: DeleteAddonForecasts :exec
+DELETE FROM addon_forecasts;
+
+-- name: InsertAddonForecasts :copyfrom
+INSERT INTO addon_forecasts (
+    addon_id, forecast_from, download_count, history_hours,
+    projected_24h, low_24h, high_24h, projected_7d, low_7d, high_7d, calculated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+);
+
+-- name: GetAddonForecast :one
+SELECT * FROM addon_forecasts WHERE addon_id = $1;
+
+-- name: ListAddonsProjectedToCross :many
+-- Active addons still below the given download count that are projected to
+-- reach it within 7 days, nearest to the count first
+SELECT sqlc.embed(a), f.projected_24h, f.projected_7d, f.low_7d, f.high_7d, f.calculated_at
+FROM addon_forecasts f
+JOIN addons a ON a.id = f.addon_id
+WHERE a.status = 'active'
+  AND a.download_count < sqlc.arg(downloads)::bigint
+  AND f.projected_7d >= sqlc.arg(downloads)::bigint
+ORDER BY a.download_count DESC, a.id
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: CountAddonsProjectedToCross :one
+SELECT COUNT(*)
+FROM addon_forecasts f
+JOIN addons a ON a.id = f.addon_id
+WHERE a.status = 'active'
+  AND a.download_count < sqlc.arg(downloads)::bigint
+  AND f.projected_7d >= sqlc.arg(downloads)::bigint;
+
+-- name: RecordMilestone :execrows
+-- Record a crossed milestone; does nothing if the addon already crossed it
+INSERT INTO milestones (addon_id, metric, threshold, crossed_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (addon_id, metric, threshold) DO NOTHING;
+
+-- name: ListMilestones :many
+-- Milestones of active addons, most recently crossed first. An empty metric
+-- lists every metric.
+SELECT sqlc.embed(a), m.metric, m.threshold, m.crossed_at
+FROM milestones m
+JOIN addons a ON a.id = m.addon_id
+WHERE a.status = 'active'
+  AND (sqlc.arg(metric)::text = '' OR m.metric = sqlc.arg(metric)::text)
+ORDER BY m.crossed_at DESC, m.id DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: CountMilestones :one
+SELECT COUNT(*)
+FROM milestones m
+JOIN addons a ON a.id = m.addon_id
+WHERE a.status = 'active'
+  AND (sqlc.arg(metric)::text = '' OR m.metric = sqlc.arg(metric)::text);
+
+-- name: ListAddonMilestones :many
+SELECT metric, threshold, crossed_at
+FROM milestones
+WHERE addon_id = $1
+ORDER BY crossed_at DESC, id DESC;
 
 -- name: InsertRankHistory :exec
 -- Record current rank for an addon in a category (deprecated: use InsertRankHistoryWithTime)
@@ -365,6 +721,123 @@ LIMIT 1;
 DELETE FROM trending_rank_history
 WHERE recorded_at < NOW() - INTERVAL '8 days';
 
+-- name: DeleteArchivedLeaderboard :exec
+-- Clear an archived list before archiving its replacement
+DELETE FROM leaderboard_archive
+WHERE period = $1 AND period_start = $2 AND list = $3;
+
+-- name: InsertArchivedLeaderboardEntry :exec
+INSERT INTO leaderboard_archive (period, period_start, list, rank, addon_id, score, recorded_at, run_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: DeletePreviousLeaderboards :exec
+DELETE FROM leaderboard_archive_previous;
+
+-- name: KeepArchivedLeaderboard :exec
+-- Keep an archived list before the given run replaces it
+INSERT INTO leaderboard_archive_previous (period, period_start, list, rank, addon_id, score, recorded_at, run_id, replaced_by_run_id)
+SELECT period, period_start, list, rank, addon_id, score, recorded_at, run_id, sqlc.arg(replaced_by_run_id)
+FROM leaderboard_archive
+WHERE period = sqlc.arg(period) AND period_start = sqlc.arg(period_start) AND list = sqlc.arg(list);
+
+-- name: DeleteArchivedLeaderboardsOfRun :execrows
+DELETE FROM leaderboard_archive WHERE run_id = $1;
+
+-- name: RestorePreviousLeaderboards :execrows
+-- Put back the archived lists the given run replaced
+INSERT INTO leaderboard_archive (period, period_start, list, rank, addon_id, score, recorded_at, run_id)
+SELECT period, period_start, list, rank, addon_id, score, recorded_at, run_id
+FROM leaderboard_archive_previous
+WHERE replaced_by_run_id = $1;
+
+-- name: ListArchivedLeaderboard :many
+-- An archived list with its addons, by rank
+SELECT sqlc.embed(a), l.rank, l.score, l.recorded_at
+FROM leaderboard_archive l
+JOIN addons a ON a.id = l.addon_id
+WHERE l.period = $1 AND l.period_start = $2 AND l.list = $3
+ORDER BY l.rank;
+
+-- name: ListOpenStints :many
+SELECT id, addon_id, category, last_rank, last_seen_at
+FROM trending_stints
+WHERE exited_at IS NULL;
+
+-- name: OpenStint :exec
+INSERT INTO trending_stints (addon_id, category, entered_at, last_seen_at, last_rank, best_rank)
+VALUES (sqlc.arg(addon_id), sqlc.arg(category), sqlc.arg(seen_at), sqlc.arg(seen_at), sqlc.arg(rank), sqlc.arg(rank));
+
+-- name: ExtendStint :exec
+UPDATE trending_stints
+SET last_rank = sqlc.arg(rank),
+    best_rank = LEAST(best_rank, sqlc.arg(rank)),
+    last_seen_at = sqlc.arg(seen_at),
+    hours_at_top = hours_at_top + sqlc.arg(top_hours)::numeric
+WHERE id = sqlc.arg(id);
+
+-- name: CloseStint :exec
+UPDATE trending_stints
+SET exited_at = sqlc.arg(exited_at),
+    hours_at_top = hours_at_top + sqlc.arg(top_hours)::numeric
+WHERE id = sqlc.arg(id);
+
+-- name: DeletePreviousStints :exec
+DELETE FROM trending_stints_previous;
+
+-- name: KeepOpenStints :exec
+-- Keep the open stints as they are before the given run advances them
+INSERT INTO trending_stints_previous (id, replaced_by_run_id, exited_at, last_seen_at, last_rank, best_rank, hours_at_top)
+SELECT id, sqlc.arg(run_id), exited_at, last_seen_at, last_rank, best_rank, hours_at_top
+FROM trending_stints
+WHERE exited_at IS NULL;
+
+-- name: RestorePreviousStints :execrows
+-- Undo how the given run advanced the stints it kept
+UPDATE trending_stints s
+SET exited_at = p.exited_at,
+    last_seen_at = p.last_seen_at,
+    last_rank = p.last_rank,
+    best_rank = p.best_rank,
+    hours_at_top = p.hours_at_top
+FROM trending_stints_previous p
+WHERE p.id = s.id AND p.replaced_by_run_id = sqlc.arg(run_id);
+
+-- name: DeleteStintsEnteredSince :execrows
+-- Drop stints opened by a generation that was rolled back
+DELETE FROM trending_stints
+WHERE entered_at >= sqlc.arg(since);
+
+-- name: ListAddonStints :many
+SELECT category, entered_at, exited_at, last_seen_at, best_rank, hours_at_top
+FROM trending_stints
+WHERE addon_id = $1
+ORDER BY entered_at DESC, id DESC;
+
+-- name: ListLongestStints :many
+-- Each active addon's longest stint on a list, longest first. Stints still
+-- running count up to the last calculation that listed the addon.
+WITH longest AS (
+    SELECT DISTINCT ON (addon_id)
+        addon_id, entered_at, exited_at, best_rank, hours_at_top,
+        (EXTRACT(EPOCH FROM COALESCE(exited_at, last_seen_at) - entered_at) / 3600)::float8 AS hours
+    FROM trending_stints
+    WHERE category = sqlc.arg(category)
+    ORDER BY addon_id, COALESCE(exited_at, last_seen_at) - entered_at DESC, id
+)
+SELECT sqlc.embed(a), l.entered_at, l.exited_at, l.hours, l.best_rank, l.hours_at_top
+FROM longest l
+JOIN addons a ON a.id = l.addon_id
+WHERE a.status = 'active'
+ORDER BY l.hours DESC, l.addon_id
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: CountLongestStints :one
+SELECT COUNT(DISTINCT s.addon_id)
+FROM trending_stints s
+JOIN addons a ON a.id = s.addon_id
+WHERE a.status = 'active'
+  AND s.category = sqlc.arg(category);
+
 -- name: GetRankChanges :many
 -- Get rank changes for top addons (24h and 7d ago)
 WITH current_ranks AS (
@@ -396,3 +869,699 @@ SELECT
 FROM current_ranks c
 LEFT JOIN ranks_24h r24 ON c.addon_id = r24.addon_id AND c.category = r24.category
 LEFT JOIN ranks_7d r7 ON c.addon_id = r7.addon_id AND c.category = r7.category;
+
+-- name: ListScheduledJobRuns :many
+SELECT * FROM scheduled_job_runs;
+
+-- name: RecordScheduledJobRun :exec
+-- Record the outcome of a daemon job run; last_success_at is kept when the run failed
+INSERT INTO scheduled_job_runs (job_name, last_started_at, last_finished_at, last_success_at, last_error)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (job_name) DO UPDATE SET
+    last_started_at = EXCLUDED.last_started_at,
+    last_finished_at = EXCLUDED.last_finished_at,
+    last_success_at = COALESCE(EXCLUDED.last_success_at, scheduled_job_runs.last_success_at),
+    last_error = EXCLUDED.last_error;
+
+-- name: TryJobAdvisoryLock :one
+-- Session-level lock: must run on a dedicated connection that is held for the whole job
+SELECT pg_try_advisory_lock(hashtextextended(sqlc.arg(job_name)::text, 0)) AS acquired;
+
+-- name: ReleaseJobAdvisoryLock :one
+SELECT pg_advisory_unlock(hashtextextended(sqlc.arg(job_name)::text, 0)) AS released;
+
+-- name: ClaimJobLock :exec
+INSERT INTO job_locks (job_name, holder, backend_pid, acquired_at, heartbeat_at)
+VALUES ($1, $2, pg_backend_pid(), NOW(), NOW())
+ON CONFLICT (job_name) DO UPDATE SET
+    holder = EXCLUDED.holder,
+    backend_pid = EXCLUDED.backend_pid,
+    acquired_at = EXCLUDED.acquired_at,
+    heartbeat_at = EXCLUDED.heartbeat_at;
+
+-- name: HeartbeatJobLock :execrows
+UPDATE job_locks SET heartbeat_at = NOW()
+WHERE job_name = $1 AND holder = $2;
+
+-- name: DeleteJobLock :exec
+DELETE FROM job_locks WHERE job_name = $1 AND holder = $2;
+
+-- name: GetJobLock :one
+SELECT
+    l.job_name, l.holder, l.backend_pid, l.acquired_at, l.heartbeat_at,
+    EXISTS (SELECT 1 FROM pg_stat_activity a WHERE a.pid = l.backend_pid) AS holder_connected
+FROM job_locks l
+WHERE l.job_name = $1;
+
+-- name: ListJobLocks :many
+SELECT
+    l.job_name, l.holder, l.backend_pid, l.acquired_at, l.heartbeat_at,
+    EXISTS (SELECT 1 FROM pg_stat_activity a WHERE a.pid = l.backend_pid) AS holder_connected
+FROM job_locks l
+ORDER BY l.job_name;
+
+-- name: InsertSyncRun :exec
+INSERT INTO sync_runs (started_at, fetched_count, synced_count, error_count, baseline_count, quarantined, quarantine_reason)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetLatestSyncRun :one
+SELECT * FROM sync_runs
+ORDER BY started_at DESC
+LIMIT 1;
+
+-- name: GetLastHealthySyncRun :one
+-- Most recent run that was not quarantined, used as the baseline for relative thresholds
+SELECT * FROM sync_runs
+WHERE NOT quarantined
+ORDER BY started_at DESC
+LIMIT 1;
+
+-- name: RegisterTrendingParamSet :exec
+-- Record a parameter set; an existing version is left untouched
+INSERT INTO trending_param_sets (version, params)
+VALUES ($1, $2)
+ON CONFLICT (version) DO NOTHING;
+
+-- name: GetTrendingParamSet :one
+SELECT * FROM trending_param_sets WHERE version = $1;
+
+-- name: GetActiveTrendingParamSet :one
+SELECT * FROM trending_param_sets WHERE is_active;
+
+-- name: DeactivateTrendingParamSets :exec
+UPDATE trending_param_sets SET is_active = FALSE WHERE is_active;
+
+-- name: ActivateTrendingParamSet :execrows
+UPDATE trending_param_sets SET is_active = TRUE WHERE version = $1;
+
+-- name: GetShadowTrendingParamSet :one
+SELECT * FROM trending_param_sets WHERE is_shadow;
+
+-- name: ClearShadowTrendingParamSets :exec
+UPDATE trending_param_sets SET is_shadow = FALSE WHERE is_shadow;
+
+-- name: MarkShadowTrendingParamSet :execrows
+UPDATE trending_param_sets SET is_shadow = TRUE WHERE version = $1;
+
+-- name: InsertTrendingCalculationRun :one
+INSERT INTO trending_calculation_runs (params_version, params_source, started_at, processed_count)
+VALUES ($1, $2, $3, $4)
+RETURNING id;
+
+-- name: GetCurrentTrendingParamSet :one
+-- Parameter set that produced the live trending scores
+SELECT p.version, p.params, p.is_active, p.is_shadow, p.created_at
+FROM trending_generations g
+JOIN trending_calculation_runs r ON r.id = g.run_id
+JOIN trending_param_sets p ON p.version = r.params_version
+WHERE g.slot = 'live';
+
+-- name: GetAllSnapshotStatsAsOf :many
+-- GetAllSnapshotStats as it would have returned at as_of, using only snapshots
+-- recorded by then; counts come from each addon's latest visible snapshot
+WITH visible AS (
+    SELECT addon_id, recorded_at, download_count, thumbs_up_count, latest_file_date
+    FROM snapshots
+    WHERE recorded_at <= sqlc.arg(as_of)::timestamptz
+      AND recorded_at >= sqlc.arg(as_of)::timestamptz - INTERVAL '7 days'
+),
+latest AS (
+    SELECT DISTINCT ON (addon_id) addon_id, download_count, thumbs_up_count, latest_file_date
+    FROM visible
+    ORDER BY addon_id, recorded_at DESC
+),
+stats_24h AS (
+    SELECT
+        addon_id,
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        COUNT(*)::int AS snapshot_count,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope,
+        regr_r2(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_fit,
+        regr_r2(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_fit,
+        EXTRACT(EPOCH FROM MAX(recorded_at) - MIN(recorded_at))::float8 / 3600 AS span_hours
+    FROM visible
+    WHERE recorded_at >= sqlc.arg(as_of)::timestamptz - INTERVAL '24 hours'
+    GROUP BY addon_id
+),
+stats_7d AS (
+    SELECT
+        addon_id,
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        MIN(download_count)::bigint AS min_downloads,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope
+    FROM visible
+    GROUP BY addon_id
+)
+SELECT
+    l.addon_id,
+    l.download_count,
+    l.thumbs_up_count,
+    l.latest_file_date,
+    a.created_at,
+    a.author_id,
+    a.primary_category_id,
+    COALESCE(s24.download_change, 0) AS download_change_24h,
+    COALESCE(s24.thumbs_change, 0) AS thumbs_change_24h,
+    COALESCE(s24.snapshot_count, 0) AS snapshot_count_24h,
+    s7.download_change AS download_change_7d,
+    s7.thumbs_change AS thumbs_change_7d,
+    s7.min_downloads AS min_downloads_7d,
+    COALESCE(s24.download_slope, 0)::float8 AS download_slope_24h,
+    COALESCE(s24.thumbs_slope, 0)::float8 AS thumbs_slope_24h,
+    COALESCE(s24.download_fit, 0)::float8 AS download_fit_24h,
+    COALESCE(s24.thumbs_fit, 0)::float8 AS thumbs_fit_24h,
+    COALESCE(s24.span_hours, 0)::float8 AS span_hours_24h,
+    COALESCE(s7.download_slope, 0)::float8 AS download_slope_7d,
+    COALESCE(s7.thumbs_slope, 0)::float8 AS thumbs_slope_7d
+FROM latest l
+JOIN addons a ON a.id = l.addon_id
+JOIN stats_7d s7 ON s7.addon_id = l.addon_id
+LEFT JOIN stats_24h s24 ON s24.addon_id = l.addon_id;
+
+-- name: GetDownloadPercentileAsOf :one
+-- GetDownloadPercentile over each addon's latest snapshot in the week before as_of
+SELECT COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY download_count), 500000)::FLOAT8 AS percentile_95
+FROM (
+    SELECT DISTINCT ON (addon_id) download_count
+    FROM snapshots
+    WHERE recorded_at <= sqlc.arg(as_of)::timestamptz
+      AND recorded_at >= sqlc.arg(as_of)::timestamptz - INTERVAL '7 days'
+    ORDER BY addon_id, recorded_at DESC
+) latest
+WHERE download_count > 0;
+
+-- name: CountAllRecentFileUpdatesAsOf :many
+-- CountAllRecentFileUpdates as it would have returned at as_of
+SELECT
+    addon_id,
+    COUNT(DISTINCT DATE(latest_file_date))::int AS update_count
+FROM snapshots
+WHERE recorded_at <= sqlc.arg(as_of)::timestamptz
+  AND recorded_at >= sqlc.arg(as_of)::timestamptz - INTERVAL '90 days'
+  AND latest_file_date IS NOT NULL
+GROUP BY addon_id;
+
+-- name: ListTopDownloadGains :many
+-- Addons with the largest download gains between two times
+SELECT
+    addon_id,
+    MIN(download_count)::bigint AS start_downloads,
+    (MAX(download_count) - MIN(download_count))::bigint AS gain
+FROM snapshots
+WHERE recorded_at >= sqlc.arg(from_time)::timestamptz
+  AND recorded_at <= sqlc.arg(to_time)::timestamptz
+GROUP BY addon_id
+ORDER BY gain DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetAddonSnapshotStats :one
+-- GetAllSnapshotStats for a single addon, whatever its status
+WITH stats_24h AS (
+    SELECT
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        COUNT(*)::int AS snapshot_count,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope,
+        regr_r2(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_fit,
+        regr_r2(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_fit,
+        EXTRACT(EPOCH FROM MAX(recorded_at) - MIN(recorded_at))::float8 / 3600 AS span_hours
+    FROM snapshots
+    WHERE addon_id = sqlc.arg(addon_id)
+      AND recorded_at >= NOW() - INTERVAL '24 hours'
+),
+stats_7d AS (
+    SELECT
+        COALESCE(MAX(download_count) - MIN(download_count), 0)::bigint AS download_change,
+        COALESCE(MAX(thumbs_up_count) - MIN(thumbs_up_count), 0)::int AS thumbs_change,
+        MIN(download_count)::bigint AS min_downloads,
+        regr_slope(download_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS download_slope,
+        regr_slope(thumbs_up_count, EXTRACT(EPOCH FROM recorded_at)::float8 / 3600) AS thumbs_slope
+    FROM snapshots
+    WHERE addon_id = sqlc.arg(addon_id)
+      AND recorded_at >= NOW() - INTERVAL '7 days'
+)
+SELECT
+    a.id AS addon_id,
+    a.download_count,
+    a.thumbs_up_count,
+    a.latest_file_date,
+    a.created_at,
+    a.author_id,
+    a.primary_category_id,
+    s24.download_change AS download_change_24h,
+    s24.thumbs_change AS thumbs_change_24h,
+    s24.snapshot_count AS snapshot_count_24h,
+    s7.download_change AS download_change_7d,
+    s7.thumbs_change AS thumbs_change_7d,
+    COALESCE(s7.min_downloads, a.download_count)::bigint AS min_downloads_7d,
+    COALESCE(s24.download_slope, 0)::float8 AS download_slope_24h,
+    COALESCE(s24.thumbs_slope, 0)::float8 AS thumbs_slope_24h,
+    COALESCE(s24.download_fit, 0)::float8 AS download_fit_24h,
+    COALESCE(s24.thumbs_fit, 0)::float8 AS thumbs_fit_24h,
+    COALESCE(s24.span_hours, 0)::float8 AS span_hours_24h,
+    COALESCE(s7.download_slope, 0)::float8 AS download_slope_7d,
+    COALESCE(s7.thumbs_slope, 0)::float8 AS thumbs_slope_7d
+FROM addons a, stats_24h s24, stats_7d s7
+WHERE a.id = sqlc.arg(addon_id);
+
+-- name: CountAddonRecentFileUpdates :one
+-- CountAllRecentFileUpdates for a single addon
+SELECT COUNT(DISTINCT DATE(latest_file_date))::int AS update_count
+FROM snapshots
+WHERE addon_id = $1
+  AND recorded_at >= NOW() - INTERVAL '90 days'
+  AND latest_file_date IS NOT NULL;
+
+-- name: CountHotAddonsAbove :one
+-- Entries ranked above an addon off the hot list with the given score: every
+-- list member, then higher scores
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND (t.hot_rank IS NOT NULL OR t.hot_score > sqlc.arg(score)::numeric);
+
+-- name: CountFreshAddonsAbove :one
+-- Fresh list entries ranked above the given score
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.fresh_score > sqlc.arg(score)::numeric;
+
+-- name: CountLovedAddonsAbove :one
+-- Entries ranked above an addon off the loved list with the given score: every
+-- list member, then higher scores
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND (t.loved_rank IS NOT NULL OR t.loved_score > sqlc.arg(score)::numeric);
+
+-- name: CountRisingAddonsAbove :one
+-- Entries ranked above an addon off the rising list with the given score: every
+-- list member, then higher scores
+SELECT COUNT(*)
+FROM addons a
+JOIN trending_scores t ON a.id = t.addon_id
+WHERE a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND a.download_count <= sqlc.arg(max_downloads)::bigint
+  AND (t.rising_rank IS NOT NULL OR t.rising_score > sqlc.arg(score)::numeric)
+  AND a.id NOT IN (
+      SELECT addon_id FROM trending_scores
+      WHERE hot_score > 0
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT sqlc.arg(hot_list_size)
+  );
+
+-- name: GetCategoryDownloadPercentiles :many
+-- 95th percentile of downloads among each category's active addons, for the
+-- per-category size multiplier
+SELECT
+    c.id AS category_id,
+    PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY a.download_count)::FLOAT8 AS percentile_95
+FROM categories c
+JOIN addons a ON c.id = ANY(a.categories)
+WHERE c.deleted_at IS NULL
+  AND a.status = 'active'
+  AND a.download_count > 0
+GROUP BY c.id;
+
+-- name: ListActiveAddonCategories :many
+-- Category memberships of every active addon
+SELECT a.id AS addon_id, c.id AS category_id
+FROM addons a
+JOIN categories c ON c.id = ANY(a.categories)
+WHERE a.status = 'active'
+  AND c.deleted_at IS NULL;
+
+-- name: GetAllCategoryTrendingScores :many
+-- Bulk fetch list ages and places for every category
+SELECT category_id, addon_id, first_hot_at, first_rising_at, hot_rank, rising_rank, hot_misses, rising_misses
+FROM category_trending_scores;
+
+-- name: InsertStagedCategoryTrendingScores :copyfrom
+INSERT INTO category_trending_scores_staging (
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+);
+
+-- name: ListCategoryHotAddonsPaginated :many
+SELECT sqlc.embed(a), t.hot_score, t.download_velocity
+FROM addons a
+JOIN category_trending_scores t ON a.id = t.addon_id
+WHERE t.category_id = sqlc.arg(category_id)
+  AND a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.hot_score > 0
+ORDER BY CASE WHEN sqlc.arg(raw_order)::boolean THEN t.hot_raw_rank ELSE t.hot_rank END NULLS LAST,
+    t.hot_score DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: CountCategoryHotAddons :one
+SELECT COUNT(*)
+FROM addons a
+JOIN category_trending_scores t ON a.id = t.addon_id
+WHERE t.category_id = sqlc.arg(category_id)
+  AND a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND t.hot_score > 0;
+
+-- name: ListCategoryRisingAddonsPaginated :many
+-- Rising within a category excludes the top of that category's hot list
+SELECT sqlc.embed(a), t.rising_score, t.download_velocity
+FROM addons a
+JOIN category_trending_scores t ON a.id = t.addon_id
+WHERE t.category_id = sqlc.arg(category_id)
+  AND a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND a.download_count <= sqlc.arg(max_downloads)::bigint
+  AND t.rising_score > 0
+  AND a.id NOT IN (
+      SELECT addon_id FROM category_trending_scores
+      WHERE category_id = sqlc.arg(category_id)
+        AND hot_score > 0
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT sqlc.arg(hot_list_size)
+  )
+ORDER BY CASE WHEN sqlc.arg(raw_order)::boolean THEN t.rising_raw_rank ELSE t.rising_rank END NULLS LAST,
+    t.rising_score DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: CountCategoryRisingAddons :one
+SELECT COUNT(*)
+FROM addons a
+JOIN category_trending_scores t ON a.id = t.addon_id
+WHERE t.category_id = sqlc.arg(category_id)
+  AND a.status = 'active'
+  AND a.download_count >= sqlc.arg(min_downloads)::bigint
+  AND a.download_count <= sqlc.arg(max_downloads)::bigint
+  AND t.rising_score > 0
+  AND a.id NOT IN (
+      SELECT addon_id FROM category_trending_scores
+      WHERE category_id = sqlc.arg(category_id)
+        AND hot_score > 0
+      ORDER BY hot_rank NULLS LAST, hot_score DESC
+      LIMIT sqlc.arg(hot_list_size)
+  );
+
+-- name: InsertCategoryRankHistory :exec
+INSERT INTO category_rank_history (category_id, addon_id, list, rank, score, recorded_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: DeleteOldCategoryRankHistory :execrows
+-- Same 8-day retention as trending_rank_history
+DELETE FROM category_rank_history
+WHERE recorded_at < NOW() - INTERVAL '8 days';
+
+-- name: GetCategoryRankChanges :many
+-- GetRankChanges for one category's lists
+WITH current_ranks AS (
+    SELECT DISTINCT ON (addon_id, list) addon_id, list, rank, score
+    FROM category_rank_history
+    WHERE category_id = sqlc.arg(category_id)
+    ORDER BY addon_id, list, recorded_at DESC
+),
+ranks_24h AS (
+    SELECT DISTINCT ON (addon_id, list) addon_id, list, rank
+    FROM category_rank_history
+    WHERE category_id = sqlc.arg(category_id)
+      AND recorded_at <= NOW() - INTERVAL '24 hours'
+    ORDER BY addon_id, list, recorded_at DESC
+),
+ranks_7d AS (
+    SELECT DISTINCT ON (addon_id, list) addon_id, list, rank
+    FROM category_rank_history
+    WHERE category_id = sqlc.arg(category_id)
+      AND recorded_at <= NOW() - INTERVAL '7 days'
+    ORDER BY addon_id, list, recorded_at DESC
+)
+SELECT
+    c.addon_id,
+    c.list,
+    c.rank AS current_rank,
+    c.score,
+    r24.rank AS rank_24h_ago,
+    r7.rank AS rank_7d_ago
+FROM current_ranks c
+LEFT JOIN ranks_24h r24 ON c.addon_id = r24.addon_id AND c.list = r24.list
+LEFT JOIN ranks_7d r7 ON c.addon_id = r7.addon_id AND c.list = r7.list;
+
+-- name: DeleteStagedTrendingScores :exec
+DELETE FROM trending_scores_staging;
+
+-- name: DeletePreviousTrendingScores :exec
+DELETE FROM trending_scores_previous;
+
+-- name: DeleteTrendingScores :exec
+DELETE FROM trending_scores;
+
+-- name: CopyTrendingScoresToPrevious :exec
+INSERT INTO trending_scores_previous (
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+)
+SELECT
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_scores;
+
+-- name: PublishStagedTrendingScores :exec
+INSERT INTO trending_scores (
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+)
+SELECT
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_scores_staging;
+
+-- name: RestorePreviousTrendingScores :exec
+INSERT INTO trending_scores (
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+)
+SELECT
+    addon_id, hot_score, rising_score, download_velocity, thumbs_velocity,
+    download_growth_pct, thumbs_growth_pct, size_multiplier, maintenance_multiplier,
+    first_hot_at, first_rising_at, calculated_at, params_version, inputs_hash,
+    loved_score, first_loved_at, fresh_score,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_scores_previous;
+
+-- name: DeleteStagedCategoryTrendingScores :exec
+DELETE FROM category_trending_scores_staging;
+
+-- name: DeletePreviousCategoryTrendingScores :exec
+DELETE FROM category_trending_scores_previous;
+
+-- name: DeleteCategoryTrendingScores :exec
+DELETE FROM category_trending_scores;
+
+-- name: CopyCategoryTrendingScoresToPrevious :exec
+INSERT INTO category_trending_scores_previous (
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+)
+SELECT
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+FROM category_trending_scores;
+
+-- name: PublishStagedCategoryTrendingScores :exec
+INSERT INTO category_trending_scores (
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+)
+SELECT
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+FROM category_trending_scores_staging;
+
+-- name: RestorePreviousCategoryTrendingScores :exec
+INSERT INTO category_trending_scores (
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+)
+SELECT
+    category_id, addon_id, hot_score, rising_score, download_velocity,
+    size_multiplier, first_hot_at, first_rising_at, calculated_at,
+    hot_rank, rising_rank, hot_misses, rising_misses
+FROM category_trending_scores_previous;
+
+-- name: GetTrendingGeneration :one
+SELECT * FROM trending_generations WHERE slot = $1;
+
+-- name: SetTrendingGeneration :exec
+INSERT INTO trending_generations (slot, run_id, published_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (slot) DO UPDATE SET
+    run_id = EXCLUDED.run_id,
+    published_at = EXCLUDED.published_at;
+
+-- name: DeleteTrendingGeneration :exec
+DELETE FROM trending_generations WHERE slot = $1;
+
+-- name: ListTrendingGenerations :many
+-- Live and previous generations with the calculation runs that produced them
+SELECT g.slot, g.run_id, g.published_at, r.params_version, r.finished_at, r.processed_count
+FROM trending_generations g
+JOIN trending_calculation_runs r ON r.id = g.run_id
+ORDER BY g.published_at DESC;
+
+-- name: DeleteRankHistorySince :execrows
+-- Drop ranks recorded from a generation that was rolled back
+DELETE FROM trending_rank_history
+WHERE recorded_at >= sqlc.arg(since);
+
+-- name: DeleteCategoryRankHistorySince :execrows
+DELETE FROM category_rank_history
+WHERE recorded_at >= sqlc.arg(since);
+
+-- name: GetAllShadowScores :many
+-- List state of the shadow parameter set's last calculation
+SELECT
+    addon_id, params_version, first_hot_at, first_rising_at, first_loved_at,
+    hot_rank, rising_rank, loved_rank, hot_misses, rising_misses, loved_misses,
+    hot_raw_rank, rising_raw_rank, loved_raw_rank
+FROM trending_shadow_scores;
+
+-- name: DeleteShadowScores :exec
+DELETE FROM trending_shadow_scores;
+
+-- name: InsertShadowScores :copyfrom
+INSERT INTO trending_shadow_scores (
+    addon_id, params_version, hot_score, rising_score, loved_score, fresh_score,
+    first_hot_at, first_rising_at, first_loved_at, hot_rank, rising_rank, loved_rank,
+    hot_misses, rising_misses, loved_misses, hot_raw_rank, rising_raw_rank, loved_raw_rank,
+    calculated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+);
+
+-- name: InsertShadowComparison :exec
+INSERT INTO trending_shadow_comparisons (
+    calculated_at, live_version, shadow_version, list, overlap, rank_correlation, live_churn, shadow_churn
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: DeleteOldShadowComparisons :execrows
+DELETE FROM trending_shadow_comparisons
+WHERE calculated_at < NOW() - INTERVAL '90 days';
+
+-- name: ListShadowComparisons :many
+-- A list's comparisons since the given time, oldest first
+SELECT calculated_at, live_version, shadow_version, overlap, rank_correlation, live_churn, shadow_churn
+FROM trending_shadow_comparisons
+WHERE list = sqlc.arg(list) AND calculated_at >= sqlc.arg(since)::timestamptz
+ORDER BY calculated_at, id;
+
+-- name: ListShadowDiff :many
+-- Addons on a live or shadow list with their rank on each, by live rank then
+-- shadow rank
+WITH live AS (
+    SELECT addon_id,
+        CASE sqlc.arg(list)::text WHEN 'hot' THEN hot_rank WHEN 'rising' THEN rising_rank ELSE loved_rank END AS rank
+    FROM trending_scores
+),
+shadow AS (
+    SELECT addon_id,
+        CASE sqlc.arg(list)::text WHEN 'hot' THEN hot_rank WHEN 'rising' THEN rising_rank ELSE loved_rank END AS rank
+    FROM trending_shadow_scores
+)
+SELECT sqlc.embed(a), l.rank AS live_rank, s.rank AS shadow_rank
+FROM addons a
+LEFT JOIN live l ON l.addon_id = a.id
+LEFT JOIN shadow s ON s.addon_id = a.id
+WHERE l.rank IS NOT NULL OR s.rank IS NOT NULL
+ORDER BY l.rank NULLS LAST, s.rank NULLS LAST, a.id;
+
+-- name: DeleteHeatIndexDay :exec
+DELETE FROM heat_index_daily WHERE day = $1;
+
+-- name: InsertHeatIndexDaily :copyfrom
+INSERT INTO heat_index_daily (addon_id, day, heat_index, run_id) VALUES ($1, $2, $3, $4);
+
+-- name: DeletePreviousHeatIndex :exec
+DELETE FROM heat_index_previous;
+
+-- name: KeepHeatIndexDay :exec
+-- Keep the day's heat index before the given run replaces it
+INSERT INTO heat_index_previous (addon_id, day, heat_index, run_id, replaced_by_run_id)
+SELECT addon_id, day, heat_index, run_id, sqlc.arg(replaced_by_run_id)
+FROM heat_index_daily
+WHERE day = sqlc.arg(day);
+
+-- name: DeleteHeatIndexOfRun :execrows
+DELETE FROM heat_index_daily WHERE run_id = $1;
+
+-- name: RestorePreviousHeatIndex :execrows
+-- Put back the heat index rows the given run replaced
+INSERT INTO heat_index_daily (addon_id, day, heat_index, run_id)
+SELECT addon_id, day, heat_index, run_id
+FROM heat_index_previous
+WHERE replaced_by_run_id = $1;
+
+-- name: GetLatestHeatIndexDay :one
+SELECT MAX(day)::date AS day FROM heat_index_daily;
+
+-- name: UpdateAddonHeat :execrows
+-- Set the heat index of every addon scored on the given day, with a sparkline
+-- of the 7 days up to it; days without a heat index count as 0
+UPDATE addons a
+SET heat_index = cur.heat_index,
+    heat_sparkline = (
+        SELECT array_agg(COALESCE(d.heat_index, 0)::smallint ORDER BY o.n)
+        FROM generate_series(-6, 0) AS o(n)
+        LEFT JOIN heat_index_daily d ON d.addon_id = a.id AND d.day = $1::date + o.n
+    )
+FROM heat_index_daily cur
+WHERE cur.addon_id = a.id AND cur.day = $1;
+
+-- name: ClearStaleAddonHeat :execrows
+-- Clear the heat index of addons not scored on the given day
+UPDATE addons
+SET heat_index = NULL, heat_sparkline = NULL
+WHERE heat_index IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM heat_index_daily d WHERE d.addon_id = addons.id AND d.day = $1);
+
+-- name: DeleteOldHeatIndex :execrows
+-- Heat index days are UTC dates
+DELETE FROM heat_index_daily WHERE day < (NOW() AT TIME ZONE 'UTC')::date - 7;
@@ -22,13 +22,19 @@ CREATE TABLE addons (
     thumbs_up_count INTEGER DEFAULT 0,
     popularity_rank INTEGER,
     rating DECIMAL(3,2),
-    latest_file_date TIMESTAMPTZ
+    latest_file_date TIMESTAMPTZ,
+    comeback_at TIMESTAMPTZ,        -- Last time the addon was detected reviving after dormancy
+
+    -- Heat index (updated each trending calculation)
+    heat_index SMALLINT,            -- 0-100 percentile of hot or rising score; NULL when not scored
+    heat_sparkline SMALLINT[]       -- Daily heat index over the last 7 days, oldest first
 );
 
 CREATE INDEX idx_addons_slug ON addons(slug);
 CREATE INDEX idx_addons_is_hot ON addons(is_hot) WHERE is_hot = TRUE;
 CREATE INDEX idx_addons_status ON addons(status);
 CREATE INDEX idx_addons_categories ON addons USING GIN (categories);
+CREATE INDEX idx_addons_heat_index ON addons(heat_index DESC NULLS LAST) WHERE status = 'active';
 
 -- Snapshots table: time-series metrics
 CREATE TABLE snapshots (
@@ -45,15 +51,82 @@ CREATE TABLE snapshots (
 CREATE INDEX idx_snapshots_addon_time ON snapshots(addon_id, recorded_at DESC);
 CREATE INDEX idx_snapshots_recorded_at ON snapshots(recorded_at DESC);
 
+-- Addon status events: lifecycle transitions (vanished from or returned to CurseForge)
+CREATE TABLE addon_status_events (
+    id BIGSERIAL PRIMARY KEY,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    download_count BIGINT,      -- Last known stats at the time of the transition
+    thumbs_up_count INTEGER,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_status_events_addon ON addon_status_events(addon_id, occurred_at DESC);
+CREATE INDEX idx_status_events_to_status ON addon_status_events(to_status, occurred_at DESC);
+
+-- Comeback events: a release after months without one that brought downloads back
+CREATE TABLE comeback_events (
+    id BIGSERIAL PRIMARY KEY,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    release_date TIMESTAMPTZ NOT NULL,           -- File that ended the dormancy
+    previous_release_date TIMESTAMPTZ NOT NULL,  -- File before it
+    dormant_velocity DECIMAL(20,10) NOT NULL,    -- Downloads per hour before the release
+    revived_velocity DECIMAL(20,10) NOT NULL,    -- Downloads per hour since the release
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (addon_id, release_date)
+);
+
+CREATE INDEX idx_comeback_events_detected ON comeback_events(detected_at DESC);
+
+-- Addon forecasts: downloads projected from a Holt-Winters fit of each addon's
+-- hourly snapshots, replaced as a whole by every trending calculation
+CREATE TABLE addon_forecasts (
+    addon_id INTEGER PRIMARY KEY REFERENCES addons(id) ON DELETE CASCADE,
+    forecast_from TIMESTAMPTZ NOT NULL,   -- Hour of the last snapshot the fit used
+    download_count BIGINT NOT NULL,       -- Downloads at that snapshot
+    history_hours INTEGER NOT NULL,       -- Hours of snapshots the fit used
+    projected_24h BIGINT NOT NULL,        -- Total downloads expected 24 hours on
+    low_24h BIGINT NOT NULL,              -- 95% prediction interval
+    high_24h BIGINT NOT NULL,
+    projected_7d BIGINT NOT NULL,         -- Total downloads expected 7 days on
+    low_7d BIGINT NOT NULL,
+    high_7d BIGINT NOT NULL,
+    calculated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_addon_forecasts_projected_7d ON addon_forecasts(projected_7d DESC);
+
+-- Milestones: download and thumbs-up counts an addon crossed between two snapshots
+CREATE TABLE milestones (
+    id BIGSERIAL PRIMARY KEY,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    metric TEXT NOT NULL,                  -- 'downloads' or 'thumbs_up'
+    threshold BIGINT NOT NULL,
+    crossed_at TIMESTAMPTZ NOT NULL,       -- Interpolated between the snapshots either side
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (addon_id, metric, threshold)
+);
+
+CREATE INDEX idx_milestones_crossed ON milestones(crossed_at DESC);
+
 -- Categories table: reference data
 CREATE TABLE categories (
     id INTEGER PRIMARY KEY,
     name TEXT NOT NULL,
     slug TEXT NOT NULL,
     parent_id INTEGER REFERENCES categories(id),
-    icon_url TEXT
+    icon_url TEXT,
+    class_id INTEGER,                          -- Top-level class (e.g. "Addons") this category belongs to
+    is_class BOOLEAN NOT NULL DEFAULT FALSE,
+    display_index INTEGER NOT NULL DEFAULT 0,  -- CurseForge display order among siblings
+    deleted_at TIMESTAMPTZ                     -- Set when CurseForge no longer returns the category
 );
 
+CREATE INDEX idx_categories_slug ON categories(slug) WHERE deleted_at IS NULL;
+CREATE INDEX idx_categories_parent ON categories(parent_id) WHERE deleted_at IS NULL;
+
 -- Trending scores table: cached trending calculations
 CREATE TABLE trending_scores (
     addon_id INTEGER PRIMARY KEY REFERENCES addons(id) ON DELETE CASCADE,
@@ -67,16 +140,118 @@ CREATE TABLE trending_scores (
     maintenance_multiplier DECIMAL(5,4) DEFAULT 1.0,
     first_hot_at TIMESTAMPTZ,
     first_rising_at TIMESTAMPTZ,
-    calculated_at TIMESTAMPTZ DEFAULT NOW()
+    calculated_at TIMESTAMPTZ DEFAULT NOW(),
+    params_version TEXT,           -- Trending parameter set that produced this score
+    inputs_hash BIGINT,            -- Fingerprint of the scoring inputs, to skip unchanged addons
+    loved_score DECIMAL(20,10) DEFAULT 0,  -- Thumbs-up momentum relative to downloads
+    first_loved_at TIMESTAMPTZ,
+    fresh_score DECIMAL(20,10) DEFAULT 0,  -- Early traction of addons created recently
+    hot_rank SMALLINT,                     -- Displayed place on the hot list, NULL when off it
+    rising_rank SMALLINT,
+    loved_rank SMALLINT,
+    hot_misses SMALLINT NOT NULL DEFAULT 0,     -- Consecutive runs held on the hot list below its cut-off
+    rising_misses SMALLINT NOT NULL DEFAULT 0,
+    loved_misses SMALLINT NOT NULL DEFAULT 0,
+    hot_raw_rank SMALLINT,                 -- Place on the hot list before the diversity caps
+    rising_raw_rank SMALLINT,
+    loved_raw_rank SMALLINT
 );
 
 CREATE INDEX idx_trending_hot ON trending_scores(hot_score DESC) WHERE hot_score > 0;
 CREATE INDEX idx_trending_rising ON trending_scores(rising_score DESC) WHERE rising_score > 0;
+CREATE INDEX idx_trending_loved ON trending_scores(loved_score DESC) WHERE loved_score > 0;
+CREATE INDEX idx_trending_fresh ON trending_scores(fresh_score DESC) WHERE fresh_score > 0;
+
+-- Trending score generations: each calculation writes trending_scores_staging, then
+-- publishes it to trending_scores in one transaction. The generation it replaced is
+-- kept in trending_scores_previous for rollback. Columns match trending_scores.
+CREATE TABLE trending_scores_staging (
+    addon_id INTEGER PRIMARY KEY REFERENCES addons(id) ON DELETE CASCADE,
+    hot_score DECIMAL(20,10) DEFAULT 0,
+    rising_score DECIMAL(20,10) DEFAULT 0,
+    download_velocity DECIMAL(15,5) DEFAULT 0,
+    thumbs_velocity DECIMAL(15,5) DEFAULT 0,
+    download_growth_pct DECIMAL(10,5) DEFAULT 0,
+    thumbs_growth_pct DECIMAL(10,5) DEFAULT 0,
+    size_multiplier DECIMAL(5,4) DEFAULT 1.0,
+    maintenance_multiplier DECIMAL(5,4) DEFAULT 1.0,
+    first_hot_at TIMESTAMPTZ,
+    first_rising_at TIMESTAMPTZ,
+    calculated_at TIMESTAMPTZ DEFAULT NOW(),
+    params_version TEXT,
+    inputs_hash BIGINT,
+    loved_score DECIMAL(20,10) DEFAULT 0,
+    first_loved_at TIMESTAMPTZ,
+    fresh_score DECIMAL(20,10) DEFAULT 0,
+    hot_rank SMALLINT,
+    rising_rank SMALLINT,
+    loved_rank SMALLINT,
+    hot_misses SMALLINT NOT NULL DEFAULT 0,
+    rising_misses SMALLINT NOT NULL DEFAULT 0,
+    loved_misses SMALLINT NOT NULL DEFAULT 0,
+    hot_raw_rank SMALLINT,
+    rising_raw_rank SMALLINT,
+    loved_raw_rank SMALLINT
+);
+
+CREATE TABLE trending_scores_previous (
+    addon_id INTEGER PRIMARY KEY REFERENCES addons(id) ON DELETE CASCADE,
+    hot_score DECIMAL(20,10) DEFAULT 0,
+    rising_score DECIMAL(20,10) DEFAULT 0,
+    download_velocity DECIMAL(15,5) DEFAULT 0,
+    thumbs_velocity DECIMAL(15,5) DEFAULT 0,
+    download_growth_pct DECIMAL(10,5) DEFAULT 0,
+    thumbs_growth_pct DECIMAL(10,5) DEFAULT 0,
+    size_multiplier DECIMAL(5,4) DEFAULT 1.0,
+    maintenance_multiplier DECIMAL(5,4) DEFAULT 1.0,
+    first_hot_at TIMESTAMPTZ,
+    first_rising_at TIMESTAMPTZ,
+    calculated_at TIMESTAMPTZ DEFAULT NOW(),
+    params_version TEXT,
+    inputs_hash BIGINT,
+    loved_score DECIMAL(20,10) DEFAULT 0,
+    first_loved_at TIMESTAMPTZ,
+    fresh_score DECIMAL(20,10) DEFAULT 0,
+    hot_rank SMALLINT,
+    rising_rank SMALLINT,
+    loved_rank SMALLINT,
+    hot_misses SMALLINT NOT NULL DEFAULT 0,
+    rising_misses SMALLINT NOT NULL DEFAULT 0,
+    loved_misses SMALLINT NOT NULL DEFAULT 0,
+    hot_raw_rank SMALLINT,
+    rising_raw_rank SMALLINT,
+    loved_raw_rank SMALLINT
+);
+
+-- Trending parameter sets: every algorithm configuration that has produced scores.
+-- A version is immutable once recorded; change parameters by adding a new version.
+CREATE TABLE trending_param_sets (
+    version TEXT PRIMARY KEY,
+    params JSONB NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,  -- Used when no parameter file is configured
+    is_shadow BOOLEAN NOT NULL DEFAULT FALSE,  -- Scored alongside the live set for comparison, never served
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_trending_param_sets_active ON trending_param_sets(is_active) WHERE is_active;
+CREATE UNIQUE INDEX idx_trending_param_sets_shadow ON trending_param_sets(is_shadow) WHERE is_shadow;
+
+-- Trending calculation runs: which parameter set was live for each calculation
+CREATE TABLE trending_calculation_runs (
+    id BIGSERIAL PRIMARY KEY,
+    params_version TEXT NOT NULL REFERENCES trending_param_sets(version),
+    params_source TEXT NOT NULL,   -- 'file', 'table' or 'default'
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_count INTEGER NOT NULL
+);
+
+CREATE INDEX idx_trending_calc_runs_finished ON trending_calculation_runs(finished_at DESC);
 
 -- Trending rank history: tracks position changes over time
 CREATE TABLE trending_rank_history (
     addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
-    category TEXT NOT NULL CHECK (category IN ('hot', 'rising')),
+    category TEXT NOT NULL CHECK (category IN ('hot', 'rising', 'loved', 'fresh')),
     rank SMALLINT NOT NULL,
     score DECIMAL(20,10) NOT NULL,
     recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -88,3 +263,241 @@ CREATE INDEX idx_rank_history_time
 
 CREATE INDEX idx_rank_history_recorded
     ON trending_rank_history(recorded_at);
+
+-- Leaderboard archive: the final hot and rising lists of every UTC day and ISO week,
+-- kept indefinitely. Each calculation replaces the lists of its own day and week, so
+-- the last calculation of a period is the one archived.
+CREATE TABLE leaderboard_archive (
+    period TEXT NOT NULL CHECK (period IN ('daily', 'weekly')),
+    period_start DATE NOT NULL,   -- The day, or the Monday of the ISO week
+    list TEXT NOT NULL CHECK (list IN ('hot', 'rising')),
+    rank SMALLINT NOT NULL,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    score DECIMAL(20,10) NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    run_id BIGINT NOT NULL REFERENCES trending_calculation_runs(id),
+    PRIMARY KEY (period, period_start, list, rank)
+);
+
+-- Archived lists replaced by a later run in the same period, restored if that
+-- run's generation is rolled back
+CREATE TABLE leaderboard_archive_previous (
+    period TEXT NOT NULL,
+    period_start DATE NOT NULL,
+    list TEXT NOT NULL,
+    rank SMALLINT NOT NULL,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    score DECIMAL(20,10) NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    run_id BIGINT NOT NULL REFERENCES trending_calculation_runs(id),
+    replaced_by_run_id BIGINT NOT NULL REFERENCES trending_calculation_runs(id),
+    PRIMARY KEY (period, period_start, list, rank)
+);
+
+-- Trending stints: each unbroken run of an addon on an overall list, kept
+-- indefinitely and advanced by every calculation
+CREATE TABLE trending_stints (
+    id BIGSERIAL PRIMARY KEY,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    category TEXT NOT NULL CHECK (category IN ('hot', 'rising', 'loved', 'fresh')),
+    entered_at TIMESTAMPTZ NOT NULL,
+    exited_at TIMESTAMPTZ,                -- First calculation without it; NULL while still listed
+    last_seen_at TIMESTAMPTZ NOT NULL,    -- Last calculation that listed it
+    last_rank SMALLINT NOT NULL,
+    best_rank SMALLINT NOT NULL,
+    hours_at_top DECIMAL(10,2) NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_trending_stints_open ON trending_stints(addon_id, category) WHERE exited_at IS NULL;
+CREATE INDEX idx_trending_stints_addon ON trending_stints(addon_id, entered_at DESC);
+
+-- Stints as they were before the run that last advanced them, restored if that
+-- run's generation is rolled back
+CREATE TABLE trending_stints_previous (
+    id BIGINT PRIMARY KEY REFERENCES trending_stints(id) ON DELETE CASCADE,
+    replaced_by_run_id BIGINT NOT NULL REFERENCES trending_calculation_runs(id),
+    exited_at TIMESTAMPTZ,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    last_rank SMALLINT NOT NULL,
+    best_rank SMALLINT NOT NULL,
+    hours_at_top DECIMAL(10,2) NOT NULL
+);
+
+-- Heat index history: every addon's heat index at the last calculation of each
+-- UTC day, kept for 8 days for sparklines
+CREATE TABLE heat_index_daily (
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    heat_index SMALLINT NOT NULL,
+    run_id BIGINT NOT NULL REFERENCES trending_calculation_runs(id),
+    PRIMARY KEY (addon_id, day)
+);
+
+-- Heat index rows replaced by a later run on the same day, restored if that
+-- run's generation is rolled back
+CREATE TABLE heat_index_previous (
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    heat_index SMALLINT NOT NULL,
+    run_id BIGINT NOT NULL REFERENCES trending_calculation_runs(id),
+    replaced_by_run_id BIGINT NOT NULL REFERENCES trending_calculation_runs(id),
+    PRIMARY KEY (addon_id, day)
+);
+
+-- Category trending scores: hot and rising ranked within each category, with the size
+-- multiplier normalized to the category's own download percentile. Only addons with a
+-- positive score in a category are kept.
+CREATE TABLE category_trending_scores (
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    hot_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    rising_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    download_velocity DECIMAL(15,5) NOT NULL DEFAULT 0,
+    size_multiplier DECIMAL(5,4) NOT NULL DEFAULT 1.0,
+    first_hot_at TIMESTAMPTZ,
+    first_rising_at TIMESTAMPTZ,
+    calculated_at TIMESTAMPTZ NOT NULL,
+    hot_rank SMALLINT,                          -- Place on the category's lists after hysteresis
+    rising_rank SMALLINT,
+    hot_misses SMALLINT NOT NULL DEFAULT 0,     -- Consecutive runs held on them below the cut-off
+    rising_misses SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (category_id, addon_id)
+);
+
+CREATE INDEX idx_category_trending_hot
+    ON category_trending_scores(category_id, hot_score DESC) WHERE hot_score > 0;
+CREATE INDEX idx_category_trending_rising
+    ON category_trending_scores(category_id, rising_score DESC) WHERE rising_score > 0;
+
+-- Staged and previous generations of category_trending_scores, published and
+-- rolled back together with trending_scores
+CREATE TABLE category_trending_scores_staging (
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    hot_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    rising_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    download_velocity DECIMAL(15,5) NOT NULL DEFAULT 0,
+    size_multiplier DECIMAL(5,4) NOT NULL DEFAULT 1.0,
+    first_hot_at TIMESTAMPTZ,
+    first_rising_at TIMESTAMPTZ,
+    calculated_at TIMESTAMPTZ NOT NULL,
+    hot_rank SMALLINT,
+    rising_rank SMALLINT,
+    hot_misses SMALLINT NOT NULL DEFAULT 0,
+    rising_misses SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (category_id, addon_id)
+);
+
+CREATE TABLE category_trending_scores_previous (
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    hot_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    rising_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    download_velocity DECIMAL(15,5) NOT NULL DEFAULT 0,
+    size_multiplier DECIMAL(5,4) NOT NULL DEFAULT 1.0,
+    first_hot_at TIMESTAMPTZ,
+    first_rising_at TIMESTAMPTZ,
+    calculated_at TIMESTAMPTZ NOT NULL,
+    hot_rank SMALLINT,
+    rising_rank SMALLINT,
+    hot_misses SMALLINT NOT NULL DEFAULT 0,
+    rising_misses SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (category_id, addon_id)
+);
+
+-- Category rank history: per-category counterpart of trending_rank_history
+CREATE TABLE category_rank_history (
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    addon_id INTEGER NOT NULL REFERENCES addons(id) ON DELETE CASCADE,
+    list TEXT NOT NULL CHECK (list IN ('hot', 'rising')),
+    rank SMALLINT NOT NULL,
+    score DECIMAL(20,10) NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (category_id, list, addon_id, recorded_at)
+);
+
+CREATE INDEX idx_category_rank_history_recorded
+    ON category_rank_history(recorded_at);
+
+-- Scheduled job runs: last run of each daemon job, used to detect missed runs across restarts
+CREATE TABLE scheduled_job_runs (
+    job_name TEXT PRIMARY KEY,
+    last_started_at TIMESTAMPTZ NOT NULL,
+    last_finished_at TIMESTAMPTZ NOT NULL,
+    last_success_at TIMESTAMPTZ,
+    last_error TEXT              -- NULL when the last run succeeded
+);
+
+-- Job locks: who holds each job's advisory lock, for diagnosing overlapping or stuck runs.
+-- The advisory lock itself is the source of truth; a row without a live backend is stale.
+CREATE TABLE job_locks (
+    job_name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,          -- command@host:pid
+    backend_pid INTEGER NOT NULL,  -- Postgres session holding the advisory lock
+    acquired_at TIMESTAMPTZ NOT NULL,
+    heartbeat_at TIMESTAMPTZ NOT NULL
+);
+
+-- Sync runs: outcome of each full sync, and why suspicious runs were quarantined.
+-- Quarantined runs keep their snapshots but skip inactive marking and trending publication.
+CREATE TABLE sync_runs (
+    id BIGSERIAL PRIMARY KEY,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    fetched_count INTEGER NOT NULL,
+    synced_count INTEGER NOT NULL,
+    error_count INTEGER NOT NULL,
+    baseline_count INTEGER,        -- Addon count the run was compared against
+    quarantined BOOLEAN NOT NULL DEFAULT FALSE,
+    quarantine_reason TEXT
+);
+
+CREATE INDEX idx_sync_runs_started ON sync_runs(started_at DESC);
+
+-- Trending generations: the calculation runs whose scores are live and kept for rollback
+CREATE TABLE trending_generations (
+    slot TEXT PRIMARY KEY CHECK (slot IN ('live', 'previous')),
+    run_id BIGINT NOT NULL REFERENCES trending_calculation_runs(id),
+    published_at TIMESTAMPTZ NOT NULL
+);
+
+-- Shadow trending scores: the shadow parameter set's last calculation, kept
+-- only to carry its list state into the next one. Addons without a score or
+-- list state are left out.
+CREATE TABLE trending_shadow_scores (
+    addon_id INTEGER PRIMARY KEY REFERENCES addons(id) ON DELETE CASCADE,
+    params_version TEXT NOT NULL REFERENCES trending_param_sets(version),
+    hot_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    rising_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    loved_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    fresh_score DECIMAL(20,10) NOT NULL DEFAULT 0,
+    first_hot_at TIMESTAMPTZ,
+    first_rising_at TIMESTAMPTZ,
+    first_loved_at TIMESTAMPTZ,
+    hot_rank SMALLINT,
+    rising_rank SMALLINT,
+    loved_rank SMALLINT,
+    hot_misses SMALLINT NOT NULL DEFAULT 0,
+    rising_misses SMALLINT NOT NULL DEFAULT 0,
+    loved_misses SMALLINT NOT NULL DEFAULT 0,
+    hot_raw_rank SMALLINT,
+    rising_raw_rank SMALLINT,
+    loved_raw_rank SMALLINT,
+    calculated_at TIMESTAMPTZ NOT NULL
+);
+
+-- Shadow comparisons: how each list of the shadow parameter set compared with
+-- the live one at every calculation, kept for 90 days
+CREATE TABLE trending_shadow_comparisons (
+    id BIGSERIAL PRIMARY KEY,
+    calculated_at TIMESTAMPTZ NOT NULL,
+    live_version TEXT NOT NULL,
+    shadow_version TEXT NOT NULL,
+    list TEXT NOT NULL CHECK (list IN ('hot', 'rising', 'loved')),
+    overlap DECIMAL(5,4) NOT NULL,           -- Jaccard similarity of the two lists
+    rank_correlation DECIMAL(5,4) NOT NULL,  -- Spearman correlation over both lists
+    live_churn DECIMAL(5,4),                 -- Share of each list new since the last calculation;
+    shadow_churn DECIMAL(5,4)                -- NULL without a previous list
+);
+
+CREATE INDEX idx_shadow_comparisons_list ON trending_shadow_comparisons(list, calculated_at DESC);